@@ -0,0 +1,173 @@
+// Package client is a typed Go client for the pickleball paddle API.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultTimeout is used when no http.Client is provided
+const defaultTimeout = 30 * time.Second
+
+// apiVersion is sent as Accept-Version, so uploads use the current field
+// names and the create response's id is the paddle ID
+const apiVersion = "2"
+
+// Client calls the pickleball paddle API
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	// APIKey is sent in the X-API-Key header when set; it is only needed for admin endpoints
+	APIKey string
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the underlying http.Client
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.HTTPClient = httpClient
+	}
+}
+
+// WithAPIKey sets the API key sent with every request
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.APIKey = key
+	}
+}
+
+// New creates a Client for the API served at baseURL
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: defaultTimeout},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// GetPaddle fetches the full details of a paddle
+func (c *Client) GetPaddle(ctx context.Context, id string) (*Paddle, error) {
+	var paddle Paddle
+	if err := c.do(ctx, http.MethodGet, "/api/paddles/"+url.PathEscape(id), nil, &paddle); err != nil {
+		return nil, err
+	}
+	return &paddle, nil
+}
+
+// ListPaddles fetches a page of paddles. A nil opts uses the server defaults.
+func (c *Client) ListPaddles(ctx context.Context, opts *ListOptions) ([]Paddle, error) {
+	path := "/api/paddles"
+	if opts != nil {
+		query := url.Values{}
+		if opts.Limit > 0 {
+			query.Set("limit", strconv.Itoa(opts.Limit))
+		}
+		if opts.Offset > 0 {
+			query.Set("offset", strconv.Itoa(opts.Offset))
+		}
+		if len(query) > 0 {
+			path += "?" + query.Encode()
+		}
+	}
+
+	var paddles []Paddle
+	if err := c.do(ctx, http.MethodGet, path, nil, &paddles); err != nil {
+		return nil, err
+	}
+	return paddles, nil
+}
+
+// CreatePaddle uploads a new paddle
+func (c *Client) CreatePaddle(ctx context.Context, input *PaddleInput) (*CreatedPaddle, error) {
+	var created CreatedPaddle
+	if err := c.do(ctx, http.MethodPost, "/api/paddles", input, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UpdatePerformance replaces the performance measurements of a paddle
+func (c *Client) UpdatePerformance(ctx context.Context, id string, performance *Performance) (*Paddle, error) {
+	var paddle Paddle
+	if err := c.do(ctx, http.MethodPut, "/api/paddles/"+url.PathEscape(id)+"/performance", performance, &paddle); err != nil {
+		return nil, err
+	}
+	return &paddle, nil
+}
+
+// do sends a request with an optional JSON body and decodes a JSON response into out.
+// Non-2xx responses are returned as *APIError.
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encoding request body: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept-Version", apiVersion)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.APIKey != "" {
+		req.Header.Set("X-API-Key", c.APIKey)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return decodeAPIError(resp)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+// decodeAPIError builds an APIError from an error response, tolerating
+// bodies that are not the API's JSON error shape
+func decodeAPIError(resp *http.Response) error {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     http.StatusText(resp.StatusCode),
+	}
+
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	var decoded APIError
+	if err := json.Unmarshal(raw, &decoded); err == nil && decoded.Message != "" {
+		apiErr.Message = decoded.Message
+		apiErr.ErrorCode = decoded.ErrorCode
+	} else {
+		apiErr.Message = strings.TrimSpace(string(raw))
+	}
+
+	return apiErr
+}
@@ -0,0 +1,153 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestServer starts a server with a canned handler per route
+func newTestServer(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return New(server.URL, WithAPIKey("secret"))
+}
+
+// TestGetPaddle tests fetching and decoding a paddle
+func TestGetPaddle(t *testing.T) {
+	c := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/paddles/engage-pursuit-mx-6.0" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("X-API-Key") != "secret" {
+			t.Errorf("Expected API key header to be sent")
+		}
+		json.NewEncoder(w).Encode(Paddle{
+			ID:          "engage-pursuit-mx-6.0",
+			Metadata:    Metadata{Brand: "Engage", Model: "Pursuit MX 6.0"},
+			Performance: Performance{Power: 75},
+		})
+	})
+
+	paddle, err := c.GetPaddle(context.Background(), "engage-pursuit-mx-6.0")
+	if err != nil {
+		t.Fatalf("GetPaddle() returned error: %v", err)
+	}
+	if paddle.Metadata.Brand != "Engage" || paddle.Performance.Power != 75 {
+		t.Errorf("GetPaddle() = %+v, unexpected values", paddle)
+	}
+}
+
+// TestGetPaddleNotFound tests that a 404 maps to ErrNotFound
+func TestGetPaddleNotFound(t *testing.T) {
+	c := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"Not Found","message":"Paddle not found","code":404}`))
+	})
+
+	_, err := c.GetPaddle(context.Background(), "missing")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v", err)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "Paddle not found" {
+		t.Errorf("Expected APIError with message, got %#v", err)
+	}
+}
+
+// TestCreatePaddleValidationErrorCode tests that the validation error code is exposed
+func TestCreatePaddleValidationErrorCode(t *testing.T) {
+	c := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"Bad Request","message":"Validation error: invalid specs: invalid shape","code":400,"error_code":"SHAPE_INVALID"}`))
+	})
+
+	_, err := c.CreatePaddle(context.Background(), &PaddleInput{})
+	if !errors.Is(err, ErrBadRequest) {
+		t.Fatalf("Expected ErrBadRequest, got %v", err)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode != "SHAPE_INVALID" {
+		t.Errorf("Expected APIError with code SHAPE_INVALID, got %#v", err)
+	}
+}
+
+// TestListPaddles tests that pagination options are sent as query parameters
+func TestListPaddles(t *testing.T) {
+	c := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("limit"); got != "5" {
+			t.Errorf("limit = %q, want 5", got)
+		}
+		if got := r.URL.Query().Get("offset"); got != "10" {
+			t.Errorf("offset = %q, want 10", got)
+		}
+		json.NewEncoder(w).Encode([]Paddle{{ID: "a"}, {ID: "b"}})
+	})
+
+	paddles, err := c.ListPaddles(context.Background(), &ListOptions{Limit: 5, Offset: 10})
+	if err != nil {
+		t.Fatalf("ListPaddles() returned error: %v", err)
+	}
+	if len(paddles) != 2 {
+		t.Errorf("ListPaddles() returned %d paddles, want 2", len(paddles))
+	}
+}
+
+// TestCreatePaddle tests posting a paddle and mapping validation and conflict errors
+func TestCreatePaddle(t *testing.T) {
+	status := http.StatusCreated
+	c := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Unexpected request %s with content type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		if got := r.Header.Get("Accept-Version"); got != "2" {
+			t.Errorf("Accept-Version = %q, want %q", got, "2")
+		}
+		var input PaddleInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		w.WriteHeader(status)
+		if status == http.StatusCreated {
+			json.NewEncoder(w).Encode(CreatedPaddle{DatabaseID: 7, PaddleID: "engage-x", Paddle: Paddle{ID: "engage-x", Metadata: input.Metadata}})
+			return
+		}
+		w.Write([]byte(`{"error":"Conflict","message":"Paddle with ID engage-x already exists","code":409}`))
+	})
+
+	input := &PaddleInput{Metadata: Metadata{Brand: "Engage", Model: "X"}}
+	created, err := c.CreatePaddle(context.Background(), input)
+	if err != nil {
+		t.Fatalf("CreatePaddle() returned error: %v", err)
+	}
+	if created.DatabaseID != 7 || created.PaddleID != "engage-x" || created.Metadata.Brand != "Engage" {
+		t.Errorf("CreatePaddle() = %+v, unexpected values", created)
+	}
+
+	status = http.StatusConflict
+	if _, err := c.CreatePaddle(context.Background(), input); !errors.Is(err, ErrConflict) {
+		t.Errorf("Expected ErrConflict, got %v", err)
+	}
+}
+
+// TestPlainTextError tests that non-JSON error bodies are still surfaced
+func TestPlainTextError(t *testing.T) {
+	c := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "upstream unavailable", http.StatusBadGateway)
+	})
+
+	_, err := c.ListPaddles(context.Background(), nil)
+	if !errors.Is(err, ErrServer) {
+		t.Fatalf("Expected ErrServer, got %v", err)
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Message != "upstream unavailable" {
+		t.Errorf("Message = %q, want %q", apiErr.Message, "upstream unavailable")
+	}
+}
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors matched by APIError via errors.Is
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrServer       = errors.New("server error")
+)
+
+// APIError is returned for any non-2xx response from the API
+type APIError struct {
+	StatusCode int    `json:"code"`
+	Status     string `json:"error"`
+	Message    string `json:"message"`
+	// ErrorCode is the stable validation code, such as SHAPE_INVALID, when the server sent one
+	ErrorCode string `json:"error_code,omitempty"`
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("pickleball API: %d %s: %s", e.StatusCode, e.Status, e.Message)
+	}
+	return fmt.Sprintf("pickleball API: %d %s", e.StatusCode, e.Status)
+}
+
+// Is lets callers test an APIError against the sentinel errors, e.g.
+// errors.Is(err, client.ErrNotFound)
+func (e *APIError) Is(target error) bool {
+	switch e.StatusCode {
+	case http.StatusBadRequest:
+		return target == ErrBadRequest
+	case http.StatusUnauthorized:
+		return target == ErrUnauthorized
+	case http.StatusForbidden:
+		return target == ErrForbidden
+	case http.StatusNotFound:
+		return target == ErrNotFound
+	case http.StatusConflict:
+		return target == ErrConflict
+	}
+	return e.StatusCode >= 500 && target == ErrServer
+}
@@ -0,0 +1,91 @@
+package client
+
+// The types below mirror the JSON shapes served by the API. They are kept in
+// this package because the server lives in package main and cannot be imported.
+
+// Metadata represents the identifying information of a paddle
+type Metadata struct {
+	Brand      string   `json:"brand"`
+	Model      string   `json:"model"`
+	Year       *int     `json:"year,omitempty"`
+	SKU        string   `json:"sku,omitempty"`
+	ProductURL string   `json:"product_url,omitempty"`
+	Price      *float64 `json:"price,omitempty"`
+}
+
+// Specs represents the specifications of a paddle
+type Specs struct {
+	Shape             string  `json:"shape"`
+	Surface           string  `json:"surface"`
+	AverageWeight     float64 `json:"average_weight"`
+	Core              float64 `json:"core"` // millimetres unless CoreUnit says otherwise
+	CoreUnit          string  `json:"core_unit,omitempty"`
+	PaddleLength      float64 `json:"paddle_length"`
+	PaddleWidth       float64 `json:"paddle_width"`
+	GripLength        float64 `json:"grip_length"`
+	GripType          string  `json:"grip_type"`
+	GripCircumference float64 `json:"grip_circumference"`
+	EdgeGuard         string  `json:"edge_guard,omitempty"`
+	HandleType        string  `json:"handle_type,omitempty"`
+	SurfaceFront      string  `json:"surface_front,omitempty"`
+	SurfaceBack       string  `json:"surface_back,omitempty"`
+}
+
+// Performance represents the performance metrics of a paddle
+type Performance struct {
+	Power        float64 `json:"power"`
+	Pop          float64 `json:"pop"`
+	Spin         float64 `json:"spin"`
+	TwistWeight  float64 `json:"twist_weight"`
+	SwingWeight  float64 `json:"swing_weight"`
+	BalancePoint float64 `json:"balance_point"`
+
+	// Optional standard deviations of the metrics above; nil when not measured
+	PowerStddev        *float64 `json:"power_stddev,omitempty"`
+	PopStddev          *float64 `json:"pop_stddev,omitempty"`
+	SpinStddev         *float64 `json:"spin_stddev,omitempty"`
+	TwistWeightStddev  *float64 `json:"twist_weight_stddev,omitempty"`
+	SwingWeightStddev  *float64 `json:"swing_weight_stddev,omitempty"`
+	BalancePointStddev *float64 `json:"balance_point_stddev,omitempty"`
+}
+
+// SpecRange is a manufacturer-quoted range for a spec
+type SpecRange struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// PaddleInput represents the input data for creating a paddle
+type PaddleInput struct {
+	Metadata    Metadata             `json:"metadata"`
+	Specs       Specs                `json:"specs"`
+	Performance Performance          `json:"performance"`
+	SpecRanges  map[string]SpecRange `json:"spec_ranges,omitempty"`
+}
+
+// Paddle represents a paddle with its specs and performance
+type Paddle struct {
+	ID                 string               `json:"id"`
+	DisplayName        string               `json:"display_name,omitempty"`
+	Metadata           Metadata             `json:"metadata"`
+	Specs              Specs                `json:"specs"`
+	Performance        Performance          `json:"performance"`
+	SpecRanges         map[string]SpecRange `json:"spec_ranges,omitempty"`
+	Tags               []string             `json:"tags,omitempty"`
+	PerformanceSamples int                  `json:"performance_samples,omitempty"`
+	Status             string               `json:"status,omitempty"`
+	Control            float64              `json:"control"`
+}
+
+// CreatedPaddle is returned when a paddle is created
+type CreatedPaddle struct {
+	DatabaseID int    `json:"db_id"`
+	PaddleID   string `json:"paddle_id"`
+	Paddle
+}
+
+// ListOptions controls pagination of ListPaddles
+type ListOptions struct {
+	Limit  int
+	Offset int
+}
@@ -3,8 +3,9 @@ module go-pickleball
 go 1.24.0
 
 require (
+	github.com/go-pdf/fpdf v0.9.0
 	github.com/gorilla/mux v1.8.1
 	github.com/lib/pq v1.10.9
+	github.com/rs/cors v1.11.1
+	golang.org/x/text v0.30.0
 )
-
-require github.com/rs/cors v1.11.1 // indirect
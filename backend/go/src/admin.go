@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// getIntegrityReport handles the admin request for running the database integrity checks
+func getIntegrityReport(w http.ResponseWriter, r *http.Request) {
+	report, err := RunIntegrityChecks()
+	if err != nil {
+		log.Printf("Error running integrity checks: %v", err)
+		respondWithError(w, "Failed to run integrity checks", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// getSQLDump handles the admin request for downloading the dataset as SQL INSERT statements
+func getSQLDump(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+	if err := WriteSQLDump(&buf); err != nil {
+		log.Printf("Error generating SQL dump: %v", err)
+		respondWithError(w, "Failed to generate SQL dump", http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("pickleball-dump-%s.sql", time.Now().UTC().Format("20060102-150405"))
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Write(buf.Bytes())
+}
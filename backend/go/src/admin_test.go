@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// TestGetIntegrityReport tests that the integrity report flags an orphaned
+// specs row, and lists stubs without failing because of them
+func TestGetIntegrityReport(t *testing.T) {
+	setupTestDB(t)
+
+	apiKey = "test-key"
+	defer func() { apiKey = "" }()
+
+	getReport := func() IntegrityReport {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/api/admin/integrity", nil)
+		req.Header.Set(apiKeyHeader, apiKey)
+		rr := httptest.NewRecorder()
+		requireAPIKey(getIntegrityReport)(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+		}
+		var report IntegrityReport
+		if err := json.NewDecoder(rr.Body).Decode(&report); err != nil {
+			t.Fatalf("Failed to decode report: %v", err)
+		}
+		return report
+	}
+
+	// A stub with specs but no performance yet is expected
+	stub := (&PaddleInput{Metadata: Metadata{Brand: "Engage", Model: "Stub"}, Specs: testSpecs}).ToPaddle()
+	stubDBID, err := SavePaddle(stub)
+	if err != nil {
+		t.Fatalf("Failed to save stub: %v", err)
+	}
+	report := getReport()
+	if !report.OK || !slices.Equal(report.Stubs, []int{stubDBID}) {
+		t.Errorf("report = %+v, want ok with stub %d listed", report, stubDBID)
+	}
+
+	// Specs attached to no paddle are an inconsistency
+	var specID int
+	err = DB.QueryRow(`
+		INSERT INTO paddle_specs (
+			paddle_id, shape, surface, average_weight, core, paddle_length,
+			paddle_width, grip_length, grip_type, grip_circumference
+		) VALUES (NULL, 'Hybrid', 'Composite', 220, 15, 16.5, 7.5, 4.5, 'Comfort', 4)
+		RETURNING id
+	`).Scan(&specID)
+	if err != nil {
+		t.Fatalf("Failed to insert specs: %v", err)
+	}
+
+	report = getReport()
+	if report.OK {
+		t.Error("Expected report to be not OK with an orphaned specs row")
+	}
+	found := false
+	for _, check := range report.Checks {
+		if check.Name == "specs_without_paddle" && slices.Contains(check.IDs, specID) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected specs_without_paddle to report specs ID %d, got %+v", specID, report.Checks)
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// currentAPIVersion is the version clients opt into with the Accept-Version
+// header. Requests without the header are treated as version 1.
+const currentAPIVersion = 2
+
+// legacyFieldAliases maps field paths used by API version 1 to their current
+// names. Add an entry here when renaming an upload field.
+var legacyFieldAliases = map[string]string{
+	"specs.weight": "specs.average_weight",
+}
+
+// requestAPIVersion returns the API version requested via Accept-Version,
+// accepting "2" or "v2". Old clients send no header and get version 1.
+func requestAPIVersion(r *http.Request) (int, error) {
+	raw := strings.TrimSpace(r.Header.Get("Accept-Version"))
+	if raw == "" {
+		return 1, nil
+	}
+
+	version, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(raw), "v"))
+	if err != nil || version < 1 || version > currentAPIVersion {
+		return 0, fmt.Errorf("Accept-Version must be between 1 and %d", currentAPIVersion)
+	}
+	return version, nil
+}
+
+// applyFieldAliases renames legacy fields in a JSON object, or in each object
+// of a JSON array, to their current names. It returns the rewritten document
+// and the legacy paths that were found. Sending both a legacy field and its
+// replacement is an error.
+func applyFieldAliases(data []byte, aliases map[string]string) ([]byte, []string, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, nil, err
+	}
+
+	var used []string
+	renameAll := func(obj map[string]interface{}) error {
+		for legacy, current := range aliases {
+			renamed, err := renameField(obj, strings.Split(legacy, "."), strings.Split(current, "."))
+			if err != nil {
+				return err
+			}
+			if renamed {
+				used = append(used, legacy)
+			}
+		}
+		return nil
+	}
+
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		if err := renameAll(v); err != nil {
+			return nil, nil, err
+		}
+	case []interface{}:
+		for _, item := range v {
+			if obj, ok := item.(map[string]interface{}); ok {
+				if err := renameAll(obj); err != nil {
+					return nil, nil, err
+				}
+			}
+		}
+	}
+
+	if len(used) == 0 {
+		return data, nil, nil
+	}
+
+	rewritten, err := json.Marshal(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rewritten, used, nil
+}
+
+// renameField moves the value at path from to path to within obj. Both paths
+// must share the same parent object. It reports whether a field was moved.
+func renameField(obj map[string]interface{}, from, to []string) (bool, error) {
+	if len(from) != len(to) {
+		return false, fmt.Errorf("alias %s must stay in the same object as %s", strings.Join(from, "."), strings.Join(to, "."))
+	}
+
+	for i := 0; i < len(from)-1; i++ {
+		child, ok := obj[from[i]].(map[string]interface{})
+		if !ok {
+			return false, nil
+		}
+		obj = child
+	}
+
+	legacy, current := from[len(from)-1], to[len(to)-1]
+	value, ok := obj[legacy]
+	if !ok {
+		return false, nil
+	}
+	if _, exists := obj[current]; exists {
+		return false, fmt.Errorf("%s and %s are the same field; send only %s", strings.Join(from, "."), strings.Join(to, "."), strings.Join(to, "."))
+	}
+
+	delete(obj, legacy)
+	obj[current] = value
+	return true, nil
+}
+
+// decodeVersionedBody decodes an upload body like decodeJSONBody, first
+// renaming legacy field names when the client has not opted into the
+// current API version. Each legacy field used is logged as deprecated.
+func decodeVersionedBody(r *http.Request, v interface{}) error {
+	version, err := requestAPIVersion(r)
+	if err != nil {
+		return err
+	}
+
+	data, err := readJSONBody(r.Body)
+	if err != nil {
+		return err
+	}
+
+	if version < currentAPIVersion {
+		var used []string
+		data, used, err = applyFieldAliases(data, legacyFieldAliases)
+		if err != nil {
+			return err
+		}
+		for _, legacy := range used {
+			log.Printf("Deprecated field %q used in %s %s; send %q instead", legacy, r.Method, r.URL.Path, legacyFieldAliases[legacy])
+		}
+	}
+
+	return decodeJSONData(data, v)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestApplyFieldAliases tests renaming legacy fields in objects and arrays
+func TestApplyFieldAliases(t *testing.T) {
+	aliases := map[string]string{"specs.weight": "specs.average_weight"}
+
+	tests := []struct {
+		name     string
+		body     string
+		want     string
+		wantUsed int
+		wantErr  string
+	}{
+		{name: "Legacy field", body: `{"specs": {"weight": 220}}`, want: `{"specs":{"average_weight":220}}`, wantUsed: 1},
+		{name: "Current field", body: `{"specs": {"average_weight": 220}}`, want: `{"specs": {"average_weight": 220}}`},
+		{name: "Legacy field at the wrong level", body: `{"weight": 220}`, want: `{"weight": 220}`},
+		{name: "Bulk array", body: `[{"specs": {"weight": 220}}, {"specs": {"average_weight": 230}}]`, want: `[{"specs":{"average_weight":220}},{"specs":{"average_weight":230}}]`, wantUsed: 1},
+		{name: "Both names", body: `{"specs": {"weight": 220, "average_weight": 220}}`, wantErr: "send only specs.average_weight"},
+		{name: "Number precision kept", body: `{"specs": {"weight": 220.123456789012345}}`, want: `{"specs":{"average_weight":220.123456789012345}}`, wantUsed: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, used, err := applyFieldAliases([]byte(tt.body), aliases)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("applyFieldAliases() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyFieldAliases() unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("applyFieldAliases() = %s, want %s", got, tt.want)
+			}
+			if len(used) != tt.wantUsed {
+				t.Errorf("applyFieldAliases() used %v, want %d aliases", used, tt.wantUsed)
+			}
+		})
+	}
+}
+
+// TestUploadPaddleLegacyWeight tests that old clients may still send weight
+// instead of average_weight, while clients on the current version may not
+func TestUploadPaddleLegacyWeight(t *testing.T) {
+	setupTestStore(t)
+
+	body := `{
+		"metadata": {"brand": "Engage", "model": "Pursuit MX 6.0"},
+		"specs": {"shape": "Hybrid", "surface": "Composite", "weight": 220.0, "core": 15.0,
+			"paddle_length": 16.5, "paddle_width": 7.5, "grip_length": 4.5, "grip_type": "Comfort", "grip_circumference": 4.0},
+		"performance": {"power": 75.0, "pop": 70.0, "spin": 3000.0, "twist_weight": 200.0,
+			"swing_weight": 220.0, "balance_point": 30.0}
+	}`
+
+	post := func(version string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/paddles", bytes.NewBufferString(body))
+		if version != "" {
+			req.Header.Set("Accept-Version", version)
+		}
+		rr := httptest.NewRecorder()
+		uploadPaddleStats(rr, req)
+		return rr
+	}
+
+	if rr := post("v2"); rr.Code != http.StatusBadRequest || !strings.Contains(rr.Body.String(), `unknown field \"weight\"`) {
+		t.Errorf("Current-version upload with weight returned %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr := post("3"); rr.Code != http.StatusBadRequest {
+		t.Errorf("Unsupported Accept-Version returned %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+
+	if rr := post(""); rr.Code != http.StatusCreated {
+		t.Fatalf("Legacy upload returned %d, want %d: %s", rr.Code, http.StatusCreated, rr.Body.String())
+	}
+
+	paddle, err := store.GetPaddleByID(generatePaddleID("Engage", "Pursuit MX 6.0"))
+	if err != nil {
+		t.Fatalf("Failed to read uploaded paddle: %v", err)
+	}
+	if paddle.Specs.AverageWeight != 220.0 {
+		t.Errorf("AverageWeight = %v, want 220", paddle.Specs.AverageWeight)
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+// apiKeyHeader is the request header carrying the admin API key
+const apiKeyHeader = "X-API-Key"
+
+// apiKey is the key required by admin endpoints, set from API_KEY at startup.
+// When it is empty, admin endpoints are disabled.
+var apiKey string
+
+// requireAPIKey is middleware that only lets requests carrying the admin API key through
+func requireAPIKey(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if apiKey == "" {
+			respondWithError(w, "Admin API is disabled", http.StatusForbidden)
+			return
+		}
+
+		if !hasAPIKey(r) {
+			respondWithError(w, "Invalid or missing API key", http.StatusUnauthorized)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// hasAPIKey reports whether a request carries the admin API key, for public
+// endpoints with admin-only options. It is always false when the admin API is disabled.
+func hasAPIKey(r *http.Request) bool {
+	if apiKey == "" {
+		return false
+	}
+	provided := r.Header.Get(apiKeyHeader)
+	return subtle.ConstantTimeCompare([]byte(provided), []byte(apiKey)) == 1
+}
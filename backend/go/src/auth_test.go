@@ -0,0 +1,46 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestRequireAPIKey tests the requireAPIKey middleware
+func TestRequireAPIKey(t *testing.T) {
+	defer func() { apiKey = "" }()
+
+	okHandler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	tests := []struct {
+		name           string
+		configuredKey  string
+		providedKey    string
+		expectedStatus int
+	}{
+		{name: "Valid key", configuredKey: "secret", providedKey: "secret", expectedStatus: http.StatusOK},
+		{name: "Wrong key", configuredKey: "secret", providedKey: "guess", expectedStatus: http.StatusUnauthorized},
+		{name: "Missing key", configuredKey: "secret", providedKey: "", expectedStatus: http.StatusUnauthorized},
+		{name: "Admin disabled", configuredKey: "", providedKey: "secret", expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiKey = tt.configuredKey
+
+			req := httptest.NewRequest("GET", "/api/admin/integrity", nil)
+			if tt.providedKey != "" {
+				req.Header.Set(apiKeyHeader, tt.providedKey)
+			}
+			rr := httptest.NewRecorder()
+
+			requireAPIKey(okHandler)(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("requireAPIKey returned status %d, want %d", rr.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// AveragePaddle is the mean paddle of a set. Text fields are only set when
+// every paddle in the set shares the same value.
+type AveragePaddle struct {
+	Metadata    Metadata    `json:"metadata"`
+	Specs       Specs       `json:"specs"`
+	Performance Performance `json:"performance"`
+	Control     float64     `json:"control"`
+	SampleSize  int         `json:"sample_size"`
+}
+
+// computeAverage averages the specs and performance of a set of paddles.
+// It returns nil for an empty set.
+func computeAverage(paddles []*Paddle) *AveragePaddle {
+	if len(paddles) == 0 {
+		return nil
+	}
+
+	avg := &AveragePaddle{SampleSize: len(paddles)}
+	measurements := make([]Performance, 0, len(paddles))
+	brands := make([]string, 0, len(paddles))
+	shapes := make([]string, 0, len(paddles))
+	surfaces := make([]string, 0, len(paddles))
+	gripTypes := make([]string, 0, len(paddles))
+
+	for _, paddle := range paddles {
+		avg.Specs.AverageWeight += paddle.Specs.AverageWeight
+		avg.Specs.Core += paddle.Specs.Core
+		avg.Specs.PaddleLength += paddle.Specs.PaddleLength
+		avg.Specs.PaddleWidth += paddle.Specs.PaddleWidth
+		avg.Specs.GripLength += paddle.Specs.GripLength
+		avg.Specs.GripCircumference += paddle.Specs.GripCircumference
+
+		measurements = append(measurements, paddle.Performance)
+		brands = append(brands, paddle.Metadata.Brand)
+		shapes = append(shapes, string(paddle.Specs.Shape))
+		surfaces = append(surfaces, paddle.Specs.Surface)
+		gripTypes = append(gripTypes, paddle.Specs.GripType)
+	}
+
+	n := float64(len(paddles))
+	avg.Specs.AverageWeight /= n
+	avg.Specs.Core /= n
+	avg.Specs.PaddleLength /= n
+	avg.Specs.PaddleWidth /= n
+	avg.Specs.GripLength /= n
+	avg.Specs.GripCircumference /= n
+
+	avg.Metadata.Brand = commonValue(brands)
+	avg.Specs.Shape = PaddleShape(commonValue(shapes))
+	avg.Specs.Surface = commonValue(surfaces)
+	avg.Specs.GripType = commonValue(gripTypes)
+
+	avg.Performance = averagePerformance(measurements).Performance
+	// Pooled measurement stddevs would read as the spread between paddles
+	for _, stddev := range avg.Performance.stddevs() {
+		*stddev = nil
+	}
+	avg.Control = (&Paddle{Performance: avg.Performance}).ControlRating()
+
+	return avg
+}
+
+// commonValue returns the value shared by every element, or "" if they differ
+func commonValue(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	for _, v := range values[1:] {
+		if v != values[0] {
+			return ""
+		}
+	}
+	return values[0]
+}
+
+// getAveragePaddle handles the API request for the average paddle of a filtered set
+func getAveragePaddle(w http.ResponseWriter, r *http.Request) {
+	filter, err := parsePaddleFilter(r.URL.Query())
+	if err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid filter: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	paddles, err := GetFilteredPaddles(filter)
+	if err != nil {
+		log.Printf("Error retrieving paddles for average: %v", err)
+		respondWithError(w, "Failed to compute average paddle", http.StatusInternalServerError)
+		return
+	}
+
+	avg := computeAverage(paddles)
+	if avg == nil {
+		respondWithError(w, "No paddles match the filter", http.StatusNotFound)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(avg); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestComputeAverage tests averaging specs and performance across paddles
+func TestComputeAverage(t *testing.T) {
+	paddles := []*Paddle{
+		{
+			Metadata:    Metadata{Brand: "Engage", Model: "Pursuit MX 6.0"},
+			Specs:       Specs{Shape: Elongated, Surface: "Raw Carbon", AverageWeight: 8.0, Core: 16, PaddleLength: 16.5, PaddleWidth: 7.5, GripLength: 5.5, GripType: "Cushion", GripCircumference: 4.25},
+			Performance: Performance{Power: 60, Pop: 40, Spin: 2000, TwistWeight: 6.0, SwingWeight: 110, BalancePoint: 23},
+		},
+		{
+			Metadata:    Metadata{Brand: "Engage", Model: "Pursuit EX 6.0"},
+			Specs:       Specs{Shape: Hybrid, Surface: "Raw Carbon", AverageWeight: 8.4, Core: 14, PaddleLength: 16.0, PaddleWidth: 7.9, GripLength: 5.0, GripType: "Cushion", GripCircumference: 4.25},
+			Performance: Performance{Power: 80, Pop: 60, Spin: 2400, TwistWeight: 7.0, SwingWeight: 120, BalancePoint: 24},
+		},
+	}
+
+	avg := computeAverage(paddles)
+	if avg == nil {
+		t.Fatal("computeAverage() returned nil for a non-empty set")
+	}
+
+	if avg.SampleSize != 2 {
+		t.Errorf("SampleSize = %d, want 2", avg.SampleSize)
+	}
+
+	floats := []struct {
+		name      string
+		got, want float64
+	}{
+		{"average_weight", avg.Specs.AverageWeight, 8.2},
+		{"core", avg.Specs.Core, 15},
+		{"paddle_length", avg.Specs.PaddleLength, 16.25},
+		{"paddle_width", avg.Specs.PaddleWidth, 7.7},
+		{"grip_length", avg.Specs.GripLength, 5.25},
+		{"power", avg.Performance.Power, 70},
+		{"pop", avg.Performance.Pop, 50},
+		{"spin", avg.Performance.Spin, 2200},
+		{"swing_weight", avg.Performance.SwingWeight, 115},
+		{"control", avg.Control, 38},
+	}
+	for _, f := range floats {
+		if math.Abs(f.got-f.want) > 1e-9 {
+			t.Errorf("%s = %v, want %v", f.name, f.got, f.want)
+		}
+	}
+
+	// Shared text fields are kept, differing ones are blank
+	if avg.Metadata.Brand != "Engage" || avg.Specs.Surface != "Raw Carbon" || avg.Specs.GripType != "Cushion" {
+		t.Errorf("Expected shared brand, surface and grip type to be kept, got %+v %+v", avg.Metadata, avg.Specs)
+	}
+	if avg.Specs.Shape != "" || avg.Metadata.Model != "" {
+		t.Errorf("Expected differing shape and model to be blank, got shape %q model %q", avg.Specs.Shape, avg.Metadata.Model)
+	}
+}
+
+// TestComputeAverageEmpty tests that an empty set has no average
+func TestComputeAverageEmpty(t *testing.T) {
+	if avg := computeAverage(nil); avg != nil {
+		t.Errorf("computeAverage(nil) = %+v, want nil", avg)
+	}
+}
+
+// TestGetAveragePaddleOnePerPaddle tests that a paddle measured several
+// times counts once, with its mean, in the average paddle
+func TestGetAveragePaddleOnePerPaddle(t *testing.T) {
+	setupTestDB(t)
+
+	retested := saveTestPaddle(t, testPaddleInput("Engage", "Pursuit MX 6.0"))
+	for _, power := range []float64{90, 90} {
+		if _, err := DB.Exec(`
+			INSERT INTO paddle_performance (paddle_spec_id, power, pop, spin, twist_weight, swing_weight, balance_point)
+			SELECT s.id, $2, 70, 3000, 200, 220, 30
+			FROM paddle_specs s JOIN paddles p ON p.id = s.paddle_id
+			WHERE p.paddle_id = $1
+		`, retested.ID, power); err != nil {
+			t.Fatalf("Failed to add a measurement: %v", err)
+		}
+	}
+
+	other := testPaddleInput("Selkirk", "Vanguard Power Air")
+	other.Performance.Power = 65
+	saveTestPaddle(t, other)
+
+	rr := httptest.NewRecorder()
+	getAveragePaddle(rr, httptest.NewRequest("GET", "/api/paddles/average", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	var avg AveragePaddle
+	if err := json.Unmarshal(rr.Body.Bytes(), &avg); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if avg.SampleSize != 2 {
+		t.Errorf("sample_size = %d, want 2 paddles", avg.SampleSize)
+	}
+	if want := (85.0 + 65) / 2; math.Abs(avg.Performance.Power-want) > 1e-9 {
+		t.Errorf("power = %v, want %v from the retested paddle's mean and the other paddle", avg.Performance.Power, want)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// BrandCount is the number of paddles listed under one brand
+type BrandCount struct {
+	Brand string `json:"brand"`
+	Count int    `json:"count"`
+}
+
+// GetBrandCounts counts published paddles per brand in alphabetical order
+func GetBrandCounts() ([]BrandCount, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	rows, err := timedQuery(ctx, DB, "get_brand_counts", `
+		SELECT brand, COUNT(*)
+		FROM paddles
+		WHERE status = 'published'
+		GROUP BY brand
+		ORDER BY brand
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []BrandCount{}
+	for rows.Next() {
+		var count BrandCount
+		if err := rows.Scan(&count.Brand, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+	return counts, rows.Err()
+}
+
+// getBrands handles the API request for the list of brands
+func getBrands(w http.ResponseWriter, r *http.Request) {
+	counts, err := store.GetBrandCounts()
+	if err != nil {
+		log.Printf("Error counting paddles by brand: %v", err)
+		respondWithError(w, "Failed to retrieve brands", http.StatusInternalServerError)
+		return
+	}
+
+	response := struct {
+		Brands []BrandCount `json:"brands"`
+	}{
+		Brands: counts,
+	}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding brands: %v", err)
+	}
+}
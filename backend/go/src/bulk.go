@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// maxBulkItems caps how many items a single bulk request may contain
+const maxBulkItems = 100
+
+// BulkItemResult is the outcome of one item in a bulk request
+type BulkItemResult struct {
+	Index  int    `json:"index"`
+	ID     string `json:"id,omitempty"`
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// ErrorCode is the validation error code, when the item failed validation
+	ErrorCode string `json:"error_code,omitempty"`
+	// Warnings are the sanity check warnings of an item that was saved
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// BulkResult collects per-item outcomes of a bulk request
+type BulkResult struct {
+	Results []BulkItemResult `json:"results"`
+}
+
+// Succeed records a successful item with any sanity check warnings
+func (b *BulkResult) Succeed(index int, id string, status int, warnings ...string) {
+	b.Results = append(b.Results, BulkItemResult{Index: index, ID: id, Status: status, Warnings: warnings})
+}
+
+// Fail records a failed item
+func (b *BulkResult) Fail(index int, id string, status int, err error) {
+	b.Results = append(b.Results, BulkItemResult{Index: index, ID: id, Status: status, Error: err.Error(), ErrorCode: validationCode(err)})
+}
+
+// StatusCode returns the overall response status: successStatus when every
+// item succeeded, 207 Multi-Status when only some did, and 400 (or 500 if any
+// item failed server-side) when none did
+func (b *BulkResult) StatusCode(successStatus int) int {
+	succeeded, serverErrors := 0, 0
+	for _, result := range b.Results {
+		switch {
+		case result.Status < http.StatusBadRequest:
+			succeeded++
+		case result.Status >= http.StatusInternalServerError:
+			serverErrors++
+		}
+	}
+
+	switch {
+	case succeeded == len(b.Results):
+		return successStatus
+	case succeeded > 0:
+		return http.StatusMultiStatus
+	case serverErrors > 0:
+		return http.StatusInternalServerError
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// respondWithBulkResult writes a bulk result with its overall status code
+func respondWithBulkResult(w http.ResponseWriter, result *BulkResult, successStatus int) {
+	w.WriteHeader(result.StatusCode(successStatus))
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Printf("Error encoding bulk result: %v", err)
+	}
+}
+
+// bulkUploadPaddles handles the API request for creating several paddles at once.
+// Each item is validated and saved independently.
+func bulkUploadPaddles(w http.ResponseWriter, r *http.Request) {
+	var inputs []PaddleInput
+	if err := decodeVersionedBody(r, &inputs); err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if len(inputs) == 0 {
+		respondWithError(w, "Request must contain at least one paddle", http.StatusBadRequest)
+		return
+	}
+	if len(inputs) > maxBulkItems {
+		respondWithError(w, fmt.Sprintf("Request may contain at most %d paddles", maxBulkItems), http.StatusBadRequest)
+		return
+	}
+
+	result := &BulkResult{}
+	for i := range inputs {
+		input := &inputs[i]
+		if err := validatePaddleInput(input, fullProfile); err != nil {
+			result.Fail(i, "", http.StatusBadRequest, fmt.Errorf("validation error: %w", err))
+			continue
+		}
+		warnings, err := checkPopPower(&input.Performance)
+		if err != nil {
+			result.Fail(i, "", http.StatusBadRequest, fmt.Errorf("validation error: %w", err))
+			continue
+		}
+
+		paddle := input.ToPaddle()
+		_, err = store.SavePaddle(paddle)
+		if errors.Is(err, ErrPaddleExists) {
+			result.Fail(i, paddle.ID, http.StatusConflict, fmt.Errorf("paddle with ID %s already exists", paddle.ID))
+			continue
+		}
+		if errors.Is(err, ErrQuotaExceeded) {
+			result.Fail(i, paddle.ID, http.StatusForbidden, errors.New(quotaExceededMessage()))
+			continue
+		}
+		if err != nil {
+			log.Printf("Error saving paddle %s in bulk upload: %v", paddle.ID, err)
+			result.Fail(i, paddle.ID, http.StatusInternalServerError, errors.New("failed to save paddle data"))
+			continue
+		}
+
+		result.Succeed(i, paddle.ID, http.StatusCreated, warnings...)
+	}
+
+	respondWithBulkResult(w, result, http.StatusCreated)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestBulkResultStatusCode tests the overall status for mixed item outcomes
+func TestBulkResultStatusCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		want     int
+	}{
+		{name: "All succeeded", statuses: []int{201, 201}, want: http.StatusCreated},
+		{name: "Some succeeded", statuses: []int{201, 400}, want: http.StatusMultiStatus},
+		{name: "All failed validation", statuses: []int{400, 409}, want: http.StatusBadRequest},
+		{name: "All failed with a server error", statuses: []int{400, 500}, want: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &BulkResult{}
+			for i, status := range tt.statuses {
+				if status < http.StatusBadRequest {
+					result.Succeed(i, "", status)
+				} else {
+					result.Fail(i, "", status, errors.New("failed"))
+				}
+			}
+			if got := result.StatusCode(http.StatusCreated); got != tt.want {
+				t.Errorf("StatusCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestBulkUploadPaddlesPartialFailure tests a bulk upload where half the items fail validation
+func TestBulkUploadPaddlesPartialFailure(t *testing.T) {
+	setupTestDB(t)
+
+	valid := func(model string) PaddleInput {
+		return *testPaddleInput("Engage", model)
+	}
+
+	inputs := []PaddleInput{
+		valid("Bulk Test A"),
+		valid(""), // missing model
+		valid("Bulk Test B"),
+		valid("Bulk Test C"),
+	}
+	inputs[3].Specs.Shape = "Round"
+
+	body, _ := json.Marshal(inputs)
+	req := httptest.NewRequest("POST", "/api/paddles/bulk", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+	bulkUploadPaddles(rr, req)
+
+	if rr.Code != http.StatusMultiStatus {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusMultiStatus, rr.Code, rr.Body.String())
+	}
+
+	var result BulkResult
+	if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+
+	wantStatuses := []int{http.StatusCreated, http.StatusBadRequest, http.StatusCreated, http.StatusBadRequest}
+	if len(result.Results) != len(wantStatuses) {
+		t.Fatalf("Expected %d results, got %d", len(wantStatuses), len(result.Results))
+	}
+	for i, want := range wantStatuses {
+		item := result.Results[i]
+		if item.Index != i || item.Status != want {
+			t.Errorf("Result %d = %+v, want index %d status %d", i, item, i, want)
+		}
+		if (item.Error != "") != (want != http.StatusCreated) {
+			t.Errorf("Result %d error = %q, unexpected for status %d", i, item.Error, want)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// BulkUpdate is the body of a bulk update. Only the brand can be changed
+// across many paddles at once, to fix misspellings.
+type BulkUpdate struct {
+	Brand string `json:"brand"`
+}
+
+// validate checks the new brand like the brand of an upload
+func (u *BulkUpdate) validate() error {
+	if strings.TrimSpace(u.Brand) == "" {
+		return newValidationError("BRAND_REQUIRED", "brand is required")
+	}
+	return validateText("brand", u.Brand)
+}
+
+// validatePaddle checks a paddle as it would be after the update with the
+// rules an upload must pass, registered hooks included. Stubs are checked
+// like stub uploads, so their missing specs and performance are allowed.
+func (u *BulkUpdate) validatePaddle(paddle *Paddle) error {
+	input := &PaddleInput{
+		Metadata:    paddle.Metadata,
+		Specs:       paddle.Specs,
+		Performance: paddle.Performance,
+	}
+	input.Metadata.Brand = u.Brand
+	if err := validatePaddleInput(input, stubProfile); err != nil {
+		return fmt.Errorf("paddle %s: %w", paddle.ID, err)
+	}
+	return nil
+}
+
+// IDChange is a paddle whose ID changed with its brand
+type IDChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// BulkUpdateResult reports what a bulk update changed. Paddles whose new ID
+// already belongs to another paddle are left untouched and listed as
+// collisions for an admin to resolve by hand.
+type BulkUpdateResult struct {
+	Matched    int        `json:"matched"`
+	Updated    int        `json:"updated"`
+	Renamed    []IDChange `json:"renamed"`
+	Collisions []IDChange `json:"collisions"`
+}
+
+// updatedPaddleID returns the ID a paddle should have after its metadata
+// changes. Only IDs derived from the brand and model follow the metadata.
+func updatedPaddleID(id string, metadata Metadata) string {
+	if _, derived := idGenerator.(brandModelIDGenerator); !derived {
+		return id
+	}
+	return idGenerator.GenerateID(metadata)
+}
+
+// BulkUpdatePaddles sets the brand of every paddle matching filter, drafts
+// and stubs included, and regenerates their IDs in one transaction. Every
+// matching paddle must pass validatePaddle first, or nothing is updated.
+// Paddles are updated in id order, so a paddle may take an ID another one
+// in the same batch has just given up; each is snapshotted to its history
+// before it changes.
+func BulkUpdatePaddles(filter paddleFilter, update BulkUpdate) (*BulkUpdateResult, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Stubs have no specs, so they are joined optionally
+	where, args := filter.where()
+	rows, err := timedQuery(ctx, tx, "select_paddles_for_bulk_update", `
+		SELECT p.id, p.paddle_id, p.status, p.brand, p.model, p.year, COALESCE(p.sku, ''), COALESCE(p.product_url, ''), p.price
+		FROM paddles p
+		LEFT JOIN paddle_specs s ON p.id = s.paddle_id
+		`+where+`
+		ORDER BY p.id
+		FOR UPDATE OF p
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	type match struct {
+		dbID     int
+		id       string
+		status   PaddleStatus
+		metadata Metadata
+	}
+	var matches []match
+	for rows.Next() {
+		var m match
+		err := rows.Scan(&m.dbID, &m.id, &m.status, &m.metadata.Brand, &m.metadata.Model, &m.metadata.Year,
+			&m.metadata.SKU, &m.metadata.ProductURL, &m.metadata.Price)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		matches = append(matches, m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, m := range matches {
+		paddle, err := scanFullPaddle(timedQueryRow(ctx, tx, "read_paddle_for_bulk_update", fullPaddleQuery+`
+			WHERE p.id = $1
+			ORDER BY perf.id
+			LIMIT 1
+		`, m.dbID))
+		if errors.Is(err, sql.ErrNoRows) {
+			// A stub has only its metadata
+			paddle = &Paddle{ID: m.id, Metadata: m.metadata}
+		} else if err != nil {
+			return nil, err
+		}
+		if err := update.validatePaddle(paddle); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &BulkUpdateResult{Matched: len(matches), Renamed: []IDChange{}, Collisions: []IDChange{}}
+	for _, m := range matches {
+		newID := updatedPaddleID(m.id, Metadata{Brand: update.Brand, Model: m.metadata.Model})
+		if newID != m.id {
+			var taken bool
+			err := timedQueryRow(ctx, tx, "check_bulk_update_collision",
+				"SELECT EXISTS (SELECT 1 FROM paddles WHERE LOWER(paddle_id) = LOWER($1) AND id <> $2)", newID, m.dbID,
+			).Scan(&taken)
+			if err != nil {
+				return nil, err
+			}
+			if taken {
+				result.Collisions = append(result.Collisions, IDChange{From: m.id, To: newID})
+				continue
+			}
+		}
+
+		if err := recordPaddleSnapshot(ctx, tx, m.id); err != nil {
+			return nil, err
+		}
+		_, err := timedExec(ctx, tx, "bulk_update_paddle", `
+			UPDATE paddles SET brand = $1, paddle_id = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3
+		`, update.Brand, newID, m.dbID)
+		if err != nil {
+			return nil, err
+		}
+		result.Updated++
+		if newID != m.id {
+			result.Renamed = append(result.Renamed, IDChange{From: m.id, To: newID})
+		}
+
+		// A renamed paddle is gone from its old ID and new at the other one.
+		// Drafts were never in the changes feed, so they need no tombstone.
+		eventType := eventPaddleUpdated
+		if newID != m.id {
+			if err := enqueueEvent(ctx, tx, eventPaddleDeleted, paddleDeletion{ID: m.id, ReplacedBy: newID}); err != nil {
+				return nil, err
+			}
+			if m.status == StatusPublished {
+				if err := recordTombstone(ctx, tx, m.id, newID); err != nil {
+					return nil, err
+				}
+			}
+			eventType = eventPaddleCreated
+		}
+		// Stubs without performance cannot be read back as a full paddle
+		if err := enqueueStoredPaddleEvent(ctx, tx, eventType, newID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// bulkUpdatePaddles handles the admin request for changing the brand of
+// every paddle matching the filter in the query, such as ?brand=Engaage
+func bulkUpdatePaddles(w http.ResponseWriter, r *http.Request) {
+	filter, err := parsePaddleFilter(r.URL.Query())
+	if err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid filter: %v", err), http.StatusBadRequest)
+		return
+	}
+	// Fixes apply to drafts too, but never to every paddle by accident
+	filter.Status = ""
+	if where, _ := filter.where(); where == "" {
+		respondWithError(w, "A filter is required: brand, shape, surface, tag or year", http.StatusBadRequest)
+		return
+	}
+
+	var update BulkUpdate
+	if err := decodeJSONBody(r.Body, &update); err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	if err := update.validate(); err != nil {
+		respondWithValidationError(w, r, err)
+		return
+	}
+
+	result, err := store.BulkUpdatePaddles(filter, update)
+	if validationCode(err) != "" {
+		respondWithValidationError(w, r, err)
+		return
+	}
+	if err != nil {
+		log.Printf("Error bulk updating paddles: %v", err)
+		respondWithError(w, "Failed to update paddles", http.StatusInternalServerError)
+		return
+	}
+	log.Printf("Bulk update set brand %q on %d of %d paddles, %d collisions", update.Brand, result.Updated, result.Matched, len(result.Collisions))
+
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Printf("Error encoding bulk update result: %v", err)
+	}
+}
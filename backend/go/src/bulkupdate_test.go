@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// TestBulkUpdatePaddles tests that renaming a brand updates the brand and the
+// derived IDs, reports collisions and leaves other brands alone
+func TestBulkUpdatePaddles(t *testing.T) {
+	setupTestStore(t)
+	apiKey = "test-key"
+	defer func() { apiKey = "" }()
+
+	for _, name := range [][2]string{
+		{"Engaage", "Pursuit MX 6.0"},
+		{"Engaage", "Pursuit Pro"},
+		{"Engage", "Pursuit Pro"},
+		{"Selkirk", "Vanguard"},
+	} {
+		saveTestPaddle(t, testPaddleInput(name[0], name[1]))
+	}
+
+	patch := func(query, body string, key string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest("PATCH", "/api/admin/paddles?"+query, bytes.NewBufferString(body))
+		req.Header.Set(apiKeyHeader, key)
+		rr := httptest.NewRecorder()
+		requireAPIKey(bulkUpdatePaddles)(rr, req)
+		return rr
+	}
+
+	if rr := patch("brand=Engaage", `{"brand": "Engage"}`, "wrong-key"); rr.Code != http.StatusUnauthorized {
+		t.Errorf("Wrong API key returned %d, want %d", rr.Code, http.StatusUnauthorized)
+	}
+	if rr := patch("", `{"brand": "Engage"}`, apiKey); rr.Code != http.StatusBadRequest {
+		t.Errorf("No filter returned %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+	if rr := patch("brand=Engaage", `{"brand": " "}`, apiKey); rr.Code != http.StatusBadRequest {
+		t.Errorf("Blank brand returned %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+
+	rr := patch("brand=Engaage", `{"brand": "Engage"}`, apiKey)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Bulk update returned %d: %s", rr.Code, rr.Body.String())
+	}
+	var result BulkUpdateResult
+	if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if result.Matched != 2 || result.Updated != 1 {
+		t.Errorf("matched %d, updated %d; want 2 and 1", result.Matched, result.Updated)
+	}
+	if want := []IDChange{{From: "engaage-pursuit-mx-6.0", To: "engage-pursuit-mx-6.0"}}; !slices.Equal(result.Renamed, want) {
+		t.Errorf("renamed = %v, want %v", result.Renamed, want)
+	}
+	if want := []IDChange{{From: "engaage-pursuit-pro", To: "engage-pursuit-pro"}}; !slices.Equal(result.Collisions, want) {
+		t.Errorf("collisions = %v, want %v", result.Collisions, want)
+	}
+
+	renamed, err := store.GetPaddleByID("engage-pursuit-mx-6.0")
+	if err != nil {
+		t.Fatalf("GetPaddleByID() of the new ID error: %v", err)
+	}
+	if renamed.Metadata.Brand != "Engage" {
+		t.Errorf("brand = %q, want Engage", renamed.Metadata.Brand)
+	}
+	if _, err := store.GetPaddleByID("engaage-pursuit-mx-6.0"); !errors.Is(err, ErrPaddleNotFound) {
+		t.Errorf("GetPaddleByID() of the old ID error = %v, want ErrPaddleNotFound", err)
+	}
+
+	// The colliding paddle keeps its misspelled brand, and other brands are untouched
+	if collided, err := store.GetPaddleByID("engaage-pursuit-pro"); err != nil || collided.Metadata.Brand != "Engaage" {
+		t.Errorf("colliding paddle = %v, %v; want it unchanged", collided, err)
+	}
+	if other, err := store.GetPaddleByID("selkirk-vanguard"); err != nil || other.Metadata.Brand != "Selkirk" {
+		t.Errorf("other brand = %v, %v; want it unchanged", other, err)
+	}
+}
+
+// TestBulkUpdateRunsValidationHooks tests that a bulk update is rejected,
+// leaving every paddle untouched, when a paddle with the new brand would
+// fail a registered validation hook
+func TestBulkUpdateRunsValidationHooks(t *testing.T) {
+	setupTestStore(t)
+	apiKey = "test-key"
+	defer func() { apiKey = "" }()
+
+	previous := validationHooks
+	defer func() { validationHooks = previous }()
+	validationHooks = nil
+	RegisterValidationHook(func(input *PaddleInput) error {
+		if input.Metadata.Brand == "Knockoff" && input.Metadata.Model == "Pursuit Pro" {
+			return errors.New("Knockoff Pursuit Pro is not accepted")
+		}
+		return nil
+	})
+
+	saveTestPaddle(t, testPaddleInput("Engaage", "Pursuit MX 6.0"))
+	saveTestPaddle(t, testPaddleInput("Engaage", "Pursuit Pro"))
+
+	req := httptest.NewRequest("PATCH", "/api/admin/paddles?brand=Engaage", bytes.NewBufferString(`{"brand": "Knockoff"}`))
+	req.Header.Set(apiKeyHeader, apiKey)
+	rr := httptest.NewRecorder()
+	requireAPIKey(bulkUpdatePaddles)(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("Bulk update failing a hook returned %d, want %d: %s", rr.Code, http.StatusBadRequest, rr.Body.String())
+	}
+	var body errorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil || body.ErrorCode != "CUSTOM_RULE_FAILED" {
+		t.Errorf("error = %+v, %v; want CUSTOM_RULE_FAILED", body, err)
+	}
+
+	for _, id := range []string{"engaage-pursuit-mx-6.0", "engaage-pursuit-pro"} {
+		if paddle, err := store.GetPaddleByID(id); err != nil || paddle.Metadata.Brand != "Engaage" {
+			t.Errorf("%s = %v, %v; want it unchanged", id, paddle, err)
+		}
+	}
+}
+
+// TestBulkUpdateSnapshotsHistory tests that the Postgres bulk update keeps the
+// paddle as it was before the rename in its history
+func TestBulkUpdateSnapshotsHistory(t *testing.T) {
+	setupTestDB(t)
+
+	saveTestPaddle(t, testPaddleInput("Engaage", "Pursuit MX 6.0"))
+	result, err := BulkUpdatePaddles(paddleFilter{Brand: "Engaage"}, BulkUpdate{Brand: "Engage"})
+	if err != nil || len(result.Renamed) != 1 {
+		t.Fatalf("BulkUpdatePaddles() = %+v, %v; want one rename", result, err)
+	}
+
+	snapshot, err := GetPaddleSnapshot(result.Renamed[0].To, 1)
+	if err != nil {
+		t.Fatalf("GetPaddleSnapshot() error: %v", err)
+	}
+	if snapshot.ID != result.Renamed[0].From || snapshot.Metadata.Brand != "Engaage" {
+		t.Errorf("snapshot = %s by %q, want %s by Engaage", snapshot.ID, snapshot.Metadata.Brand, result.Renamed[0].From)
+	}
+}
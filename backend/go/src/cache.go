@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// cachePolicy groups endpoints by how often their data changes
+type cachePolicy int
+
+const (
+	// cacheStatic is for rarely-changing data such as the schema and brands
+	cacheStatic cachePolicy = iota
+	// cacheList is for the paddle list, which changes with every upload
+	cacheList
+)
+
+// Default Cache-Control max-age in seconds, overridable via
+// STATIC_CACHE_MAX_AGE and LIST_CACHE_MAX_AGE
+const (
+	defaultStaticCacheMaxAge = 3600
+	defaultListCacheMaxAge   = 0
+)
+
+var cacheMaxAges = map[cachePolicy]int{
+	cacheStatic: defaultStaticCacheMaxAge,
+	cacheList:   defaultListCacheMaxAge,
+}
+
+// loadCacheConfig reads the cache lifetimes, in seconds, from the environment
+func loadCacheConfig() (map[cachePolicy]int, error) {
+	settings := []struct {
+		policy       cachePolicy
+		env          string
+		defaultValue int
+	}{
+		{cacheStatic, "STATIC_CACHE_MAX_AGE", defaultStaticCacheMaxAge},
+		{cacheList, "LIST_CACHE_MAX_AGE", defaultListCacheMaxAge},
+	}
+
+	maxAges := map[cachePolicy]int{}
+	for _, setting := range settings {
+		maxAge, err := strconv.Atoi(getEnv(setting.env, strconv.Itoa(setting.defaultValue)))
+		if err != nil || maxAge < 0 {
+			return nil, fmt.Errorf("%s must be a non-negative integer", setting.env)
+		}
+		maxAges[setting.policy] = maxAge
+	}
+	return maxAges, nil
+}
+
+// cacheControl returns the Cache-Control header value for a policy. A max-age
+// of 0 makes clients revalidate on every request.
+func cacheControl(policy cachePolicy) string {
+	maxAge := cacheMaxAges[policy]
+	if maxAge == 0 {
+		return "no-cache"
+	}
+	return fmt.Sprintf("public, max-age=%d", maxAge)
+}
+
+// withCacheControl sets the Cache-Control header of a policy on successful
+// responses. Error responses get no-store so a cache never keeps them.
+func withCacheControl(policy cachePolicy, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		next(&cacheControlWriter{ResponseWriter: w, value: cacheControl(policy)}, r)
+	}
+}
+
+// cacheControlWriter sets Cache-Control once the response status is known
+type cacheControlWriter struct {
+	http.ResponseWriter
+	value       string
+	wroteHeader bool
+}
+
+func (cw *cacheControlWriter) WriteHeader(status int) {
+	if !cw.wroteHeader {
+		cw.wroteHeader = true
+		if status < http.StatusMultipleChoices {
+			cw.Header().Set("Cache-Control", cw.value)
+		} else {
+			cw.Header().Set("Cache-Control", "no-store")
+		}
+	}
+	cw.ResponseWriter.WriteHeader(status)
+}
+
+func (cw *cacheControlWriter) Write(p []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	return cw.ResponseWriter.Write(p)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// TestBrandsCacheControl tests that the brands endpoint can be cached publicly
+// and lists each brand once with its paddle count
+func TestBrandsCacheControl(t *testing.T) {
+	setupTestStore(t)
+	for _, metadata := range []Metadata{
+		{Brand: "Selkirk", Model: "Vanguard"},
+		{Brand: "Engage", Model: "Pursuit MX 6.0"},
+		{Brand: "Engage", Model: "Pursuit EX 6.0"},
+	} {
+		paddle := &Paddle{ID: generatePaddleID(metadata.Brand, metadata.Model), Metadata: metadata}
+		if _, err := store.SavePaddle(paddle); err != nil {
+			t.Fatalf("Failed to save test paddle: %v", err)
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	withCacheControl(cacheStatic, getBrands)(rr, httptest.NewRequest("GET", "/api/brands", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	if got, want := rr.Header().Get("Cache-Control"), "public, max-age=3600"; got != want {
+		t.Errorf("Cache-Control = %q, want %q", got, want)
+	}
+
+	var response struct {
+		Brands []BrandCount `json:"brands"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	want := []BrandCount{{Brand: "Engage", Count: 2}, {Brand: "Selkirk", Count: 1}}
+	if !slices.Equal(response.Brands, want) {
+		t.Errorf("brands = %+v, want %+v", response.Brands, want)
+	}
+}
+
+// TestWithCacheControl tests the header for each policy and for error responses
+func TestWithCacheControl(t *testing.T) {
+	previous := cacheMaxAges
+	t.Cleanup(func() { cacheMaxAges = previous })
+
+	ok := func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("{}")) }
+	failing := func(w http.ResponseWriter, r *http.Request) {
+		respondWithError(w, "Failed to retrieve brands", http.StatusInternalServerError)
+	}
+
+	t.Setenv("STATIC_CACHE_MAX_AGE", "86400")
+	t.Setenv("LIST_CACHE_MAX_AGE", "")
+	maxAges, err := loadCacheConfig()
+	if err != nil {
+		t.Fatalf("loadCacheConfig() error: %v", err)
+	}
+	cacheMaxAges = maxAges
+
+	tests := []struct {
+		name    string
+		policy  cachePolicy
+		handler http.HandlerFunc
+		want    string
+	}{
+		{name: "Static data", policy: cacheStatic, handler: ok, want: "public, max-age=86400"},
+		{name: "Paddle list", policy: cacheList, handler: ok, want: "no-cache"},
+		{name: "Error response", policy: cacheStatic, handler: failing, want: "no-store"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			withCacheControl(tt.policy, tt.handler)(rr, httptest.NewRequest("GET", "/", nil))
+			if got := rr.Header().Get("Cache-Control"); got != tt.want {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Setenv("LIST_CACHE_MAX_AGE", "-5")
+	if _, err := loadCacheConfig(); err == nil {
+		t.Error("loadCacheConfig() with a negative max-age expected an error")
+	}
+}
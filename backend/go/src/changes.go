@@ -0,0 +1,196 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ChangesPage is one page of paddles created or updated after a cursor, and
+// of paddle IDs that have gone away, oldest change first. Passing NextSince
+// and NextAfter back as since and after continues from the last entry; once
+// HasMore is false they are where the next sync starts.
+type ChangesPage struct {
+	Changes    []PaddleResponse  `json:"changes"`
+	Tombstones []PaddleTombstone `json:"tombstones"`
+	NextSince  string            `json:"next_since"`
+	NextAfter  string            `json:"next_after"`
+	HasMore    bool              `json:"has_more"`
+}
+
+// PaddleTombstone marks a paddle ID that no longer exists, so synced clients
+// can drop it. Paddles cannot be deleted through the API, but a bulk update
+// that renames a published paddle leaves its old ID behind; ReplacedBy is
+// the ID the paddle moved to.
+type PaddleTombstone struct {
+	ID         string `json:"id"`
+	ReplacedBy string `json:"replaced_by,omitempty"`
+	DeletedAt  Time   `json:"deleted_at"`
+	Deleted    bool   `json:"deleted"`
+}
+
+// compareTombstones orders tombstones like the changes feed, by time then ID
+func compareTombstones(a, b PaddleTombstone) int {
+	return cmp.Or(a.DeletedAt.Compare(b.DeletedAt.Time), cmp.Compare(a.ID, b.ID))
+}
+
+// recordTombstone records that a paddle ID is gone within tx. An ID given up
+// more than once keeps only its latest tombstone.
+func recordTombstone(ctx context.Context, tx *sql.Tx, paddleId, replacedBy string) error {
+	_, err := timedExec(ctx, tx, "record_paddle_tombstone", `
+		INSERT INTO paddle_tombstones (paddle_id, replaced_by)
+		VALUES ($1, NULLIF($2, ''))
+		ON CONFLICT (paddle_id) DO UPDATE SET
+			replaced_by = EXCLUDED.replaced_by,
+			deleted_at = CURRENT_TIMESTAMP
+	`, paddleId, replacedBy)
+	return err
+}
+
+// GetPaddleTombstones returns up to limit tombstones recorded after since, or
+// at since with an ID after after, ordered like GetPaddleChanges
+func GetPaddleTombstones(since time.Time, after string, limit int) ([]PaddleTombstone, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	rows, err := timedQuery(ctx, DB, "get_paddle_tombstones", `
+		SELECT paddle_id, COALESCE(replaced_by, ''), deleted_at
+		FROM paddle_tombstones
+		WHERE deleted_at > $1 OR (deleted_at = $1 AND $2 <> '' AND paddle_id > $2)
+		ORDER BY deleted_at, paddle_id
+		LIMIT $3
+	`, since, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tombstones := []PaddleTombstone{}
+	for rows.Next() {
+		tombstone := PaddleTombstone{Deleted: true}
+		if err := rows.Scan(&tombstone.ID, &tombstone.ReplacedBy, &tombstone.DeletedAt); err != nil {
+			return nil, err
+		}
+		tombstones = append(tombstones, tombstone)
+	}
+	return tombstones, rows.Err()
+}
+
+// parseChangesCursor reads the required since timestamp, RFC3339 with
+// optional fractional seconds, and the optional after paddle ID that breaks
+// ties between paddles updated in the same instant
+func parseChangesCursor(query url.Values) (time.Time, string, error) {
+	raw := query.Get("since")
+	if raw == "" {
+		return time.Time{}, "", fmt.Errorf("since is required")
+	}
+	since, err := time.Parse(time.RFC3339Nano, raw)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("since must be an RFC3339 timestamp such as 2024-01-02T15:04:05Z")
+	}
+
+	after := query.Get("after")
+	if after != "" {
+		if err := validatePaddleID(after); err != nil {
+			return time.Time{}, "", fmt.Errorf("after must be a paddle ID: %w", err)
+		}
+	}
+	return since.UTC(), after, nil
+}
+
+// GetPaddleChanges returns up to limit published paddles updated after since,
+// or at since with an ID after after when after is given, ordered by update
+// time then ID, each once with its mean performance. Stubs are left out until
+// they are completed.
+func GetPaddleChanges(since time.Time, after string, limit int) ([]*Paddle, error) {
+	return queryPaddleMeans("get_paddle_changes", `
+		WHERE
+			(p.updated_at > $1 OR (p.updated_at = $1 AND $2 <> '' AND p.paddle_id > $2))
+			AND p.status = 'published'
+		ORDER BY
+			p.updated_at, p.paddle_id
+		LIMIT $3
+	`, since, after, limit)
+}
+
+// newChangedPaddle returns a paddle as the changes feed reports it
+func newChangedPaddle(paddle *Paddle) PaddleResponse {
+	paddle.convertUnits(metricUnits)
+	return newPaddleResponse(paddle, nil)
+}
+
+// getPaddleChanges handles the API request for paddles changed since a
+// cursor, for clients that sync incrementally
+func getPaddleChanges(w http.ResponseWriter, r *http.Request) {
+	since, after, err := parseChangesCursor(r.URL.Query())
+	if err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid cursor: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	limit, _, err := parsePagination(r)
+	if err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid pagination: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// One extra entry tells whether another page follows
+	paddles, err := store.GetPaddleChanges(since, after, limit+1)
+	if err != nil {
+		log.Printf("Error retrieving paddle changes: %v", err)
+		respondWithError(w, "Failed to retrieve paddle changes", http.StatusInternalServerError)
+		return
+	}
+	tombstones, err := store.GetPaddleTombstones(since, after, limit+1)
+	if err != nil {
+		log.Printf("Error retrieving paddle tombstones: %v", err)
+		respondWithError(w, "Failed to retrieve paddle changes", http.StatusInternalServerError)
+		return
+	}
+
+	page := ChangesPage{
+		Changes:    []PaddleResponse{},
+		Tombstones: []PaddleTombstone{},
+		NextSince:  since.Format(time.RFC3339Nano),
+		NextAfter:  after,
+	}
+
+	// Merge both lists by time then ID. On a tie the tombstone goes first,
+	// since a rename can hand an ID straight to another paddle, and the
+	// paddle is then kept on the same page because the cursor cannot split
+	// them.
+	var lastAt time.Time
+	var lastID string
+	i, j := 0, 0
+	for i+j < limit && (i < len(paddles) || j < len(tombstones)) {
+		if j < len(tombstones) && (i == len(paddles) || compareTombstones(tombstones[j], PaddleTombstone{ID: paddles[i].ID, DeletedAt: paddles[i].UpdatedAt}) <= 0) {
+			lastAt, lastID = tombstones[j].DeletedAt.Time, tombstones[j].ID
+			page.Tombstones = append(page.Tombstones, tombstones[j])
+			j++
+			if i < len(paddles) && paddles[i].ID == lastID && paddles[i].UpdatedAt.Equal(lastAt) {
+				page.Changes = append(page.Changes, newChangedPaddle(paddles[i]))
+				i++
+			}
+			continue
+		}
+		lastAt, lastID = paddles[i].UpdatedAt.Time, paddles[i].ID
+		page.Changes = append(page.Changes, newChangedPaddle(paddles[i]))
+		i++
+	}
+	page.HasMore = i < len(paddles) || j < len(tombstones)
+	if lastID != "" {
+		// The cursor keeps full precision, unlike the times in the response
+		page.NextSince = lastAt.UTC().Format(time.RFC3339Nano)
+		page.NextAfter = lastID
+	}
+
+	if err := json.NewEncoder(w).Encode(page); err != nil {
+		log.Printf("Error encoding paddle changes: %v", err)
+	}
+}
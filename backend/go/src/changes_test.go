@@ -0,0 +1,227 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// TestParseChangesCursor tests validating the since and after parameters
+func TestParseChangesCursor(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		wantSince time.Time
+		wantErr   bool
+	}{
+		{name: "UTC", query: "since=2024-01-02T15:04:05Z", wantSince: time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{name: "Offset", query: "since=2024-01-02T17:04:05%2B02:00", wantSince: time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{name: "Fractional", query: "since=2024-01-02T15:04:05.123456Z&after=engage-pursuit", wantSince: time.Date(2024, 1, 2, 15, 4, 5, 123456000, time.UTC)},
+		{name: "Missing", query: "", wantErr: true},
+		{name: "Date only", query: "since=2024-01-02", wantErr: true},
+		{name: "Epoch", query: "since=1704207845", wantErr: true},
+		{name: "Invalid after", query: "since=2024-01-02T15:04:05Z&after=-bad-", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, _ := url.ParseQuery(tt.query)
+			since, _, err := parseChangesCursor(query)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseChangesCursor(%q) accepted the cursor", tt.query)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseChangesCursor(%q) error: %v", tt.query, err)
+			}
+			if !since.Equal(tt.wantSince) || since.Location() != time.UTC {
+				t.Errorf("since = %v, want %v in UTC", since, tt.wantSince)
+			}
+		})
+	}
+}
+
+// TestGetPaddleChanges tests that only paddles changed after the cursor are
+// returned, oldest first, and that the cursor pages through ties
+func TestGetPaddleChanges(t *testing.T) {
+	setupTestStore(t)
+
+	clock := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	save := func(model string, at time.Time) string {
+		clock = at
+		paddle := saveTestPaddle(t, testPaddleInput("Engage", model))
+		return paddle.ID
+	}
+
+	t0 := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	old := save("Old", t0)
+	tiedB := save("Tied B", t0.Add(time.Hour))
+	tiedA := save("Tied A", t0.Add(time.Hour))
+
+	// Updating the old paddle later moves it to the end
+	clock = t0.Add(2 * time.Hour)
+	if err := store.UpdatePaddlePerformance(old, &Performance{Power: 80, Pop: 70, Spin: 3000, TwistWeight: 200, SwingWeight: 220, BalancePoint: 30}); err != nil {
+		t.Fatalf("UpdatePaddlePerformance() error: %v", err)
+	}
+	// Changed exactly at since, so not after it
+	save("Untouched", t0)
+
+	get := func(query string) ChangesPage {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		getPaddleChanges(rr, httptest.NewRequest("GET", "/api/paddles/changes?"+query, nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("GET ?%s returned %d: %s", query, rr.Code, rr.Body.String())
+		}
+		var page ChangesPage
+		if err := json.Unmarshal(rr.Body.Bytes(), &page); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return page
+	}
+	ids := func(page ChangesPage) []string {
+		got := []string{}
+		for _, change := range page.Changes {
+			got = append(got, change.PaddleID)
+		}
+		return got
+	}
+
+	page := get("since=" + url.QueryEscape(t0.Format(time.RFC3339)))
+	if got := ids(page); len(got) != 3 || got[0] != tiedA || got[1] != tiedB || got[2] != old {
+		t.Fatalf("changes = %v, want [%s %s %s]", got, tiedA, tiedB, old)
+	}
+	if page.HasMore || page.NextAfter != old || page.NextSince != t0.Add(2*time.Hour).Format(time.RFC3339Nano) {
+		t.Errorf("cursor = %q/%q, has_more %v; want the old paddle's update and no more", page.NextSince, page.NextAfter, page.HasMore)
+	}
+
+	// A page of one stops between the tied paddles, and the cursor resumes after it
+	page = get("limit=1&since=" + url.QueryEscape(t0.Format(time.RFC3339)))
+	if got := ids(page); len(got) != 1 || got[0] != tiedA || !page.HasMore {
+		t.Fatalf("first page = %v, has_more %v; want [%s] with more", got, page.HasMore, tiedA)
+	}
+	page = get("limit=1&since=" + url.QueryEscape(page.NextSince) + "&after=" + page.NextAfter)
+	if got := ids(page); len(got) != 1 || got[0] != tiedB {
+		t.Errorf("second page = %v, want [%s]", got, tiedB)
+	}
+
+	// Nothing changed after the last cursor
+	page = get("since=" + url.QueryEscape(t0.Add(2*time.Hour).Format(time.RFC3339Nano)) + "&after=" + old)
+	if len(page.Changes) != 0 || page.HasMore || page.NextAfter != old {
+		t.Errorf("changes after the last cursor = %v, want none with the cursor unchanged", ids(page))
+	}
+
+	rr := httptest.NewRecorder()
+	getPaddleChanges(rr, httptest.NewRequest("GET", "/api/paddles/changes?since=yesterday", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Invalid since returned %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+// TestPaddleChangesTombstones tests that a bulk rename leaves a tombstone for
+// the old ID, and that tag changes move a paddle into the feed
+func TestPaddleChangesTombstones(t *testing.T) {
+	setupTestStore(t)
+
+	clock := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	misspelled := saveTestPaddle(t, testPaddleInput("Engaage", "Pursuit")).ID
+	tagged := saveTestPaddle(t, testPaddleInput("Joola", "Perseus")).ID
+	t0 := clock
+
+	get := func(query string) ChangesPage {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		getPaddleChanges(rr, httptest.NewRequest("GET", "/api/paddles/changes?"+query, nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("GET ?%s returned %d: %s", query, rr.Code, rr.Body.String())
+		}
+		var page ChangesPage
+		if err := json.Unmarshal(rr.Body.Bytes(), &page); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return page
+	}
+	since := "since=" + url.QueryEscape(t0.Format(time.RFC3339))
+
+	clock = t0.Add(time.Hour)
+	result, err := store.BulkUpdatePaddles(paddleFilter{Brand: "Engaage"}, BulkUpdate{Brand: "Engage"})
+	if err != nil || len(result.Renamed) != 1 {
+		t.Fatalf("BulkUpdatePaddles() = %+v, %v; want one rename", result, err)
+	}
+	renamed := result.Renamed[0].To
+
+	page := get(since)
+	if len(page.Tombstones) != 1 || page.Tombstones[0].ID != misspelled || page.Tombstones[0].ReplacedBy != renamed || !page.Tombstones[0].Deleted {
+		t.Fatalf("tombstones = %+v, want %s replaced by %s", page.Tombstones, misspelled, renamed)
+	}
+	if len(page.Changes) != 1 || page.Changes[0].PaddleID != renamed {
+		t.Errorf("changes = %v, want only %s", page.Changes, renamed)
+	}
+
+	// A page of one still carries the paddle changed with the tombstone
+	clock = t0.Add(2 * time.Hour)
+	if _, err := store.AddTags(tagged, []string{"beginner-friendly"}); err != nil {
+		t.Fatalf("AddTags() error: %v", err)
+	}
+	page = get("limit=1&" + since)
+	if len(page.Tombstones) != 1 || len(page.Changes) != 0 || !page.HasMore {
+		t.Fatalf("first page = %d tombstones and %d changes, has_more %v; want the tombstone with more", len(page.Tombstones), len(page.Changes), page.HasMore)
+	}
+	page = get("limit=1&since=" + url.QueryEscape(page.NextSince) + "&after=" + page.NextAfter)
+	if len(page.Changes) != 1 || page.Changes[0].PaddleID != renamed || !page.HasMore {
+		t.Fatalf("second page = %+v, want %s with more", page.Changes, renamed)
+	}
+	page = get("limit=1&since=" + url.QueryEscape(page.NextSince) + "&after=" + page.NextAfter)
+	if len(page.Changes) != 1 || page.Changes[0].PaddleID != tagged || page.HasMore {
+		t.Errorf("third page = %+v, has_more %v; want the tagged paddle and no more", page.Changes, page.HasMore)
+	}
+
+	// Removing the tag changes the paddle again
+	clock = t0.Add(3 * time.Hour)
+	if _, err := store.RemoveTag(tagged, "beginner-friendly"); err != nil {
+		t.Fatalf("RemoveTag() error: %v", err)
+	}
+	page = get("since=" + url.QueryEscape(t0.Add(2*time.Hour).Format(time.RFC3339)))
+	if len(page.Changes) != 1 || page.Changes[0].PaddleID != tagged || len(page.Tombstones) != 0 {
+		t.Errorf("changes after removing the tag = %+v, want only %s", page.Changes, tagged)
+	}
+}
+
+// TestGetPaddleTombstones tests that a Postgres bulk rename records the old
+// ID, and that reading tombstones honors the cursor
+func TestGetPaddleTombstones(t *testing.T) {
+	setupTestDB(t)
+
+	paddle := testPaddleInput("Engaage", "Pursuit").ToPaddle()
+	if _, err := SavePaddle(paddle); err != nil {
+		t.Fatalf("Failed to save paddle: %v", err)
+	}
+	result, err := BulkUpdatePaddles(paddleFilter{Brand: "Engaage"}, BulkUpdate{Brand: "Engage"})
+	if err != nil || len(result.Renamed) != 1 {
+		t.Fatalf("BulkUpdatePaddles() = %+v, %v; want one rename", result, err)
+	}
+
+	tombstones, err := GetPaddleTombstones(time.Time{}, "", 10)
+	if err != nil {
+		t.Fatalf("GetPaddleTombstones() error: %v", err)
+	}
+	if len(tombstones) != 1 || tombstones[0].ID != paddle.ID || tombstones[0].ReplacedBy != result.Renamed[0].To {
+		t.Fatalf("tombstones = %+v, want %s replaced by %s", tombstones, paddle.ID, result.Renamed[0].To)
+	}
+
+	later, err := GetPaddleTombstones(tombstones[0].DeletedAt.Time, paddle.ID, 10)
+	if err != nil || len(later) != 0 {
+		t.Errorf("tombstones after the last one = %+v, %v; want none", later, err)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// CloneRequest holds the overrides applied when cloning a paddle. It is
+// pre-filled with the source paddle, so only the fields present in the
+// request body change.
+type CloneRequest struct {
+	ModelSuffix string      `json:"model_suffix"`
+	Metadata    Metadata    `json:"metadata"`
+	Specs       Specs       `json:"specs"`
+	Performance Performance `json:"performance"`
+}
+
+// buildCloneInput applies a clone request to the source paddle and returns the new paddle input
+func buildCloneInput(req *CloneRequest) *PaddleInput {
+	input := &PaddleInput{
+		Metadata:    req.Metadata,
+		Specs:       req.Specs,
+		Performance: req.Performance,
+	}
+
+	if suffix := strings.TrimSpace(req.ModelSuffix); suffix != "" {
+		input.Metadata.Model = strings.TrimSpace(input.Metadata.Model) + " " + suffix
+	}
+
+	return input
+}
+
+// clonePaddle handles the API request for creating a variant of an existing paddle
+func clonePaddle(w http.ResponseWriter, r *http.Request) {
+	paddleId := paddleIDFromRequest(r)
+
+	if err := validatePaddleID(paddleId); err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid paddle ID: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	source, err := store.GetPaddleByID(paddleId)
+	if err != nil {
+		log.Printf("Error retrieving paddle to clone: %v", err)
+		respondWithError(w, "Paddle not found", http.StatusNotFound)
+		return
+	}
+
+	// Decode the overrides on top of the source paddle
+	cloneReq := CloneRequest{
+		Metadata:    source.Metadata,
+		Specs:       source.Specs,
+		Performance: source.Performance,
+	}
+	if err := decodeJSONBody(r.Body, &cloneReq); err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	input := buildCloneInput(&cloneReq)
+	if err := validatePaddleInput(input, fullProfile); err != nil {
+		respondWithValidationError(w, r, err)
+		return
+	}
+	warnings, err := checkPopPower(&input.Performance)
+	if err != nil {
+		respondWithValidationError(w, r, err)
+		return
+	}
+
+	clone := input.ToPaddle()
+
+	// Reject clones that would collide with an existing paddle
+	exists, err := PaddleExists(clone.ID)
+	if err != nil {
+		log.Printf("Error checking for existing paddle: %v", err)
+		respondWithError(w, "Failed to clone paddle", http.StatusInternalServerError)
+		return
+	}
+	if exists {
+		respondWithError(w, fmt.Sprintf("Paddle with ID %s already exists; change the model or add a model_suffix", clone.ID), http.StatusConflict)
+		return
+	}
+
+	_, err = store.SavePaddle(clone)
+	if errors.Is(err, ErrPaddleExists) {
+		respondWithError(w, fmt.Sprintf("Paddle with ID %s already exists", clone.ID), http.StatusConflict)
+		return
+	}
+	if errors.Is(err, ErrQuotaExceeded) {
+		respondWithError(w, quotaExceededMessage(), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		log.Printf("Error saving cloned paddle: %v", err)
+		respondWithError(w, "Failed to clone paddle", http.StatusInternalServerError)
+		return
+	}
+
+	response := struct {
+		SourceID string `json:"source_id"` // Paddle the clone was created from
+		PaddleResponse
+	}{
+		SourceID:       source.ID,
+		PaddleResponse: newPaddleResponse(clone, warnings),
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding response: %v", err)
+		return
+	}
+}
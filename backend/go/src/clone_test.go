@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// TestBuildCloneInput tests applying a model suffix to a clone
+func TestBuildCloneInput(t *testing.T) {
+	req := &CloneRequest{
+		ModelSuffix: " Large Grip ",
+		Metadata:    Metadata{Brand: "Engage", Model: "Pursuit MX 6.0"},
+		Specs:       Specs{GripCircumference: 4.5},
+	}
+
+	input := buildCloneInput(req)
+	if input.Metadata.Model != "Pursuit MX 6.0 Large Grip" {
+		t.Errorf("Model = %q, want %q", input.Metadata.Model, "Pursuit MX 6.0 Large Grip")
+	}
+	if input.Specs.GripCircumference != 4.5 {
+		t.Errorf("GripCircumference = %v, want 4.5", input.Specs.GripCircumference)
+	}
+}
+
+// TestClonePaddle tests cloning a paddle with a grip override
+func TestClonePaddle(t *testing.T) {
+	setupTestDB(t)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/paddles/{id}/clone", clonePaddle).Methods("POST")
+
+	source := testPaddleInput("Engage", "Pursuit MX 6.0").ToPaddle()
+	if _, err := SavePaddle(source); err != nil {
+		t.Fatalf("Failed to save source paddle: %v", err)
+	}
+
+	body := []byte(`{"model_suffix": "4.25", "specs": {"grip_circumference": 4.25}}`)
+	req := httptest.NewRequest("POST", "/api/paddles/"+source.ID+"/clone", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Handler returned wrong status code: got %v want %v, body %s", rr.Code, http.StatusCreated, rr.Body.String())
+	}
+
+	var clone Paddle
+	if err := json.Unmarshal(rr.Body.Bytes(), &clone); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if clone.ID == source.ID {
+		t.Error("Clone should have a new ID")
+	}
+	if clone.Specs.GripCircumference != 4.25 {
+		t.Errorf("GripCircumference = %v, want 4.25", clone.Specs.GripCircumference)
+	}
+	if clone.Specs.AverageWeight != source.Specs.AverageWeight || clone.Performance != source.Performance {
+		t.Error("Clone should keep the source's other specs and performance")
+	}
+
+	// Cloning again with the same suffix collides with the first clone
+	req = httptest.NewRequest("POST", "/api/paddles/"+source.ID+"/clone", bytes.NewBuffer(body))
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Handler returned wrong status code for colliding clone: got %v want %v", rr.Code, http.StatusConflict)
+	}
+}
+
+// TestClonePaddlePopPowerCheck tests that clones go through the pop/power
+// check like uploads
+func TestClonePaddlePopPowerCheck(t *testing.T) {
+	setupTestDB(t)
+	defer func() { popPowerCheck, popPowerMaxGap = popPowerOff, defaultPopPowerMaxGap }()
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/paddles/{id}/clone", clonePaddle).Methods("POST")
+
+	source := testPaddleInput("Engage", "Pursuit MX 6.0").ToPaddle()
+	if _, err := SavePaddle(source); err != nil {
+		t.Fatalf("Failed to save source paddle: %v", err)
+	}
+	clone := func() *httptest.ResponseRecorder {
+		body := bytes.NewBufferString(`{"model_suffix": "Gapped", "performance": {"pop": 20}}`)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/paddles/"+source.ID+"/clone", body))
+		return rr
+	}
+
+	popPowerCheck = popPowerReject
+	if rr := clone(); rr.Code != http.StatusBadRequest {
+		t.Errorf("clone in reject mode returned %d, want %d: %s", rr.Code, http.StatusBadRequest, rr.Body.String())
+	}
+
+	popPowerCheck = popPowerWarn
+	rr := clone()
+	var created struct {
+		Warnings []string `json:"warnings"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &created); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if rr.Code != http.StatusCreated || len(created.Warnings) != 1 {
+		t.Errorf("clone in warn mode returned %d with warnings %v, want 201 with one warning", rr.Code, created.Warnings)
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/lib/pq"
+)
+
+// CompareRow is one paddle in the comparison table: its display name, the
+// specs and the mean performance the grid shows, and nothing else
+type CompareRow struct {
+	ID                string      `json:"id"`
+	Name              string      `json:"name"`
+	Shape             PaddleShape `json:"shape"`
+	Surface           string      `json:"surface"`
+	AverageWeight     float64     `json:"average_weight"`
+	Core              float64     `json:"core"`
+	CoreUnit          string      `json:"core_unit"`
+	PaddleLength      float64     `json:"paddle_length"`
+	PaddleWidth       float64     `json:"paddle_width"`
+	GripLength        float64     `json:"grip_length"`
+	GripCircumference float64     `json:"grip_circumference"`
+	Power             float64     `json:"power"`
+	Pop               float64     `json:"pop"`
+	Spin              float64     `json:"spin"`
+	TwistWeight       float64     `json:"twist_weight"`
+	SwingWeight       float64     `json:"swing_weight"`
+	BalancePoint      float64     `json:"balance_point"`
+	Control           float64     `json:"control"`
+}
+
+// newCompareRow picks the comparison fields out of a paddle
+func newCompareRow(paddle *Paddle) CompareRow {
+	return CompareRow{
+		ID:                paddle.ID,
+		Name:              paddle.Metadata.Brand + " " + paddle.Metadata.Model,
+		Shape:             paddle.Specs.Shape,
+		Surface:           paddle.Specs.Surface,
+		AverageWeight:     paddle.Specs.AverageWeight,
+		Core:              paddle.Specs.Core,
+		PaddleLength:      paddle.Specs.PaddleLength,
+		PaddleWidth:       paddle.Specs.PaddleWidth,
+		GripLength:        paddle.Specs.GripLength,
+		GripCircumference: paddle.Specs.GripCircumference,
+		Power:             paddle.Performance.Power,
+		Pop:               paddle.Performance.Pop,
+		Spin:              paddle.Performance.Spin,
+		TwistWeight:       paddle.Performance.TwistWeight,
+		SwingWeight:       paddle.Performance.SwingWeight,
+		BalancePoint:      paddle.Performance.BalancePoint,
+		Control:           paddle.ControlRating(),
+	}
+}
+
+// convertUnits rewrites the row's core thickness into the requested unit
+// system, like the core of a paddle response
+func (row *CompareRow) convertUnits(units unitSystem) {
+	specs := Specs{Core: row.Core}
+	specs.convertUnits(units)
+	row.Core, row.CoreUnit = specs.Core, specs.CoreUnit
+}
+
+// GetCompareRows returns the comparison row of each requested paddle in one
+// query, reading only the columns the table shows. Performance is the mean
+// across measurements, like the details endpoint. Unknown IDs are left out.
+func GetCompareRows(paddleIds []string) (map[string]CompareRow, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	rows, err := timedQuery(ctx, DB, "get_compare_rows", `
+		SELECT
+			p.paddle_id, p.brand, p.model,
+			s.shape, s.surface, s.average_weight, s.core, s.paddle_length,
+			s.paddle_width, s.grip_length, s.grip_circumference,
+			AVG(perf.power), AVG(perf.pop), AVG(perf.spin),
+			AVG(perf.twist_weight), AVG(perf.swing_weight), AVG(perf.balance_point)
+		FROM
+			paddles p
+		JOIN
+			paddle_specs s ON p.id = s.paddle_id
+		JOIN
+			paddle_performance perf ON s.id = perf.paddle_spec_id
+		WHERE
+			p.paddle_id = ANY($1)
+		GROUP BY
+			p.id, s.id
+	`, pq.Array(paddleIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	compareRows := map[string]CompareRow{}
+	for rows.Next() {
+		paddle := &Paddle{}
+		err := rows.Scan(
+			&paddle.ID, &paddle.Metadata.Brand, &paddle.Metadata.Model,
+			&paddle.Specs.Shape, &paddle.Specs.Surface, &paddle.Specs.AverageWeight, &paddle.Specs.Core,
+			&paddle.Specs.PaddleLength, &paddle.Specs.PaddleWidth, &paddle.Specs.GripLength, &paddle.Specs.GripCircumference,
+			&paddle.Performance.Power, &paddle.Performance.Pop, &paddle.Performance.Spin,
+			&paddle.Performance.TwistWeight, &paddle.Performance.SwingWeight, &paddle.Performance.BalancePoint,
+		)
+		if err != nil {
+			return nil, err
+		}
+		roundPerformance(&paddle.Performance)
+		compareRows[paddle.ID] = newCompareRow(paddle)
+	}
+	return compareRows, rows.Err()
+}
+
+// CompareTable is the comparison table for a set of paddles. Rows are in the
+// order the IDs were requested; IDs without a paddle are listed in NotFound.
+type CompareTable struct {
+	Rows     []CompareRow `json:"rows"`
+	NotFound []string     `json:"not_found"`
+}
+
+// getCompareFields handles the API request for the comparison table of many paddles
+func getCompareFields(w http.ResponseWriter, r *http.Request) {
+	ids, err := parsePerformanceIDs(r.URL.Query()["ids"])
+	if err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid ids: %v", err), http.StatusBadRequest)
+		return
+	}
+	units, err := parseUnits(r.URL.Query().Get("units"))
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	compareRows, err := store.GetCompareRows(ids)
+	if err != nil {
+		log.Printf("Error retrieving comparison rows: %v", err)
+		respondWithError(w, "Failed to retrieve comparison table", http.StatusInternalServerError)
+		return
+	}
+
+	table := CompareTable{Rows: []CompareRow{}, NotFound: []string{}}
+	for _, id := range ids {
+		if row, ok := compareRows[id]; ok {
+			row.convertUnits(units)
+			table.Rows = append(table.Rows, row)
+		} else {
+			table.NotFound = append(table.NotFound, id)
+		}
+	}
+
+	if err := json.NewEncoder(w).Encode(table); err != nil {
+		log.Printf("Error encoding comparison table: %v", err)
+	}
+}
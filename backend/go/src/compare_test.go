@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// TestGetCompareFields tests that the comparison table keeps the request
+// order, lists unknown IDs and carries only the comparison fields
+func TestGetCompareFields(t *testing.T) {
+	setupTestStore(t)
+
+	price := 199.99
+	for _, model := range []string{"Pursuit MX 6.0", "Pursuit Pro"} {
+		paddle := (&PaddleInput{
+			Metadata: Metadata{Brand: "Engage", Model: model, SKU: "EN-1", Price: &price},
+			Specs: Specs{
+				Shape: Hybrid, Surface: "Composite", AverageWeight: 220.0, Core: 15.0, PaddleLength: 16.5,
+				PaddleWidth: 7.5, GripLength: 4.5, GripType: "Comfort", GripCircumference: 4.0, EdgeGuard: "Edgeless",
+			},
+			Performance: testPerformance,
+		}).ToPaddle()
+		if _, err := store.SavePaddle(paddle); err != nil {
+			t.Fatalf("SavePaddle() error: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/api/paddles/compare-fields?ids=engage-pursuit-pro,missing-paddle,engage-pursuit-mx-6.0", nil)
+	rr := httptest.NewRecorder()
+	getCompareFields(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	var table CompareTable
+	if err := json.Unmarshal(rr.Body.Bytes(), &table); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(table.Rows) != 2 || table.Rows[0].ID != "engage-pursuit-pro" || table.Rows[1].ID != "engage-pursuit-mx-6.0" {
+		t.Fatalf("rows = %+v, want engage-pursuit-pro then engage-pursuit-mx-6.0", table.Rows)
+	}
+	if table.Rows[0].Name != "Engage Pursuit Pro" || table.Rows[0].Power != 75.0 || table.Rows[0].Control != 27.0 {
+		t.Errorf("row = %+v, want name Engage Pursuit Pro, power 75 and control 27", table.Rows[0])
+	}
+	if !slices.Equal(table.NotFound, []string{"missing-paddle"}) {
+		t.Errorf("not_found = %v, want [missing-paddle]", table.NotFound)
+	}
+
+	// The grid only needs these fields; metadata, grip type, construction
+	// details, stddevs and timestamps are left out
+	want := []string{
+		"id", "name", "shape", "surface", "average_weight", "core", "core_unit", "paddle_length", "paddle_width",
+		"grip_length", "grip_circumference", "power", "pop", "spin", "twist_weight", "swing_weight",
+		"balance_point", "control",
+	}
+	var raw struct {
+		Rows []map[string]json.RawMessage `json:"rows"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &raw); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	for _, row := range raw.Rows {
+		for field := range row {
+			if !slices.Contains(want, field) {
+				t.Errorf("row contains unused field %q", field)
+			}
+		}
+		if len(row) != len(want) {
+			t.Errorf("row has %d fields, want %d", len(row), len(want))
+		}
+	}
+
+	// units=imperial gives the core in inches, like the details endpoint
+	rr = httptest.NewRecorder()
+	getCompareFields(rr, httptest.NewRequest("GET", "/api/paddles/compare-fields?ids=engage-pursuit-pro&units=imperial", nil))
+	table = CompareTable{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &table); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(table.Rows) != 1 || table.Rows[0].Core != mmToInches(15.0) || table.Rows[0].CoreUnit != coreUnitInch {
+		t.Errorf("imperial rows = %+v, want core %v in", table.Rows, mmToInches(15.0))
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// defaultMaxConcurrentRequests is how many requests may be processed at once,
+// overridable via MAX_CONCURRENT_REQUESTS. 0 turns the limiter off.
+const defaultMaxConcurrentRequests = 100
+
+// concurrencyRetryAfter is the Retry-After, in seconds, sent with a 503 when
+// the limiter is full. Requests are short, so a slot frees up quickly.
+const concurrencyRetryAfter = 1
+
+// loadConcurrencyLimit reads the concurrent request limit from the environment
+func loadConcurrencyLimit() (int, error) {
+	limit, err := strconv.Atoi(getEnv("MAX_CONCURRENT_REQUESTS", strconv.Itoa(defaultMaxConcurrentRequests)))
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("MAX_CONCURRENT_REQUESTS must be a non-negative integer")
+	}
+	return limit, nil
+}
+
+// concurrencyLimiter caps how many requests are processed at once with a
+// semaphore of buffered channel slots, so a burst of traffic cannot open more
+// database work than the pool can take
+type concurrencyLimiter struct {
+	slots chan struct{}
+}
+
+// newConcurrencyLimiter returns a limiter allowing limit concurrent requests
+func newConcurrencyLimiter(limit int) *concurrencyLimiter {
+	return &concurrencyLimiter{slots: make(chan struct{}, limit)}
+}
+
+// middleware takes a slot for each request, refusing it with 503 and a
+// Retry-After when none are free rather than queueing it. Probes are always
+// served, so a busy instance is not restarted as unhealthy.
+func (l *concurrencyLimiter) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if probePaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		select {
+		case l.slots <- struct{}{}:
+			defer func() { <-l.slots }()
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Retry-After", strconv.Itoa(concurrencyRetryAfter))
+			respondWithError(w, "Server is busy, try again shortly", http.StatusServiceUnavailable)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
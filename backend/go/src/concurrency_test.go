@@ -0,0 +1,60 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// TestConcurrencyLimiter tests that a request arriving while every slot is
+// taken gets 503 with Retry-After, and that slots free up afterwards
+func TestConcurrencyLimiter(t *testing.T) {
+	limiter := newConcurrencyLimiter(2)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := limiter.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/paddles/slow" {
+			started <- struct{}{}
+			<-release
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// Saturate the limiter with requests that block until released
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/paddles/slow", nil))
+		}()
+		<-started
+	}
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/api/paddles", nil))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("Overflow request returned %d, want %d", rr.Code, http.StatusServiceUnavailable)
+	}
+	if got := rr.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("Retry-After = %q, want 1", got)
+	}
+
+	// Probes are served even when the limiter is full
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/readyz", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("Probe returned %d while saturated, want %d", rr.Code, http.StatusOK)
+	}
+
+	close(release)
+	wg.Wait()
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/api/paddles", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("Request after the slots freed returned %d, want %d", rr.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DBConfig holds the Postgres connection settings
+type DBConfig struct {
+	Host            string
+	Port            string
+	User            string
+	Password        string
+	Name            string
+	ApplicationName string
+}
+
+// Config is the server configuration, read from the environment once at
+// startup. Loading it has no side effects; apply installs it.
+type Config struct {
+	DB     DBConfig
+	APIKey string
+	CORS   corsConfig
+
+	Pagination    paginationConfig
+	DefaultSort   listSort
+	Query         queryConfig
+	Precision     precisionConfig
+	PopPower      popPowerConfig
+	Duplicates    duplicateConfig
+	BodyLogging   bodyLoggingConfig
+	RequestLimits requestLimitConfig
+	HealthCheck   healthCheckConfig
+	JSON          jsonConfig
+	TimeFormat    string
+	CacheMaxAges  map[cachePolicy]int
+	MaxPaddles    int
+	IDGenerator   IDGenerator
+	Features      featureSet
+	ResetAllowed  bool
+
+	// Only used by main to start the limiter and background goroutines
+	MaxConcurrentRequests  int
+	OutboxPollInterval     time.Duration
+	OutboxRetention        time.Duration
+	StatsRefreshInterval   time.Duration
+	CountReconcileInterval time.Duration
+	PoolStatsInterval      time.Duration
+}
+
+// loadSection runs one settings loader, recording its error under the
+// section's name so that LoadConfig can report every invalid section at once
+func loadSection[T any](errs *[]error, name string, load func() (T, error)) T {
+	value, err := load()
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("invalid %s configuration: %w", name, err))
+	}
+	return value
+}
+
+// LoadConfig reads every environment variable the server uses. Invalid
+// values are all reported together, so one restart is enough to fix them.
+func LoadConfig() (*Config, error) {
+	var errs []error
+	cfg := &Config{
+		DB:     loadSection(&errs, "database", loadDBConfig),
+		APIKey: getEnv("API_KEY", ""),
+		CORS:   loadSection(&errs, "CORS", loadCORSConfig),
+
+		Pagination:    loadSection(&errs, "pagination", loadPaginationConfig),
+		DefaultSort:   loadSection(&errs, "sort", loadDefaultListSort),
+		Query:         loadSection(&errs, "query", loadQueryConfig),
+		Precision:     loadSection(&errs, "precision", loadPrecisionConfig),
+		PopPower:      loadSection(&errs, "pop/power check", loadPopPowerConfig),
+		Duplicates:    loadSection(&errs, "duplicate detection", loadDuplicateConfig),
+		BodyLogging:   loadSection(&errs, "body logging", loadBodyLoggingConfig),
+		RequestLimits: loadSection(&errs, "request limit", loadRequestLimitConfig),
+		HealthCheck:   loadSection(&errs, "health check", loadHealthCheckConfig),
+		JSON:          loadSection(&errs, "JSON strictness", loadJSONConfig),
+		TimeFormat:    loadSection(&errs, "time format", loadTimeFormat),
+		CacheMaxAges:  loadSection(&errs, "cache", loadCacheConfig),
+		MaxPaddles:    loadSection(&errs, "quota", loadMaxPaddles),
+		IDGenerator:   loadSection(&errs, "ID", loadIDGenerator),
+		Features:      loadSection(&errs, "feature", loadFeatures),
+		ResetAllowed:  loadSection(&errs, "reset", loadResetAllowed),
+
+		MaxConcurrentRequests:  loadSection(&errs, "concurrency limit", loadConcurrencyLimit),
+		OutboxPollInterval:     loadSection(&errs, "outbox", loadOutboxPollInterval),
+		OutboxRetention:        loadSection(&errs, "outbox retention", loadOutboxRetention),
+		StatsRefreshInterval:   loadSection(&errs, "dataset stats", loadStatsRefreshInterval),
+		CountReconcileInterval: loadSection(&errs, "paddle counter", loadCountReconcileInterval),
+		PoolStatsInterval:      loadSection(&errs, "pool monitor", loadPoolStatsInterval),
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return cfg, nil
+}
+
+// apply installs the settings read by handlers and middleware. main calls it
+// once, after LoadConfig succeeds and before the database is initialized.
+func (cfg *Config) apply() {
+	apiKey = cfg.APIKey
+	defaultPageSize, maxPageSize = cfg.Pagination.DefaultPageSize, cfg.Pagination.MaxPageSize
+	defaultListSort = cfg.DefaultSort
+	slowQueryThreshold, queryTimeout = cfg.Query.SlowQueryThreshold, cfg.Query.Timeout
+	floatPrecision, spinPrecision = cfg.Precision.Float, cfg.Precision.Spin
+	popPowerCheck, popPowerMaxGap = cfg.PopPower.Mode, cfg.PopPower.MaxGap
+	duplicateThreshold, duplicateSpecTolerance = cfg.Duplicates.Threshold, cfg.Duplicates.SpecTolerance
+	logBodies, logBodiesMaxBytes = cfg.BodyLogging.Enabled, cfg.BodyLogging.MaxBytes
+	maxQueryParams, maxHeaderBytes = cfg.RequestLimits.MaxQueryParams, cfg.RequestLimits.MaxHeaderBytes
+	healthPingTimeout, healthPingRetries = cfg.HealthCheck.PingTimeout, cfg.HealthCheck.PingRetries
+	jsonMaxDepth = cfg.JSON.MaxDepth
+	jsonAllowDuplicateKeys = cfg.JSON.AllowDuplicateKeys
+	jsonNumericStrings = cfg.JSON.NumericStrings
+	timeFormat = cfg.TimeFormat
+	cacheMaxAges = cfg.CacheMaxAges
+	maxPaddles = cfg.MaxPaddles
+	idGenerator = cfg.IDGenerator
+	enabledFeatures = cfg.Features
+	resetAllowed = cfg.ResetAllowed
+
+	log.Printf("Enabled features: [%s]", strings.Join(enabledFeatures.names(), ", "))
+	if resetAllowed {
+		log.Printf("Data reset is enabled at POST /api/admin/reset")
+	}
+}
+
+// loadDBConfig reads the DB_* variables, or defaults for development
+func loadDBConfig() (DBConfig, error) {
+	db := DBConfig{
+		Host:            getEnv("DB_HOST", "localhost"),
+		Port:            getEnv("DB_PORT", "5432"),
+		User:            getEnv("DB_USER", "postgres"),
+		Password:        getEnv("DB_PASSWORD", "postgres"),
+		Name:            getEnv("DB_NAME", "pickleball_db"),
+		ApplicationName: getEnv("DB_APPLICATION_NAME", defaultApplicationName),
+	}
+	if port, err := strconv.Atoi(db.Port); err != nil || port < 1 || port > 65535 {
+		return DBConfig{}, fmt.Errorf("DB_PORT must be a port number between 1 and 65535")
+	}
+	return db, nil
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// restoreDefaultConfig applies the default configuration when the test ends,
+// after the variables it set are restored
+func restoreDefaultConfig(t *testing.T) {
+	t.Cleanup(func() {
+		cfg, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("LoadConfig() with the default environment error: %v", err)
+		}
+		cfg.apply()
+	})
+}
+
+// TestLoadConfig tests reading the database settings, API key and the
+// settings used by other files, and that apply installs them
+func TestLoadConfig(t *testing.T) {
+	restoreDefaultConfig(t)
+
+	t.Setenv("DB_HOST", "db.internal")
+	t.Setenv("DB_PORT", "6543")
+	t.Setenv("DB_NAME", "pickleball_test")
+	t.Setenv("API_KEY", "secret")
+	t.Setenv("DEFAULT_PAGE_SIZE", "5")
+	t.Setenv("ID_STRATEGY", "uuid")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error: %v", err)
+	}
+
+	want := DBConfig{
+		Host: "db.internal", Port: "6543", User: "postgres", Password: "postgres",
+		Name: "pickleball_test", ApplicationName: defaultApplicationName,
+	}
+	if cfg.DB != want {
+		t.Errorf("DB = %+v, want %+v", cfg.DB, want)
+	}
+	if cfg.APIKey != "secret" {
+		t.Errorf("APIKey = %q, want secret", cfg.APIKey)
+	}
+	if cfg.Pagination.DefaultPageSize != 5 {
+		t.Errorf("Pagination.DefaultPageSize = %d, want 5", cfg.Pagination.DefaultPageSize)
+	}
+	if _, ok := cfg.IDGenerator.(uuidIDGenerator); !ok {
+		t.Errorf("IDGenerator = %T, want uuidIDGenerator", cfg.IDGenerator)
+	}
+	if defaultPageSize == 5 {
+		t.Error("LoadConfig() changed defaultPageSize before apply")
+	}
+
+	cfg.apply()
+	if apiKey != "secret" {
+		t.Errorf("apiKey = %q after apply, want secret", apiKey)
+	}
+	if defaultPageSize != 5 {
+		t.Errorf("defaultPageSize = %d after apply, want 5", defaultPageSize)
+	}
+	if _, ok := idGenerator.(uuidIDGenerator); !ok {
+		t.Errorf("idGenerator = %T after apply, want uuidIDGenerator", idGenerator)
+	}
+}
+
+// TestLoadConfigInvalid tests that every invalid setting is reported at once,
+// without changing any of the settings in use
+func TestLoadConfigInvalid(t *testing.T) {
+	restoreDefaultConfig(t)
+
+	t.Setenv("DB_PORT", "postgres")
+	t.Setenv("MAX_PAGE_SIZE", "lots")
+	t.Setenv("TIME_FORMAT", "iso")
+	t.Setenv("CORS_MAX_AGE", "forever")
+	t.Setenv("DEFAULT_PAGE_SIZE", "5")
+	t.Setenv("ID_STRATEGY", "uuid")
+
+	cfg, err := LoadConfig()
+	if err == nil {
+		t.Fatalf("LoadConfig() = %+v, want an error", cfg)
+	}
+	for _, want := range []string{"database configuration", "pagination configuration", "time format configuration", "CORS configuration"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("LoadConfig() error = %q, want it to mention the invalid %s", err, want)
+		}
+	}
+	if defaultPageSize == 5 {
+		t.Error("LoadConfig() with invalid settings changed defaultPageSize")
+	}
+	if _, ok := idGenerator.(uuidIDGenerator); ok {
+		t.Error("LoadConfig() with invalid settings changed idGenerator")
+	}
+}
+
+// TestLoadDBConfigPort tests validating DB_PORT
+func TestLoadDBConfigPort(t *testing.T) {
+	tests := []struct {
+		port    string
+		wantErr bool
+	}{
+		{port: "", wantErr: false},
+		{port: "5433", wantErr: false},
+		{port: "0", wantErr: true},
+		{port: "70000", wantErr: true},
+		{port: "five", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.port, func(t *testing.T) {
+			t.Setenv("DB_PORT", tt.port)
+			if _, err := loadDBConfig(); (err != nil) != tt.wantErr {
+				t.Errorf("loadDBConfig() with DB_PORT=%q error = %v, wantErr %v", tt.port, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestLoadOutboxRetention tests validating OUTBOX_RETENTION_HOURS
+func TestLoadOutboxRetention(t *testing.T) {
+	t.Setenv("OUTBOX_RETENTION_HOURS", "")
+	if got, err := loadOutboxRetention(); err != nil || got != defaultOutboxRetentionHours*time.Hour {
+		t.Errorf("loadOutboxRetention() default = %s, %v, want %d hours", got, err, defaultOutboxRetentionHours)
+	}
+
+	t.Setenv("OUTBOX_RETENTION_HOURS", "48")
+	if got, err := loadOutboxRetention(); err != nil || got != 48*time.Hour {
+		t.Errorf("loadOutboxRetention() = %s, %v, want 48h", got, err)
+	}
+
+	for _, raw := range []string{"0", "-1", "week"} {
+		t.Setenv("OUTBOX_RETENTION_HOURS", raw)
+		if _, err := loadOutboxRetention(); err == nil {
+			t.Errorf("loadOutboxRetention() with %q succeeded, want an error", raw)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// pearson returns the Pearson correlation coefficient of xs and ys, or NaN
+// when it is undefined: fewer than two pairs, mismatched lengths, or a column
+// with no variance
+func pearson(xs, ys []float64) float64 {
+	n := len(xs)
+	if n < 2 || n != len(ys) {
+		return math.NaN()
+	}
+
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= float64(n)
+	meanY /= float64(n)
+
+	var cov, varX, varY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+
+	if varX == 0 || varY == 0 {
+		return math.NaN()
+	}
+	return cov / math.Sqrt(varX*varY)
+}
+
+// isConstant reports whether every value is the same
+func isConstant(values []float64) bool {
+	for _, v := range values {
+		if v != values[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// parseCorrelationMetrics reads and validates the x and y metric names
+func parseCorrelationMetrics(query url.Values) (string, string, error) {
+	x, y := query.Get("x"), query.Get("y")
+	for _, param := range []struct{ name, metric string }{{"x", x}, {"y", y}} {
+		if _, ok := performanceMetricColumns[param.metric]; !ok {
+			return "", "", fmt.Errorf("%s must be one of %s", param.name, strings.Join(performanceMetrics, ", "))
+		}
+	}
+	return x, y, nil
+}
+
+// GetMetricPairs returns the x and y metrics of every published paddle, averaged across
+// each paddle's measurements like the details endpoint
+func GetMetricPairs(x, y string) ([]float64, []float64, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	// Column names come from the performanceMetricColumns whitelist
+	rows, err := timedQuery(ctx, DB, "get_metric_pairs", fmt.Sprintf(`
+		SELECT
+			AVG(%s), AVG(%s)
+		FROM
+			paddles p
+		JOIN
+			paddle_specs s ON p.id = s.paddle_id
+		JOIN
+			paddle_performance perf ON s.id = perf.paddle_spec_id
+		WHERE
+			p.status = 'published'
+		GROUP BY
+			p.id
+	`, performanceMetricColumns[x], performanceMetricColumns[y]))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var xs, ys []float64
+	for rows.Next() {
+		var xv, yv float64
+		if err := rows.Scan(&xv, &yv); err != nil {
+			return nil, nil, err
+		}
+		xs = append(xs, xv)
+		ys = append(ys, yv)
+	}
+	return xs, ys, rows.Err()
+}
+
+// CorrelationResult is the correlation between two metrics across the dataset.
+// Coefficient is null when it is undefined, with Reason explaining why.
+type CorrelationResult struct {
+	X           string   `json:"x"`
+	Y           string   `json:"y"`
+	SampleCount int      `json:"sample_count"`
+	Coefficient *float64 `json:"coefficient"`
+	Reason      string   `json:"reason,omitempty"`
+}
+
+// correlate builds the correlation result for paired metric values
+func correlate(x, y string, xs, ys []float64) CorrelationResult {
+	result := CorrelationResult{X: x, Y: y, SampleCount: len(xs)}
+
+	switch {
+	case len(xs) < 2:
+		result.Reason = "at least two paddles are needed to compute a correlation"
+	case isConstant(xs):
+		result.Reason = fmt.Sprintf("%s is the same for every paddle, so it cannot correlate with anything", x)
+	case isConstant(ys):
+		result.Reason = fmt.Sprintf("%s is the same for every paddle, so it cannot correlate with anything", y)
+	default:
+		coefficient := pearson(xs, ys)
+		result.Coefficient = &coefficient
+	}
+	return result
+}
+
+// getCorrelation handles the API request for the correlation between two metrics
+func getCorrelation(w http.ResponseWriter, r *http.Request) {
+	x, y, err := parseCorrelationMetrics(r.URL.Query())
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	xs, ys, err := GetMetricPairs(x, y)
+	if err != nil {
+		log.Printf("Error retrieving metrics for correlation: %v", err)
+		respondWithError(w, "Failed to retrieve paddle metrics", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(correlate(x, y, xs, ys)); err != nil {
+		log.Printf("Error encoding correlation: %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"math"
+	"net/url"
+	"testing"
+)
+
+// TestPearson tests the coefficient against known datasets
+func TestPearson(t *testing.T) {
+	tests := []struct {
+		name string
+		xs   []float64
+		ys   []float64
+		want float64
+	}{
+		// Age against glucose level, a common textbook example
+		{name: "Textbook dataset", xs: []float64{43, 21, 25, 42, 57, 59}, ys: []float64{99, 65, 79, 75, 87, 81}, want: 0.5298089018901744},
+		{name: "Moderate positive", xs: []float64{1, 2, 3, 4, 5}, ys: []float64{2, 4, 5, 4, 5}, want: 0.7745966692414834},
+		{name: "Perfect positive", xs: []float64{1, 2, 3}, ys: []float64{10, 20, 30}, want: 1},
+		{name: "Perfect negative", xs: []float64{1, 2, 3}, ys: []float64{30, 20, 10}, want: -1},
+		{name: "Constant column", xs: []float64{1, 2, 3}, ys: []float64{5, 5, 5}, want: math.NaN()},
+		{name: "Single pair", xs: []float64{1}, ys: []float64{2}, want: math.NaN()},
+		{name: "Mismatched lengths", xs: []float64{1, 2, 3}, ys: []float64{1, 2}, want: math.NaN()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pearson(tt.xs, tt.ys)
+			if math.IsNaN(tt.want) {
+				if !math.IsNaN(got) {
+					t.Errorf("pearson() = %v, want NaN", got)
+				}
+				return
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("pearson() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCorrelate tests that undefined coefficients come back null with a reason
+func TestCorrelate(t *testing.T) {
+	result := correlate("power", "spin", []float64{60, 70, 80}, []float64{2500, 2500, 2500})
+	if result.Coefficient != nil || result.Reason == "" {
+		t.Errorf("Constant spin gave coefficient %v and reason %q, want null with a reason", result.Coefficient, result.Reason)
+	}
+
+	result = correlate("power", "spin", []float64{60}, []float64{2500})
+	if result.Coefficient != nil || result.Reason == "" {
+		t.Errorf("Single paddle gave coefficient %v and reason %q, want null with a reason", result.Coefficient, result.Reason)
+	}
+
+	result = correlate("power", "spin", []float64{60, 70, 80}, []float64{2400, 2600, 2800})
+	if result.Coefficient == nil || *result.Coefficient != 1 || result.SampleCount != 3 {
+		t.Errorf("correlate() = %+v, want coefficient 1 over 3 paddles", result)
+	}
+}
+
+// TestParseCorrelationMetrics tests that only whitelisted metrics are accepted
+func TestParseCorrelationMetrics(t *testing.T) {
+	if x, y, err := parseCorrelationMetrics(url.Values{"x": {"power"}, "y": {"spin"}}); err != nil || x != "power" || y != "spin" {
+		t.Errorf("parseCorrelationMetrics() = %q, %q, %v; want power, spin", x, y, err)
+	}
+
+	for _, query := range []url.Values{
+		{"x": {"power"}},
+		{"x": {"power"}, "y": {"power; DROP TABLE paddles"}},
+		{"x": {"control"}, "y": {"spin"}},
+	} {
+		if _, _, err := parseCorrelationMetrics(query); err == nil {
+			t.Errorf("parseCorrelationMetrics(%v) expected an error", query)
+		}
+	}
+}
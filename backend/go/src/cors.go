@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rs/cors"
+)
+
+// Default CORS settings, overridable via the CORS_* environment variables
+const (
+	defaultCORSMaxAge        = 600
+	defaultCORSOrigins       = "https://pickleball-db.vercel.app,https://pickleball-db.com"
+	defaultCORSPublicMethods = "GET,POST,PUT,DELETE"
+	defaultCORSAdminMethods  = "GET,POST,PUT,PATCH,DELETE"
+)
+
+// corsGroup applies its own CORS policy to every path under prefix
+type corsGroup struct {
+	prefix string
+	cors   *cors.Cors
+}
+
+// corsConfig holds the CORS settings shared by all route groups
+type corsConfig struct {
+	Origins       []string
+	MaxAge        int
+	PublicMethods []string
+	AdminMethods  []string
+}
+
+// loadCORSConfig reads the CORS settings from the environment
+func loadCORSConfig() (corsConfig, error) {
+	maxAge, err := strconv.Atoi(getEnv("CORS_MAX_AGE", strconv.Itoa(defaultCORSMaxAge)))
+	if err != nil || maxAge < 0 {
+		return corsConfig{}, fmt.Errorf("CORS_MAX_AGE must be a non-negative integer")
+	}
+
+	return corsConfig{
+		Origins:       splitList(getEnv("CORS_ALLOWED_ORIGINS", defaultCORSOrigins)),
+		MaxAge:        maxAge,
+		PublicMethods: splitList(getEnv("CORS_PUBLIC_METHODS", defaultCORSPublicMethods)),
+		AdminMethods:  splitList(getEnv("CORS_ADMIN_METHODS", defaultCORSAdminMethods)),
+	}, nil
+}
+
+// newCORSHandler wraps a handler so that admin routes and public API routes
+// each get their own allowed methods, sharing origins and preflight max-age
+func newCORSHandler(cfg corsConfig, next http.Handler) http.Handler {
+	newCors := func(methods []string) *cors.Cors {
+		return cors.New(cors.Options{
+			AllowedOrigins:   cfg.Origins,
+			AllowedMethods:   append(methods, http.MethodOptions),
+			AllowedHeaders:   []string{"*"},
+			ExposedHeaders:   []string{"Accept-Ranges", "Content-Range"},
+			AllowCredentials: true,
+			MaxAge:           cfg.MaxAge,
+		})
+	}
+
+	// Ordered from most to least specific prefix
+	groups := []corsGroup{
+		{prefix: "/api/admin/", cors: newCors(cfg.AdminMethods)},
+		{prefix: "/", cors: newCors(cfg.PublicMethods)},
+	}
+
+	handlers := make([]http.Handler, len(groups))
+	for i, group := range groups {
+		handlers[i] = group.cors.Handler(next)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for i, group := range groups {
+			if strings.HasPrefix(r.URL.Path, group.prefix) {
+				handlers[i].ServeHTTP(w, r)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// splitList splits a comma-separated value into trimmed, non-empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
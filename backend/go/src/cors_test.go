@@ -0,0 +1,92 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// preflight sends a CORS preflight request through handler
+func preflight(handler http.Handler, path, method string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodOptions, path, nil)
+	req.Header.Set("Origin", "https://pickleball-db.com")
+	req.Header.Set("Access-Control-Request-Method", method)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+// TestCORSPreflightMaxAge tests that preflight responses include the configured max-age
+func TestCORSPreflightMaxAge(t *testing.T) {
+	cfg, err := loadCORSConfig()
+	if err != nil {
+		t.Fatalf("loadCORSConfig() returned error: %v", err)
+	}
+
+	handler := newCORSHandler(cfg, http.NotFoundHandler())
+	rr := preflight(handler, "/api/paddles", http.MethodGet)
+
+	if got := rr.Header().Get("Access-Control-Max-Age"); got != "600" {
+		t.Errorf("Access-Control-Max-Age = %q, want %q", got, "600")
+	}
+	if got := rr.Header().Get("Access-Control-Allow-Origin"); got != "https://pickleball-db.com" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want the request origin", got)
+	}
+}
+
+// TestCORSMethodsPerGroup tests that each route group only allows its own methods
+func TestCORSMethodsPerGroup(t *testing.T) {
+	t.Setenv("CORS_PUBLIC_METHODS", "GET")
+	t.Setenv("CORS_ADMIN_METHODS", "GET,DELETE")
+
+	cfg, err := loadCORSConfig()
+	if err != nil {
+		t.Fatalf("loadCORSConfig() returned error: %v", err)
+	}
+	handler := newCORSHandler(cfg, http.NotFoundHandler())
+
+	tests := []struct {
+		name    string
+		path    string
+		method  string
+		allowed bool
+	}{
+		{name: "Public GET", path: "/api/paddles", method: http.MethodGet, allowed: true},
+		{name: "Public DELETE", path: "/api/paddles/x", method: http.MethodDelete, allowed: false},
+		{name: "Admin DELETE", path: "/api/admin/integrity", method: http.MethodDelete, allowed: true},
+		{name: "Admin POST", path: "/api/admin/integrity", method: http.MethodPost, allowed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := preflight(handler, tt.path, tt.method)
+			allowed := rr.Header().Get("Access-Control-Allow-Methods") != ""
+			if allowed != tt.allowed {
+				t.Errorf("preflight %s %s allowed = %v, want %v", tt.method, tt.path, allowed, tt.allowed)
+			}
+		})
+	}
+}
+
+// TestCORSDefaultPublicDelete tests that the default public methods allow the
+// public DELETE routes, such as removing a tag, from the browser
+func TestCORSDefaultPublicDelete(t *testing.T) {
+	cfg, err := loadCORSConfig()
+	if err != nil {
+		t.Fatalf("loadCORSConfig() returned error: %v", err)
+	}
+	handler := newCORSHandler(cfg, http.NotFoundHandler())
+
+	rr := preflight(handler, "/api/paddles/engage-pursuit-mx-6-0/tags/beginner-friendly", http.MethodDelete)
+	if rr.Header().Get("Access-Control-Allow-Methods") == "" {
+		t.Error("preflight DELETE on a public route should be allowed by default")
+	}
+}
+
+// TestLoadCORSConfigInvalidMaxAge tests that a bad max-age is rejected
+func TestLoadCORSConfigInvalidMaxAge(t *testing.T) {
+	t.Setenv("CORS_MAX_AGE", "soon")
+	if _, err := loadCORSConfig(); err == nil {
+		t.Error("loadCORSConfig() should fail with a non-numeric max-age")
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// defaultCountReconcileMS is how often the paddle counter is checked against
+// the database, overridable via COUNT_RECONCILE_MS
+const defaultCountReconcileMS = 300000
+
+// paddleCounters are in-memory totals for the summary endpoint, so showing
+// them does not hit the database. Tracked is every published paddle, stubs
+// included; served counts paddle details responses since startup.
+var paddleCounters struct {
+	tracked      atomic.Int64
+	served       atomic.Int64
+	reconciledAt atomic.Pointer[time.Time]
+}
+
+// loadCountReconcileInterval reads the reconcile interval from the environment
+func loadCountReconcileInterval() (time.Duration, error) {
+	reconcileMS, err := strconv.Atoi(getEnv("COUNT_RECONCILE_MS", strconv.Itoa(defaultCountReconcileMS)))
+	if err != nil || reconcileMS <= 0 {
+		return 0, fmt.Errorf("COUNT_RECONCILE_MS must be a positive integer")
+	}
+	return time.Duration(reconcileMS) * time.Millisecond, nil
+}
+
+// CountPaddles counts every published paddle
+func CountPaddles() (int, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	var count int
+	err := timedQueryRow(ctx, DB, "count_paddles", "SELECT COUNT(*) FROM paddles WHERE status = 'published'").Scan(&count)
+	return count, err
+}
+
+// reconcilePaddleCounters resets the tracked counter to the stored count,
+// correcting any drift from paddles added or removed outside this process
+func reconcilePaddleCounters() error {
+	count, err := store.CountPaddles()
+	if err != nil {
+		return err
+	}
+
+	if previous := paddleCounters.tracked.Swap(int64(count)); previous != int64(count) && paddleCounters.reconciledAt.Load() != nil {
+		log.Printf("Paddle counter drifted: counted %d, database has %d", previous, count)
+	}
+	reconciledAt := now()
+	paddleCounters.reconciledAt.Store(&reconciledAt)
+	return nil
+}
+
+// runPaddleCountReconciler reconciles the counters every interval until ctx is cancelled
+func runPaddleCountReconciler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := reconcilePaddleCounters(); err != nil {
+				log.Printf("Error reconciling paddle counters: %v", err)
+			}
+		}
+	}
+}
+
+// countingStore wraps a Store to count each paddle as it becomes published:
+// saved or upserted as published, or a draft being published. Paddles cannot
+// be deleted or unpublished through the API and a bulk rename keeps the
+// count, so nothing else lowers it apart from ResetData; rows changed outside
+// the API are left to the reconciler.
+type countingStore struct {
+	Store
+}
+
+func (s countingStore) SavePaddle(paddle *Paddle) (int, error) {
+	id, err := s.Store.SavePaddle(paddle)
+	if err == nil && paddle.Status != StatusDraft {
+		paddleCounters.tracked.Add(1)
+	}
+	return id, err
+}
+
+func (s countingStore) UpsertPaddle(paddle *Paddle) (int, bool, error) {
+	id, created, err := s.Store.UpsertPaddle(paddle)
+	if err == nil && created && paddle.Status != StatusDraft {
+		paddleCounters.tracked.Add(1)
+	}
+	return id, created, err
+}
+
+func (s countingStore) PublishPaddle(paddleId string) (bool, error) {
+	published, err := s.Store.PublishPaddle(paddleId)
+	if err == nil && published {
+		paddleCounters.tracked.Add(1)
+	}
+	return published, err
+}
+
+func (s countingStore) ResetData() error {
+	err := s.Store.ResetData()
+	if err == nil {
+		paddleCounters.tracked.Store(0)
+	}
+	return err
+}
+
+// StatsSummary is the headline numbers for the homepage
+type StatsSummary struct {
+	PaddlesTracked int64 `json:"paddles_tracked"`
+	PaddlesServed  int64 `json:"paddles_served"`
+	ReconciledAt   Time  `json:"reconciled_at,omitzero"`
+}
+
+// getStatsSummary handles the API request for the counters, served from memory
+func getStatsSummary(w http.ResponseWriter, r *http.Request) {
+	summary := StatsSummary{
+		PaddlesTracked: paddleCounters.tracked.Load(),
+		PaddlesServed:  paddleCounters.served.Load(),
+	}
+	if reconciledAt := paddleCounters.reconciledAt.Load(); reconciledAt != nil {
+		summary.ReconciledAt = NewTime(*reconciledAt)
+	}
+
+	if err := json.NewEncoder(w).Encode(summary); err != nil {
+		log.Printf("Error encoding stats summary: %v", err)
+	}
+}
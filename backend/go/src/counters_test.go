@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestPaddleCounters tests that saving or publishing a paddle increments the
+// tracked counter, and that reconciling corrects it from the store
+func TestPaddleCounters(t *testing.T) {
+	setupTestStore(t)
+	store = countingStore{store}
+	paddleCounters.tracked.Store(0)
+	defer paddleCounters.tracked.Store(0)
+
+	paddle := (&PaddleInput{
+		Metadata:    Metadata{Brand: "Engage", Model: "Pursuit MX 6.0"},
+		Performance: Performance{Power: 60, Pop: 40},
+	}).ToPaddle()
+	if _, err := store.SavePaddle(paddle); err != nil {
+		t.Fatalf("SavePaddle() returned error: %v", err)
+	}
+	if got := paddleCounters.tracked.Load(); got != 1 {
+		t.Errorf("tracked after a save = %d, want 1", got)
+	}
+
+	// A failed save and an upsert of an existing paddle add nothing
+	store.SavePaddle(paddle)
+	store.UpsertPaddle(paddle)
+	if got := paddleCounters.tracked.Load(); got != 1 {
+		t.Errorf("tracked after a duplicate save and upsert = %d, want 1", got)
+	}
+
+	// A draft is counted once it is published, and only once
+	draft := testPaddleInput("Joola", "Perseus").ToPaddle()
+	draft.Status = StatusDraft
+	if _, err := store.SavePaddle(draft); err != nil {
+		t.Fatalf("SavePaddle() returned error: %v", err)
+	}
+	if got := paddleCounters.tracked.Load(); got != 1 {
+		t.Errorf("tracked after saving a draft = %d, want 1", got)
+	}
+	for range 2 {
+		if _, err := store.PublishPaddle(draft.ID); err != nil {
+			t.Fatalf("PublishPaddle() returned error: %v", err)
+		}
+	}
+	if got := paddleCounters.tracked.Load(); got != 2 {
+		t.Errorf("tracked after publishing the draft twice = %d, want 2", got)
+	}
+
+	paddleCounters.tracked.Store(42)
+	if err := reconcilePaddleCounters(); err != nil {
+		t.Fatalf("reconcilePaddleCounters() returned error: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	getStatsSummary(rr, httptest.NewRequest("GET", "/api/stats/summary", nil))
+	var summary StatsSummary
+	if err := json.Unmarshal(rr.Body.Bytes(), &summary); err != nil {
+		t.Fatalf("Failed to decode summary: %v", err)
+	}
+	if summary.PaddlesTracked != 2 {
+		t.Errorf("paddles_tracked after reconciling = %d, want 2", summary.PaddlesTracked)
+	}
+	if summary.ReconciledAt.IsZero() {
+		t.Error("Expected reconciled_at to be set")
+	}
+}
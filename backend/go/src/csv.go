@@ -0,0 +1,300 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// maxCSVRows caps how many data rows a single CSV request may contain
+const maxCSVRows = 1000
+
+// csvColumn maps one CSV column to a PaddleInput field in both directions,
+// so imported and exported spreadsheets share the same layout
+type csvColumn struct {
+	Name   string
+	format func(input *PaddleInput) string
+	parse  func(input *PaddleInput, value string) error
+}
+
+// stringColumn maps a column to a string field
+func stringColumn(name string, field func(*PaddleInput) *string) csvColumn {
+	return csvColumn{
+		Name:   name,
+		format: func(input *PaddleInput) string { return *field(input) },
+		parse: func(input *PaddleInput, value string) error {
+			*field(input) = value
+			return nil
+		},
+	}
+}
+
+// floatColumn maps a column to a required number. An empty cell leaves the
+// field at 0 for validation to reject.
+func floatColumn(name string, field func(*PaddleInput) *float64) csvColumn {
+	return csvColumn{
+		Name:   name,
+		format: func(input *PaddleInput) string { return strconv.FormatFloat(*field(input), 'f', -1, 64) },
+		parse: func(input *PaddleInput, value string) error {
+			if value == "" {
+				return nil
+			}
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return newValidationError("CSV_NUMBER_INVALID", "%s must be a number", name)
+			}
+			*field(input) = v
+			return nil
+		},
+	}
+}
+
+// optionalFloatColumn maps a column to an optional number, left nil when the cell is empty
+func optionalFloatColumn(name string, field func(*PaddleInput) **float64) csvColumn {
+	return csvColumn{
+		Name: name,
+		format: func(input *PaddleInput) string {
+			if v := *field(input); v != nil {
+				return strconv.FormatFloat(*v, 'f', -1, 64)
+			}
+			return ""
+		},
+		parse: func(input *PaddleInput, value string) error {
+			if value == "" {
+				return nil
+			}
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return newValidationError("CSV_NUMBER_INVALID", "%s must be a number", name)
+			}
+			*field(input) = &v
+			return nil
+		},
+	}
+}
+
+// paddleCSVColumns is the CSV layout of a paddle, in column order
+var paddleCSVColumns = []csvColumn{
+	stringColumn("brand", func(p *PaddleInput) *string { return &p.Metadata.Brand }),
+	stringColumn("model", func(p *PaddleInput) *string { return &p.Metadata.Model }),
+	{
+		Name: "year",
+		format: func(p *PaddleInput) string {
+			if p.Metadata.Year != nil {
+				return strconv.Itoa(*p.Metadata.Year)
+			}
+			return ""
+		},
+		parse: func(p *PaddleInput, value string) error {
+			if value == "" {
+				return nil
+			}
+			year, err := strconv.Atoi(value)
+			if err != nil {
+				return newValidationError("CSV_NUMBER_INVALID", "year must be a whole number")
+			}
+			p.Metadata.Year = &year
+			return nil
+		},
+	},
+	stringColumn("sku", func(p *PaddleInput) *string { return &p.Metadata.SKU }),
+	stringColumn("product_url", func(p *PaddleInput) *string { return &p.Metadata.ProductURL }),
+	optionalFloatColumn("price", func(p *PaddleInput) **float64 { return &p.Metadata.Price }),
+	{
+		Name:   "shape",
+		format: func(p *PaddleInput) string { return string(p.Specs.Shape) },
+		parse: func(p *PaddleInput, value string) error {
+			p.Specs.Shape = PaddleShape(value)
+			return nil
+		},
+	},
+	stringColumn("surface", func(p *PaddleInput) *string { return &p.Specs.Surface }),
+	floatColumn("average_weight", func(p *PaddleInput) *float64 { return &p.Specs.AverageWeight }),
+	floatColumn("core", func(p *PaddleInput) *float64 { return &p.Specs.Core }),
+	floatColumn("paddle_length", func(p *PaddleInput) *float64 { return &p.Specs.PaddleLength }),
+	floatColumn("paddle_width", func(p *PaddleInput) *float64 { return &p.Specs.PaddleWidth }),
+	floatColumn("grip_length", func(p *PaddleInput) *float64 { return &p.Specs.GripLength }),
+	stringColumn("grip_type", func(p *PaddleInput) *string { return &p.Specs.GripType }),
+	floatColumn("grip_circumference", func(p *PaddleInput) *float64 { return &p.Specs.GripCircumference }),
+	stringColumn("edge_guard", func(p *PaddleInput) *string { return &p.Specs.EdgeGuard }),
+	stringColumn("handle_type", func(p *PaddleInput) *string { return &p.Specs.HandleType }),
+	stringColumn("surface_front", func(p *PaddleInput) *string { return &p.Specs.SurfaceFront }),
+	stringColumn("surface_back", func(p *PaddleInput) *string { return &p.Specs.SurfaceBack }),
+	floatColumn("power", func(p *PaddleInput) *float64 { return &p.Performance.Power }),
+	floatColumn("pop", func(p *PaddleInput) *float64 { return &p.Performance.Pop }),
+	floatColumn("spin", func(p *PaddleInput) *float64 { return &p.Performance.Spin }),
+	floatColumn("twist_weight", func(p *PaddleInput) *float64 { return &p.Performance.TwistWeight }),
+	floatColumn("swing_weight", func(p *PaddleInput) *float64 { return &p.Performance.SwingWeight }),
+	floatColumn("balance_point", func(p *PaddleInput) *float64 { return &p.Performance.BalancePoint }),
+	optionalFloatColumn("power_stddev", func(p *PaddleInput) **float64 { return &p.Performance.PowerStddev }),
+	optionalFloatColumn("pop_stddev", func(p *PaddleInput) **float64 { return &p.Performance.PopStddev }),
+	optionalFloatColumn("spin_stddev", func(p *PaddleInput) **float64 { return &p.Performance.SpinStddev }),
+	optionalFloatColumn("twist_weight_stddev", func(p *PaddleInput) **float64 { return &p.Performance.TwistWeightStddev }),
+	optionalFloatColumn("swing_weight_stddev", func(p *PaddleInput) **float64 { return &p.Performance.SwingWeightStddev }),
+	optionalFloatColumn("balance_point_stddev", func(p *PaddleInput) **float64 { return &p.Performance.BalancePointStddev }),
+}
+
+// paddleCSVHeader returns the header row of the paddle CSV layout
+func paddleCSVHeader() []string {
+	header := make([]string, len(paddleCSVColumns))
+	for i, column := range paddleCSVColumns {
+		header[i] = column.Name
+	}
+	return header
+}
+
+// paddleCSVRecord formats a paddle as a CSV row in paddleCSVHeader order
+func paddleCSVRecord(input *PaddleInput) []string {
+	record := make([]string, len(paddleCSVColumns))
+	for i, column := range paddleCSVColumns {
+		record[i] = column.format(input)
+	}
+	return record
+}
+
+// csvColumnsForHeader looks up the column of each header cell. Columns may
+// appear in any order and be left out, but not be unknown or repeated.
+func csvColumnsForHeader(header []string) ([]csvColumn, error) {
+	byName := make(map[string]csvColumn, len(paddleCSVColumns))
+	for _, column := range paddleCSVColumns {
+		byName[column.Name] = column
+	}
+
+	columns := make([]csvColumn, len(header))
+	seen := map[string]bool{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		column, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q: must be one of %s", name, strings.Join(paddleCSVHeader(), ", "))
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("column %q appears more than once", name)
+		}
+		seen[name] = true
+		columns[i] = column
+	}
+	return columns, nil
+}
+
+// parsePaddleCSVRow parses one data row into a PaddleInput. Every cell is
+// parsed so all malformed numbers in a row are reported together.
+func parsePaddleCSVRow(columns []csvColumn, record []string) (*PaddleInput, []error) {
+	input := &PaddleInput{}
+	var errs []error
+	for i, column := range columns {
+		if err := column.parse(input, strings.TrimSpace(record[i])); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return input, errs
+}
+
+// CSVRowError is one problem found in a CSV row
+type CSVRowError struct {
+	Message   string `json:"message"`
+	ErrorCode string `json:"error_code,omitempty"`
+}
+
+// CSVRowResult is the validation outcome of one CSV row. Row is the
+// spreadsheet row number, so the header is row 1 and the first paddle row 2.
+type CSVRowResult struct {
+	Row    int           `json:"row"`
+	ID     string        `json:"id,omitempty"`
+	OK     bool          `json:"ok"`
+	Errors []CSVRowError `json:"errors,omitempty"`
+}
+
+// validateCSVRow parses and validates one row the same way a JSON upload is validated
+func validateCSVRow(row int, columns []csvColumn, record []string) CSVRowResult {
+	result := CSVRowResult{Row: row}
+
+	input, errs := parsePaddleCSVRow(columns, record)
+	if len(errs) == 0 {
+		if err := validatePaddleInput(input, fullProfile); err != nil {
+			errs = append(errs, err)
+		} else if _, err := checkPopPower(&input.Performance); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if input.Metadata.Brand != "" && input.Metadata.Model != "" {
+		result.ID = idGenerator.GenerateID(input.Metadata)
+	}
+	for _, err := range errs {
+		result.Errors = append(result.Errors, CSVRowError{Message: err.Error(), ErrorCode: validationCode(err)})
+	}
+	result.OK = len(result.Errors) == 0
+	return result
+}
+
+// validatePaddlesCSV handles the API request for checking a spreadsheet of
+// paddles before uploading it. Nothing is saved.
+func validatePaddlesCSV(w http.ResponseWriter, r *http.Request) {
+	reader := csv.NewReader(r.Body)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		respondWithError(w, "CSV must start with a header row", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		respondWithError(w, fmt.Sprintf("Malformed CSV: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	columns, err := csvColumnsForHeader(header)
+	if err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid CSV header: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// Read every row first so a malformed file is rejected as a whole
+	var records [][]string
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			respondWithError(w, fmt.Sprintf("Malformed CSV: %v", err), http.StatusBadRequest)
+			return
+		}
+		if len(records) == maxCSVRows {
+			respondWithError(w, fmt.Sprintf("CSV may contain at most %d paddles", maxCSVRows), http.StatusBadRequest)
+			return
+		}
+		records = append(records, record)
+	}
+
+	if len(records) == 0 {
+		respondWithError(w, "CSV must contain at least one paddle", http.StatusBadRequest)
+		return
+	}
+
+	response := struct {
+		Rows    []CSVRowResult `json:"rows"`
+		Valid   int            `json:"valid"`
+		Invalid int            `json:"invalid"`
+	}{}
+	for i, record := range records {
+		result := validateCSVRow(i+2, columns, record)
+		if result.OK {
+			response.Valid++
+		} else {
+			response.Invalid++
+		}
+		response.Rows = append(response.Rows, result)
+	}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding CSV validation report: %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestValidatePaddlesCSV tests a CSV with one good and one bad row
+func TestValidatePaddlesCSV(t *testing.T) {
+	body := strings.Join([]string{
+		"brand,model,year,shape,surface,average_weight,core,paddle_length,paddle_width,grip_length,grip_type,grip_circumference,power,pop,spin,twist_weight,swing_weight,balance_point",
+		"Engage,Pursuit MX 6.0,2023,Hybrid,Composite,220,15,16.5,7.5,4.5,Comfort,4,75,70,3000,200,220,30",
+		"Selkirk,Vanguard,2024,Round,Composite,220,15,16.5,7.5,4.5,Comfort,4,lots,70,3000,200,220,30",
+	}, "\n")
+
+	rr := httptest.NewRecorder()
+	validatePaddlesCSV(rr, httptest.NewRequest("POST", "/api/paddles/validate-csv", strings.NewReader(body)))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	var response struct {
+		Rows    []CSVRowResult `json:"rows"`
+		Valid   int            `json:"valid"`
+		Invalid int            `json:"invalid"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Valid != 1 || response.Invalid != 1 || len(response.Rows) != 2 {
+		t.Fatalf("report = %+v, want one valid and one invalid row", response)
+	}
+
+	good, bad := response.Rows[0], response.Rows[1]
+	if good.Row != 2 || !good.OK || good.ID != "engage-pursuit-mx-6.0" || len(good.Errors) != 0 {
+		t.Errorf("good row = %+v, want row 2 ok", good)
+	}
+	if bad.Row != 3 || bad.OK || len(bad.Errors) != 1 || bad.Errors[0].ErrorCode != "CSV_NUMBER_INVALID" {
+		t.Errorf("bad row = %+v, want row 3 with a CSV_NUMBER_INVALID error", bad)
+	}
+}
+
+// TestValidatePaddlesCSVRejected tests requests that cannot be read as a paddle CSV
+func TestValidatePaddlesCSVRejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantMsg string
+	}{
+		{name: "Empty", body: "", wantMsg: "header row"},
+		{name: "Header only", body: "brand,model\n", wantMsg: "at least one paddle"},
+		{name: "Unknown column", body: "brand,colour\nEngage,red\n", wantMsg: `unknown column \"colour\"`},
+		{name: "Repeated column", body: "brand,brand\nEngage,Engage\n", wantMsg: "more than once"},
+		{name: "Wrong field count", body: "brand,model\nEngage\n", wantMsg: "Malformed CSV"},
+		{name: "Bare quote", body: "brand,model\nEn\"gage,Pursuit\n", wantMsg: "Malformed CSV"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			validatePaddlesCSV(rr, httptest.NewRequest("POST", "/api/paddles/validate-csv", strings.NewReader(tt.body)))
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d", rr.Code, http.StatusBadRequest)
+			}
+			if !strings.Contains(rr.Body.String(), tt.wantMsg) {
+				t.Errorf("body = %s, want it to contain %q", rr.Body.String(), tt.wantMsg)
+			}
+		})
+	}
+}
+
+// TestPaddleCSVRoundTrip tests that a formatted row parses back to the same paddle
+func TestPaddleCSVRoundTrip(t *testing.T) {
+	year, powerStddev := 2023, 2.5
+	input := &PaddleInput{
+		Metadata: Metadata{Brand: "Engage", Model: "Pursuit, MX 6.0", Year: &year},
+		Specs: Specs{
+			Shape: Hybrid, Surface: "Composite", AverageWeight: 220.5, Core: 15.0,
+			PaddleLength: 16.5, PaddleWidth: 7.5, GripLength: 4.5, GripType: "Comfort", GripCircumference: 4.0,
+		},
+		Performance: Performance{Power: 75.0, Pop: 70.0, Spin: 3000.0, TwistWeight: 200.0, SwingWeight: 220.0, BalancePoint: 30.0, PowerStddev: &powerStddev},
+	}
+
+	columns, err := csvColumnsForHeader(paddleCSVHeader())
+	if err != nil {
+		t.Fatalf("csvColumnsForHeader() error: %v", err)
+	}
+	parsed, errs := parsePaddleCSVRow(columns, paddleCSVRecord(input))
+	if len(errs) != 0 {
+		t.Fatalf("parsePaddleCSVRow() errors: %v", errs)
+	}
+
+	if parsed.Metadata.Model != input.Metadata.Model || *parsed.Metadata.Year != year || parsed.Specs != input.Specs {
+		t.Errorf("parsed = %+v, want %+v", parsed, input)
+	}
+	if parsed.Performance.PowerStddev == nil || *parsed.Performance.PowerStddev != powerStddev || parsed.Performance.PopStddev != nil {
+		t.Errorf("parsed stddevs = %+v, want only power_stddev %v", parsed.Performance, powerStddev)
+	}
+	if !slices.Equal(paddleCSVRecord(parsed), paddleCSVRecord(input)) {
+		t.Errorf("record changed after a round trip: %v", paddleCSVRecord(parsed))
+	}
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -13,23 +15,34 @@ import (
 // DB is the global database connection
 var DB *sql.DB
 
-// InitDB initializes the database connection
-func InitDB() error {
-	// Get database connection details from environment variables
-	// or use defaults for development
-	host := getEnv("DB_HOST", "localhost")
-	port := getEnv("DB_PORT", "5432")
-	user := getEnv("DB_USER", "postgres")
-	password := getEnv("DB_PASSWORD", "postgres")
-	dbname := getEnv("DB_NAME", "pickleball_db")
-
-	// Connection string
-	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		host, port, user, password, dbname)
+// ErrPaddleNotFound is returned when no paddle matches the requested ID
+var ErrPaddleNotFound = errors.New("paddle not found")
+
+// ErrPaddleExists is returned when saving a paddle whose ID is already taken
+var ErrPaddleExists = errors.New("paddle already exists")
+
+// defaultApplicationName identifies this service's connections in pg_stat_activity
+const defaultApplicationName = "go-pickleball"
+
+// connectionString builds the Postgres connection string from the database settings
+func connectionString(cfg DBConfig) string {
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable application_name=%s",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, quoteConnValue(cfg.ApplicationName))
+}
 
+// quoteConnValue single-quotes a connection string value, escaping quotes and
+// backslashes, so it may contain spaces
+func quoteConnValue(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return "'" + value + "'"
+}
+
+// InitDB initializes the database connection
+func InitDB(cfg DBConfig) error {
 	// Open a connection to the database
 	var err error
-	DB, err = sql.Open("postgres", connStr)
+	DB, err = sql.Open("postgres", connectionString(cfg))
 	if err != nil {
 		return fmt.Errorf("failed to open database connection: %w", err)
 	}
@@ -40,13 +53,50 @@ func InitDB() error {
 		return fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	// Create tables if they don't exist
-	err = createTables()
+	// Create tables and apply migrations, one instance at a time
+	if err := initSchema(); err != nil {
+		return err
+	}
+
+	log.Println("Database connection established successfully")
+	return nil
+}
+
+// schemaLockKey identifies the advisory lock held while initializing the schema
+const schemaLockKey = 7246913
+
+// initSchema creates the tables and applies migrations while holding a
+// Postgres advisory lock, so instances starting together against a fresh
+// database take turns running DDL instead of racing each other
+func initSchema() error {
+	ctx := context.Background()
+
+	// Advisory locks belong to a session, so lock and unlock on one pinned connection
+	conn, err := DB.Conn(ctx)
 	if err != nil {
+		return fmt.Errorf("failed to get connection for schema lock: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", schemaLockKey); err != nil {
+		return fmt.Errorf("failed to acquire schema lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", schemaLockKey); err != nil {
+			log.Printf("Error releasing schema lock: %v", err)
+		}
+	}()
+
+	// Create tables if they don't exist
+	if err := createTables(); err != nil {
 		return fmt.Errorf("failed to create tables: %w", err)
 	}
 
-	log.Println("Database connection established successfully")
+	// Apply schema migrations
+	if err := runMigrations(); err != nil {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+
 	return nil
 }
 
@@ -59,7 +109,7 @@ func createTables() error {
 			paddle_id VARCHAR(100) UNIQUE NOT NULL,
 			brand VARCHAR(100) NOT NULL,
 			model VARCHAR(100) NOT NULL,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
 		)
 	`)
 	if err != nil {
@@ -80,7 +130,7 @@ func createTables() error {
 			grip_length FLOAT NOT NULL,
 			grip_type VARCHAR(50) NOT NULL,
 			grip_circumference FLOAT NOT NULL,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
 		)
 	`)
 	if err != nil {
@@ -98,153 +148,671 @@ func createTables() error {
 			twist_weight FLOAT NOT NULL,
 			swing_weight FLOAT NOT NULL,
 			balance_point FLOAT NOT NULL,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
 		)
 	`)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return upgradeBaseTimestamps()
 }
 
-// GetPaddleByID retrieves a paddle with its specs and performance by ID
-// Example ID: "ENGAGE-PURSUIT-MX-6.0-2023-42069"
-func GetPaddleByID(paddleId string) (*Paddle, error) {
-	paddle := &Paddle{}
+// baseTimestampColumns are the columns createTables made as TIMESTAMP before
+// it stored instants, as table and column name pairs
+var baseTimestampColumns = [][2]string{
+	{"paddles", "created_at"},
+	{"paddle_specs", "created_at"},
+	{"paddle_performance", "created_at"},
+}
 
-	// Query for paddle, specs, and performance in a single query using JOINs
-	row := DB.QueryRow(`
+// upgradeBaseTimestamps converts baseTimestampColumns still holding local
+// wall-clock time to TIMESTAMPTZ. Existing values were written with this
+// server's TimeZone setting and are read back in it.
+func upgradeBaseTimestamps() error {
+	for _, col := range baseTimestampColumns {
+		var dataType string
+		err := DB.QueryRow(`
+			SELECT data_type FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = $1 AND column_name = $2
+		`, col[0], col[1]).Scan(&dataType)
+		if err != nil {
+			return fmt.Errorf("failed to inspect %s.%s: %w", col[0], col[1], err)
+		}
+		if dataType != "timestamp without time zone" {
+			continue
+		}
+		_, err = DB.Exec(fmt.Sprintf(
+			"ALTER TABLE %[1]s ALTER COLUMN %[2]s TYPE TIMESTAMPTZ USING %[2]s AT TIME ZONE current_setting('TimeZone')",
+			col[0], col[1]))
+		if err != nil {
+			return fmt.Errorf("failed to convert %s.%s to timestamptz: %w", col[0], col[1], err)
+		}
+	}
+	return nil
+}
+
+// fullPaddleQuery selects paddles with their specs and performance. Callers
+// append their own WHERE/ORDER BY clauses and scan rows with scanFullPaddle.
+const fullPaddleQuery = `
 		SELECT 
-			p.paddle_id, p.brand, p.model,
+			p.paddle_id, p.brand, p.model, p.year, COALESCE(p.sku, ''), COALESCE(p.product_url, ''), p.price,
+			p.id, p.status, p.created_at, p.updated_at,
 			s.shape, s.surface, s.average_weight, s.core, s.paddle_length, 
 			s.paddle_width, s.grip_length, s.grip_type, s.grip_circumference,
-			perf.power, perf.pop, perf.spin, perf.twist_weight, perf.swing_weight, perf.balance_point
+			COALESCE(s.edge_guard, ''), COALESCE(s.handle_type, ''),
+			COALESCE(s.surface_front, ''), COALESCE(s.surface_back, ''),
+			` + performanceColumns + `
 		FROM 
 			paddles p
 		JOIN 
 			paddle_specs s ON p.id = s.paddle_id
 		JOIN 
 			paddle_performance perf ON s.id = perf.paddle_spec_id
-		WHERE 
-			p.paddle_id = $1
-	`, paddleId)
+`
+
+// paddleMeansQuery selects each paddle once, with its specs and the mean of
+// its performance measurements in the columns fullPaddleQuery returns, so
+// rows scan with scanFullPaddle and clauses may name perf.* columns. Stddevs
+// are pooled as averagePerformance pools them. Paddles without measurements
+// are left out, as the join in fullPaddleQuery leaves them out.
+const paddleMeansQuery = `
+		SELECT 
+			p.paddle_id, p.brand, p.model, p.year, COALESCE(p.sku, ''), COALESCE(p.product_url, ''), p.price,
+			p.id, p.status, p.created_at, p.updated_at,
+			s.shape, s.surface, s.average_weight, s.core, s.paddle_length, 
+			s.paddle_width, s.grip_length, s.grip_type, s.grip_circumference,
+			COALESCE(s.edge_guard, ''), COALESCE(s.handle_type, ''),
+			COALESCE(s.surface_front, ''), COALESCE(s.surface_back, ''),
+			` + performanceColumns + `
+		FROM 
+			paddles p
+		JOIN 
+			paddle_specs s ON p.id = s.paddle_id
+		JOIN LATERAL (
+			SELECT
+				AVG(m.power) AS power, AVG(m.pop) AS pop, AVG(m.spin) AS spin,
+				AVG(m.twist_weight) AS twist_weight, AVG(m.swing_weight) AS swing_weight,
+				AVG(m.balance_point) AS balance_point,
+				SQRT(AVG(m.power_stddev * m.power_stddev)) AS power_stddev,
+				SQRT(AVG(m.pop_stddev * m.pop_stddev)) AS pop_stddev,
+				SQRT(AVG(m.spin_stddev * m.spin_stddev)) AS spin_stddev,
+				SQRT(AVG(m.twist_weight_stddev * m.twist_weight_stddev)) AS twist_weight_stddev,
+				SQRT(AVG(m.swing_weight_stddev * m.swing_weight_stddev)) AS swing_weight_stddev,
+				SQRT(AVG(m.balance_point_stddev * m.balance_point_stddev)) AS balance_point_stddev
+			FROM 
+				paddle_performance m
+			WHERE 
+				m.paddle_spec_id = s.id
+			GROUP BY 
+				m.paddle_spec_id
+		) perf ON TRUE
+`
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
 
-	err := row.Scan(
-		&paddle.ID, &paddle.Metadata.Brand, &paddle.Metadata.Model,
+// scanFullPaddle scans a row selected by fullPaddleQuery into a paddle
+func scanFullPaddle(row rowScanner) (*Paddle, error) {
+	paddle := &Paddle{}
+	dest := []interface{}{
+		&paddle.ID, &paddle.Metadata.Brand, &paddle.Metadata.Model, &paddle.Metadata.Year,
+		&paddle.Metadata.SKU, &paddle.Metadata.ProductURL, &paddle.Metadata.Price,
+		&paddle.DBID, &paddle.Status, &paddle.CreatedAt, &paddle.UpdatedAt,
 		&paddle.Specs.Shape, &paddle.Specs.Surface, &paddle.Specs.AverageWeight,
 		&paddle.Specs.Core, &paddle.Specs.PaddleLength, &paddle.Specs.PaddleWidth,
 		&paddle.Specs.GripLength, &paddle.Specs.GripType, &paddle.Specs.GripCircumference,
-		&paddle.Performance.Power, &paddle.Performance.Pop, &paddle.Performance.Spin,
-		&paddle.Performance.TwistWeight, &paddle.Performance.SwingWeight, &paddle.Performance.BalancePoint,
-	)
-
+		&paddle.Specs.EdgeGuard, &paddle.Specs.HandleType,
+		&paddle.Specs.SurfaceFront, &paddle.Specs.SurfaceBack,
+	}
+	err := row.Scan(append(dest, performanceScanDest(&paddle.Performance)...)...)
 	if err != nil {
 		return nil, err
 	}
 
+	paddle.Control = paddle.ControlRating()
 	return paddle, nil
 }
 
+// queryFullPaddles runs fullPaddleQuery followed by clause (WHERE, ORDER BY, LIMIT...)
+// and returns the matching paddles, once per measurement
+func queryFullPaddles(label, clause string, args ...interface{}) ([]*Paddle, error) {
+	return queryPaddles(label, fullPaddleQuery+clause, args...)
+}
+
+// queryPaddleMeans runs paddleMeansQuery followed by clause and returns the
+// matching paddles, each once with its mean performance
+func queryPaddleMeans(label, clause string, args ...interface{}) ([]*Paddle, error) {
+	return queryPaddles(label, paddleMeansQuery+clause, args...)
+}
+
+// queryPaddles runs a query selecting the columns scanFullPaddle reads and
+// returns the paddles it selects
+func queryPaddles(label, query string, args ...interface{}) ([]*Paddle, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	rows, err := timedQuery(ctx, DB, label, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	paddles := []*Paddle{}
+	for rows.Next() {
+		paddle, err := scanFullPaddle(rows)
+		if err != nil {
+			return nil, err
+		}
+		paddles = append(paddles, paddle)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return paddles, nil
+}
+
+// GetPaddleByID retrieves a paddle with its specs and performance by ID.
+// The ID is normalized first, so casing and accents do not matter.
+// Example ID: "engage-pursuit-mx-6.0"
+func GetPaddleByID(paddleId string) (*Paddle, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	paddleId = NormalizePaddleID(paddleId)
+
+	// Query for paddle, specs, and performance in a single query using JOINs
+	row := timedQueryRow(ctx, DB, "get_paddle_by_id", fullPaddleQuery+`
+		WHERE 
+			p.paddle_id = $1
+	`, paddleId)
+
+	return scanFullPaddle(row)
+}
+
+// GetPaddleByDBID retrieves a paddle with its specs and performance by its
+// numeric primary key, as referenced by internal tools
+func GetPaddleByDBID(id int) (*Paddle, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	row := timedQueryRow(ctx, DB, "get_paddle_by_db_id", fullPaddleQuery+`
+		WHERE 
+			p.id = $1
+	`, id)
+
+	paddle, err := scanFullPaddle(row)
+	if err == sql.ErrNoRows {
+		return nil, ErrPaddleNotFound
+	}
+	return paddle, err
+}
+
 // SavePaddle saves a paddle's specs and performance to the database
 func SavePaddle(paddle *Paddle) (int, error) {
-	// For testing environments, we could check for a special prefix
-	if strings.Contains(paddle.Metadata.Model, "Test-") {
-		// Skip the duplicate check for test data
-	} else {
-		// Check if a paddle with this business ID already exists
-		var existingID int
-		err := DB.QueryRow("SELECT id FROM paddles WHERE LOWER(paddle_id) = LOWER($1)", paddle.ID).Scan(&existingID)
-		if err == nil {
-			// If no error, then a paddle with this ID was found
-			return 0, fmt.Errorf("paddle with ID %s already exists", paddle.ID)
-		} else if err != sql.ErrNoRows {
-			// If error is not "no rows", then it's a database error
-			return 0, fmt.Errorf("error checking for existing paddle: %w", err)
-		}
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	// Check if a paddle with this business ID already exists
+	var existingID int
+	err := timedQueryRow(ctx, DB, "check_existing_paddle", "SELECT id FROM paddles WHERE LOWER(paddle_id) = LOWER($1)", paddle.ID).Scan(&existingID)
+	if err == nil {
+		// If no error, then a paddle with this ID was found
+		return 0, fmt.Errorf("%w: %s", ErrPaddleExists, paddle.ID)
+	} else if err != sql.ErrNoRows {
+		// If error is not "no rows", then it's a database error
+		return 0, fmt.Errorf("error checking for existing paddle: %w", err)
 	}
 
 	// Begin a transaction
-	tx, err := DB.Begin()
+	tx, err := DB.BeginTx(ctx, nil)
 	if err != nil {
 		return 0, err
 	}
 	defer tx.Rollback()
 
+	// Count inside the transaction so concurrent inserts cannot overshoot the quota
+	if err := checkPaddleQuota(ctx, tx); err != nil {
+		return 0, err
+	}
+
 	// Insert into paddles table first
 	var paddleDBID int
-	err = tx.QueryRow(`
+	err = timedQueryRow(ctx, tx, "insert_paddle", `
 		INSERT INTO paddles (
-			paddle_id, brand, model
-		) VALUES ($1, $2, $3)
-		RETURNING id
+			paddle_id, brand, model, year, sku, product_url, price, status
+		) VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), $7, COALESCE(NULLIF($8, ''), 'published'))
+		RETURNING id, created_at, updated_at
 	`,
-		paddle.ID, paddle.Metadata.Brand, paddle.Metadata.Model,
-	).Scan(&paddleDBID)
+		paddle.ID, paddle.Metadata.Brand, paddle.Metadata.Model, paddle.Metadata.Year,
+		paddle.Metadata.SKU, paddle.Metadata.ProductURL, paddle.Metadata.Price, paddle.Status,
+	).Scan(&paddleDBID, &paddle.CreatedAt, &paddle.UpdatedAt)
 
 	if err != nil {
 		return 0, err
 	}
+	paddle.DBID = paddleDBID
+
+	// Insert specs, performance and spec ranges
+	if err := insertPaddleDetails(ctx, tx, paddleDBID, paddle); err != nil {
+		return 0, err
+	}
+
+	// Keep the body it was uploaded with, so both are saved or neither is
+	if err := insertRawUpload(ctx, tx, paddleDBID, paddle.UploadBody); err != nil {
+		return 0, err
+	}
+
+	// Announce the new paddle once the transaction commits
+	if err := enqueueEvent(ctx, tx, eventPaddleCreated, paddle); err != nil {
+		return 0, err
+	}
+
+	// Commit the transaction
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return paddleDBID, nil
+}
+
+// insertPaddleDetails inserts the specs, performance and spec ranges of a
+// paddle whose paddles row is paddleDBID, inside tx
+func insertPaddleDetails(ctx context.Context, tx *sql.Tx, paddleDBID int, paddle *Paddle) error {
+	// Stubs may leave out specs, and with them performance and spec ranges
+	if paddle.Specs == (Specs{}) {
+		return nil
+	}
 
 	// Check if a paddle_specs record with this paddle_id already exists
 	var existingSpecID int
-	err = tx.QueryRow("SELECT id FROM paddle_specs WHERE paddle_id = $1", paddleDBID).Scan(&existingSpecID)
+	err := timedQueryRow(ctx, tx, "check_existing_specs", "SELECT id FROM paddle_specs WHERE paddle_id = $1", paddleDBID).Scan(&existingSpecID)
 	if err == nil {
 		// If no error, then specs for this paddle already exist
-		return 0, fmt.Errorf("specs for paddle with database ID %d already exist", paddleDBID)
+		return fmt.Errorf("specs for paddle with database ID %d already exist", paddleDBID)
 	} else if err != sql.ErrNoRows {
 		// If error is not "no rows", then it's a database error
-		return 0, fmt.Errorf("error checking for existing paddle specs: %w", err)
+		return fmt.Errorf("error checking for existing paddle specs: %w", err)
 	}
 	// If err is sql.ErrNoRows, then no specs for this paddle exist, so we can proceed
 
 	var specID int
 	// Insert paddle specs
-	err = tx.QueryRow(`
+	err = timedQueryRow(ctx, tx, "insert_paddle_specs", `
 		INSERT INTO paddle_specs (
 			paddle_id, shape, surface, average_weight, core, paddle_length, 
-			paddle_width, grip_length, grip_type, grip_circumference
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			paddle_width, grip_length, grip_type, grip_circumference, edge_guard, handle_type,
+			surface_front, surface_back
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, ''), NULLIF($12, ''), NULLIF($13, ''), NULLIF($14, ''))
 		RETURNING id
 	`,
 		paddleDBID, paddle.Specs.Shape, paddle.Specs.Surface, paddle.Specs.AverageWeight,
 		paddle.Specs.Core, paddle.Specs.PaddleLength, paddle.Specs.PaddleWidth,
 		paddle.Specs.GripLength, paddle.Specs.GripType, paddle.Specs.GripCircumference,
+		paddle.Specs.EdgeGuard, paddle.Specs.HandleType,
+		paddle.Specs.SurfaceFront, paddle.Specs.SurfaceBack,
 	).Scan(&specID)
 
 	if err != nil {
-		return 0, err
+		return err
+	}
+
+	// Stubs may leave out performance
+	if paddle.Performance == (Performance{}) {
+		return insertSpecRanges(ctx, tx, paddleDBID, paddle.SpecRanges)
 	}
 
 	// Insert paddle performance
-	_, err = tx.Exec(`
+	_, err = timedExec(ctx, tx, "insert_paddle_performance", `
 		INSERT INTO paddle_performance (
-			paddle_spec_id, power, pop, spin, twist_weight, swing_weight, balance_point
-		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			paddle_spec_id, power, pop, spin, twist_weight, swing_weight, balance_point,
+			power_stddev, pop_stddev, spin_stddev, twist_weight_stddev, swing_weight_stddev, balance_point_stddev
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 	`,
 		specID, paddle.Performance.Power, paddle.Performance.Pop, paddle.Performance.Spin,
 		paddle.Performance.TwistWeight, paddle.Performance.SwingWeight, paddle.Performance.BalancePoint,
+		paddle.Performance.PowerStddev, paddle.Performance.PopStddev, paddle.Performance.SpinStddev,
+		paddle.Performance.TwistWeightStddev, paddle.Performance.SwingWeightStddev, paddle.Performance.BalancePointStddev,
 	)
 
 	if err != nil {
-		return 0, err
+		return err
 	}
 
-	// Commit the transaction
-	if err = tx.Commit(); err != nil {
-		return 0, err
+	// Insert any manufacturer-quoted spec ranges
+	return insertSpecRanges(ctx, tx, paddleDBID, paddle.SpecRanges)
+}
+
+// UpsertPaddle inserts a paddle, or replaces the metadata, specs, performance
+// and spec ranges of the paddle with the same ID if one exists. created
+// reports which branch was taken. A replaced paddle is snapshotted to its
+// history first, like a performance update, and keeps its status.
+func UpsertPaddle(paddle *Paddle) (paddleDBID int, created bool, err error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, false, err
 	}
+	defer tx.Rollback()
 
-	return paddleDBID, nil
+	var exists bool
+	err = timedQueryRow(ctx, tx, "check_existing_paddle_for_upsert",
+		"SELECT EXISTS (SELECT 1 FROM paddles WHERE paddle_id = $1)", paddle.ID).Scan(&exists)
+	if err != nil {
+		return 0, false, fmt.Errorf("error checking for existing paddle: %w", err)
+	}
+	if exists {
+		if err := recordPaddleSnapshot(ctx, tx, paddle.ID); err != nil {
+			return 0, false, err
+		}
+	} else if err := checkPaddleQuota(ctx, tx); err != nil {
+		return 0, false, err
+	}
+
+	// xmax is 0 only for a freshly inserted row, which tells the branches apart
+	err = timedQueryRow(ctx, tx, "upsert_paddle", `
+		INSERT INTO paddles (
+			paddle_id, brand, model, year, sku, product_url, price, status
+		) VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), $7, COALESCE(NULLIF($8, ''), 'published'))
+		ON CONFLICT (paddle_id) DO UPDATE SET
+			brand = EXCLUDED.brand,
+			model = EXCLUDED.model,
+			year = EXCLUDED.year,
+			sku = EXCLUDED.sku,
+			product_url = EXCLUDED.product_url,
+			price = EXCLUDED.price,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING id, (xmax = 0), status, created_at, updated_at
+	`,
+		paddle.ID, paddle.Metadata.Brand, paddle.Metadata.Model, paddle.Metadata.Year,
+		paddle.Metadata.SKU, paddle.Metadata.ProductURL, paddle.Metadata.Price, paddle.Status,
+	).Scan(&paddleDBID, &created, &paddle.Status, &paddle.CreatedAt, &paddle.UpdatedAt)
+	if err != nil {
+		return 0, false, err
+	}
+	paddle.DBID = paddleDBID
+
+	// Clear the old details so they can be inserted afresh, children first
+	if !created {
+		for _, stmt := range []string{
+			"DELETE FROM paddle_performance WHERE paddle_spec_id IN (SELECT id FROM paddle_specs WHERE paddle_id = $1)",
+			"DELETE FROM paddle_specs WHERE paddle_id = $1",
+			"DELETE FROM paddle_spec_ranges WHERE paddle_id = $1",
+		} {
+			if _, err := timedExec(ctx, tx, "delete_paddle_details_for_upsert", stmt, paddleDBID); err != nil {
+				return 0, false, err
+			}
+		}
+	}
+
+	if err := insertPaddleDetails(ctx, tx, paddleDBID, paddle); err != nil {
+		return 0, false, err
+	}
+	if err := insertRawUpload(ctx, tx, paddleDBID, paddle.UploadBody); err != nil {
+		return 0, false, err
+	}
+
+	event := eventPaddleCreated
+	if !created {
+		event = eventPaddleUpdated
+	}
+	if err := enqueueEvent(ctx, tx, event, paddle); err != nil {
+		return 0, false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, false, err
+	}
+
+	return paddleDBID, created, nil
+}
+
+// PaddleExists reports whether a paddle with the given ID is stored
+func PaddleExists(paddleId string) (bool, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	var exists bool
+	err := timedQueryRow(ctx, DB, "paddle_exists",
+		"SELECT EXISTS (SELECT 1 FROM paddles WHERE LOWER(paddle_id) = LOWER($1))", paddleId,
+	).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// FindPaddleIDByName returns the ID of the oldest paddle with the brand and
+// model, compared case-insensitively, or ErrPaddleNotFound
+func FindPaddleIDByName(brand, model string) (string, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	var paddleId string
+	err := timedQueryRow(ctx, DB, "find_paddle_id_by_name",
+		"SELECT paddle_id FROM paddles WHERE LOWER(brand) = LOWER($1) AND LOWER(model) = LOWER($2) ORDER BY id LIMIT 1",
+		brand, model,
+	).Scan(&paddleId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrPaddleNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return paddleId, nil
+}
+
+// UpdatePaddlePerformance replaces the performance measurements of an existing paddle,
+// leaving its metadata and specs untouched
+func UpdatePaddlePerformance(paddleId string, performance *Performance) error {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Find the specs row the performance measurements belong to
+	var specID int
+	err = timedQueryRow(ctx, tx, "find_paddle_specs", `
+		SELECT s.id
+		FROM paddles p
+		JOIN paddle_specs s ON p.id = s.paddle_id
+		WHERE p.paddle_id = $1
+	`, paddleId).Scan(&specID)
+	if err == sql.ErrNoRows {
+		return ErrPaddleNotFound
+	} else if err != nil {
+		return fmt.Errorf("error looking up paddle specs: %w", err)
+	}
+
+	// Keep the previous measurements so re-tests can be compared later
+	if err := recordPaddleSnapshot(ctx, tx, paddleId); err != nil {
+		return err
+	}
+
+	// Replace only the latest measurement; earlier ones stay in the average
+	result, err := timedExec(ctx, tx, "update_paddle_performance", `
+		UPDATE paddle_performance
+		SET power = $1, pop = $2, spin = $3, twist_weight = $4, swing_weight = $5, balance_point = $6,
+			power_stddev = $7, pop_stddev = $8, spin_stddev = $9, twist_weight_stddev = $10,
+			swing_weight_stddev = $11, balance_point_stddev = $12
+		WHERE id = (
+			SELECT id FROM paddle_performance WHERE paddle_spec_id = $13 ORDER BY id DESC LIMIT 1
+		)
+	`,
+		performance.Power, performance.Pop, performance.Spin,
+		performance.TwistWeight, performance.SwingWeight, performance.BalancePoint,
+		performance.PowerStddev, performance.PopStddev, performance.SpinStddev,
+		performance.TwistWeightStddev, performance.SwingWeightStddev, performance.BalancePointStddev,
+		specID,
+	)
+	if err != nil {
+		return err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return ErrPaddleNotFound
+	}
+
+	_, err = timedExec(ctx, tx, "touch_paddle_updated_at",
+		"UPDATE paddles SET updated_at = CURRENT_TIMESTAMP WHERE paddle_id = $1", paddleId)
+	if err != nil {
+		return err
+	}
+
+	if err := enqueueStoredPaddleEvent(ctx, tx, eventPaddleUpdated, paddleId); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetAggregatedPerformance averages every performance measurement recorded for a paddle.
+// With a single measurement it returns that measurement with a sample count of 1.
+func GetAggregatedPerformance(paddleId string) (*AggregatedPerformance, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	rows, err := timedQuery(ctx, DB, "get_performance_measurements", `
+		SELECT 
+			`+performanceColumns+`
+		FROM 
+			paddles p
+		JOIN 
+			paddle_specs s ON p.id = s.paddle_id
+		JOIN 
+			paddle_performance perf ON s.id = perf.paddle_spec_id
+		WHERE 
+			p.paddle_id = $1
+		ORDER BY 
+			perf.id
+	`, paddleId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var measurements []Performance
+	for rows.Next() {
+		var m Performance
+		err := rows.Scan(performanceScanDest(&m)...)
+		if err != nil {
+			return nil, err
+		}
+		measurements = append(measurements, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(measurements) == 0 {
+		return nil, ErrPaddleNotFound
+	}
+
+	agg := averagePerformance(measurements)
+	return &agg, nil
+}
+
+// GetPaddleBySKU retrieves the paddle with a manufacturer SKU. SKUs are not
+// unique; when several paddles share one, the first by insertion order is
+// returned and a warning is logged.
+func GetPaddleBySKU(sku string) (*Paddle, error) {
+	paddles, err := queryFullPaddles("get_paddle_by_sku", `
+		WHERE 
+			p.sku = $1
+		ORDER BY 
+			p.id
+	`, sku)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(paddles) == 0 {
+		return nil, ErrPaddleNotFound
+	}
+
+	// Paddles with several measurements appear once per measurement, so
+	// count distinct IDs before warning about a shared SKU
+	ids := map[string]bool{}
+	for _, paddle := range paddles {
+		ids[paddle.ID] = true
+	}
+	if len(ids) > 1 {
+		log.Printf("WARN: %d paddles share SKU %q, returning %s", len(ids), sku, paddles[0].ID)
+	}
+
+	return paddles[0], nil
+}
+
+// YearCount is the number of paddles released in a year
+type YearCount struct {
+	Year  int `json:"year"`
+	Count int `json:"count"`
+}
+
+// GetPaddleCountsByYear counts published paddles per release year in ascending
+// year order, along with the number of them whose year is unknown
+func GetPaddleCountsByYear() ([]YearCount, int, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	rows, err := timedQuery(ctx, DB, "get_paddle_counts_by_year", `
+		SELECT year, COUNT(*)
+		FROM paddles
+		WHERE status = 'published'
+		GROUP BY year
+		ORDER BY year NULLS LAST
+	`)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	counts := []YearCount{}
+	unknown := 0
+	for rows.Next() {
+		var year sql.NullInt64
+		var count int
+		if err := rows.Scan(&year, &count); err != nil {
+			return nil, 0, err
+		}
+		if !year.Valid {
+			unknown = count
+			continue
+		}
+		counts = append(counts, YearCount{Year: int(year.Int64), Count: count})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return counts, unknown, nil
 }
 
 // GetAllPaddles retrieves all paddles with their metadata and specs
 func GetAllPaddles() ([]*Paddle, error) {
-	rows, err := DB.Query(`
-		SELECT 
-			p.paddle_id, p.brand, p.model,
-			s.shape, s.surface, s.average_weight, s.core, s.paddle_length,
-			s.paddle_width, s.grip_length, s.grip_type, s.grip_circumference
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	rows, err := timedQuery(ctx, DB, "get_all_paddles", `
+		SELECT `+paddleSummaryColumns+`
 		FROM 
 			paddles p
 		JOIN 
@@ -257,22 +825,91 @@ func GetAllPaddles() ([]*Paddle, error) {
 	}
 	defer rows.Close()
 
+	return scanPaddleSummaries(rows)
+}
+
+// GetPaddlesPage retrieves a single page of paddles with their metadata and specs
+func GetPaddlesPage(filter paddleFilter, sort listSort, limit, offset int) ([]*Paddle, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	where, args := filter.where()
+	args = append(args, limit, offset)
+	rows, err := timedQuery(ctx, DB, "get_paddles_page", `
+		SELECT `+paddleSummaryColumns+`
+		FROM 
+			paddles p
+		JOIN 
+			paddle_specs s ON p.id = s.paddle_id
+		`+where+`
+		`+sort.orderBy()+`
+		`+fmt.Sprintf("LIMIT $%d OFFSET $%d", len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPaddleSummaries(rows)
+}
+
+// CountPaddlesMatching counts the paddles GetPaddlesPage pages through
+func CountPaddlesMatching(filter paddleFilter) (int, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	where, args := filter.where()
+	var count int
+	err := timedQueryRow(ctx, DB, "count_paddles_matching", `
+		SELECT COUNT(*)
+		FROM 
+			paddles p
+		JOIN 
+			paddle_specs s ON p.id = s.paddle_id
+		`+where, args...).Scan(&count)
+	return count, err
+}
+
+// paddleSummaryColumns selects the metadata, specs and control rating
+// scanned by scanPaddleSummary
+var paddleSummaryColumns = `
+			p.paddle_id, p.brand, p.model, p.year, p.status, p.created_at, p.updated_at,
+			s.shape, s.surface, s.average_weight, s.core, s.paddle_length,
+			s.paddle_width, s.grip_length, s.grip_type, s.grip_circumference,
+			COALESCE(s.edge_guard, ''), COALESCE(s.handle_type, ''),
+			COALESCE(s.surface_front, ''), COALESCE(s.surface_back, ''),
+			COALESCE(` + controlColumn + `, 0)`
+
+// scanPaddleSummary scans a row of paddleSummaryColumns into a paddle
+func scanPaddleSummary(row rowScanner) (*Paddle, error) {
+	paddle := &Paddle{}
+	err := row.Scan(
+		&paddle.ID, &paddle.Metadata.Brand, &paddle.Metadata.Model, &paddle.Metadata.Year,
+		&paddle.Status, &paddle.CreatedAt, &paddle.UpdatedAt,
+		&paddle.Specs.Shape, &paddle.Specs.Surface, &paddle.Specs.AverageWeight,
+		&paddle.Specs.Core, &paddle.Specs.PaddleLength, &paddle.Specs.PaddleWidth,
+		&paddle.Specs.GripLength, &paddle.Specs.GripType, &paddle.Specs.GripCircumference,
+		&paddle.Specs.EdgeGuard, &paddle.Specs.HandleType,
+		&paddle.Specs.SurfaceFront, &paddle.Specs.SurfaceBack,
+		&paddle.Control,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return paddle, nil
+}
+
+// scanPaddleSummaries scans rows of paddleSummaryColumns into paddles
+func scanPaddleSummaries(rows *sql.Rows) ([]*Paddle, error) {
 	var paddles []*Paddle
 	for rows.Next() {
-		paddle := &Paddle{}
-		err := rows.Scan(
-			&paddle.ID, &paddle.Metadata.Brand, &paddle.Metadata.Model,
-			&paddle.Specs.Shape, &paddle.Specs.Surface, &paddle.Specs.AverageWeight,
-			&paddle.Specs.Core, &paddle.Specs.PaddleLength, &paddle.Specs.PaddleWidth,
-			&paddle.Specs.GripLength, &paddle.Specs.GripType, &paddle.Specs.GripCircumference,
-		)
+		paddle, err := scanPaddleSummary(rows)
 		if err != nil {
 			return nil, err
 		}
 		paddles = append(paddles, paddle)
 	}
 
-	if err = rows.Err(); err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestConnectionStringApplicationName tests that the connection string names the application
+func TestConnectionStringApplicationName(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		want string
+	}{
+		{name: "Default", env: "", want: "application_name='go-pickleball'"},
+		{name: "Override", env: "pickleball-worker", want: "application_name='pickleball-worker'"},
+		{name: "Quoted", env: `it's a\test`, want: `application_name='it\'s a\\test'`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DB_APPLICATION_NAME", tt.env)
+			cfg, err := loadDBConfig()
+			if err != nil {
+				t.Fatalf("loadDBConfig() error: %v", err)
+			}
+			if got := connectionString(cfg); !strings.Contains(got, tt.want) {
+				t.Errorf("connectionString() = %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestPaddleListsOnePerPaddle tests that every paddle list returns a paddle
+// measured several times once, with its mean performance
+func TestPaddleListsOnePerPaddle(t *testing.T) {
+	setupTestDB(t)
+
+	paddle := saveTestPaddle(t, testPaddleInput("Engage", "Pursuit MX 6.0"))
+	addTestMeasurement(t, paddle.ID, 85)
+	const meanPower = 80.0
+
+	checkOnce := func(name string, paddles []*Paddle, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("%s error: %v", name, err)
+		}
+		if len(paddles) != 1 || paddles[0].Performance.Power != meanPower {
+			t.Errorf("%s returned %d paddles, want %s once with power %v", name, len(paddles), paddle.ID, meanPower)
+		}
+	}
+
+	changes, err := GetPaddleChanges(time.Time{}, "", 10)
+	checkOnce("GetPaddleChanges()", changes, err)
+
+	recent, err := GetRecentPaddles(30, 10)
+	checkOnce("GetRecentPaddles()", recent, err)
+
+	recommended, err := GetRecommendedPaddles([]recommendationTarget{{Metric: "power", Min: 78, Max: 82}}, 10)
+	checkOnce("GetRecommendedPaddles()", recommended, err)
+
+	elite, err := GetElitePaddles("power", meanPower, 10, 0)
+	checkOnce("GetElitePaddles()", elite, err)
+
+	rr := httptest.NewRecorder()
+	streamPaddles(rr, httptest.NewRequest("GET", "/api/paddles/stream", nil))
+	var streamed []*Paddle
+	if err := json.Unmarshal(rr.Body.Bytes(), &streamed); err != nil {
+		t.Fatalf("Failed to decode stream: %v", err)
+	}
+	checkOnce("streamPaddles", streamed, nil)
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// defaultStatsRefreshMS is how often the dataset stats are recomputed,
+// overridable via STATS_REFRESH_MS
+const defaultStatsRefreshMS = 300000
+
+// FieldStats summarizes one performance metric across paddles
+type FieldStats struct {
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+	Mean float64 `json:"mean"`
+}
+
+// DatasetStats holds the dataset-wide stats that relative metrics such as
+// radar scaling are computed against, over one value per published paddle:
+// its mean across measurements, the same value those metrics score. A
+// paddle measured five times counts once. Fields is empty when nothing has
+// been measured yet. Percentiles holds each metric's 0th to 100th percentile, for
+// thresholds, and Stddevs its population standard deviation, for z-scores;
+// both are left out of responses.
+type DatasetStats struct {
+	SampleCount int                   `json:"sample_count"`
+	Fields      map[string]FieldStats `json:"fields"`
+	Percentiles map[string][]float64  `json:"-"`
+	Stddevs     map[string]float64    `json:"-"`
+	RefreshedAt Time                  `json:"refreshed_at,omitzero"`
+}
+
+// percentileFractions are the fractions 0, 0.01, ... 1 of every whole percentile
+var percentileFractions = func() []float64 {
+	fractions := make([]float64, 101)
+	for i := range fractions {
+		fractions[i] = float64(i) / 100
+	}
+	return fractions
+}()
+
+// percentiles returns the whole percentiles of sorted values, interpolating
+// between neighbours like Postgres percentile_cont
+func percentiles(sorted []float64) []float64 {
+	cuts := make([]float64, len(percentileFractions))
+	for i, fraction := range percentileFractions {
+		position := fraction * float64(len(sorted)-1)
+		lower := int(position)
+		upper := min(lower+1, len(sorted)-1)
+		cuts[i] = sorted[lower] + (position-float64(lower))*(sorted[upper]-sorted[lower])
+	}
+	return cuts
+}
+
+// bounds returns the min and max of each metric. Metrics without stats get
+// an empty range.
+func (s *DatasetStats) bounds() map[string]metricBounds {
+	bounds := make(map[string]metricBounds, len(s.Fields))
+	for metric, field := range s.Fields {
+		bounds[metric] = metricBounds{Min: field.Min, Max: field.Max}
+	}
+	return bounds
+}
+
+// datasetStatsCache holds the last computed stats between refreshes
+var datasetStatsCache struct {
+	sync.RWMutex
+	stats *DatasetStats
+}
+
+// loadStatsRefreshInterval reads the stats refresh interval from the environment
+func loadStatsRefreshInterval() (time.Duration, error) {
+	refreshMS, err := strconv.Atoi(getEnv("STATS_REFRESH_MS", strconv.Itoa(defaultStatsRefreshMS)))
+	if err != nil || refreshMS <= 0 {
+		return 0, fmt.Errorf("STATS_REFRESH_MS must be a positive integer")
+	}
+	return time.Duration(refreshMS) * time.Millisecond, nil
+}
+
+// summarizePerformance computes dataset stats over the mean performance of each paddle
+func summarizePerformance(means []Performance) *DatasetStats {
+	stats := newDatasetStats(len(means))
+	if len(means) == 0 {
+		return stats
+	}
+
+	for _, metric := range performanceMetrics {
+		values := make([]float64, len(means))
+		sum := 0.0
+		for i := range means {
+			values[i] = metricValue(&means[i], metric)
+			sum += values[i]
+		}
+		mean := sum / float64(len(values))
+		squares := 0.0
+		for _, value := range values {
+			squares += (value - mean) * (value - mean)
+		}
+		slices.Sort(values)
+		stats.Fields[metric] = FieldStats{Min: values[0], Max: values[len(values)-1], Mean: mean}
+		stats.Percentiles[metric] = percentiles(values)
+		stats.Stddevs[metric] = math.Sqrt(squares / float64(len(values)))
+	}
+	return stats
+}
+
+// newDatasetStats returns stats over sampleCount paddles with no fields yet
+func newDatasetStats(sampleCount int) *DatasetStats {
+	return &DatasetStats{
+		SampleCount: sampleCount,
+		Fields:      map[string]FieldStats{},
+		Percentiles: map[string][]float64{},
+		Stddevs:     map[string]float64{},
+	}
+}
+
+// aggregateSelects returns the MIN, MAX and AVG of each column, in order, to
+// be scanned with aggregateScanDest. Column names must come from a whitelist.
+func aggregateSelects(columns []string) string {
+	selects := make([]string, len(columns))
+	for i, column := range columns {
+		selects[i] = fmt.Sprintf("MIN(%s), MAX(%s), AVG(%s)", column, column, column)
+	}
+	return strings.Join(selects, ", ")
+}
+
+// nullableFieldStats holds a scanned aggregate, which is NULL when no row
+// had a value for the column
+type nullableFieldStats struct {
+	Min, Max, Mean *float64
+}
+
+// aggregateScanDest returns scan destinations matching aggregateSelects
+func aggregateScanDest(fields []nullableFieldStats) []interface{} {
+	dest := make([]interface{}, 0, 3*len(fields))
+	for i := range fields {
+		dest = append(dest, &fields[i].Min, &fields[i].Max, &fields[i].Mean)
+	}
+	return dest
+}
+
+// stats returns the aggregate, or nil when there were no values
+func (n nullableFieldStats) stats() *FieldStats {
+	if n.Min == nil || n.Max == nil || n.Mean == nil {
+		return nil
+	}
+	return &FieldStats{Min: *n.Min, Max: *n.Max, Mean: *n.Mean}
+}
+
+// percentileSelects returns the whole percentiles of each column as arrays,
+// in order, given the fractions as the first argument. Column names must
+// come from a whitelist.
+func percentileSelects(columns []string) string {
+	selects := make([]string, len(columns))
+	for i, column := range columns {
+		selects[i] = fmt.Sprintf("percentile_cont($1::float8[]) WITHIN GROUP (ORDER BY %s)", column)
+	}
+	return strings.Join(selects, ", ")
+}
+
+// stddevSelects returns the population standard deviation of each column, in
+// order. Column names must come from a whitelist.
+func stddevSelects(columns []string) string {
+	selects := make([]string, len(columns))
+	for i, column := range columns {
+		selects[i] = fmt.Sprintf("STDDEV_POP(%s)", column)
+	}
+	return strings.Join(selects, ", ")
+}
+
+// GetDatasetStats computes the min, max, mean, standard deviation and
+// percentiles of every performance metric across published paddles in one
+// query, averaging each paddle's measurements first
+func GetDatasetStats() (*DatasetStats, error) {
+	// Column names come from the performanceMetricColumns whitelist, and the
+	// per-paddle means are named after their metric
+	means := make([]string, len(performanceMetrics))
+	for i, metric := range performanceMetrics {
+		means[i] = fmt.Sprintf("AVG(%s) AS %s", performanceMetricColumns[metric], metric)
+	}
+	columns := performanceMetrics
+
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	var count int
+	fields := make([]nullableFieldStats, len(performanceMetrics))
+	stddevs := make([]*float64, len(performanceMetrics))
+	cuts := make([][]float64, len(performanceMetrics))
+	dest := append([]interface{}{&count}, aggregateScanDest(fields)...)
+	for i := range stddevs {
+		dest = append(dest, &stddevs[i])
+	}
+	for i := range cuts {
+		dest = append(dest, pq.Array(&cuts[i]))
+	}
+	err := timedQueryRow(ctx, DB, "get_dataset_stats",
+		"SELECT COUNT(*), "+aggregateSelects(columns)+", "+stddevSelects(columns)+", "+percentileSelects(columns)+`
+		FROM (
+			SELECT `+strings.Join(means, ", ")+`
+			FROM paddles p
+			JOIN paddle_specs s ON p.id = s.paddle_id
+			JOIN paddle_performance perf ON s.id = perf.paddle_spec_id
+			WHERE p.status = 'published'
+			GROUP BY p.id
+		) paddle_means`,
+		pq.Array(percentileFractions)).Scan(dest...)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := newDatasetStats(count)
+	for i, metric := range performanceMetrics {
+		if field := fields[i].stats(); field != nil && stddevs[i] != nil {
+			stats.Fields[metric] = *field
+			stats.Percentiles[metric] = cuts[i]
+			stats.Stddevs[metric] = *stddevs[i]
+		}
+	}
+	return stats, nil
+}
+
+// refreshDatasetStats recomputes the stats and replaces the cached copy
+func refreshDatasetStats() (*DatasetStats, error) {
+	stats, err := store.GetDatasetStats()
+	if err != nil {
+		return nil, err
+	}
+	stats.RefreshedAt = NewTime(now())
+
+	datasetStatsCache.Lock()
+	datasetStatsCache.stats = stats
+	datasetStatsCache.Unlock()
+	return stats, nil
+}
+
+// currentDatasetStats returns the cached stats, computing them on first use
+func currentDatasetStats() (*DatasetStats, error) {
+	datasetStatsCache.RLock()
+	stats := datasetStatsCache.stats
+	datasetStatsCache.RUnlock()
+
+	if stats != nil {
+		return stats, nil
+	}
+	return refreshDatasetStats()
+}
+
+// runDatasetStatsRefresher recomputes the stats every interval until ctx is cancelled
+func runDatasetStatsRefresher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := refreshDatasetStats(); err != nil {
+				log.Printf("Error refreshing dataset stats: %v", err)
+			}
+		}
+	}
+}
+
+// refreshStats handles the admin request to recompute the dataset stats now,
+// rather than waiting for the next automatic refresh
+func refreshStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := refreshDatasetStats()
+	if err != nil {
+		log.Printf("Error refreshing dataset stats: %v", err)
+		respondWithError(w, "Failed to refresh dataset stats", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(stats); err != nil {
+		log.Printf("Error encoding dataset stats: %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestSummarizePerformance tests min, max and mean, and the empty dataset
+func TestSummarizePerformance(t *testing.T) {
+	empty := summarizePerformance(nil)
+	if empty.SampleCount != 0 || len(empty.Fields) != 0 {
+		t.Errorf("summarizePerformance(nil) = %+v, want no samples and no fields", empty)
+	}
+	if bounds := empty.bounds(); scaleMetric(70, bounds["power"]) != 50 {
+		t.Errorf("empty dataset scales power to %v, want 50", scaleMetric(70, bounds["power"]))
+	}
+
+	stats := summarizePerformance([]Performance{
+		{Power: 60, Pop: 50, Spin: 2000, TwistWeight: 6, SwingWeight: 110, BalancePoint: 22},
+		{Power: 90, Pop: 70, Spin: 3000, TwistWeight: 7, SwingWeight: 120, BalancePoint: 24},
+	})
+	if stats.SampleCount != 2 || len(stats.Fields) != len(performanceMetrics) {
+		t.Fatalf("summarizePerformance() = %+v, want 2 samples over every metric", stats)
+	}
+	if got, want := stats.Fields["power"], (FieldStats{Min: 60, Max: 90, Mean: 75}); got != want {
+		t.Errorf("power stats = %+v, want %+v", got, want)
+	}
+	if got, want := stats.Fields["spin"], (FieldStats{Min: 2000, Max: 3000, Mean: 2500}); got != want {
+		t.Errorf("spin stats = %+v, want %+v", got, want)
+	}
+	if cuts := stats.Percentiles["spin"]; len(cuts) != 101 || cuts[0] != 2000 || cuts[90] != 2900 || cuts[100] != 3000 {
+		t.Errorf("spin percentiles = %v, want 101 cuts from 2000 to 3000 with 2900 at the 90th", cuts)
+	}
+}
+
+// TestRefreshDatasetStats tests that a new extreme paddle shows up in the
+// cached stats only once they are refreshed
+func TestRefreshDatasetStats(t *testing.T) {
+	setupTestStore(t)
+	t.Cleanup(func() { datasetStatsCache.stats = nil })
+	datasetStatsCache.stats = nil
+
+	save := func(model string, power float64) {
+		t.Helper()
+		input := testPaddleInput("Engage", model)
+		input.Performance.Power = power
+		saveTestPaddle(t, input)
+	}
+
+	save("Pursuit MX 6.0", 60)
+	save("Pursuit EX 6.0", 80)
+
+	stats, err := currentDatasetStats()
+	if err != nil {
+		t.Fatalf("currentDatasetStats() error: %v", err)
+	}
+	if stats.Fields["power"].Max != 80 {
+		t.Fatalf("power max = %v, want 80", stats.Fields["power"].Max)
+	}
+
+	// The cache keeps serving the old stats until refreshed
+	save("Pursuit Pro", 99)
+	if stats, _ := currentDatasetStats(); stats.Fields["power"].Max != 80 {
+		t.Errorf("cached power max = %v before refresh, want 80", stats.Fields["power"].Max)
+	}
+
+	rr := httptest.NewRecorder()
+	refreshStats(rr, httptest.NewRequest("POST", "/api/admin/refresh-stats", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("refresh status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	var refreshed DatasetStats
+	if err := json.Unmarshal(rr.Body.Bytes(), &refreshed); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if refreshed.SampleCount != 3 || refreshed.Fields["power"].Max != 99 {
+		t.Errorf("refreshed stats = %+v, want 3 samples with power max 99", refreshed)
+	}
+	if stats, _ := currentDatasetStats(); stats.Fields["power"].Max != 99 {
+		t.Errorf("cached power max = %v after refresh, want 99", stats.Fields["power"].Max)
+	}
+}
+
+// TestGetDatasetStatsOnePerPaddle tests that a paddle measured several times
+// counts once, with its mean, in the dataset stats
+func TestGetDatasetStatsOnePerPaddle(t *testing.T) {
+	setupTestDB(t)
+
+	retested := testPaddleInput("Engage", "Pursuit MX 6.0")
+	retested.Performance.Power = 60
+	saveTestPaddle(t, retested)
+	for _, power := range []float64{100, 100} {
+		if _, err := DB.Exec(`
+			INSERT INTO paddle_performance (paddle_spec_id, power, pop, spin, twist_weight, swing_weight, balance_point)
+			SELECT s.id, $2, 60, 2500, 190, 210, 29
+			FROM paddle_specs s JOIN paddles p ON p.id = s.paddle_id
+			WHERE p.paddle_id = $1
+		`, "engage-pursuit-mx-6.0", power); err != nil {
+			t.Fatalf("Failed to add a measurement: %v", err)
+		}
+	}
+
+	other := testPaddleInput("Selkirk", "Vanguard Power Air")
+	other.Performance.Power = 70
+	saveTestPaddle(t, other)
+
+	stats, err := GetDatasetStats()
+	if err != nil {
+		t.Fatalf("GetDatasetStats() error: %v", err)
+	}
+	if stats.SampleCount != 2 {
+		t.Errorf("SampleCount = %d, want 2 paddles", stats.SampleCount)
+	}
+	if got, want := stats.Fields["power"], (FieldStats{Min: 70, Max: 260.0 / 3, Mean: (70 + 260.0/3) / 2}); math.Abs(got.Max-want.Max) > 1e-9 || got.Min != want.Min || math.Abs(got.Mean-want.Mean) > 1e-9 {
+		t.Errorf("power stats = %+v, want %+v", got, want)
+	}
+	if cuts := stats.Percentiles["power"]; cuts[0] != 70 || math.Abs(cuts[100]-260.0/3) > 1e-9 {
+		t.Errorf("power percentiles run from %v to %v, want 70 to the retested paddle's mean", cuts[0], cuts[100])
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// defaultActivityMinMS is the default duration a query must have run to be
+// listed by the activity endpoint
+const defaultActivityMinMS = 1000
+
+// ErrBackendNotFound is returned when cancelling a pid that is not one of
+// this application's backends in the current database
+var ErrBackendNotFound = errors.New("backend not found")
+
+// DBActivity is a running query from pg_stat_activity
+type DBActivity struct {
+	PID           int     `json:"pid"`
+	User          string  `json:"user,omitempty"`
+	State         string  `json:"state"`
+	WaitEventType string  `json:"wait_event_type,omitempty"`
+	WaitEvent     string  `json:"wait_event,omitempty"`
+	QueryStart    Time    `json:"query_start"`
+	DurationMS    float64 `json:"duration_ms"`
+	Query         string  `json:"query"`
+}
+
+// activityScope limits pg_stat_activity to other backends of this application
+// in the current database, so the endpoints cannot see or cancel anything else
+const activityScope = `
+		datname = current_database()
+		AND application_name = current_setting('application_name')
+		AND pid <> pg_backend_pid()
+`
+
+// parseActivityMinMS reads the min_ms parameter, defaulting to defaultActivityMinMS
+func parseActivityMinMS(raw string) (int, error) {
+	if raw == "" {
+		return defaultActivityMinMS, nil
+	}
+
+	minMS, err := strconv.Atoi(raw)
+	if err != nil || minMS < 0 {
+		return 0, fmt.Errorf("min_ms must be a non-negative integer")
+	}
+	return minMS, nil
+}
+
+// scanDBActivity scans a row selected by GetDBActivity. User, state and the
+// wait event are NULL for some backends and come back empty.
+func scanDBActivity(row rowScanner) (*DBActivity, error) {
+	var activity DBActivity
+	var user, state, waitEventType, waitEvent sql.NullString
+	var queryStart time.Time
+	err := row.Scan(&activity.PID, &user, &state, &waitEventType, &waitEvent,
+		&queryStart, &activity.DurationMS, &activity.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	activity.User = user.String
+	activity.State = state.String
+	activity.WaitEventType = waitEventType.String
+	activity.WaitEvent = waitEvent.String
+	activity.QueryStart = NewTime(queryStart)
+	activity.DurationMS = roundTo(activity.DurationMS, 1)
+	return &activity, nil
+}
+
+// GetDBActivity returns this application's non-idle queries that have been
+// running for at least minMS milliseconds, longest-running first
+func GetDBActivity(minMS int) ([]*DBActivity, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	rows, err := timedQuery(ctx, DB, "get_db_activity", `
+		SELECT
+			pid, usename, state, wait_event_type, wait_event, query_start,
+			EXTRACT(EPOCH FROM now() - query_start) * 1000, query
+		FROM
+			pg_stat_activity
+		WHERE
+			`+activityScope+`
+			AND state <> 'idle'
+			AND query_start IS NOT NULL
+			AND now() - query_start >= $1 * interval '1 millisecond'
+		ORDER BY
+			query_start
+	`, minMS)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	activity := []*DBActivity{}
+	for rows.Next() {
+		a, err := scanDBActivity(rows)
+		if err != nil {
+			return nil, err
+		}
+		activity = append(activity, a)
+	}
+	return activity, rows.Err()
+}
+
+// CancelDBQuery asks Postgres to cancel the query running on backend pid. It
+// returns whether the cancel signal was sent, or ErrBackendNotFound if pid is
+// not in scope.
+func CancelDBQuery(pid int) (bool, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	var cancelled bool
+	err := timedQueryRow(ctx, DB, "cancel_db_query", `
+		SELECT
+			pg_cancel_backend(pid)
+		FROM
+			pg_stat_activity
+		WHERE
+			pid = $1
+			AND `+activityScope, pid).Scan(&cancelled)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, ErrBackendNotFound
+	}
+	return cancelled, err
+}
+
+// getDBActivity handles the admin request for listing long-running queries
+func getDBActivity(w http.ResponseWriter, r *http.Request) {
+	minMS, err := parseActivityMinMS(r.URL.Query().Get("min_ms"))
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	activity, err := GetDBActivity(minMS)
+	if err != nil {
+		log.Printf("Error retrieving database activity: %v", err)
+		respondWithError(w, "Failed to retrieve database activity", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(activity); err != nil {
+		log.Printf("Error encoding database activity: %v", err)
+	}
+}
+
+// cancelDBQuery handles the admin request for cancelling a running query
+func cancelDBQuery(w http.ResponseWriter, r *http.Request) {
+	pid, err := strconv.Atoi(mux.Vars(r)["pid"])
+	if err != nil || pid <= 0 {
+		respondWithError(w, "Invalid pid: must be a positive integer", http.StatusBadRequest)
+		return
+	}
+
+	cancelled, err := CancelDBQuery(pid)
+	if errors.Is(err, ErrBackendNotFound) {
+		respondWithError(w, fmt.Sprintf("No query from this application with pid %d", pid), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Error cancelling query on pid %d: %v", pid, err)
+		respondWithError(w, "Failed to cancel query", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Admin cancelled query on pid %d (signalled=%v)", pid, cancelled)
+	json.NewEncoder(w).Encode(struct {
+		PID       int  `json:"pid"`
+		Cancelled bool `json:"cancelled"`
+	}{
+		PID:       pid,
+		Cancelled: cancelled,
+	})
+}
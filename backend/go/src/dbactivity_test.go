@@ -0,0 +1,113 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// fakeActivityRow scans a fixed pg_stat_activity row, with nil for NULL columns
+type fakeActivityRow struct {
+	values []interface{}
+	err    error
+}
+
+func (f fakeActivityRow) Scan(dest ...interface{}) error {
+	if f.err != nil {
+		return f.err
+	}
+	for i, v := range f.values {
+		switch d := dest[i].(type) {
+		case *int:
+			*d = v.(int)
+		case *float64:
+			*d = v.(float64)
+		case *string:
+			*d = v.(string)
+		case *time.Time:
+			*d = v.(time.Time)
+		case *sql.NullString:
+			if err := d.Scan(v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// TestScanDBActivity tests parsing pg_stat_activity rows, including NULL columns
+func TestScanDBActivity(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("EST", -5*60*60))
+
+	activity, err := scanDBActivity(fakeActivityRow{values: []interface{}{
+		4242, "postgres", "active", "Lock", "relation", start, 15234.5678, "SELECT * FROM paddles FOR UPDATE",
+	}})
+	if err != nil {
+		t.Fatalf("scanDBActivity() error: %v", err)
+	}
+	want := DBActivity{
+		PID: 4242, User: "postgres", State: "active", WaitEventType: "Lock", WaitEvent: "relation",
+		QueryStart: NewTime(start), DurationMS: 15234.6, Query: "SELECT * FROM paddles FOR UPDATE",
+	}
+	if *activity != want {
+		t.Errorf("scanDBActivity() = %+v, want %+v", *activity, want)
+	}
+	if activity.QueryStart.Location() != time.UTC {
+		t.Errorf("QueryStart location = %v, want UTC", activity.QueryStart.Location())
+	}
+
+	activity, err = scanDBActivity(fakeActivityRow{values: []interface{}{
+		7, nil, "idle in transaction", nil, nil, start, 2000.0, "UPDATE paddles SET brand = brand",
+	}})
+	if err != nil {
+		t.Fatalf("scanDBActivity() with NULLs error: %v", err)
+	}
+	if activity.User != "" || activity.WaitEventType != "" || activity.WaitEvent != "" {
+		t.Errorf("NULL columns = %+v, want empty strings", *activity)
+	}
+
+	if _, err := scanDBActivity(fakeActivityRow{err: errors.New("bad row")}); err == nil {
+		t.Error("scanDBActivity() should return the scan error")
+	}
+}
+
+// TestParseActivityMinMS tests the min_ms parameter
+func TestParseActivityMinMS(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    int
+		wantErr bool
+	}{
+		{"", defaultActivityMinMS, false},
+		{"0", 0, false},
+		{"5000", 5000, false},
+		{"-1", 0, true},
+		{"fast", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseActivityMinMS(tt.raw)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseActivityMinMS(%q) = %d, %v; want %d, error %v", tt.raw, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// TestCancelDBQueryInvalidPID tests that a malformed pid is rejected before querying
+func TestCancelDBQueryInvalidPID(t *testing.T) {
+	router := mux.NewRouter()
+	router.HandleFunc("/api/admin/db/cancel/{pid}", cancelDBQuery).Methods("POST")
+
+	for _, pid := range []string{"abc", "0", "-3"} {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/admin/db/cancel/"+pid, nil))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("pid %q returned %d, want %d", pid, rr.Code, http.StatusBadRequest)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+)
+
+// Defaults for query instrumentation, overridable via SLOW_QUERY_MS and QUERY_TIMEOUT_MS
+const (
+	defaultSlowQueryMS    = 200
+	defaultQueryTimeoutMS = 5000
+)
+
+var (
+	slowQueryThreshold = defaultSlowQueryMS * time.Millisecond
+	queryTimeout       = defaultQueryTimeoutMS * time.Millisecond
+)
+
+// dbExecutor is implemented by both *sql.DB and *sql.Tx so the instrumented
+// helpers work inside and outside transactions
+type dbExecutor interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// queryConfig holds the query instrumentation settings
+type queryConfig struct {
+	SlowQueryThreshold time.Duration
+	Timeout            time.Duration
+}
+
+// loadQueryConfig reads the slow query threshold and query timeout from the environment
+func loadQueryConfig() (queryConfig, error) {
+	slowMS, err := strconv.Atoi(getEnv("SLOW_QUERY_MS", strconv.Itoa(defaultSlowQueryMS)))
+	if err != nil || slowMS <= 0 {
+		return queryConfig{}, fmt.Errorf("SLOW_QUERY_MS must be a positive integer")
+	}
+
+	timeoutMS, err := strconv.Atoi(getEnv("QUERY_TIMEOUT_MS", strconv.Itoa(defaultQueryTimeoutMS)))
+	if err != nil || timeoutMS <= 0 {
+		return queryConfig{}, fmt.Errorf("QUERY_TIMEOUT_MS must be a positive integer")
+	}
+
+	return queryConfig{
+		SlowQueryThreshold: time.Duration(slowMS) * time.Millisecond,
+		Timeout:            time.Duration(timeoutMS) * time.Millisecond,
+	}, nil
+}
+
+// queryContext returns a context bounded by the configured query timeout
+func queryContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), queryTimeout)
+}
+
+// timedQuery runs QueryContext and logs the query if it is slow.
+// The duration covers executing the query, not iterating the rows.
+func timedQuery(ctx context.Context, db dbExecutor, label, query string, args ...interface{}) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := db.QueryContext(ctx, query, args...)
+	logIfSlow(label, time.Since(start))
+	return rows, err
+}
+
+// timedQueryRow runs QueryRowContext and logs the query if it is slow
+func timedQueryRow(ctx context.Context, db dbExecutor, label, query string, args ...interface{}) *sql.Row {
+	start := time.Now()
+	row := db.QueryRowContext(ctx, query, args...)
+	logIfSlow(label, time.Since(start))
+	return row
+}
+
+// timedExec runs ExecContext and logs the statement if it is slow
+func timedExec(ctx context.Context, db dbExecutor, label, query string, args ...interface{}) (sql.Result, error) {
+	start := time.Now()
+	result, err := db.ExecContext(ctx, query, args...)
+	logIfSlow(label, time.Since(start))
+	return result, err
+}
+
+// logIfSlow logs a warning when a query took longer than the slow query threshold
+func logIfSlow(label string, elapsed time.Duration) {
+	if elapsed >= slowQueryThreshold {
+		log.Printf("WARN: slow query %s took %v (threshold %v)", label, elapsed, slowQueryThreshold)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// slowExecutor is a dbExecutor that sleeps before returning, simulating a slow query
+type slowExecutor struct {
+	delay time.Duration
+}
+
+func (e slowExecutor) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	time.Sleep(e.delay)
+	return nil, nil
+}
+
+func (e slowExecutor) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	time.Sleep(e.delay)
+	return nil
+}
+
+func (e slowExecutor) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	time.Sleep(e.delay)
+	return nil, nil
+}
+
+// captureLog redirects the standard logger into a buffer for the duration of a test
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &buf
+}
+
+// TestSlowQueryLogging tests that queries over the threshold are logged and fast ones are not
+func TestSlowQueryLogging(t *testing.T) {
+	defer func() { slowQueryThreshold = defaultSlowQueryMS * time.Millisecond }()
+	slowQueryThreshold = 10 * time.Millisecond
+
+	buf := captureLog(t)
+	ctx := context.Background()
+
+	timedQuery(ctx, slowExecutor{delay: 20 * time.Millisecond}, "slow_select", "SELECT pg_sleep(1)")
+	if !strings.Contains(buf.String(), "WARN: slow query slow_select") {
+		t.Errorf("Expected slow query log for slow_select, got %q", buf.String())
+	}
+
+	buf.Reset()
+	timedExec(ctx, slowExecutor{}, "fast_update", "UPDATE paddles SET brand = brand")
+	if buf.Len() != 0 {
+		t.Errorf("Expected no log for a fast query, got %q", buf.String())
+	}
+}
+
+// TestLoadQueryConfig tests loading the query settings from the environment
+func TestLoadQueryConfig(t *testing.T) {
+	t.Setenv("SLOW_QUERY_MS", "50")
+	t.Setenv("QUERY_TIMEOUT_MS", "1000")
+	cfg, err := loadQueryConfig()
+	if err != nil {
+		t.Fatalf("loadQueryConfig() returned error: %v", err)
+	}
+	if cfg.SlowQueryThreshold != 50*time.Millisecond {
+		t.Errorf("SlowQueryThreshold = %v, want 50ms", cfg.SlowQueryThreshold)
+	}
+	if cfg.Timeout != time.Second {
+		t.Errorf("Timeout = %v, want 1s", cfg.Timeout)
+	}
+
+	t.Setenv("SLOW_QUERY_MS", "-5")
+	if _, err := loadQueryConfig(); err == nil {
+		t.Error("loadQueryConfig() should fail with a negative threshold")
+	}
+}
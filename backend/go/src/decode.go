@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Defaults for request body strictness, overridable via JSON_MAX_DEPTH,
+// JSON_ALLOW_DUPLICATE_KEYS and JSON_NUMERIC_STRINGS. No upload is nested
+// more than four levels deep.
+const defaultJSONMaxDepth = 10
+
+var (
+	jsonMaxDepth           = defaultJSONMaxDepth
+	jsonAllowDuplicateKeys = false
+	jsonNumericStrings     = true
+)
+
+// jsonConfig holds the request body strictness settings
+type jsonConfig struct {
+	MaxDepth           int
+	AllowDuplicateKeys bool
+	NumericStrings     bool
+}
+
+// loadJSONConfig reads the request body strictness settings from the environment
+func loadJSONConfig() (jsonConfig, error) {
+	depth, err := strconv.Atoi(getEnv("JSON_MAX_DEPTH", strconv.Itoa(defaultJSONMaxDepth)))
+	if err != nil || depth <= 0 {
+		return jsonConfig{}, fmt.Errorf("JSON_MAX_DEPTH must be a positive integer")
+	}
+
+	allowDuplicates, err := strconv.ParseBool(getEnv("JSON_ALLOW_DUPLICATE_KEYS", "false"))
+	if err != nil {
+		return jsonConfig{}, fmt.Errorf("JSON_ALLOW_DUPLICATE_KEYS must be true or false")
+	}
+
+	numericStrings, err := strconv.ParseBool(getEnv("JSON_NUMERIC_STRINGS", "true"))
+	if err != nil {
+		return jsonConfig{}, fmt.Errorf("JSON_NUMERIC_STRINGS must be true or false")
+	}
+
+	return jsonConfig{MaxDepth: depth, AllowDuplicateKeys: allowDuplicates, NumericStrings: numericStrings}, nil
+}
+
+// UnmarshalJSON decodes performance metrics and their stddevs given as JSON
+// numbers or, while JSON_NUMERIC_STRINGS is on, as numeric strings such as
+// "3000", which some form libraries send. Other strings are rejected.
+// Unknown fields are rejected as in the rest of an upload, since a custom
+// unmarshaler does not inherit the decoder's settings.
+func (p *Performance) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for key, raw := range fields {
+		if !jsonNumericStrings || len(raw) == 0 || raw[0] != '"' || !isPerformanceField(key) {
+			continue
+		}
+		var text string
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return err
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			return fmt.Errorf("performance %s must be a number, got %q", key, text)
+		}
+		fields[key] = json.RawMessage(strconv.FormatFloat(value, 'g', -1, 64))
+	}
+
+	normalized, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	// plain has Performance's fields without this method, to avoid recursing
+	type plain Performance
+	decoder := json.NewDecoder(bytes.NewReader(normalized))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode((*plain)(p))
+}
+
+// isPerformanceField reports whether key names a metric or stddev field,
+// matched case-insensitively like encoding/json matches field names
+func isPerformanceField(key string) bool {
+	for _, metric := range performanceMetrics {
+		if strings.EqualFold(key, metric) || strings.EqualFold(key, metric+"_stddev") {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonFrame tracks one open object or array while walking tokens
+type jsonFrame struct {
+	object    bool
+	expectKey bool
+	keys      map[string]bool
+}
+
+// checkJSONStructure walks the tokens of a JSON document and rejects nesting
+// deeper than maxDepth and, unless allowDuplicates is set, objects that repeat
+// a key. encoding/json would otherwise silently keep the last duplicate.
+func checkJSONStructure(data []byte, maxDepth int, allowDuplicates bool) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var stack []*jsonFrame
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var parent *jsonFrame
+		if len(stack) > 0 {
+			parent = stack[len(stack)-1]
+		}
+
+		// Inside an object, tokens alternate between keys and values
+		if parent != nil && parent.object && parent.expectKey {
+			if key, ok := tok.(string); ok {
+				if parent.keys[key] && !allowDuplicates {
+					return fmt.Errorf("duplicate key %q", key)
+				}
+				parent.keys[key] = true
+				parent.expectKey = false
+				continue
+			}
+		}
+
+		delim, isDelim := tok.(json.Delim)
+		if isDelim && (delim == '}' || delim == ']') {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		// Any other token starts a value, after which the parent object expects a key
+		if parent != nil && parent.object {
+			parent.expectKey = true
+		}
+
+		if isDelim {
+			if len(stack) >= maxDepth {
+				return fmt.Errorf("JSON is nested more than %d levels deep", maxDepth)
+			}
+			frame := &jsonFrame{object: delim == '{', expectKey: delim == '{'}
+			if frame.object {
+				frame.keys = map[string]bool{}
+			}
+			stack = append(stack, frame)
+		}
+	}
+}
+
+// decodeJSONBody reads a request body into v after checking its structure.
+// Unknown fields are rejected, as in every upload.
+func decodeJSONBody(body io.Reader, v interface{}) error {
+	data, err := readJSONBody(body)
+	if err != nil {
+		return err
+	}
+	return decodeJSONData(data, v)
+}
+
+// readJSONBody reads a request body and checks its structure
+func readJSONBody(body io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkJSONStructure(data, jsonMaxDepth, jsonAllowDuplicateKeys); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// decodeJSONData decodes a checked body into v, rejecting unknown fields
+func decodeJSONData(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
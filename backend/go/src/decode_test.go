@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestCheckJSONStructure tests duplicate key and nesting depth detection
+func TestCheckJSONStructure(t *testing.T) {
+	tests := []struct {
+		name            string
+		body            string
+		maxDepth        int
+		allowDuplicates bool
+		wantErr         string
+	}{
+		{name: "Flat object", body: `{"power": 75, "pop": 70}`, maxDepth: 10},
+		{name: "Duplicate key", body: `{"power": 75, "power": 80}`, maxDepth: 10, wantErr: `duplicate key "power"`},
+		{name: "Duplicate nested key", body: `{"performance": {"power": 75, "pop": 70, "power": 80}}`, maxDepth: 10, wantErr: `duplicate key "power"`},
+		{name: "Same key in sibling objects", body: `{"a": {"min": 1}, "b": {"min": 2}}`, maxDepth: 10},
+		{name: "Same key in array elements", body: `[{"brand": "Engage"}, {"brand": "Selkirk"}]`, maxDepth: 10},
+		{name: "Key after nested value", body: `{"a": {"x": [1, {"y": 2}]}, "a": 1}`, maxDepth: 10, wantErr: `duplicate key "a"`},
+		{name: "String value equal to a key", body: `{"brand": "brand", "model": "brand"}`, maxDepth: 10},
+		{name: "Duplicates allowed", body: `{"power": 75, "power": 80}`, maxDepth: 10, allowDuplicates: true},
+		{name: "At the depth limit", body: `{"a": {"b": [1]}}`, maxDepth: 3},
+		{name: "Past the depth limit", body: `{"a": {"b": [[1]]}}`, maxDepth: 3, wantErr: "more than 3 levels"},
+		{name: "Malformed", body: `{"power" 75}`, maxDepth: 10, wantErr: "after object key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkJSONStructure([]byte(tt.body), tt.maxDepth, tt.allowDuplicates)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkJSONStructure() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("checkJSONStructure() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestUploadPaddleDuplicateKey tests that an upload with a duplicated power key is rejected
+func TestUploadPaddleDuplicateKey(t *testing.T) {
+	setupTestStore(t)
+
+	body := `{
+		"metadata": {"brand": "Engage", "model": "Pursuit MX 6.0"},
+		"specs": {"shape": "Hybrid", "surface": "Composite", "average_weight": 220.0, "core": 15.0,
+			"paddle_length": 16.5, "paddle_width": 7.5, "grip_length": 4.5, "grip_type": "Comfort", "grip_circumference": 4.0},
+		"performance": {"power": 75.0, "pop": 70.0, "spin": 3000.0, "twist_weight": 200.0,
+			"swing_weight": 220.0, "balance_point": 30.0, "power": 99.0}
+	}`
+
+	rr := httptest.NewRecorder()
+	uploadPaddleStats(rr, httptest.NewRequest("POST", "/api/paddles", bytes.NewBufferString(body)))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d: %s", rr.Code, http.StatusBadRequest, rr.Body.String())
+	}
+	if !strings.Contains(rr.Body.String(), `duplicate key \"power\"`) {
+		t.Errorf("error does not name the duplicate key: %s", rr.Body.String())
+	}
+}
+
+// TestPerformanceNumericStrings tests that metrics decode from numbers and
+// numeric strings, and that other strings and unknown fields are rejected
+func TestPerformanceNumericStrings(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    float64
+		wantErr string
+	}{
+		{name: "Number", body: `{"spin": 3000}`, want: 3000},
+		{name: "Numeric string", body: `{"spin": "3000"}`, want: 3000},
+		{name: "Padded exponent", body: `{"spin": " 3e3 "}`, want: 3000},
+		{name: "Not a number", body: `{"spin": "fast"}`, wantErr: `performance spin must be a number, got "fast"`},
+		{name: "Infinite", body: `{"spin": "Inf"}`, wantErr: "performance spin must be a number"},
+		{name: "Unknown field", body: `{"spin": 3000, "speed": "1"}`, wantErr: `unknown field "speed"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var performance Performance
+			err := decodeJSONData([]byte(tt.body), &performance)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("decode(%s) error = %v, want %q", tt.body, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decode(%s) error: %v", tt.body, err)
+			}
+			if performance.Spin != tt.want {
+				t.Errorf("spin = %g, want %g", performance.Spin, tt.want)
+			}
+		})
+	}
+
+	var performance Performance
+	if err := decodeJSONData([]byte(`{"power_stddev": "2.5"}`), &performance); err != nil || performance.PowerStddev == nil || *performance.PowerStddev != 2.5 {
+		t.Errorf("power_stddev = %v, %v; want 2.5", performance.PowerStddev, err)
+	}
+
+	jsonNumericStrings = false
+	defer func() { jsonNumericStrings = true }()
+	if err := decodeJSONData([]byte(`{"spin": "3000"}`), &performance); err == nil {
+		t.Error("decode accepted a numeric string with JSON_NUMERIC_STRINGS off")
+	}
+}
+
+// TestUploadPaddleNumericStrings tests that an upload with string metrics is
+// stored and returned with numbers
+func TestUploadPaddleNumericStrings(t *testing.T) {
+	setupTestStore(t)
+
+	body := `{
+		"metadata": {"brand": "Engage", "model": "Pursuit MX 6.0"},
+		"specs": {"shape": "Hybrid", "surface": "Composite", "average_weight": 220.0, "core": 15.0,
+			"paddle_length": 16.5, "paddle_width": 7.5, "grip_length": 4.5, "grip_type": "Comfort", "grip_circumference": 4.0},
+		"performance": {"power": "75", "pop": 70.0, "spin": "3000", "twist_weight": 200.0,
+			"swing_weight": 220.0, "balance_point": 30.0}
+	}`
+
+	rr := httptest.NewRecorder()
+	uploadPaddleStats(rr, httptest.NewRequest("POST", "/api/paddles", bytes.NewBufferString(body)))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", rr.Code, http.StatusCreated, rr.Body.String())
+	}
+	if !strings.Contains(rr.Body.String(), `"spin":3000,`) || !strings.Contains(rr.Body.String(), `"power":75,`) {
+		t.Errorf("response does not write the metrics as numbers: %s", rr.Body.String())
+	}
+}
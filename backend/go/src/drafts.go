@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// parsePaddleStatus parses a status filter value, case-insensitively
+func parsePaddleStatus(raw string) (PaddleStatus, error) {
+	switch status := PaddleStatus(strings.ToLower(strings.TrimSpace(raw))); status {
+	case StatusDraft, StatusPublished:
+		return status, nil
+	default:
+		return "", fmt.Errorf("status must be %q or %q", StatusDraft, StatusPublished)
+	}
+}
+
+// PublishPaddle makes a draft paddle public and reports whether it was a
+// draft. Publishing a paddle that is already published leaves it untouched.
+func PublishPaddle(paddleId string) (bool, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	var exists, published bool
+	err := timedQueryRow(ctx, DB, "publish_paddle", `
+		WITH published AS (
+			UPDATE paddles
+			SET status = 'published', updated_at = CURRENT_TIMESTAMP
+			WHERE paddle_id = $1 AND status <> 'published'
+			RETURNING id
+		)
+		SELECT EXISTS (SELECT 1 FROM paddles WHERE paddle_id = $1), EXISTS (SELECT 1 FROM published)
+	`, paddleId).Scan(&exists, &published)
+	if err != nil {
+		return false, err
+	}
+	if !exists {
+		return false, ErrPaddleNotFound
+	}
+	return published, nil
+}
+
+// publishPaddle handles the API request for publishing a draft paddle
+func publishPaddle(w http.ResponseWriter, r *http.Request) {
+	paddleId := paddleIDFromRequest(r)
+
+	if err := validatePaddleID(paddleId); err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid paddle ID: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if _, err := store.PublishPaddle(paddleId); err != nil {
+		if errors.Is(err, ErrPaddleNotFound) {
+			respondWithError(w, "Paddle not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Error publishing paddle: %v", err)
+		respondWithError(w, "Failed to publish paddle", http.StatusInternalServerError)
+		return
+	}
+
+	response := struct {
+		ID     string       `json:"id"`
+		Status PaddleStatus `json:"status"`
+	}{
+		ID:     paddleId,
+		Status: StatusPublished,
+	}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding publish response: %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// TestDraftPaddles tests that drafts are hidden from the public list until
+// they are published, and that only admins can create or list them
+func TestDraftPaddles(t *testing.T) {
+	setupTestStore(t)
+	apiKey = "test-key"
+	defer func() { apiKey = "" }()
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/paddles", uploadPaddleStats).Methods("POST")
+	router.HandleFunc("/api/paddles", getPaddlesList).Methods("GET")
+	router.HandleFunc("/api/paddles/{id}/publish", requireAPIKey(publishPaddle)).Methods("POST")
+
+	serve := func(method, target string, body []byte, admin bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, bytes.NewBuffer(body))
+		if admin {
+			req.Header.Set(apiKeyHeader, apiKey)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	listIDs := func(target string, admin bool) []string {
+		t.Helper()
+		rr := serve("GET", target, nil, admin)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("GET %s returned %d: %s", target, rr.Code, rr.Body.String())
+		}
+		var paddles []struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(rr.Body.Bytes(), &paddles); err != nil {
+			t.Fatalf("Failed to decode list: %v", err)
+		}
+		ids := []string{}
+		for _, p := range paddles {
+			ids = append(ids, p.ID)
+		}
+		return ids
+	}
+
+	input := func(model string) []byte {
+		body, _ := json.Marshal(testPaddleInput("Engage", model))
+		return body
+	}
+
+	if rr := serve("POST", "/api/paddles", input("Pursuit MX 6.0"), false); rr.Code != http.StatusCreated {
+		t.Fatalf("Public upload returned %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// Only admins may stage drafts
+	if rr := serve("POST", "/api/paddles?draft=true", input("Pursuit Pro"), false); rr.Code != http.StatusUnauthorized {
+		t.Errorf("Draft upload without the API key returned %d, want %d", rr.Code, http.StatusUnauthorized)
+	}
+	rr := serve("POST", "/api/paddles?draft=true", input("Pursuit Pro"), true)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Admin draft upload returned %d: %s", rr.Code, rr.Body.String())
+	}
+	var created Paddle
+	json.Unmarshal(rr.Body.Bytes(), &created)
+	if created.Status != StatusDraft {
+		t.Errorf("Created paddle status = %q, want %q", created.Status, StatusDraft)
+	}
+
+	if ids := listIDs("/api/paddles", false); len(ids) != 1 || ids[0] != "engage-pursuit-mx-6.0" {
+		t.Errorf("Public list = %v, want only the published paddle", ids)
+	}
+	if rr := serve("GET", "/api/paddles?status=draft", nil, false); rr.Code != http.StatusUnauthorized {
+		t.Errorf("Listing drafts without the API key returned %d, want %d", rr.Code, http.StatusUnauthorized)
+	}
+	if ids := listIDs("/api/paddles?status=draft", true); len(ids) != 1 || ids[0] != "engage-pursuit-pro" {
+		t.Errorf("Draft list = %v, want only the draft", ids)
+	}
+
+	if rr := serve("POST", "/api/paddles/engage-pursuit-pro/publish", nil, true); rr.Code != http.StatusOK {
+		t.Fatalf("Publish returned %d: %s", rr.Code, rr.Body.String())
+	}
+	if ids := listIDs("/api/paddles", false); len(ids) != 2 {
+		t.Errorf("Public list after publishing = %v, want both paddles", ids)
+	}
+	if rr := serve("POST", "/api/paddles/engage-missing/publish", nil, true); rr.Code != http.StatusNotFound {
+		t.Errorf("Publishing a missing paddle returned %d, want %d", rr.Code, http.StatusNotFound)
+	}
+}
+
+// TestParsePaddleStatus tests parsing of the status filter
+func TestParsePaddleStatus(t *testing.T) {
+	if status, err := parsePaddleStatus(" Draft "); err != nil || status != StatusDraft {
+		t.Errorf("parsePaddleStatus(Draft) = %q, %v, want draft", status, err)
+	}
+	if _, err := parsePaddleStatus("archived"); err == nil {
+		t.Error("Expected an error for an unknown status")
+	}
+}
+
+// seedDraftAndPublished saves a published paddle and a draft with a brand of
+// its own and out-of-range power, so either shows up in an aggregate
+func seedDraftAndPublished(t *testing.T) (published, draft *Paddle) {
+	t.Helper()
+	published = saveTestPaddle(t, testPaddleInput("Engage", "Aggregate Published"))
+
+	input := testPaddleInput("Draftbrand", "Aggregate Draft")
+	input.Performance.Power = 99
+	draft = input.ToPaddle()
+	draft.Status = StatusDraft
+	if _, err := store.SavePaddle(draft); err != nil {
+		t.Fatalf("Failed to save draft: %v", err)
+	}
+	return published, draft
+}
+
+// checkDraftAggregates tests the aggregates every store computes
+func checkDraftAggregates(t *testing.T, draft *Paddle) {
+	t.Helper()
+
+	counts, err := store.GetBrandCounts()
+	if err != nil {
+		t.Fatalf("GetBrandCounts() error: %v", err)
+	}
+	for _, count := range counts {
+		if count.Brand == draft.Metadata.Brand {
+			t.Errorf("brand counts include the draft's brand: %+v", count)
+		}
+	}
+
+	stats, err := store.GetDatasetStats()
+	if err != nil {
+		t.Fatalf("GetDatasetStats() error: %v", err)
+	}
+	if stats.Fields["power"].Max >= draft.Performance.Power {
+		t.Errorf("dataset stats max power = %v, which includes the draft", stats.Fields["power"].Max)
+	}
+}
+
+// TestDraftsHiddenFromAggregates tests that drafts are left out of the
+// brand counts and dataset stats
+func TestDraftsHiddenFromAggregates(t *testing.T) {
+	setupTestStore(t)
+	_, draft := seedDraftAndPublished(t)
+	checkDraftAggregates(t, draft)
+}
+
+// TestDraftsHiddenFromAggregatesPostgres tests that drafts are left out of
+// every public aggregate the Postgres store computes
+func TestDraftsHiddenFromAggregatesPostgres(t *testing.T) {
+	setupTestDB(t)
+	published, draft := seedDraftAndPublished(t)
+	checkDraftAggregates(t, draft)
+
+	values, err := GetMetricValues("power")
+	if err != nil {
+		t.Fatalf("GetMetricValues() error: %v", err)
+	}
+	if slices.Contains(values, draft.Performance.Power) {
+		t.Errorf("histogram values %v include the draft's power", values)
+	}
+
+	years, unknown, err := GetPaddleCountsByYear()
+	if err != nil {
+		t.Fatalf("GetPaddleCountsByYear() error: %v", err)
+	}
+	total := unknown
+	for _, year := range years {
+		total += year.Count
+	}
+	if total != 1 {
+		t.Errorf("counts by year cover %d paddles, want only the published one", total)
+	}
+
+	xs, _, err := GetMetricPairs("power", "spin")
+	if err != nil {
+		t.Fatalf("GetMetricPairs() error: %v", err)
+	}
+	if slices.Contains(xs, draft.Performance.Power) {
+		t.Errorf("correlation values %v include the draft's power", xs)
+	}
+
+	candidates, err := GetRankingCandidates(paddleFilter{Status: StatusPublished})
+	if err != nil {
+		t.Fatalf("GetRankingCandidates() error: %v", err)
+	}
+	var ids []string
+	for _, paddle := range candidates {
+		ids = append(ids, paddle.ID)
+	}
+	if slices.Contains(ids, draft.ID) || !slices.Contains(ids, published.ID) {
+		t.Errorf("value ranking candidates = %v, want %s and not the draft %s", ids, published.ID, draft.ID)
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// drainer tracks in-flight requests and whether the instance is draining
+// ahead of a blue-green switch-over. mu orders admitting a request against
+// starting the drain, so no request is added to inFlight once Wait may run.
+type drainer struct {
+	mu       sync.Mutex
+	draining atomic.Bool
+	inFlight sync.WaitGroup
+	active   atomic.Int64
+}
+
+// serverDrainer is the drain state of this instance
+var serverDrainer = &drainer{}
+
+// buildVersion identifies the deployed build; set it at build time with
+// -ldflags "-X main.buildVersion=<version>"
+var buildVersion = "dev"
+
+// probePaths are always served, even while draining, so the orchestrator can
+// observe the instance
+var probePaths = map[string]bool{
+	"/healthz":        true,
+	"/healthz/detail": true,
+	"/readyz":         true,
+}
+
+// middleware counts in-flight requests and refuses new ones with 503 once draining
+func (d *drainer) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if probePaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		d.mu.Lock()
+		if d.draining.Load() {
+			d.mu.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Connection", "close")
+			respondWithError(w, "Server is draining", http.StatusServiceUnavailable)
+			return
+		}
+		d.inFlight.Add(1)
+		d.mu.Unlock()
+
+		d.active.Add(1)
+		defer func() {
+			d.active.Add(-1)
+			d.inFlight.Done()
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// start puts the instance into draining mode and logs once all in-flight
+// requests have finished. It returns false if draining had already started.
+func (d *drainer) start() bool {
+	d.mu.Lock()
+	started := d.draining.CompareAndSwap(false, true)
+	d.mu.Unlock()
+	if !started {
+		return false
+	}
+
+	go func() {
+		d.inFlight.Wait()
+		log.Println("Drain complete: no requests in flight")
+	}()
+
+	return true
+}
+
+// healthz is the liveness probe; it reports ok as long as the process is serving
+func healthz(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+// healthzDetail reports the build version and schema version alongside the
+// liveness status, so ops can spot an instance running against a half-migrated
+// database. The schema version is "unknown" if it cannot be read.
+func healthzDetail(w http.ResponseWriter, r *http.Request) {
+	schemaVersion := "unknown"
+	version, ok, err := GetSchemaVersion()
+	if err != nil {
+		log.Printf("Error reading schema version: %v", err)
+	} else if ok {
+		schemaVersion = strconv.Itoa(version)
+	}
+
+	json.NewEncoder(w).Encode(struct {
+		Status                string `json:"status"`
+		BuildVersion          string `json:"build_version"`
+		SchemaVersion         string `json:"schema_version"`
+		ExpectedSchemaVersion string `json:"expected_schema_version"`
+	}{
+		Status:                "ok",
+		BuildVersion:          buildVersion,
+		SchemaVersion:         schemaVersion,
+		ExpectedSchemaVersion: strconv.Itoa(latestMigrationVersion()),
+	})
+}
+
+// readyz is the readiness probe; it fails while draining or when the database is unreachable
+func readyz(w http.ResponseWriter, r *http.Request) {
+	if serverDrainer.draining.Load() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"status": "draining"})
+		return
+	}
+
+	// A nil *sql.DB must not become a non-nil pinger
+	var db pinger
+	if DB != nil {
+		db = DB
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), healthPingTimeout)
+	defer cancel()
+	if reason := pingDatabase(ctx, db, healthPingRetries); reason != "" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"status": "database unavailable", "reason": reason})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"status": "ready"})
+}
+
+// drainServer handles the admin request for draining this instance before shutdown.
+// The process keeps running; it only stops accepting new requests.
+func drainServer(w http.ResponseWriter, r *http.Request) {
+	started := serverDrainer.start()
+	if started {
+		log.Println("Draining: refusing new requests")
+	}
+
+	// The drain request itself is counted as in flight
+	inFlight := serverDrainer.active.Load() - 1
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(struct {
+		Status   string `json:"status"`
+		InFlight int64  `json:"in_flight"`
+	}{
+		Status:   "draining",
+		InFlight: inFlight,
+	})
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestDrain tests that draining fails readiness and new requests while in-flight requests finish
+func TestDrain(t *testing.T) {
+	previous := serverDrainer
+	serverDrainer = &drainer{}
+	defer func() { serverDrainer = previous }()
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("/slow", slow)
+	mux.HandleFunc("/fast", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	mux.HandleFunc("/readyz", readyz)
+	mux.HandleFunc("/drain", drainServer)
+	handler := serverDrainer.middleware(mux)
+
+	// Start a request that stays in flight until released
+	inFlight := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		handler.ServeHTTP(inFlight, httptest.NewRequest("GET", "/slow", nil))
+		close(done)
+	}()
+	<-started
+
+	drain := httptest.NewRecorder()
+	handler.ServeHTTP(drain, httptest.NewRequest("POST", "/drain", nil))
+	if drain.Code != http.StatusAccepted {
+		t.Fatalf("Drain returned status %d, want %d", drain.Code, http.StatusAccepted)
+	}
+
+	ready := httptest.NewRecorder()
+	handler.ServeHTTP(ready, httptest.NewRequest("GET", "/readyz", nil))
+	if ready.Code != http.StatusServiceUnavailable {
+		t.Errorf("/readyz returned status %d after draining, want %d", ready.Code, http.StatusServiceUnavailable)
+	}
+
+	refused := httptest.NewRecorder()
+	handler.ServeHTTP(refused, httptest.NewRequest("GET", "/fast", nil))
+	if refused.Code != http.StatusServiceUnavailable {
+		t.Errorf("New request returned status %d after draining, want %d", refused.Code, http.StatusServiceUnavailable)
+	}
+
+	close(release)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("In-flight request did not finish")
+	}
+	if inFlight.Code != http.StatusOK {
+		t.Errorf("In-flight request returned status %d, want %d", inFlight.Code, http.StatusOK)
+	}
+}
+
+// TestDrainConcurrentRequests tests starting a drain while requests arrive:
+// each request is either served or refused, and the drain completes once
+// the served ones finish
+func TestDrainConcurrentRequests(t *testing.T) {
+	d := &drainer{}
+	handler := d.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest("GET", "/fast", nil))
+			if rr.Code != http.StatusOK && rr.Code != http.StatusServiceUnavailable {
+				t.Errorf("request returned status %d", rr.Code)
+			}
+		}()
+		if i == 25 {
+			d.start()
+		}
+	}
+	wg.Wait()
+
+	done := make(chan struct{})
+	go func() {
+		d.inFlight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("drain did not complete")
+	}
+}
+
+// healthDetail decodes the /healthz/detail response
+func healthDetail(t *testing.T) map[string]string {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	healthzDetail(rr, httptest.NewRequest("GET", "/healthz/detail", nil))
+
+	var body map[string]string
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	return body
+}
+
+// TestHealthzDetailWithoutDatabase tests that the schema version is unknown without a database
+func TestHealthzDetailWithoutDatabase(t *testing.T) {
+	previous := DB
+	DB = nil
+	defer func() { DB = previous }()
+
+	body := healthDetail(t)
+	if body["schema_version"] != "unknown" {
+		t.Errorf("schema_version = %q, want unknown", body["schema_version"])
+	}
+	if body["build_version"] != buildVersion || body["status"] != "ok" {
+		t.Errorf("Unexpected health detail: %v", body)
+	}
+}
+
+// TestHealthzDetailSchemaVersion tests that the reported version matches after migrations run
+func TestHealthzDetailSchemaVersion(t *testing.T) {
+	setupTestDB(t)
+
+	body := healthDetail(t)
+	want := strconv.Itoa(latestMigrationVersion())
+	if body["schema_version"] != want || body["expected_schema_version"] != want {
+		t.Errorf("Health detail = %v, want schema_version and expected_schema_version %s", body, want)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dumpTables are exported in foreign-key-safe order: parents before children
+var dumpTables = []string{"paddles", "paddle_specs", "paddle_performance", "paddle_history", "paddle_spec_ranges", "paddle_tags"}
+
+// WriteSQLDump writes INSERT statements reproducing every paddle table to w.
+// The output is a single transaction that also resets the id sequences.
+func WriteSQLDump(w io.Writer) error {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	buf := bufio.NewWriter(w)
+
+	fmt.Fprintf(buf, "-- go-pickleball data dump generated %s\n", time.Now().UTC().Format(time.RFC3339))
+	fmt.Fprintln(buf, "SET standard_conforming_strings = on;")
+	fmt.Fprintln(buf, "BEGIN;")
+
+	for _, table := range dumpTables {
+		// Table names come from the fixed dumpTables list, never from user input
+		rows, err := timedQuery(ctx, DB, "dump_"+table, "SELECT * FROM "+table+" ORDER BY id")
+		if err != nil {
+			return fmt.Errorf("failed to query %s: %w", table, err)
+		}
+
+		columns, err := rows.Columns()
+		if err != nil {
+			rows.Close()
+			return err
+		}
+
+		fmt.Fprintf(buf, "\n-- %s\n", table)
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+
+		for rows.Next() {
+			if err := rows.Scan(pointers...); err != nil {
+				rows.Close()
+				return err
+			}
+
+			literals := make([]string, len(values))
+			for i, value := range values {
+				literals[i] = sqlLiteral(value)
+			}
+
+			fmt.Fprintf(buf, "INSERT INTO %s (%s) VALUES (%s);\n",
+				table, strings.Join(columns, ", "), strings.Join(literals, ", "))
+		}
+
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(buf, "SELECT setval(pg_get_serial_sequence('%s', 'id'), COALESCE((SELECT MAX(id) FROM %s), 0) + 1, false);\n", table, table)
+	}
+
+	fmt.Fprintln(buf, "\nCOMMIT;")
+	return buf.Flush()
+}
+
+// sqlLiteral formats a scanned database value as a SQL literal
+func sqlLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return sqlQuote(v.Format("2006-01-02 15:04:05.999999"))
+	case []byte:
+		return sqlQuote(string(v))
+	case string:
+		return sqlQuote(v)
+	default:
+		return sqlQuote(fmt.Sprint(v))
+	}
+}
+
+// sqlQuote quotes a string as a SQL literal by doubling embedded single quotes.
+// This is safe with standard_conforming_strings on, which the dump sets.
+func sqlQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// TestSQLLiteral tests formatting of scanned values as SQL literals
+func TestSQLLiteral(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{name: "Nil", value: nil, want: "NULL"},
+		{name: "Integer", value: int64(42), want: "42"},
+		{name: "Float", value: 220.5, want: "220.5"},
+		{name: "String", value: "Engage", want: "'Engage'"},
+		{name: "Bytes", value: []byte("Hybrid"), want: "'Hybrid'"},
+		{name: "Embedded quote", value: "Rock'n Roll", want: "'Rock''n Roll'"},
+		{name: "Injection attempt", value: "x'); DROP TABLE paddles; --", want: "'x''); DROP TABLE paddles; --'"},
+		{name: "Backslash", value: `C:\paddles\`, want: `'C:\paddles\'`},
+		{name: "Time", value: time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC), want: "'2024-03-01 12:30:00'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sqlLiteral(tt.value); got != tt.want {
+				t.Errorf("sqlLiteral(%v) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSQLDumpRoundTrip tests that replaying the dump on emptied tables reproduces the rows.
+// The replay runs in a transaction that is rolled back.
+func TestSQLDumpRoundTrip(t *testing.T) {
+	setupTestDB(t)
+
+	paddle := (&PaddleInput{
+		Metadata: Metadata{
+			Brand: "O'Brien",
+			Model: "Dump'); DROP TABLE paddles; --",
+		},
+		Specs: Specs{
+			Shape: Hybrid, Surface: "Carbon", AverageWeight: 220.25, Core: 16, PaddleLength: 16.5,
+			PaddleWidth: 7.5, GripLength: 5.25, GripType: "Standard", GripCircumference: 4.25,
+		},
+		Performance: Performance{
+			Power: 75, Pop: 70, Spin: 2100, TwistWeight: 6.5, SwingWeight: 115, BalancePoint: 23.5,
+		},
+	}).ToPaddle()
+	if _, err := SavePaddle(paddle); err != nil {
+		t.Fatalf("Failed to save test paddle: %v", err)
+	}
+
+	before, err := snapshotTables()
+	if err != nil {
+		t.Fatalf("Failed to snapshot tables: %v", err)
+	}
+
+	var dump bytes.Buffer
+	if err := WriteSQLDump(&dump); err != nil {
+		t.Fatalf("WriteSQLDump() returned error: %v", err)
+	}
+
+	// Pin a single connection so the replay and rollback share a session
+	conn, err := DB.Conn(t.Context())
+	if err != nil {
+		t.Fatalf("Failed to get connection: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(t.Context(), "BEGIN; TRUNCATE paddle_tags, paddle_spec_ranges, paddle_history, paddle_performance, paddle_specs, paddles"); err != nil {
+		t.Fatalf("Failed to truncate tables: %v", err)
+	}
+	defer conn.ExecContext(t.Context(), "ROLLBACK")
+
+	// Strip the dump's own BEGIN/COMMIT so it replays inside the outer transaction
+	replay := bytes.Replace(dump.Bytes(), []byte("BEGIN;"), nil, 1)
+	replay = bytes.Replace(replay, []byte("COMMIT;"), nil, 1)
+	if _, err := conn.ExecContext(t.Context(), string(replay)); err != nil {
+		t.Fatalf("Failed to replay dump: %v", err)
+	}
+
+	var after string
+	if err := conn.QueryRowContext(t.Context(), tableSnapshotQuery).Scan(&after); err != nil {
+		t.Fatalf("Failed to snapshot replayed tables: %v", err)
+	}
+
+	if after != before {
+		t.Errorf("Replayed tables differ from the original:\nbefore: %s\nafter:  %s", before, after)
+	}
+}
+
+// tableSnapshotQuery renders every row of the paddle tables as one string for comparison
+const tableSnapshotQuery = `
+	SELECT
+		(SELECT COALESCE(string_agg(t::text, '|' ORDER BY id), '') FROM paddles t) || '#' ||
+		(SELECT COALESCE(string_agg(t::text, '|' ORDER BY id), '') FROM paddle_specs t) || '#' ||
+		(SELECT COALESCE(string_agg(t::text, '|' ORDER BY id), '') FROM paddle_performance t) || '#' ||
+		(SELECT COALESCE(string_agg(t::text, '|' ORDER BY id), '') FROM paddle_history t) || '#' ||
+		(SELECT COALESCE(string_agg(t::text, '|' ORDER BY id), '') FROM paddle_spec_ranges t) || '#' ||
+		(SELECT COALESCE(string_agg(t::text, '|' ORDER BY id), '') FROM paddle_tags t)
+`
+
+// snapshotTables returns the current contents of the paddle tables
+func snapshotTables() (string, error) {
+	var snapshot string
+	err := DB.QueryRow(tableSnapshotQuery).Scan(&snapshot)
+	return snapshot, err
+}
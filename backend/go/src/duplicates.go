@@ -0,0 +1,307 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// defaultDuplicateThreshold is the minimum similarity for two paddles to be
+// reported as likely duplicates, overridable via DUPLICATE_THRESHOLD
+const defaultDuplicateThreshold = 0.85
+
+var duplicateThreshold = defaultDuplicateThreshold
+
+// defaultDuplicateSpecTolerance is the relative difference within which two
+// numeric specs count as equal, overridable via DUPLICATE_SPEC_TOLERANCE.
+// 220.0 g and 220.01 g are the same paddle weighed twice.
+const defaultDuplicateSpecTolerance = 0.01
+
+var duplicateSpecTolerance = defaultDuplicateSpecTolerance
+
+// DuplicateMatch is an existing paddle that closely resembles a candidate
+type DuplicateMatch struct {
+	ID    string  `json:"id"`
+	Brand string  `json:"brand"`
+	Model string  `json:"model"`
+	Score float64 `json:"score"`
+}
+
+// duplicateConfig holds the duplicate detection settings
+type duplicateConfig struct {
+	Threshold     float64
+	SpecTolerance float64
+}
+
+// loadDuplicateConfig reads the duplicate similarity threshold and spec
+// tolerance from the environment
+func loadDuplicateConfig() (duplicateConfig, error) {
+	threshold, err := parseThreshold(getEnv("DUPLICATE_THRESHOLD", strconv.FormatFloat(defaultDuplicateThreshold, 'f', -1, 64)))
+	if err != nil {
+		return duplicateConfig{}, fmt.Errorf("invalid DUPLICATE_THRESHOLD: %w", err)
+	}
+
+	tolerance, err := strconv.ParseFloat(getEnv("DUPLICATE_SPEC_TOLERANCE", strconv.FormatFloat(defaultDuplicateSpecTolerance, 'f', -1, 64)), 64)
+	if err != nil || tolerance < 0 || tolerance >= 1 {
+		return duplicateConfig{}, fmt.Errorf("DUPLICATE_SPEC_TOLERANCE must be a number from 0 up to but not including 1")
+	}
+
+	return duplicateConfig{Threshold: threshold, SpecTolerance: tolerance}, nil
+}
+
+// parseThreshold parses a similarity threshold, which must be in (0, 1]
+func parseThreshold(raw string) (float64, error) {
+	threshold, err := strconv.ParseFloat(raw, 64)
+	if err != nil || threshold <= 0 || threshold > 1 {
+		return 0, fmt.Errorf("threshold must be a number greater than 0 and at most 1")
+	}
+	return threshold, nil
+}
+
+// normalizeForComparison lowercases a name, replaces punctuation other than
+// dots (as in "6.0") with spaces and collapses whitespace
+func normalizeForComparison(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), r == '.':
+			b.WriteRune(r)
+		default:
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// approximatelyEqual reports whether a and b differ by at most tol relative
+// to the larger of them
+func approximatelyEqual(a, b, tol float64) bool {
+	return a == b || math.Abs(a-b) <= tol*max(math.Abs(a), math.Abs(b))
+}
+
+// numericSpecFields are the numeric specs that duplicate detection compares,
+// by their JSON names
+var numericSpecFields = []string{"average_weight", "core", "paddle_length", "paddle_width", "grip_length", "grip_circumference"}
+
+// numericSpecs returns the numeric specs keyed by numericSpecFields. Core is
+// in millimetres, as stored.
+func numericSpecs(s Specs) map[string]float64 {
+	return map[string]float64{
+		"average_weight":     s.AverageWeight,
+		"core":               s.Core,
+		"paddle_length":      s.PaddleLength,
+		"paddle_width":       s.PaddleWidth,
+		"grip_length":        s.GripLength,
+		"grip_circumference": s.GripCircumference,
+	}
+}
+
+// numericSpecsWithin reports whether every spec in want is within the
+// relative tolerance tol of the paddle's. Specs missing from want are not compared.
+func numericSpecsWithin(specs Specs, want map[string]float64, tol float64) bool {
+	have := numericSpecs(specs)
+	for field, value := range want {
+		if !approximatelyEqual(have[field], value, tol) {
+			return false
+		}
+	}
+	return true
+}
+
+// specsApproximatelyEqual reports whether two paddles have the same specs:
+// every numeric spec within the relative tolerance tol, and every text spec
+// equal once normalized like names are
+func specsApproximatelyEqual(a, b Specs, tol float64) bool {
+	if !numericSpecsWithin(a, numericSpecs(b), tol) {
+		return false
+	}
+
+	texts := [][2]string{
+		{string(a.Shape), string(b.Shape)},
+		{a.Surface, b.Surface},
+		{a.GripType, b.GripType},
+		{a.EdgeGuard, b.EdgeGuard},
+		{a.HandleType, b.HandleType},
+		{a.SurfaceFront, b.SurfaceFront},
+		{a.SurfaceBack, b.SurfaceBack},
+	}
+	for _, pair := range texts {
+		if normalizeForComparison(pair[0]) != normalizeForComparison(pair[1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// similarity scores two names between 0 (unrelated) and 1 (identical after normalization)
+func similarity(a, b string) float64 {
+	a, b = normalizeForComparison(a), normalizeForComparison(b)
+	longest := max(len([]rune(a)), len([]rune(b)))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(a, b))/float64(longest)
+}
+
+// findDuplicates scores every paddle against the given brand and model and
+// returns those at or above the threshold whose specs also match, most
+// similar first. A nil sameSpecs matches on the name alone.
+func findDuplicates(paddles []*Paddle, brand, model string, threshold float64, sameSpecs func(Specs) bool) []DuplicateMatch {
+	candidate := brand + " " + model
+
+	matches := []DuplicateMatch{}
+	for _, paddle := range paddles {
+		if sameSpecs != nil && !sameSpecs(paddle.Specs) {
+			continue
+		}
+		score := similarity(candidate, paddle.Metadata.Brand+" "+paddle.Metadata.Model)
+		if score >= threshold {
+			matches = append(matches, DuplicateMatch{
+				ID:    paddle.ID,
+				Brand: paddle.Metadata.Brand,
+				Model: paddle.Metadata.Model,
+				Score: score,
+			})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+
+	return matches
+}
+
+// findDuplicatesOf finds the paddles named like a stored paddle with the same
+// specs within tol, leaving the paddle itself out. A paddle with the same
+// name but other specs, such as a new generation, is not a duplicate.
+func findDuplicatesOf(paddles []*Paddle, candidate *Paddle, threshold, tol float64) []DuplicateMatch {
+	sameSpecs := func(specs Specs) bool { return specsApproximatelyEqual(candidate.Specs, specs, tol) }
+
+	matches := []DuplicateMatch{}
+	for _, match := range findDuplicates(paddles, candidate.Metadata.Brand, candidate.Metadata.Model, threshold, sameSpecs) {
+		if match.ID != candidate.ID {
+			matches = append(matches, match)
+		}
+	}
+	return matches
+}
+
+// parseDuplicateSpecs reads the optional numeric spec parameters, such as
+// average_weight=220, that brand and model searches match within the spec tolerance
+func parseDuplicateSpecs(query url.Values) (map[string]float64, error) {
+	specs := map[string]float64{}
+	for _, field := range numericSpecFields {
+		raw := query.Get(field)
+		if raw == "" {
+			continue
+		}
+		value, err := parsePositive(field, raw)
+		if err != nil {
+			return nil, err
+		}
+		specs[field] = value
+	}
+	return specs, nil
+}
+
+// getDuplicates handles the API request for finding paddles similar to a
+// brand and model, or to a stored paddle given by id
+func getDuplicates(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	brand := strings.TrimSpace(query.Get("brand"))
+	model := strings.TrimSpace(query.Get("model"))
+	id := query.Get("id")
+
+	switch {
+	case id != "" && (brand != "" || model != ""):
+		respondWithError(w, "Use either id or brand and model, not both", http.StatusBadRequest)
+		return
+	case id != "":
+		if err := validatePaddleID(id); err != nil {
+			respondWithError(w, fmt.Sprintf("Invalid paddle ID: %v", err), http.StatusBadRequest)
+			return
+		}
+	case brand == "" || model == "":
+		respondWithError(w, "brand and model query parameters are required", http.StatusBadRequest)
+		return
+	}
+
+	threshold := duplicateThreshold
+	if raw := query.Get("threshold"); raw != "" {
+		var err error
+		threshold, err = parseThreshold(raw)
+		if err != nil {
+			respondWithError(w, fmt.Sprintf("Invalid threshold: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	specs, err := parseDuplicateSpecs(query)
+	if err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid specs: %v", err), http.StatusBadRequest)
+		return
+	}
+	if id != "" && len(specs) > 0 {
+		respondWithError(w, "Specs are taken from the stored paddle when id is given", http.StatusBadRequest)
+		return
+	}
+
+	paddles, err := GetAllPaddles()
+	if err != nil {
+		log.Printf("Error retrieving paddles: %v", err)
+		respondWithError(w, "Failed to retrieve paddles data", http.StatusInternalServerError)
+		return
+	}
+
+	var matches []DuplicateMatch
+	if id != "" {
+		id = NormalizePaddleID(id)
+		i := slices.IndexFunc(paddles, func(p *Paddle) bool { return p.ID == id })
+		if i < 0 {
+			respondWithError(w, "Paddle not found", http.StatusNotFound)
+			return
+		}
+		matches = findDuplicatesOf(paddles, paddles[i], threshold, duplicateSpecTolerance)
+	} else {
+		sameSpecs := func(s Specs) bool { return numericSpecsWithin(s, specs, duplicateSpecTolerance) }
+		matches = findDuplicates(paddles, brand, model, threshold, sameSpecs)
+	}
+
+	if err := json.NewEncoder(w).Encode(matches); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
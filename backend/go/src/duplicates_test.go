@@ -0,0 +1,209 @@
+package main
+
+import (
+	"net/url"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// TestLevenshtein tests the edit distance function
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"pursuit mx 6", "pursuit mx 6.0", 2},
+		{"crbn", "crbn", 0},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// TestSimilarity tests that normalization ignores case, punctuation and spacing
+func TestSimilarity(t *testing.T) {
+	if got := similarity("Engage  Pursuit-MX", "engage pursuit mx"); got != 1 {
+		t.Errorf("similarity() = %v, want 1 for names differing only in formatting", got)
+	}
+	if got := similarity("Engage Pursuit MX 6", "Selkirk Vanguard Power Air"); got > 0.5 {
+		t.Errorf("similarity() = %v, want a low score for unrelated names", got)
+	}
+}
+
+// TestFindDuplicates tests that near-misses are flagged and unrelated paddles are not
+func TestFindDuplicates(t *testing.T) {
+	paddles := []*Paddle{
+		{ID: "engage-pursuit-mx-6.0", Metadata: Metadata{Brand: "Engage", Model: "Pursuit MX 6.0"}},
+		{ID: "engage-pursuit-ex-6.0", Metadata: Metadata{Brand: "Engage", Model: "Pursuit EX 6.0"}},
+		{ID: "selkirk-vanguard-power-air", Metadata: Metadata{Brand: "Selkirk", Model: "Vanguard Power Air"}},
+		{ID: "joola-perseus-pro-iv", Metadata: Metadata{Brand: "Joola", Model: "Perseus Pro IV"}},
+	}
+
+	matches := findDuplicates(paddles, "Engage", "Pursuit MX 6", defaultDuplicateThreshold, nil)
+
+	if len(matches) == 0 || matches[0].ID != "engage-pursuit-mx-6.0" {
+		t.Fatalf("Expected engage-pursuit-mx-6.0 as the best match, got %+v", matches)
+	}
+	for i := 1; i < len(matches); i++ {
+		if matches[i-1].Score < matches[i].Score {
+			t.Errorf("Matches not sorted by score: %+v", matches)
+		}
+	}
+	for _, m := range matches {
+		if m.ID == "selkirk-vanguard-power-air" || m.ID == "joola-perseus-pro-iv" {
+			t.Errorf("Unrelated paddle %s flagged as duplicate with score %v", m.ID, m.Score)
+		}
+	}
+
+	if strict := findDuplicates(paddles, "Engage", "Pursuit MX 6", 1, nil); len(strict) != 0 {
+		t.Errorf("Expected no exact matches at threshold 1, got %+v", strict)
+	}
+}
+
+// TestParseThreshold tests the threshold bounds
+func TestParseThreshold(t *testing.T) {
+	for _, raw := range []string{"0.5", "1"} {
+		if _, err := parseThreshold(raw); err != nil {
+			t.Errorf("parseThreshold(%q) returned error: %v", raw, err)
+		}
+	}
+	for _, raw := range []string{"0", "1.5", "-0.2", "high"} {
+		if _, err := parseThreshold(raw); err == nil {
+			t.Errorf("parseThreshold(%q) should fail", raw)
+		}
+	}
+}
+
+// TestSpecsApproximatelyEqual tests pairs of specs that should and should
+// not count as the same paddle
+func TestSpecsApproximatelyEqual(t *testing.T) {
+	base := Specs{
+		Shape: Hybrid, Surface: "Carbon Fiber", AverageWeight: 220.0, Core: 16.0,
+		PaddleLength: 16.5, PaddleWidth: 7.5, GripLength: 5.25, GripType: "Comfort", GripCircumference: 4.25,
+		SurfaceFront: "Carbon Fiber", SurfaceBack: "Carbon Fiber",
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Specs)
+		want   bool
+	}{
+		{name: "Identical", modify: func(s *Specs) {}, want: true},
+		{name: "Weight rounding", modify: func(s *Specs) { s.AverageWeight = 220.01 }, want: true},
+		{name: "Within tolerance", modify: func(s *Specs) { s.AverageWeight = 221.5 }, want: true},
+		{name: "Text case and punctuation", modify: func(s *Specs) { s.Surface = "carbon-fiber"; s.GripType = " COMFORT " }, want: true},
+		{name: "Heavier", modify: func(s *Specs) { s.AverageWeight = 235 }, want: false},
+		{name: "Thinner core", modify: func(s *Specs) { s.Core = 14.0 }, want: false},
+		{name: "Other shape", modify: func(s *Specs) { s.Shape = Elongated }, want: false},
+		{name: "Other back", modify: func(s *Specs) { s.SurfaceBack = "Fiberglass" }, want: false},
+		{name: "Edge guard only on one", modify: func(s *Specs) { s.EdgeGuard = "Edgeless" }, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			other := base
+			tt.modify(&other)
+			if got := specsApproximatelyEqual(base, other, defaultDuplicateSpecTolerance); got != tt.want {
+				t.Errorf("specsApproximatelyEqual() = %v, want %v", got, tt.want)
+			}
+			// The comparison is symmetric
+			if got := specsApproximatelyEqual(other, base, defaultDuplicateSpecTolerance); got != tt.want {
+				t.Errorf("specsApproximatelyEqual() reversed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	heavier := base
+	heavier.AverageWeight = 220.01
+	if specsApproximatelyEqual(base, heavier, 0) {
+		t.Error("specsApproximatelyEqual() with no tolerance matched 220 and 220.01")
+	}
+}
+
+// TestFindDuplicatesOf tests that duplicates of a stored paddle leave it out
+// and only include paddles with the same specs within the tolerance
+func TestFindDuplicatesOf(t *testing.T) {
+	specs := Specs{Shape: Hybrid, Surface: "Carbon Fiber", AverageWeight: 220.0, Core: 16.0, PaddleLength: 16.5, PaddleWidth: 7.5, GripLength: 5.25, GripType: "Comfort", GripCircumference: 4.25}
+	reweighed, thinner := specs, specs
+	reweighed.AverageWeight = 220.01
+	thinner.Core = 14.0
+
+	paddles := []*Paddle{
+		{ID: "engage-pursuit-mx-6.0", Metadata: Metadata{Brand: "Engage", Model: "Pursuit MX 6.0"}, Specs: specs},
+		{ID: "engage-pursuit-mx-6.0-2", Metadata: Metadata{Brand: "Engage", Model: "Pursuit MX 6.0 "}, Specs: reweighed},
+		{ID: "engage-pursuit-mx-6", Metadata: Metadata{Brand: "Engage", Model: "Pursuit MX 6"}, Specs: thinner},
+		{ID: "selkirk-vanguard-power-air", Metadata: Metadata{Brand: "Selkirk", Model: "Vanguard Power Air"}, Specs: specs},
+	}
+
+	var ids []string
+	for _, m := range findDuplicatesOf(paddles, paddles[0], defaultDuplicateThreshold, defaultDuplicateSpecTolerance) {
+		ids = append(ids, m.ID)
+	}
+	if want := []string{"engage-pursuit-mx-6.0-2"}; !slices.Equal(ids, want) {
+		t.Errorf("duplicates = %v, want %v", ids, want)
+	}
+
+	// Without tolerance the re-weighed paddle is not the same either
+	if matches := findDuplicatesOf(paddles, paddles[0], defaultDuplicateThreshold, 0); len(matches) != 0 {
+		t.Errorf("duplicates with no tolerance = %+v, want none", matches)
+	}
+}
+
+// TestFindDuplicatesWithSpecs tests that brand and model searches given specs
+// only match paddles whose specs are within the tolerance
+func TestFindDuplicatesWithSpecs(t *testing.T) {
+	reweighed := testSpecs
+	reweighed.AverageWeight = testSpecs.AverageWeight * 1.005
+	heavier := testSpecs
+	heavier.AverageWeight = testSpecs.AverageWeight * 1.1
+
+	paddles := []*Paddle{
+		{ID: "engage-pursuit-mx-6.0", Metadata: Metadata{Brand: "Engage", Model: "Pursuit MX 6.0"}, Specs: reweighed},
+		{ID: "engage-pursuit-mx-6", Metadata: Metadata{Brand: "Engage", Model: "Pursuit MX 6"}, Specs: heavier},
+	}
+
+	query := url.Values{"average_weight": {strconv.FormatFloat(testSpecs.AverageWeight, 'f', -1, 64)}}
+	specs, err := parseDuplicateSpecs(query)
+	if err != nil {
+		t.Fatalf("parseDuplicateSpecs() error: %v", err)
+	}
+	sameSpecs := func(s Specs) bool { return numericSpecsWithin(s, specs, defaultDuplicateSpecTolerance) }
+
+	var ids []string
+	for _, m := range findDuplicates(paddles, "Engage", "Pursuit MX 6.0", defaultDuplicateThreshold, sameSpecs) {
+		ids = append(ids, m.ID)
+	}
+	if want := []string{"engage-pursuit-mx-6.0"}; !slices.Equal(ids, want) {
+		t.Errorf("duplicates = %v, want %v", ids, want)
+	}
+
+	// Without specs both names match
+	if matches := findDuplicates(paddles, "Engage", "Pursuit MX 6.0", defaultDuplicateThreshold, nil); len(matches) != 2 {
+		t.Errorf("duplicates without specs = %+v, want both paddles", matches)
+	}
+
+	if _, err := parseDuplicateSpecs(url.Values{"core": {"thick"}}); err == nil {
+		t.Error("parseDuplicateSpecs() accepted core=thick")
+	}
+}
+
+// TestLoadDuplicateSpecTolerance tests validating DUPLICATE_SPEC_TOLERANCE
+func TestLoadDuplicateSpecTolerance(t *testing.T) {
+	t.Setenv("DUPLICATE_SPEC_TOLERANCE", "0.05")
+	if cfg, err := loadDuplicateConfig(); err != nil || cfg.SpecTolerance != 0.05 {
+		t.Errorf("loadDuplicateConfig() = %v with tolerance %v, want 0.05", err, cfg.SpecTolerance)
+	}
+	for _, raw := range []string{"-0.1", "1", "loose"} {
+		t.Setenv("DUPLICATE_SPEC_TOLERANCE", raw)
+		if _, err := loadDuplicateConfig(); err == nil {
+			t.Errorf("loadDuplicateConfig() accepted DUPLICATE_SPEC_TOLERANCE=%q", raw)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// parseEliteQuery reads the required metric and whole percentile, 0–100
+func parseEliteQuery(query url.Values) (string, int, error) {
+	metric := query.Get("metric")
+	if !slices.Contains(performanceMetrics, metric) {
+		return "", 0, fmt.Errorf("metric must be one of %s", strings.Join(performanceMetrics, ", "))
+	}
+
+	percentile, err := strconv.Atoi(query.Get("percentile"))
+	if err != nil || percentile < 0 || percentile > 100 {
+		return "", 0, fmt.Errorf("percentile must be a whole number from 0 to 100")
+	}
+	return metric, percentile, nil
+}
+
+// GetElitePaddles returns published paddles whose mean metric is at least
+// threshold, highest first, then by paddle ID
+func GetElitePaddles(metric string, threshold float64, limit, offset int) ([]*Paddle, error) {
+	// The metric comes from the performanceMetricColumns whitelist, and its
+	// column holds the paddle's mean in paddleMeansQuery
+	column := performanceMetricColumns[metric]
+	return queryPaddleMeans("get_elite_paddles", fmt.Sprintf(`
+		WHERE
+			%[1]s >= $1
+			AND p.status = 'published'
+		ORDER BY
+			%[1]s DESC, p.paddle_id
+		LIMIT $2 OFFSET $3
+	`, column), threshold, limit, offset)
+}
+
+// ElitePaddles is the response of the elite endpoint. Threshold is the
+// metric's value at the percentile of published paddles' mean performance,
+// or null when nothing has been measured.
+type ElitePaddles struct {
+	Metric     string           `json:"metric"`
+	Percentile int              `json:"percentile"`
+	Threshold  *float64         `json:"threshold"`
+	Paddles    []PaddleResponse `json:"paddles"`
+}
+
+// getElitePaddles handles the API request for paddles at or above a
+// percentile of one metric, such as ?metric=spin&percentile=90. The
+// threshold comes from the cached dataset stats.
+func getElitePaddles(w http.ResponseWriter, r *http.Request) {
+	metric, percentile, err := parseEliteQuery(r.URL.Query())
+	if err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid query: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid pagination: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	stats, err := currentDatasetStats()
+	if err != nil {
+		log.Printf("Error retrieving dataset stats: %v", err)
+		respondWithError(w, "Failed to retrieve elite paddles", http.StatusInternalServerError)
+		return
+	}
+
+	response := ElitePaddles{Metric: metric, Percentile: percentile, Paddles: []PaddleResponse{}}
+	if cuts, ok := stats.Percentiles[metric]; ok {
+		threshold := cuts[percentile]
+		response.Threshold = &threshold
+
+		paddles, err := store.GetElitePaddles(metric, threshold, limit, offset)
+		if err != nil {
+			log.Printf("Error retrieving elite paddles: %v", err)
+			respondWithError(w, "Failed to retrieve elite paddles", http.StatusInternalServerError)
+			return
+		}
+		for _, paddle := range paddles {
+			paddle.convertUnits(metricUnits)
+			response.Paddles = append(response.Paddles, newPaddleResponse(paddle, nil))
+		}
+	}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding elite paddles: %v", err)
+	}
+}
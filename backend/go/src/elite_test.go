@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+)
+
+// TestParseEliteQuery tests validating the metric and percentile
+func TestParseEliteQuery(t *testing.T) {
+	tests := []struct {
+		query   string
+		wantErr bool
+	}{
+		{query: "metric=spin&percentile=90"},
+		{query: "metric=power&percentile=0"},
+		{query: "metric=balance_point&percentile=100"},
+		{query: "percentile=90", wantErr: true},
+		{query: "metric=control&percentile=90", wantErr: true},
+		{query: "metric=spin", wantErr: true},
+		{query: "metric=spin&percentile=101", wantErr: true},
+		{query: "metric=spin&percentile=-1", wantErr: true},
+		{query: "metric=spin&percentile=90.5", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			query, _ := url.ParseQuery(tt.query)
+			if _, _, err := parseEliteQuery(query); (err != nil) != tt.wantErr {
+				t.Errorf("parseEliteQuery(%q) error = %v, wantErr %v", tt.query, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestGetElitePaddles tests that only the top decile of a seeded set is
+// returned, highest first
+func TestGetElitePaddles(t *testing.T) {
+	setupTestStore(t)
+	t.Cleanup(func() { datasetStatsCache.stats = nil })
+	datasetStatsCache.stats = nil
+
+	get := func(query string) (int, ElitePaddles) {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		getElitePaddles(rr, httptest.NewRequest("GET", "/api/paddles/elite?"+query, nil))
+		var page ElitePaddles
+		if rr.Code == http.StatusOK {
+			if err := json.Unmarshal(rr.Body.Bytes(), &page); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+		}
+		return rr.Code, page
+	}
+
+	// Nothing measured yet
+	if code, page := get("metric=spin&percentile=90"); code != http.StatusOK || page.Threshold != nil || len(page.Paddles) != 0 {
+		t.Fatalf("empty dataset returned %d with threshold %v and %d paddles, want 200, null and none", code, page.Threshold, len(page.Paddles))
+	}
+	datasetStatsCache.stats = nil
+
+	// Spin 2000, 2050, ... 2950 across 20 paddles
+	for i := range 20 {
+		input := testPaddleInput("Engage", fmt.Sprintf("Elite %02d", i))
+		input.Performance.Spin = 2000 + 50*float64(i)
+		saveTestPaddle(t, input)
+	}
+
+	code, page := get("metric=spin&percentile=90")
+	if code != http.StatusOK {
+		t.Fatalf("GET elite returned %d", code)
+	}
+	if page.Threshold == nil || *page.Threshold != 2855 {
+		t.Errorf("threshold = %v, want 2855", page.Threshold)
+	}
+	var ids []string
+	for _, paddle := range page.Paddles {
+		ids = append(ids, paddle.ID)
+	}
+	if want := []string{"engage-elite-19", "engage-elite-18"}; !slices.Equal(ids, want) {
+		t.Errorf("elite paddles = %v, want %v", ids, want)
+	}
+
+	// The 0th percentile is the lowest value, so every paddle qualifies
+	if _, page := get("metric=spin&percentile=0&limit=100"); len(page.Paddles) != 20 {
+		t.Errorf("0th percentile returned %d paddles, want 20", len(page.Paddles))
+	}
+
+	if code, _ := get("metric=spin&percentile=150"); code != http.StatusBadRequest {
+		t.Errorf("percentile 150 returned %d, want %d", code, http.StatusBadRequest)
+	}
+}
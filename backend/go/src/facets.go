@@ -0,0 +1,145 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// FacetCount is how many paddles have one value of a facet
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Facets holds the values of each facet still available under a filter, most
+// common first. Each facet is counted with every filter except its own, so
+// picking another brand, shape or surface shows how many paddles it adds.
+type Facets struct {
+	Total    int          `json:"total"`
+	Brands   []FacetCount `json:"brands"`
+	Shapes   []FacetCount `json:"shapes"`
+	Surfaces []FacetCount `json:"surfaces"`
+}
+
+// facet is one facet with the filter it is counted under
+type facet struct {
+	label  string
+	column string
+	filter paddleFilter
+	value  func(*Paddle) string
+	counts *[]FacetCount
+}
+
+// facetsOf lists the facets of result with their own filter removed. Column
+// names are constants; brands are grouped case-insensitively, like the
+// brand filter matches.
+func facetsOf(filter paddleFilter, result *Facets) []facet {
+	byBrand, byShape, bySurface := filter, filter, filter
+	byBrand.Brand = ""
+	byShape.Shape = ""
+	bySurface.Surfaces = nil
+	return []facet{
+		{"count_brand_facet", "p.brand", byBrand, func(p *Paddle) string { return p.Metadata.Brand }, &result.Brands},
+		{"count_shape_facet", "s.shape", byShape, func(p *Paddle) string { return string(p.Specs.Shape) }, &result.Shapes},
+		{"count_surface_facet", "s.surface", bySurface, func(p *Paddle) string { return p.Specs.Surface }, &result.Surfaces},
+	}
+}
+
+// sortFacetCounts orders counts most common first, then by value
+func sortFacetCounts(counts []FacetCount) {
+	slices.SortFunc(counts, func(a, b FacetCount) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Value, b.Value)
+	})
+}
+
+// GetFacets counts the paddles matching filter, and each facet's values in
+// one GROUP BY query per facet
+func GetFacets(filter paddleFilter) (*Facets, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	result := &Facets{}
+	for _, f := range facetsOf(filter, result) {
+		counts, err := countFacet(ctx, f)
+		if err != nil {
+			return nil, err
+		}
+		*f.counts = counts
+	}
+
+	where, args := filter.where()
+	err := timedQueryRow(ctx, DB, "count_facet_total", `
+		SELECT COUNT(*)
+		FROM
+			paddles p
+		JOIN
+			paddle_specs s ON p.id = s.paddle_id
+		`+where, args...).Scan(&result.Total)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// countFacet counts the paddles per value of one facet
+func countFacet(ctx context.Context, f facet) ([]FacetCount, error) {
+	where, args := f.filter.where()
+	rows, err := timedQuery(ctx, DB, f.label, fmt.Sprintf(`
+		SELECT MIN(%[1]s), COUNT(*)
+		FROM
+			paddles p
+		JOIN
+			paddle_specs s ON p.id = s.paddle_id
+		%[2]s
+		GROUP BY
+			LOWER(%[1]s)
+	`, f.column, where), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []FacetCount{}
+	for rows.Next() {
+		var count FacetCount
+		if err := rows.Scan(&count.Value, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sortFacetCounts(counts)
+	return counts, nil
+}
+
+// getFacets handles the API request for the facet counts of a search, for
+// a "refine your search" sidebar. It takes the list endpoint's filters.
+func getFacets(w http.ResponseWriter, r *http.Request) {
+	filter, err := parsePaddleFilter(r.URL.Query())
+	if err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid filter: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	facets, err := store.GetFacets(filter)
+	if err != nil {
+		log.Printf("Error counting facets: %v", err)
+		respondWithError(w, "Failed to count facets", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(facets); err != nil {
+		log.Printf("Error encoding facets: %v", err)
+	}
+}
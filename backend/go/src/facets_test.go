@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// TestGetFacets tests that each facet is counted under every filter but its
+// own, on a seeded set of paddles
+func TestGetFacets(t *testing.T) {
+	setupTestStore(t)
+
+	for _, seed := range []struct {
+		brand, model string
+		shape        PaddleShape
+		surface      string
+	}{
+		{"Engage", "Pursuit", Hybrid, "Carbon Fiber"},
+		{"Engage", "Pursuit EX", Elongated, "Raw Carbon"},
+		{"Selkirk", "Vanguard", Hybrid, "Carbon Fiber"},
+		{"Selkirk", "Amped", WideBody, "Fiberglass"},
+		{"JOOLA", "Perseus", Elongated, "Carbon Fiber"},
+	} {
+		input := testPaddleInput(seed.brand, seed.model)
+		input.Specs.Shape, input.Specs.Surface = seed.shape, seed.surface
+		saveTestPaddle(t, input)
+	}
+
+	tests := []struct {
+		name         string
+		query        string
+		wantTotal    int
+		wantBrands   []FacetCount
+		wantShapes   []FacetCount
+		wantSurfaces []FacetCount
+	}{
+		{
+			name:         "No filter",
+			wantTotal:    5,
+			wantBrands:   []FacetCount{{"Engage", 2}, {"Selkirk", 2}, {"JOOLA", 1}},
+			wantShapes:   []FacetCount{{"Elongated", 2}, {"Hybrid", 2}, {"Wide-body", 1}},
+			wantSurfaces: []FacetCount{{"Carbon Fiber", 3}, {"Fiberglass", 1}, {"Raw Carbon", 1}},
+		},
+		{
+			// Brands still show every brand with carbon fiber paddles, while
+			// shapes and surfaces only count Selkirk paddles
+			name:         "Brand and surface",
+			query:        "brand=selkirk&surface=Carbon+Fiber",
+			wantTotal:    1,
+			wantBrands:   []FacetCount{{"Engage", 1}, {"JOOLA", 1}, {"Selkirk", 1}},
+			wantShapes:   []FacetCount{{"Hybrid", 1}},
+			wantSurfaces: []FacetCount{{"Carbon Fiber", 1}, {"Fiberglass", 1}},
+		},
+		{
+			name:         "No match",
+			query:        "brand=Paddletek",
+			wantTotal:    0,
+			wantBrands:   []FacetCount{{"Engage", 2}, {"Selkirk", 2}, {"JOOLA", 1}},
+			wantShapes:   []FacetCount{},
+			wantSurfaces: []FacetCount{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			getFacets(rr, httptest.NewRequest("GET", "/api/paddles/facets?"+tt.query, nil))
+			if rr.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+			}
+
+			var facets Facets
+			if err := json.Unmarshal(rr.Body.Bytes(), &facets); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if facets.Total != tt.wantTotal {
+				t.Errorf("total = %d, want %d", facets.Total, tt.wantTotal)
+			}
+			if !slices.Equal(facets.Brands, tt.wantBrands) {
+				t.Errorf("brands = %v, want %v", facets.Brands, tt.wantBrands)
+			}
+			if !slices.Equal(facets.Shapes, tt.wantShapes) {
+				t.Errorf("shapes = %v, want %v", facets.Shapes, tt.wantShapes)
+			}
+			if !slices.Equal(facets.Surfaces, tt.wantSurfaces) {
+				t.Errorf("surfaces = %v, want %v", facets.Surfaces, tt.wantSurfaces)
+			}
+		})
+	}
+
+	rr := httptest.NewRecorder()
+	getFacets(rr, httptest.NewRequest("GET", "/api/paddles/facets?surface=Wood", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Unknown surface returned %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
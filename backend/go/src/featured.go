@@ -0,0 +1,247 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"net/http"
+	"time"
+)
+
+// FeaturedSelection is the paddle of the week. Manual is set when an admin
+// picked it rather than the weekly rotation.
+type FeaturedSelection struct {
+	PaddleID   string
+	ValidUntil time.Time
+	Manual     bool
+}
+
+// featuredWeek returns the start of the UTC week, on Monday, containing t
+func featuredWeek(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+// weeklyPick picks one of ids for the week starting at week. The same IDs
+// give the same pick all week, on every instance.
+func weeklyPick(ids []string, week time.Time) string {
+	h := fnv.New64a()
+	h.Write([]byte(week.Format(time.DateOnly)))
+	return ids[h.Sum64()%uint64(len(ids))]
+}
+
+// currentFeatured returns the stored selection while it is valid and still
+// published, and otherwise picks and stores this week's paddle. It returns
+// ErrPaddleNotFound when no paddle is published.
+func currentFeatured(at time.Time) (*FeaturedSelection, error) {
+	selection, err := store.GetFeatured()
+	if err != nil {
+		return nil, err
+	}
+	if selection != nil && at.Before(selection.ValidUntil) {
+		paddle, err := store.GetPaddleByID(selection.PaddleID)
+		if err == nil && paddle.Status == StatusPublished {
+			return selection, nil
+		}
+		if err != nil && !errors.Is(err, ErrPaddleNotFound) {
+			return nil, err
+		}
+	}
+
+	ids, err := store.GetPublishedPaddleIDs()
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, ErrPaddleNotFound
+	}
+
+	week := featuredWeek(at)
+	selection = &FeaturedSelection{PaddleID: weeklyPick(ids, week), ValidUntil: week.AddDate(0, 0, 7)}
+	if err := store.SetFeatured(*selection); err != nil {
+		return nil, err
+	}
+	log.Printf("Featured paddle for the week of %s is %s", week.Format(time.DateOnly), selection.PaddleID)
+	return selection, nil
+}
+
+// GetFeatured returns the stored selection, or nil when there is none
+func GetFeatured() (*FeaturedSelection, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	selection := &FeaturedSelection{}
+	err := timedQueryRow(ctx, DB, "get_featured", `
+		SELECT p.paddle_id, f.valid_until, f.manual
+		FROM featured f
+		JOIN paddles p ON p.id = f.paddle_id
+		WHERE f.slot = 1
+	`).Scan(&selection.PaddleID, &selection.ValidUntil, &selection.Manual)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return selection, nil
+}
+
+// SetFeatured replaces the stored selection
+func SetFeatured(selection FeaturedSelection) error {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	result, err := timedExec(ctx, DB, "set_featured", `
+		INSERT INTO featured (slot, paddle_id, valid_until, manual)
+		SELECT 1, id, $2, $3 FROM paddles WHERE paddle_id = $1
+		ON CONFLICT (slot) DO UPDATE SET
+			paddle_id = EXCLUDED.paddle_id,
+			valid_until = EXCLUDED.valid_until,
+			manual = EXCLUDED.manual,
+			selected_at = CURRENT_TIMESTAMP
+	`, selection.PaddleID, selection.ValidUntil.UTC(), selection.Manual)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrPaddleNotFound
+	}
+	return err
+}
+
+// GetPublishedPaddleIDs returns the IDs of every published paddle that can
+// be read in full, in ID order
+func GetPublishedPaddleIDs() ([]string, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	rows, err := timedQuery(ctx, DB, "get_published_paddle_ids", `
+		SELECT DISTINCT p.paddle_id
+		FROM
+			paddles p
+		JOIN
+			paddle_specs s ON p.id = s.paddle_id
+		JOIN
+			paddle_performance perf ON s.id = perf.paddle_spec_id
+		WHERE
+			p.status = 'published'
+		ORDER BY
+			p.paddle_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// FeaturedResponse is the paddle of the week and when it changes
+type FeaturedResponse struct {
+	ValidUntil Time           `json:"valid_until"`
+	Manual     bool           `json:"manual"`
+	Paddle     PaddleResponse `json:"paddle"`
+}
+
+// respondWithFeatured writes the selection with its paddle
+func respondWithFeatured(w http.ResponseWriter, selection *FeaturedSelection) {
+	paddle, err := store.GetPaddleByID(selection.PaddleID)
+	if err != nil {
+		log.Printf("Error retrieving featured paddle: %v", err)
+		respondWithError(w, "Failed to retrieve featured paddle", http.StatusInternalServerError)
+		return
+	}
+	paddle.convertUnits(metricUnits)
+
+	response := FeaturedResponse{
+		ValidUntil: NewTime(selection.ValidUntil),
+		Manual:     selection.Manual,
+		Paddle:     newPaddleResponse(paddle, nil),
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding featured paddle: %v", err)
+	}
+}
+
+// getFeaturedPaddle handles the API request for the paddle of the week,
+// which is the same for every user until it expires
+func getFeaturedPaddle(w http.ResponseWriter, r *http.Request) {
+	selection, err := currentFeatured(now())
+	if errors.Is(err, ErrPaddleNotFound) {
+		respondWithError(w, "No paddle to feature", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Error selecting featured paddle: %v", err)
+		respondWithError(w, "Failed to retrieve featured paddle", http.StatusInternalServerError)
+		return
+	}
+	respondWithFeatured(w, selection)
+}
+
+// FeaturedOverride is the body of an admin override. ValidUntil defaults to
+// the end of the current week.
+type FeaturedOverride struct {
+	ID         string     `json:"id"`
+	ValidUntil *time.Time `json:"valid_until"`
+}
+
+// setFeaturedPaddle handles the admin request for featuring a chosen paddle
+// instead of the weekly pick
+func setFeaturedPaddle(w http.ResponseWriter, r *http.Request) {
+	var override FeaturedOverride
+	if err := decodeJSONBody(r.Body, &override); err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	if err := validatePaddleID(override.ID); err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid paddle ID: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	at := now()
+	validUntil := featuredWeek(at).AddDate(0, 0, 7)
+	if override.ValidUntil != nil {
+		if !override.ValidUntil.After(at) {
+			respondWithError(w, "valid_until must be in the future", http.StatusBadRequest)
+			return
+		}
+		validUntil = override.ValidUntil.UTC()
+	}
+
+	paddle, err := store.GetPaddleByID(override.ID)
+	if errors.Is(err, ErrPaddleNotFound) {
+		respondWithError(w, "Paddle not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Error retrieving paddle to feature: %v", err)
+		respondWithError(w, "Failed to feature paddle", http.StatusInternalServerError)
+		return
+	}
+	if paddle.Status != StatusPublished {
+		respondWithError(w, "Only published paddles can be featured", http.StatusConflict)
+		return
+	}
+
+	selection := &FeaturedSelection{PaddleID: paddle.ID, ValidUntil: validUntil, Manual: true}
+	if err := store.SetFeatured(*selection); err != nil {
+		log.Printf("Error featuring paddle: %v", err)
+		respondWithError(w, "Failed to feature paddle", http.StatusInternalServerError)
+		return
+	}
+	log.Printf("Featured paddle set to %s until %s", selection.PaddleID, validUntil.Format(time.RFC3339))
+	respondWithFeatured(w, selection)
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestFeaturedWeek tests that weeks start on Monday in UTC
+func TestFeaturedWeek(t *testing.T) {
+	monday := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	for _, at := range []time.Time{
+		monday,
+		time.Date(2026, 10, 14, 15, 30, 0, 0, time.UTC),
+		time.Date(2026, 10, 18, 23, 59, 59, 0, time.UTC),
+		// Monday locally, but still Sunday in UTC
+		time.Date(2026, 10, 19, 1, 0, 0, 0, time.FixedZone("CEST", 2*3600)),
+	} {
+		if got := featuredWeek(at); !got.Equal(monday) {
+			t.Errorf("featuredWeek(%v) = %v, want %v", at, got, monday)
+		}
+	}
+	if got := featuredWeek(monday.AddDate(0, 0, 7)); !got.Equal(monday.AddDate(0, 0, 7)) {
+		t.Errorf("featuredWeek(next Monday) = %v, want next Monday", got)
+	}
+}
+
+// featuredBody is the decoded featured paddle response
+type featuredBody struct {
+	ValidUntil string `json:"valid_until"`
+	Manual     bool   `json:"manual"`
+	Paddle     struct {
+		ID string `json:"id"`
+	} `json:"paddle"`
+}
+
+// TestGetFeaturedPaddle tests that the paddle of the week stays the same all
+// week, even as paddles are added, and changes once it expires
+func TestGetFeaturedPaddle(t *testing.T) {
+	setupTestStore(t)
+
+	clock := time.Date(2026, 10, 13, 9, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	get := func() (int, featuredBody) {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		getFeaturedPaddle(rr, httptest.NewRequest("GET", "/api/paddles/featured", nil))
+		var body featuredBody
+		if rr.Code == http.StatusOK {
+			if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+		}
+		return rr.Code, body
+	}
+	save := func(model string) {
+		t.Helper()
+		input := testPaddleInput("Engage", model)
+		saveTestPaddle(t, input)
+	}
+
+	if code, _ := get(); code != http.StatusNotFound {
+		t.Fatalf("GET with no paddles returned %d, want %d", code, http.StatusNotFound)
+	}
+
+	for i := range 5 {
+		save(fmt.Sprintf("Featured %d", i))
+	}
+	code, first := get()
+	if code != http.StatusOK {
+		t.Fatalf("GET returned %d", code)
+	}
+	if first.Manual || first.ValidUntil != "2026-10-19T00:00:00Z" {
+		t.Errorf("selection = %+v, want an automatic pick valid until next Monday", first)
+	}
+
+	// Later the same week, with more paddles to choose from
+	for i := 5; i < 10; i++ {
+		save(fmt.Sprintf("Featured %d", i))
+	}
+	clock = time.Date(2026, 10, 18, 23, 0, 0, 0, time.UTC)
+	if _, again := get(); again.Paddle.ID != first.Paddle.ID {
+		t.Errorf("featured paddle changed within the week from %s to %s", first.Paddle.ID, again.Paddle.ID)
+	}
+
+	// The next week's pick only depends on the week and the paddles
+	clock = time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	ids, _ := store.GetPublishedPaddleIDs()
+	_, next := get()
+	if want := weeklyPick(ids, clock); next.Paddle.ID != want || next.ValidUntil != "2026-10-26T00:00:00Z" {
+		t.Errorf("next week's selection = %+v, want %s until 2026-10-26", next, want)
+	}
+}
+
+// TestSetFeaturedPaddle tests that an admin override replaces the weekly
+// pick until the end of the week or the given time
+func TestSetFeaturedPaddle(t *testing.T) {
+	setupTestStore(t)
+
+	clock := time.Date(2026, 10, 13, 9, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	for _, model := range []string{"Pursuit", "Pursuit EX"} {
+		input := testPaddleInput("Engage", model)
+		saveTestPaddle(t, input)
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		wantCode       int
+		wantValidUntil string
+	}{
+		{name: "End of week", body: `{"id": "engage-pursuit-ex"}`, wantCode: http.StatusOK, wantValidUntil: "2026-10-19T00:00:00Z"},
+		{name: "Until", body: `{"id": "engage-pursuit", "valid_until": "2026-10-14T12:00:00+02:00"}`, wantCode: http.StatusOK, wantValidUntil: "2026-10-14T10:00:00Z"},
+		{name: "Past", body: `{"id": "engage-pursuit", "valid_until": "2026-10-01T00:00:00Z"}`, wantCode: http.StatusBadRequest},
+		{name: "Unknown paddle", body: `{"id": "selkirk-vanguard"}`, wantCode: http.StatusNotFound},
+		{name: "Missing ID", body: `{}`, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			setFeaturedPaddle(rr, httptest.NewRequest("PUT", "/api/admin/featured", strings.NewReader(tt.body)))
+			if rr.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rr.Code, tt.wantCode, rr.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			// Every user now gets the override
+			rr = httptest.NewRecorder()
+			getFeaturedPaddle(rr, httptest.NewRequest("GET", "/api/paddles/featured", nil))
+			var body featuredBody
+			if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			var override FeaturedOverride
+			json.Unmarshal([]byte(tt.body), &override)
+			if body.Paddle.ID != override.ID || !body.Manual || body.ValidUntil != tt.wantValidUntil {
+				t.Errorf("featured = %+v, want %s picked manually until %s", body, override.ID, tt.wantValidUntil)
+			}
+		})
+	}
+
+	// Once the override expires, the weekly pick takes over again
+	clock = time.Date(2026, 10, 14, 11, 0, 0, 0, time.UTC)
+	rr := httptest.NewRecorder()
+	getFeaturedPaddle(rr, httptest.NewRequest("GET", "/api/paddles/featured", nil))
+	var body featuredBody
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if body.Manual {
+		t.Errorf("featured = %+v after the override expired, want the weekly pick", body)
+	}
+}
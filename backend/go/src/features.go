@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// Features that can be turned on or off per deployment with FEATURES
+const (
+	featureAnalytics = "analytics"
+	featureWebhooks  = "webhooks"
+)
+
+// featureRoutes registers the routes of each optional feature. Routes of a
+// disabled feature are never registered, so they get the router's 404.
+var featureRoutes = map[string]func(router *mux.Router){
+	featureAnalytics: func(router *mux.Router) {
+		// Pearson correlation between two performance metrics across all paddles
+		router.HandleFunc("/api/analytics/correlation", withCommonHeaders(getCorrelation)).Methods("GET")
+
+		// Distribution of a performance metric across all paddles, for charts
+		router.HandleFunc("/api/analytics/histogram", withCommonHeaders(getHistogram)).Methods("GET")
+	},
+	featureWebhooks: func(router *mux.Router) {
+		// Webhook registration (requires the API key, since deliveries go to arbitrary URLs)
+		router.HandleFunc("/api/webhooks", withCommonHeaders(requireAPIKey(registerWebhook))).Methods("POST")
+		router.HandleFunc("/api/webhooks/{id}", withCommonHeaders(requireAPIKey(removeWebhook))).Methods("DELETE")
+	},
+}
+
+// featureSet is the set of enabled features
+type featureSet map[string]bool
+
+// enabledFeatures holds the features enabled for this deployment, set via FEATURES
+var enabledFeatures = allFeatures()
+
+// allFeatures returns a featureSet with every feature enabled
+func allFeatures() featureSet {
+	features := featureSet{}
+	for name := range featureRoutes {
+		features[name] = true
+	}
+	return features
+}
+
+// parseFeatures parses a comma-separated list of feature names. "none"
+// disables every feature.
+func parseFeatures(raw string) (featureSet, error) {
+	features := featureSet{}
+	if strings.TrimSpace(strings.ToLower(raw)) == "none" {
+		return features, nil
+	}
+
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(strings.ToLower(name))
+		if name == "" {
+			continue
+		}
+		if _, ok := featureRoutes[name]; !ok {
+			return nil, fmt.Errorf("unknown feature %q: must be one of %s", name, strings.Join(allFeatures().names(), ", "))
+		}
+		features[name] = true
+	}
+	return features, nil
+}
+
+// loadFeatures reads the enabled features from the FEATURES environment
+// variable. Every feature is enabled when it is unset.
+func loadFeatures() (featureSet, error) {
+	raw := getEnv("FEATURES", "")
+	if raw == "" {
+		return allFeatures(), nil
+	}
+
+	features, err := parseFeatures(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid FEATURES: %w", err)
+	}
+	return features, nil
+}
+
+// Enabled reports whether the named feature is enabled
+func (f featureSet) Enabled(name string) bool {
+	return f[name]
+}
+
+// names returns the enabled feature names in alphabetical order
+func (f featureSet) names() []string {
+	names := []string{}
+	for name, enabled := range f {
+		if enabled {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// registerFeatureRoutes registers the routes of every enabled feature
+func registerFeatureRoutes(router *mux.Router, features featureSet) {
+	for _, name := range features.names() {
+		featureRoutes[name](router)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// TestParseFeatures tests parsing the FEATURES list
+func TestParseFeatures(t *testing.T) {
+	features, err := parseFeatures(" Analytics, ,webhooks")
+	if err != nil {
+		t.Fatalf("parseFeatures() returned error: %v", err)
+	}
+	if got := features.names(); !slices.Equal(got, []string{featureAnalytics, featureWebhooks}) {
+		t.Errorf("parseFeatures() = %v, want [analytics webhooks]", got)
+	}
+
+	if features, err := parseFeatures("none"); err != nil || len(features.names()) != 0 {
+		t.Errorf("parseFeatures(none) = %v, %v; want no features", features.names(), err)
+	}
+
+	if _, err := parseFeatures("analytics,graphql"); err == nil {
+		t.Error("parseFeatures() should fail with an unknown feature")
+	}
+}
+
+// TestLoadFeatures tests reading FEATURES, with every feature enabled when it is unset
+func TestLoadFeatures(t *testing.T) {
+	t.Setenv("FEATURES", "webhooks")
+	features, err := loadFeatures()
+	if err != nil {
+		t.Fatalf("loadFeatures() returned error: %v", err)
+	}
+	if features.Enabled(featureAnalytics) || !features.Enabled(featureWebhooks) {
+		t.Errorf("FEATURES=webhooks enabled %v, want only webhooks", features.names())
+	}
+
+	t.Setenv("FEATURES", "")
+	if features, err = loadFeatures(); err != nil {
+		t.Fatalf("loadFeatures() returned error: %v", err)
+	}
+	if !features.Enabled(featureAnalytics) || !features.Enabled(featureWebhooks) {
+		t.Errorf("Unset FEATURES enabled %v, want every feature", features.names())
+	}
+}
+
+// TestRegisterFeatureRoutes tests that a disabled feature's routes are not registered
+func TestRegisterFeatureRoutes(t *testing.T) {
+	router := mux.NewRouter()
+	registerFeatureRoutes(router, featureSet{featureWebhooks: true})
+
+	var match mux.RouteMatch
+	if router.Match(httptest.NewRequest("GET", "/api/analytics/correlation?x=power&y=spin", nil), &match) {
+		t.Error("Disabled analytics route /api/analytics/correlation is registered")
+	}
+	if router.Match(httptest.NewRequest("GET", "/api/analytics/histogram?metric=power", nil), &match) {
+		t.Error("Disabled analytics route /api/analytics/histogram is registered")
+	}
+	if !router.Match(httptest.NewRequest("POST", "/api/webhooks", nil), &match) {
+		t.Error("Enabled webhooks route /api/webhooks is not registered")
+	}
+}
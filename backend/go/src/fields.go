@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// paddleSections are the top-level sections of a paddle that can be selected with ?fields=
+var paddleSections = []string{"metadata", "specs", "performance"}
+
+// parseFields splits a comma-separated fields parameter and validates each
+// name against the known paddle sections
+func parseFields(raw string) ([]string, error) {
+	var fields []string
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !isPaddleSection(field) {
+			return nil, fmt.Errorf("unknown field %q: must be one of %v", field, paddleSections)
+		}
+		fields = append(fields, field)
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("at least one field is required: must be one of %v", paddleSections)
+	}
+
+	return fields, nil
+}
+
+// isPaddleSection reports whether name is a selectable paddle section
+func isPaddleSection(name string) bool {
+	for _, section := range paddleSections {
+		if section == name {
+			return true
+		}
+	}
+	return false
+}
+
+// selectFields returns a map containing the paddle ID and only the requested
+// top-level sections, ready to be marshaled as a sparse response
+func selectFields(paddle *Paddle, fields []string) (map[string]interface{}, error) {
+	selected := map[string]interface{}{
+		"id": paddle.ID,
+	}
+
+	for _, field := range fields {
+		switch field {
+		case "metadata":
+			selected["metadata"] = paddle.Metadata
+			if len(paddle.Tags) > 0 {
+				selected["tags"] = paddle.Tags
+			}
+		case "specs":
+			selected["specs"] = paddle.Specs
+			if len(paddle.SpecRanges) > 0 {
+				selected["spec_ranges"] = paddle.SpecRanges
+			}
+		case "performance":
+			selected["performance"] = paddle.Performance
+		default:
+			return nil, fmt.Errorf("unknown field %q: must be one of %v", field, paddleSections)
+		}
+	}
+
+	return selected, nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestParseFields tests the parseFields function
+func TestParseFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    int
+		wantErr bool
+	}{
+		{name: "Single section", raw: "performance", want: 1},
+		{name: "Multiple sections", raw: "metadata,performance", want: 2},
+		{name: "Whitespace", raw: " metadata , specs ", want: 2},
+		{name: "Unknown section", raw: "metadata,price", wantErr: true},
+		{name: "Empty", raw: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := parseFields(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFields() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(fields) != tt.want {
+				t.Errorf("parseFields() returned %d fields, want %d", len(fields), tt.want)
+			}
+		})
+	}
+}
+
+// TestSelectFields tests requesting a single section of a paddle
+func TestSelectFields(t *testing.T) {
+	paddle := &Paddle{
+		ID:          "engage-pursuit-mx-6.0",
+		Metadata:    Metadata{Brand: "Engage", Model: "Pursuit MX 6.0"},
+		Specs:       Specs{Shape: Hybrid, Surface: "Composite"},
+		Performance: Performance{Power: 75.0, Spin: 3000.0},
+	}
+
+	selected, err := selectFields(paddle, []string{"performance"})
+	if err != nil {
+		t.Fatalf("selectFields() returned error: %v", err)
+	}
+
+	body, err := json.Marshal(selected)
+	if err != nil {
+		t.Fatalf("Failed to marshal selection: %v", err)
+	}
+
+	var decoded map[string]json.RawMessage
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal selection: %v", err)
+	}
+
+	if _, ok := decoded["performance"]; !ok {
+		t.Error("Expected performance section in response")
+	}
+	if _, ok := decoded["id"]; !ok {
+		t.Error("Expected id in response")
+	}
+	for _, omitted := range []string{"metadata", "specs"} {
+		if _, ok := decoded[omitted]; ok {
+			t.Errorf("Expected %s section to be omitted", omitted)
+		}
+	}
+
+	if _, err := selectFields(paddle, []string{"price"}); err == nil {
+		t.Error("selectFields() should fail with an unknown field")
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lib/pq"
+)
+
+// paddleFilter narrows a paddle query by metadata and specs. Empty fields match everything.
+// Surfaces is an IN-list: a paddle matches if it has any of them. Tags match
+// only paddles that have every one of them. GripMin and GripMax bound the
+// grip circumference, inclusive.
+type paddleFilter struct {
+	Brand    string
+	Shape    string
+	Surfaces []string
+	Tags     []string
+	Year     *int
+	Status   PaddleStatus
+	GripMin  *float64
+	GripMax  *float64
+}
+
+// parsePaddleFilter reads the brand, shape, surface, tag and year query parameters.
+// surface takes a comma-separated list, or may be repeated, and every value
+// must be one of paddleSurfaces. tag works the same way and is normalized
+// like stored tags. Only published paddles match; the list endpoint lets
+// admins ask for drafts instead.
+func parsePaddleFilter(query url.Values) (paddleFilter, error) {
+	filter := paddleFilter{
+		Brand:  strings.TrimSpace(query.Get("brand")),
+		Shape:  strings.TrimSpace(query.Get("shape")),
+		Status: StatusPublished,
+	}
+	for name, value := range map[string]string{"brand": filter.Brand, "shape": filter.Shape} {
+		if err := validateQueryText(name, value); err != nil {
+			return paddleFilter{}, err
+		}
+	}
+
+	for _, raw := range query["surface"] {
+		for _, value := range strings.Split(raw, ",") {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+			surface, ok := normalizeSurface(value)
+			if !ok {
+				return paddleFilter{}, fmt.Errorf("unknown surface %q: must be one of %s", value, strings.Join(paddleSurfaces, ", "))
+			}
+			filter.Surfaces = append(filter.Surfaces, surface)
+		}
+	}
+
+	var tags []string
+	for _, raw := range query["tag"] {
+		for _, value := range strings.Split(raw, ",") {
+			if strings.TrimSpace(value) != "" {
+				tags = append(tags, value)
+			}
+		}
+	}
+	if len(tags) > 0 {
+		var err error
+		if filter.Tags, err = normalizeTags(tags); err != nil {
+			return paddleFilter{}, err
+		}
+	}
+
+	if raw := query.Get("year"); raw != "" {
+		year, err := strconv.Atoi(raw)
+		if err != nil {
+			return paddleFilter{}, fmt.Errorf("year must be an integer")
+		}
+		filter.Year = &year
+	}
+
+	return filter, nil
+}
+
+// validateQueryText rejects a query value Postgres cannot take as a text
+// parameter: invalid UTF-8, or a NUL byte among other non-printable
+// characters. Either would fail the query with a 500 rather than match
+// nothing, and no stored paddle can contain them.
+func validateQueryText(name, value string) error {
+	if !utf8.ValidString(value) {
+		return newValidationError("TEXT_INVALID_UTF8", "%s must be valid UTF-8", name)
+	}
+	return validateText(name, value)
+}
+
+// where builds a parameterized WHERE clause for the filter, numbering
+// placeholders from $1. Text fields match case-insensitively. It returns an
+// empty clause when the filter is empty.
+func (f paddleFilter) where() (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if f.Brand != "" {
+		add("LOWER(p.brand) = LOWER($%d)", f.Brand)
+	}
+	if f.Shape != "" {
+		add("LOWER(s.shape) = LOWER($%d)", f.Shape)
+	}
+	if len(f.Surfaces) > 0 {
+		lowered := make([]string, len(f.Surfaces))
+		for i, surface := range f.Surfaces {
+			lowered[i] = strings.ToLower(surface)
+		}
+		add("LOWER(s.surface) = ANY($%d)", pq.Array(lowered))
+	}
+	if len(f.Tags) > 0 {
+		// Tags are stored normalized and f.Tags is deduped, so a paddle has
+		// all of them when it matches as many tags as were asked for
+		add("p.id IN (SELECT paddle_id FROM paddle_tags WHERE tag = ANY($%[1]d) GROUP BY paddle_id HAVING COUNT(*) = cardinality($%[1]d::text[]))", pq.Array(f.Tags))
+	}
+	if f.Year != nil {
+		add("p.year = $%d", *f.Year)
+	}
+	if f.Status != "" {
+		add("p.status = $%d", string(f.Status))
+	}
+	if f.GripMin != nil {
+		add("s.grip_circumference >= $%d", *f.GripMin)
+	}
+	if f.GripMax != nil {
+		add("s.grip_circumference <= $%d", *f.GripMax)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// matches reports whether a paddle passes the filter, mirroring where() for
+// stores that filter in memory
+func (f paddleFilter) matches(paddle *Paddle) bool {
+	if f.Brand != "" && !strings.EqualFold(paddle.Metadata.Brand, f.Brand) {
+		return false
+	}
+	if f.Shape != "" && !strings.EqualFold(string(paddle.Specs.Shape), f.Shape) {
+		return false
+	}
+	if len(f.Surfaces) > 0 && !slices.ContainsFunc(f.Surfaces, func(s string) bool {
+		return strings.EqualFold(paddle.Specs.Surface, s)
+	}) {
+		return false
+	}
+	for _, tag := range f.Tags {
+		if !slices.Contains(paddle.Tags, tag) {
+			return false
+		}
+	}
+	if f.Year != nil && (paddle.Metadata.Year == nil || *paddle.Metadata.Year != *f.Year) {
+		return false
+	}
+	if f.Status != "" && paddle.Status != f.Status {
+		return false
+	}
+	if f.GripMin != nil && paddle.Specs.GripCircumference < *f.GripMin {
+		return false
+	}
+	if f.GripMax != nil && paddle.Specs.GripCircumference > *f.GripMax {
+		return false
+	}
+	return true
+}
+
+// GetFilteredPaddles returns every paddle matching the filter, each once
+// with the mean of its measurements
+func GetFilteredPaddles(filter paddleFilter) ([]*Paddle, error) {
+	where, args := filter.where()
+	return queryPaddleMeans("get_filtered_paddles", where+" ORDER BY p.id", args...)
+}
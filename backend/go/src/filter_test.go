@@ -0,0 +1,71 @@
+package main
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+// TestPaddleFilterWhere tests building WHERE clauses from query parameters.
+// Parsed filters always exclude drafts.
+func TestPaddleFilterWhere(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		wantWhere string
+		wantArgs  []interface{}
+		wantErr   bool
+	}{
+		{name: "Empty", query: "", wantWhere: "WHERE p.status = $1", wantArgs: []interface{}{"published"}},
+		{name: "Brand", query: "brand=Engage", wantWhere: "WHERE LOWER(p.brand) = LOWER($1) AND p.status = $2", wantArgs: []interface{}{"Engage", "published"}},
+		{
+			name:      "Brand, shape and year",
+			query:     "brand=Engage&shape=Elongated&year=2023",
+			wantWhere: "WHERE LOWER(p.brand) = LOWER($1) AND LOWER(s.shape) = LOWER($2) AND p.year = $3 AND p.status = $4",
+			wantArgs:  []interface{}{"Engage", "Elongated", 2023, "published"},
+		},
+		{name: "Non-numeric year", query: "year=recent", wantErr: true},
+		{
+			name:      "Surface",
+			query:     "surface=carbon+fiber",
+			wantWhere: "WHERE LOWER(s.surface) = ANY($1) AND p.status = $2",
+			wantArgs:  []interface{}{pq.Array([]string{"carbon fiber"}), "published"},
+		},
+		{
+			name:      "Surface list and brand",
+			query:     "brand=Engage&surface=Carbon+Fiber,Fiberglass&surface=Kevlar",
+			wantWhere: "WHERE LOWER(p.brand) = LOWER($1) AND LOWER(s.surface) = ANY($2) AND p.status = $3",
+			wantArgs:  []interface{}{"Engage", pq.Array([]string{"carbon fiber", "fiberglass", "kevlar"}), "published"},
+		},
+		{name: "Unknown surface", query: "surface=Carbon+Fiber,Wood", wantErr: true},
+		{
+			name:      "Tags",
+			query:     "tag=Beginner-Friendly,tournament-approved&tag=beginner-friendly",
+			wantWhere: "WHERE p.id IN (SELECT paddle_id FROM paddle_tags WHERE tag = ANY($1) GROUP BY paddle_id HAVING COUNT(*) = cardinality($1::text[])) AND p.status = $2",
+			wantArgs:  []interface{}{pq.Array([]string{"beginner-friendly", "tournament-approved"}), "published"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, _ := url.ParseQuery(tt.query)
+			filter, err := parsePaddleFilter(query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePaddleFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			where, args := filter.where()
+			if where != tt.wantWhere {
+				t.Errorf("where() clause = %q, want %q", where, tt.wantWhere)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("where() args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
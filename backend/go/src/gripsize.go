@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// Accepted hand lengths, wrist crease to the tip of the middle finger. Adult
+// hands fall well inside this range.
+const (
+	minHandLengthCM = 12.0
+	maxHandLengthCM = 26.0
+)
+
+// Tolerance around the recommended grip circumference, in inches. The default
+// matches the 1/8" steps grips are sold in; the maximum spans every size.
+const (
+	defaultGripTolerance = 0.125
+	maxGripTolerance     = 1.0
+)
+
+// gripSizeStep maps hand lengths below UpToCM to a grip circumference in inches
+type gripSizeStep struct {
+	UpToCM     float64
+	GripInches float64
+}
+
+// gripSizeChart is the hand-to-grip mapping, in ascending hand length. Hands
+// at or beyond the last step get the largest grip.
+var gripSizeChart = []gripSizeStep{
+	{UpToCM: 17.0, GripInches: 4.0},
+	{UpToCM: 18.5, GripInches: 4.125},
+	{UpToCM: 20.0, GripInches: 4.25},
+	{UpToCM: 21.5, GripInches: 4.375},
+}
+
+// largestGripInches is recommended for hands at least as long as the chart's last step
+const largestGripInches = 4.5
+
+// recommendedGrip returns the grip circumference in inches for a hand length
+func recommendedGrip(handLengthCM float64) float64 {
+	for _, step := range gripSizeChart {
+		if handLengthCM < step.UpToCM {
+			return step.GripInches
+		}
+	}
+	return largestGripInches
+}
+
+// parseGripSizeParams reads and validates the hand length and tolerance
+func parseGripSizeParams(r *http.Request) (handLength, tolerance float64, err error) {
+	raw := r.URL.Query().Get("hand_length_cm")
+	if raw == "" {
+		return 0, 0, fmt.Errorf("hand_length_cm is required")
+	}
+	handLength, err = strconv.ParseFloat(raw, 64)
+	if err != nil || handLength < minHandLengthCM || handLength > maxHandLengthCM {
+		return 0, 0, fmt.Errorf("hand_length_cm must be a number between %g and %g", minHandLengthCM, maxHandLengthCM)
+	}
+
+	tolerance = defaultGripTolerance
+	if raw := r.URL.Query().Get("tolerance"); raw != "" {
+		tolerance, err = strconv.ParseFloat(raw, 64)
+		if err != nil || tolerance < 0 || tolerance > maxGripTolerance {
+			return 0, 0, fmt.Errorf("tolerance must be a number between 0 and %g", maxGripTolerance)
+		}
+	}
+	return handLength, tolerance, nil
+}
+
+// gripFilter matches published paddles whose grip is within tolerance of grip
+func gripFilter(grip, tolerance float64) paddleFilter {
+	gripMin, gripMax := grip-tolerance, grip+tolerance
+	return paddleFilter{Status: StatusPublished, GripMin: &gripMin, GripMax: &gripMax}
+}
+
+// getGripSize handles the API request for a recommended grip size and the
+// paddles that have it
+func getGripSize(w http.ResponseWriter, r *http.Request) {
+	handLength, tolerance, err := parseGripSizeParams(r)
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid pagination: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	grip := recommendedGrip(handLength)
+	paddles, err := store.GetPaddlesPage(gripFilter(grip, tolerance), defaultListSort, limit, offset)
+	if err != nil {
+		log.Printf("Error retrieving paddles by grip size: %v", err)
+		respondWithError(w, "Failed to retrieve paddles data", http.StatusInternalServerError)
+		return
+	}
+
+	response := struct {
+		HandLengthCM      float64      `json:"hand_length_cm"`
+		GripCircumference float64      `json:"grip_circumference"`
+		Tolerance         float64      `json:"tolerance"`
+		Paddles           []paddleCard `json:"paddles"`
+	}{
+		HandLengthCM:      handLength,
+		GripCircumference: grip,
+		Tolerance:         tolerance,
+		Paddles:           make([]paddleCard, 0, len(paddles)),
+	}
+	for _, paddle := range paddles {
+		response.Paddles = append(response.Paddles, newPaddleCard(paddle, metricUnits))
+	}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding grip size: %v", err)
+	}
+}
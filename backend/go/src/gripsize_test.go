@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestRecommendedGrip tests the hand-to-grip mapping, including the step boundaries
+func TestRecommendedGrip(t *testing.T) {
+	tests := []struct {
+		handLengthCM float64
+		want         float64
+	}{
+		{12, 4.0},
+		{16.9, 4.0},
+		{17, 4.125},
+		{18.4, 4.125},
+		{18.5, 4.25},
+		{19.5, 4.25},
+		{20, 4.375},
+		{21.5, 4.5},
+		{26, 4.5},
+	}
+
+	for _, tt := range tests {
+		if got := recommendedGrip(tt.handLengthCM); got != tt.want {
+			t.Errorf("recommendedGrip(%g) = %g, want %g", tt.handLengthCM, got, tt.want)
+		}
+	}
+}
+
+// TestGripFilter tests that paddles match only within the tolerance of the recommended grip
+func TestGripFilter(t *testing.T) {
+	filter := gripFilter(4.25, 0.125)
+
+	tests := []struct {
+		grip   float64
+		status PaddleStatus
+		want   bool
+	}{
+		{4.25, StatusPublished, true},
+		{4.125, StatusPublished, true},
+		{4.375, StatusPublished, true},
+		{4.0, StatusPublished, false},
+		{4.5, StatusPublished, false},
+		{4.25, StatusDraft, false},
+	}
+
+	for _, tt := range tests {
+		paddle := &Paddle{Specs: Specs{GripCircumference: tt.grip}, Status: tt.status}
+		if got := filter.matches(paddle); got != tt.want {
+			t.Errorf("matches(grip %g, %s) = %v, want %v", tt.grip, tt.status, got, tt.want)
+		}
+	}
+
+	where, args := filter.where()
+	if want := "WHERE p.status = $1 AND s.grip_circumference >= $2 AND s.grip_circumference <= $3"; where != want {
+		t.Errorf("where() clause = %q, want %q", where, want)
+	}
+	if len(args) != 3 || args[1] != 4.125 || args[2] != 4.375 {
+		t.Errorf("where() args = %v, want [published 4.125 4.375]", args)
+	}
+}
+
+// TestGetGripSize tests the recommendation and matching paddles, and input validation
+func TestGetGripSize(t *testing.T) {
+	setupTestStore(t)
+
+	for i, grip := range []float64{4.0, 4.25, 4.375, 4.5} {
+		paddle := &Paddle{
+			ID:       fmt.Sprintf("engage-grip-%d", i),
+			Metadata: Metadata{Brand: "Engage", Model: fmt.Sprintf("Grip %d", i)},
+			Specs: Specs{
+				Shape: Hybrid, Surface: "Composite", AverageWeight: 220.0, Core: 15.0,
+				PaddleLength: 16.5, PaddleWidth: 7.5, GripLength: 4.5, GripType: "Comfort", GripCircumference: grip,
+			},
+			Performance: testPerformance,
+		}
+		if _, err := store.SavePaddle(paddle); err != nil {
+			t.Fatalf("SavePaddle() error: %v", err)
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	getGripSize(rr, httptest.NewRequest("GET", "/api/grip-size?hand_length_cm=19", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	var response struct {
+		GripCircumference float64      `json:"grip_circumference"`
+		Paddles           []paddleCard `json:"paddles"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.GripCircumference != 4.25 {
+		t.Errorf("grip_circumference = %g, want 4.25", response.GripCircumference)
+	}
+	if len(response.Paddles) != 2 || response.Paddles[0].ID != "engage-grip-1" || response.Paddles[1].ID != "engage-grip-2" {
+		t.Errorf("paddles = %+v, want engage-grip-1 and engage-grip-2", response.Paddles)
+	}
+
+	for _, query := range []string{"", "hand_length_cm=big", "hand_length_cm=5", "hand_length_cm=30", "hand_length_cm=19&tolerance=-1"} {
+		rr := httptest.NewRecorder()
+		getGripSize(rr, httptest.NewRequest("GET", "/api/grip-size?"+query, nil))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%q returned %d, want %d", query, rr.Code, http.StatusBadRequest)
+		}
+	}
+}
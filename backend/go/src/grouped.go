@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// paddleGrouping is a field the grouped catalog can be keyed by
+type paddleGrouping struct {
+	// Column is the SQL expression rows are ordered by, so each group is contiguous
+	Column string
+	key    func(p *Paddle) string
+}
+
+// paddleGroupings lists the supported values of by. Only columns in this map
+// may ever be interpolated into SQL.
+var paddleGroupings = map[string]paddleGrouping{
+	"brand": {Column: "p.brand", key: func(p *Paddle) string { return p.Metadata.Brand }},
+	"shape": {Column: "s.shape", key: func(p *Paddle) string { return string(p.Specs.Shape) }},
+}
+
+// parseGrouping reads the by parameter, defaulting to brand
+func parseGrouping(raw string) (paddleGrouping, error) {
+	if raw == "" {
+		raw = "brand"
+	}
+	grouping, ok := paddleGroupings[strings.ToLower(raw)]
+	if !ok {
+		names := make([]string, 0, len(paddleGroupings))
+		for name := range paddleGroupings {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		return paddleGrouping{}, fmt.Errorf("by must be one of %s", strings.Join(names, ", "))
+	}
+	return grouping, nil
+}
+
+// streamGroupedPaddles handles the API request for the catalog grouped by
+// brand or shape, written one row at a time like the stream endpoint
+func streamGroupedPaddles(w http.ResponseWriter, r *http.Request) {
+	grouping, err := parseGrouping(r.URL.Query().Get("by"))
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter, err := parsePaddleFilter(r.URL.Query())
+	if err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid filter: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	units, err := parseUnits(r.URL.Query().Get("units"))
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	where, args := filter.where()
+	rows, err := timedQuery(r.Context(), DB, "stream_grouped_paddles", `
+		SELECT `+paddleSummaryColumns+`
+		FROM 
+			paddles p
+		JOIN 
+			paddle_specs s ON p.id = s.paddle_id
+		`+where+`
+		ORDER BY 
+			`+grouping.Column+`, p.id
+	`, args...)
+	if err != nil {
+		log.Printf("Error starting grouped paddle stream: %v", err)
+		respondWithError(w, "Failed to retrieve paddles data", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	w.Header().Set("Transfer-Encoding", "chunked")
+	count, err := writeGroupedPaddles(w, rows, grouping, units)
+	if err != nil {
+		log.Printf("Error streaming grouped paddles after %d rows: %v", count, err)
+	}
+}
+
+// writeGroupedPaddles writes rows as a JSON object mapping each group key to
+// the cards of its paddles. Rows must be ordered by the grouping, so a group
+// is closed as soon as the key changes. Like writePaddleStream, the object
+// is always closed, and the error is returned with the number of paddles written.
+func writeGroupedPaddles(w http.ResponseWriter, rows paddleRows, grouping paddleGrouping, units unitSystem) (int, error) {
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+
+	w.Write([]byte("{"))
+	count := 0
+	open := false
+	var current string
+	defer func() {
+		if open {
+			w.Write([]byte("]"))
+		}
+		w.Write([]byte("}\n"))
+	}()
+
+	for rows.Next() {
+		paddle, err := scanPaddleSummary(rows)
+		if err != nil {
+			return count, err
+		}
+
+		if key := grouping.key(paddle); !open || key != current {
+			if open {
+				w.Write([]byte("],"))
+			}
+			name, _ := json.Marshal(key)
+			w.Write(name)
+			w.Write([]byte(":["))
+			open, current = true, key
+		} else {
+			w.Write([]byte(","))
+		}
+
+		if err := encoder.Encode(newPaddleCard(paddle, units)); err != nil {
+			return count, err
+		}
+		count++
+
+		if flusher != nil && count%streamFlushEvery == 0 {
+			flusher.Flush()
+		}
+	}
+
+	return count, rows.Err()
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// fakeSummaryRows yields paddles as rows of paddleSummaryColumns, then optionally fails
+type fakeSummaryRows struct {
+	paddles []Paddle
+	next    int
+	err     error
+}
+
+func (f *fakeSummaryRows) Next() bool {
+	f.next++
+	return f.next <= len(f.paddles)
+}
+
+func (f *fakeSummaryRows) Scan(dest ...interface{}) error {
+	paddle := f.paddles[f.next-1]
+	*dest[0].(*string) = paddle.ID
+	*dest[1].(*string) = paddle.Metadata.Brand
+	*dest[2].(*string) = paddle.Metadata.Model
+	*dest[7].(*PaddleShape) = paddle.Specs.Shape
+	return nil
+}
+
+func (f *fakeSummaryRows) Err() error {
+	return f.err
+}
+
+// TestWriteGroupedPaddles tests that rows ordered by brand are written as one
+// list of cards per brand, and that a failed stream is still a valid object
+func TestWriteGroupedPaddles(t *testing.T) {
+	seeded := []Paddle{
+		{ID: "engage-pursuit-mx", Metadata: Metadata{Brand: "Engage", Model: "Pursuit MX"}, Specs: Specs{Shape: Hybrid}},
+		{ID: "engage-pursuit-pro", Metadata: Metadata{Brand: "Engage", Model: "Pursuit Pro"}, Specs: Specs{Shape: Elongated}},
+		{ID: "joola-perseus", Metadata: Metadata{Brand: "JOOLA", Model: "Perseus"}, Specs: Specs{Shape: Elongated}},
+		{ID: "selkirk-vanguard", Metadata: Metadata{Brand: "Selkirk", Model: "Vanguard"}, Specs: Specs{Shape: WideBody}},
+		{ID: "selkirk-luxx", Metadata: Metadata{Brand: "Selkirk", Model: "Luxx"}, Specs: Specs{Shape: Hybrid}},
+	}
+
+	tests := []struct {
+		name      string
+		rows      *fakeSummaryRows
+		wantIDs   map[string][]string
+		wantCount int
+		wantErr   bool
+	}{
+		{
+			name:      "Several brands",
+			rows:      &fakeSummaryRows{paddles: seeded},
+			wantIDs:   map[string][]string{"Engage": {"engage-pursuit-mx", "engage-pursuit-pro"}, "JOOLA": {"joola-perseus"}, "Selkirk": {"selkirk-vanguard", "selkirk-luxx"}},
+			wantCount: 5,
+		},
+		{name: "Empty", rows: &fakeSummaryRows{}, wantIDs: map[string][]string{}},
+		{
+			name:      "Error after the rows",
+			rows:      &fakeSummaryRows{paddles: seeded[:3], err: errors.New("connection reset")},
+			wantIDs:   map[string][]string{"Engage": {"engage-pursuit-mx", "engage-pursuit-pro"}, "JOOLA": {"joola-perseus"}},
+			wantCount: 3,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			count, err := writeGroupedPaddles(rr, tt.rows, paddleGroupings["brand"], metricUnits)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("writeGroupedPaddles() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if count != tt.wantCount {
+				t.Errorf("writeGroupedPaddles() count = %d, want %d", count, tt.wantCount)
+			}
+
+			var groups map[string][]paddleCard
+			if err := json.Unmarshal(rr.Body.Bytes(), &groups); err != nil {
+				t.Fatalf("Response is not a valid JSON object: %v (%s)", err, rr.Body.String())
+			}
+			ids := map[string][]string{}
+			for brand, cards := range groups {
+				for _, card := range cards {
+					if card.Metadata.Brand != brand {
+						t.Errorf("%s is grouped under %q", card.ID, brand)
+					}
+					ids[brand] = append(ids[brand], card.ID)
+				}
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("groups = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+// TestParseGrouping tests reading the by parameter
+func TestParseGrouping(t *testing.T) {
+	for _, raw := range []string{"", "brand", "Shape"} {
+		if _, err := parseGrouping(raw); err != nil {
+			t.Errorf("parseGrouping(%q) returned error: %v", raw, err)
+		}
+	}
+	if _, err := parseGrouping("surface"); err == nil || err.Error() != "by must be one of brand, shape" {
+		t.Errorf("parseGrouping(surface) error = %v, want the allowed values", err)
+	}
+}
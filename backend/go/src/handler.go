@@ -1,10 +1,16 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
 )
@@ -33,19 +39,35 @@ import (
 // 	},
 // }
 
-// errorResponse represents a standardized error response
+// errorResponse represents a standardized error response. Code is the HTTP
+// status; ErrorCode is the stable validation code, when there is one.
 type errorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message,omitempty"`
-	Code    int    `json:"code"`
+	Error     string `json:"error"`
+	Message   string `json:"message,omitempty"`
+	Code      int    `json:"code"`
+	ErrorCode string `json:"error_code,omitempty"`
 }
 
 // respondWithError sends a standardized error response
 func respondWithError(w http.ResponseWriter, message string, code int) {
+	respondWithErrorCode(w, message, code, "")
+}
+
+// respondWithValidationError sends a 400 for a failed validation, including its
+// error code, with the message in the request's Accept-Language
+func respondWithValidationError(w http.ResponseWriter, r *http.Request, err error) {
+	lang := requestLanguage(r)
+	message := fmt.Sprintf("%s: %s", validationErrorPrefixes[lang], localizeError(err, lang))
+	respondWithErrorCode(w, message, http.StatusBadRequest, validationCode(err))
+}
+
+// respondWithErrorCode sends a standardized error response with a machine-readable error code
+func respondWithErrorCode(w http.ResponseWriter, message string, code int, errorCode string) {
 	response := errorResponse{
-		Error:   http.StatusText(code),
-		Message: message,
-		Code:    code,
+		Error:     http.StatusText(code),
+		Message:   message,
+		Code:      code,
+		ErrorCode: errorCode,
 	}
 
 	w.WriteHeader(code)
@@ -57,75 +79,238 @@ func respondWithError(w http.ResponseWriter, message string, code int) {
 	}
 }
 
+// notFound responds to requests for unknown routes with the standard JSON error
+func notFound(w http.ResponseWriter, r *http.Request) {
+	respondWithError(w, fmt.Sprintf("No route for %s %s", r.Method, r.URL.Path), http.StatusNotFound)
+}
+
+// routeMethods are the methods probed when listing what a path allows
+var routeMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// methodNotAllowed responds with a JSON 405 and an Allow header listing the
+// methods router accepts for the requested path
+func methodNotAllowed(router *mux.Router) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var allowed []string
+		for _, method := range routeMethods {
+			probe := r.Clone(r.Context())
+			probe.Method = method
+			var match mux.RouteMatch
+			if router.Match(probe, &match) && match.MatchErr == nil {
+				allowed = append(allowed, method)
+			}
+		}
+
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		respondWithError(w, fmt.Sprintf("Method %s is not allowed for %s", r.Method, r.URL.Path), http.StatusMethodNotAllowed)
+	}
+}
+
+// paddleIDFromRequest returns the {id} route variable in canonical form, so
+// IDs pasted with other casing or accents still match
+func paddleIDFromRequest(r *http.Request) string {
+	return NormalizePaddleID(mux.Vars(r)["id"])
+}
+
+// PaddleResponse is the shape of a single paddle returned by the create,
+// update and get endpoints. The paddle is inlined, so id is always the paddle
+// ID; the database ID is db_id.
+type PaddleResponse struct {
+	DBID        int         `json:"db_id"`              // Database ID (primary key)
+	PaddleID    string      `json:"paddle_id"`          // Business identifier
+	DisplayName string      `json:"display_name"`       // See Paddle.DisplayName
+	Links       PaddleLinks `json:"links"`              // Related resources
+	Warnings    []string    `json:"warnings,omitempty"` // Sanity check warnings, if any
+	*Paddle
+}
+
+// legacyCreateResponse is the create response for API version 1 clients,
+// whose id was the database ID. Version 2 clients get a PaddleResponse,
+// where id is the paddle ID like every other paddle response.
+type legacyCreateResponse struct {
+	ID int `json:"id"` // Database ID (primary key)
+	PaddleResponse
+}
+
+// PaddleLinks are the paths of a paddle's related resources
+type PaddleLinks struct {
+	Self        string `json:"self"`
+	Performance string `json:"performance"`
+	SpecSheet   string `json:"spec_sheet"`
+}
+
+// newPaddleResponse wraps a paddle, whose DBID must be set, for the response
+func newPaddleResponse(paddle *Paddle, warnings []string) PaddleResponse {
+	self := "/api/paddles/" + url.PathEscape(paddle.ID)
+	return PaddleResponse{
+		DBID:        paddle.DBID,
+		PaddleID:    paddle.ID,
+		DisplayName: paddle.DisplayName(),
+		Links: PaddleLinks{
+			Self:        self,
+			Performance: self + "/performance",
+			SpecSheet:   self + "/sheet.pdf",
+		},
+		Warnings: warnings,
+		Paddle:   paddle,
+	}
+}
+
 // getPaddleStats handles the API request for fetching paddle statistics
 func getPaddleStats(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	paddleId := vars["id"]
+	paddleId := paddleIDFromRequest(r)
 
 	if err := validatePaddleID(paddleId); err != nil {
 		respondWithError(w, fmt.Sprintf("Invalid paddle ID: %v", err), http.StatusBadRequest)
 		return
 	}
 
-	paddle, err := GetPaddleByID(paddleId)
+	units, err := parseUnits(r.URL.Query().Get("units"))
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	paddle, err := store.GetPaddleByID(paddleId)
 
 	if err != nil {
-		log.Printf("Error converting ID to integer: %v", err)
-		http.Error(w, "Failed to retrieve paddle data", http.StatusNotFound)
+		log.Printf("Error retrieving paddle: %v", err)
+		respondWithError(w, "Failed to retrieve paddle data", http.StatusNotFound)
+		return
 	}
+	paddle.convertUnits(units)
 
 	// Encode the stats to JSON and handle any potential errors
-	if err := json.NewEncoder(w).Encode(paddle); err != nil {
+	if err := json.NewEncoder(w).Encode(newPaddleResponse(paddle, nil)); err != nil {
 		// If there's an error, set the status code to 500 and write the error message
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 }
 
+// uploadPaddleStats handles the public upload, which requires every section of a paddle
 func uploadPaddleStats(w http.ResponseWriter, r *http.Request) {
-	decoder := json.NewDecoder(r.Body)
-	decoder.DisallowUnknownFields()
+	uploadPaddle(w, r, fullProfile)
+}
+
+// uploadPaddleStub handles the internal upload of a paddle stub: metadata only,
+// with specs and performance filled in later by a full upsert
+func uploadPaddleStub(w http.ResponseWriter, r *http.Request) {
+	uploadPaddle(w, r, stubProfile)
+}
+
+// uploadPaddle validates and saves an uploaded paddle against a validation profile
+func uploadPaddle(w http.ResponseWriter, r *http.Request, profile validationProfile) {
+	// ?upsert=true updates an existing paddle instead of rejecting the duplicate.
+	// Stubs are only ever created, so a stub never overwrites a complete paddle.
+	upsert := false
+	if raw := r.URL.Query().Get("upsert"); raw != "" && profile == fullProfile {
+		var err error
+		upsert, err = strconv.ParseBool(raw)
+		if err != nil {
+			respondWithError(w, "upsert must be true or false", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// ?draft=true stages the paddle out of public listings until it is
+	// published. Only admins may create drafts.
+	draft := false
+	if raw := r.URL.Query().Get("draft"); raw != "" {
+		var err error
+		draft, err = strconv.ParseBool(raw)
+		if err != nil {
+			respondWithError(w, "draft must be true or false", http.StatusBadRequest)
+			return
+		}
+		if draft && !hasAPIKey(r) {
+			respondWithError(w, "Creating a draft requires the admin API key", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	// Keep the exact body for the audit trail before it is decoded
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		respondWithError(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
 
 	// Parse the JSON body into PaddleInput
 	var paddleInput PaddleInput
-	if err := decoder.Decode(&paddleInput); err != nil {
-		// This will catch any extra fields in the JSON
+	if err := decodeVersionedBody(r, &paddleInput); err != nil {
+		// This also catches extra fields, duplicate keys and excessive nesting
 		respondWithError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
 		return
 	}
 
 	// Validate the paddle input
-	if err := validatePaddleInput(&paddleInput); err != nil {
-		respondWithError(w, fmt.Sprintf("Validation error: %v", err), http.StatusBadRequest)
+	if err := validatePaddleInput(&paddleInput, profile); err != nil {
+		respondWithValidationError(w, r, err)
+		return
+	}
+
+	// Optionally sanity check pop against power
+	warnings, err := checkPopPower(&paddleInput.Performance)
+	if err != nil {
+		respondWithValidationError(w, r, err)
 		return
 	}
 
 	// Convert PaddleInput to Paddle (this generates the ID)
 	paddle := paddleInput.ToPaddle()
+	paddle.UploadBody = body
+	if draft {
+		paddle.Status = StatusDraft
+	}
 
-	log.Printf("paddle: %v", *paddle)
+	log.Printf("Saving paddle %s", paddle.ID)
 
-	// Save the paddle to the database
-	paddleDBID, err := SavePaddle(paddle)
+	// Save the paddle to the database, which sets its database ID and timestamps
+	status := http.StatusCreated
+	if upsert {
+		paddle.ID, err = upsertTargetID(paddle)
+		if err != nil {
+			log.Printf("Error finding paddle to upsert: %v", err)
+			http.Error(w, "Failed to save paddle data", http.StatusInternalServerError)
+			return
+		}
+		var created bool
+		_, created, err = store.UpsertPaddle(paddle)
+		if !created {
+			status = http.StatusOK
+		}
+	} else {
+		_, err = store.SavePaddle(paddle)
+	}
+	if errors.Is(err, ErrPaddleExists) {
+		respondWithError(w, fmt.Sprintf("Paddle with ID %s already exists", paddle.ID), http.StatusConflict)
+		return
+	}
+	if errors.Is(err, ErrQuotaExceeded) {
+		respondWithError(w, quotaExceededMessage(), http.StatusForbidden)
+		return
+	}
 	if err != nil {
 		log.Printf("Error saving paddle: %v", err)
 		http.Error(w, "Failed to save paddle data", http.StatusInternalServerError)
 		return
 	}
 
-	// Create a response that includes both the database ID and the paddle data
-	response := struct {
-		ID       int    `json:"id"`        // Database ID (primary key)
-		PaddleID string `json:"paddle_id"` // Business identifier
-		*Paddle         // Embed the full paddle data
-	}{
-		ID:       paddleDBID,
-		PaddleID: paddle.ID,
-		Paddle:   paddle,
+	// Respond with the paddle as the details endpoint reports it: stored in
+	// metric units, with the one measurement just uploaded
+	paddle.convertUnits(metricUnits)
+	if profile == fullProfile {
+		paddle.PerformanceSamples = 1
+	}
+	var response interface{} = newPaddleResponse(paddle, warnings)
+	if version, _ := requestAPIVersion(r); version < currentAPIVersion {
+		response = legacyCreateResponse{ID: paddle.DBID, PaddleResponse: newPaddleResponse(paddle, warnings)}
 	}
 
 	// Set status code BEFORE writing any data
-	w.WriteHeader(http.StatusCreated)
+	w.WriteHeader(status)
 
 	// Encode the response
 	if err := json.NewEncoder(w).Encode(response); err != nil {
@@ -135,6 +320,53 @@ func uploadPaddleStats(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// updatePaddlePerformance handles the API request for replacing a paddle's
+// performance measurements after a re-test
+func updatePaddlePerformance(w http.ResponseWriter, r *http.Request) {
+	paddleId := paddleIDFromRequest(r)
+
+	if err := validatePaddleID(paddleId); err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid paddle ID: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	var performance Performance
+	if err := decodeJSONBody(r.Body, &performance); err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := validatePerformance(&performance); err != nil {
+		respondWithValidationError(w, r, err)
+		return
+	}
+	roundPerformance(&performance)
+
+	if err := store.UpdatePaddlePerformance(paddleId, &performance); err != nil {
+		if errors.Is(err, ErrPaddleNotFound) {
+			respondWithError(w, "Paddle not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Error updating paddle performance: %v", err)
+		respondWithError(w, "Failed to update paddle performance", http.StatusInternalServerError)
+		return
+	}
+
+	// Return the full paddle with the updated performance
+	paddle, err := store.GetPaddleByID(paddleId)
+	if err != nil {
+		log.Printf("Error retrieving updated paddle: %v", err)
+		respondWithError(w, "Failed to retrieve updated paddle", http.StatusInternalServerError)
+		return
+	}
+	paddle.convertUnits(metricUnits)
+
+	if err := json.NewEncoder(w).Encode(newPaddleResponse(paddle, nil)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
 // Middleware to set common headers and handle errors
 func withCommonHeaders(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -146,9 +378,103 @@ func withCommonHeaders(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
-// getPaddlesList handles the API request for fetching basic paddle information for cards
+// paddleCard is the basic paddle information shown on catalog cards
+type paddleCard struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"display_name"`
+	Metadata    struct {
+		Brand string `json:"brand"`
+		Model string `json:"model"`
+		Year  *int   `json:"year,omitempty"`
+	} `json:"metadata"`
+	Specs   Specs   `json:"specs"`
+	Control float64 `json:"control"`
+}
+
+// newPaddleCard returns the card for a paddle, with its specs in units
+func newPaddleCard(paddle *Paddle, units unitSystem) paddleCard {
+	card := paddleCard{ID: paddle.ID, DisplayName: paddle.DisplayName(), Specs: paddle.Specs, Control: paddle.Control}
+	card.Specs.convertUnits(units)
+	card.Metadata.Brand = paddle.Metadata.Brand
+	card.Metadata.Model = paddle.Metadata.Model
+	card.Metadata.Year = paddle.Metadata.Year
+	return card
+}
+
+// getPaddlesList handles the API request for fetching basic paddle information for cards.
+// A Range header such as paddles=0-49 may replace limit and offset, for data
+// grids that load progressively; the slice is then returned with 206.
 func getPaddlesList(w http.ResponseWriter, r *http.Request) {
-	paddles, err := GetAllPaddles()
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid pagination: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	span, ranged, err := parseListRange(r.Header.Get("Range"))
+	if err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid range: %v", err), http.StatusBadRequest)
+		return
+	}
+	if ranged {
+		if r.URL.Query().Has("limit") || r.URL.Query().Has("offset") {
+			respondWithError(w, "Invalid pagination: use either the Range header or limit and offset", http.StatusBadRequest)
+			return
+		}
+		limit, offset = span.last-span.first+1, span.first
+	}
+	w.Header().Set("Accept-Ranges", listRangeUnit)
+	w.Header().Add("Vary", "Range")
+
+	filter, err := parsePaddleFilter(r.URL.Query())
+	if err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid filter: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	units, err := parseUnits(r.URL.Query().Get("units"))
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// ?status=draft lists the drafts instead, for admins only
+	if raw := r.URL.Query().Get("status"); raw != "" {
+		filter.Status, err = parsePaddleStatus(raw)
+		if err != nil {
+			respondWithError(w, fmt.Sprintf("Invalid filter: %v", err), http.StatusBadRequest)
+			return
+		}
+		if filter.Status == StatusDraft && !hasAPIKey(r) {
+			respondWithError(w, "Listing drafts requires the admin API key", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	sort := defaultListSort
+	if raw := r.URL.Query().Get("sort"); raw != "" {
+		sort, err = parseListSort(raw)
+		if err != nil {
+			respondWithError(w, fmt.Sprintf("Invalid sort: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	var total int
+	if ranged {
+		total, err = store.CountPaddlesMatching(filter)
+		if err != nil {
+			log.Printf("Error counting paddles: %v", err)
+			respondWithError(w, "Failed to retrieve paddles data", http.StatusInternalServerError)
+			return
+		}
+		if offset >= total {
+			respondRangeNotSatisfiable(w, total)
+			return
+		}
+	}
+
+	paddles, err := store.GetPaddlesPage(filter, sort, limit, offset)
 	if err != nil {
 		log.Printf("Error retrieving paddles: %v", err)
 		respondWithError(w, "Failed to retrieve paddles data", http.StatusInternalServerError)
@@ -156,41 +482,189 @@ func getPaddlesList(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create a simplified response with only the necessary fields for cards
-	type SimplePaddle struct {
-		ID       string `json:"id"`
-		Metadata struct {
-			Brand string `json:"brand"`
-			Model string `json:"model"`
-		} `json:"metadata"`
-		Specs Specs `json:"specs"`
+	cards := make([]paddleCard, 0, len(paddles))
+	for _, paddle := range paddles {
+		cards = append(cards, newPaddleCard(paddle, units))
 	}
 
-	simplePaddles := make([]SimplePaddle, 0, len(paddles))
-	for _, paddle := range paddles {
-		simplePaddle := SimplePaddle{
-			ID: paddle.ID,
-			Metadata: struct {
-				Brand string `json:"brand"`
-				Model string `json:"model"`
-			}{
-				Brand: paddle.Metadata.Brand,
-				Model: paddle.Metadata.Model,
-			},
-			Specs: paddle.Specs,
+	if ranged {
+		// Paddles may have been removed since they were counted
+		if len(cards) == 0 {
+			respondRangeNotSatisfiable(w, total)
+			return
 		}
-		simplePaddles = append(simplePaddles, simplePaddle)
+		w.Header().Set("Content-Range", fmt.Sprintf("%s %d-%d/%d", listRangeUnit, offset, offset+len(cards)-1, total))
+		w.WriteHeader(http.StatusPartialContent)
+	}
+
+	if err := json.NewEncoder(w).Encode(cards); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// getPaddleCountsByYear handles the API request for counting paddles per release year
+func getPaddleCountsByYear(w http.ResponseWriter, r *http.Request) {
+	counts, unknown, err := GetPaddleCountsByYear()
+	if err != nil {
+		log.Printf("Error counting paddles by year: %v", err)
+		respondWithError(w, "Failed to count paddles by year", http.StatusInternalServerError)
+		return
+	}
+
+	response := struct {
+		Years       []YearCount `json:"years"`
+		UnknownYear int         `json:"unknown_year"`
+	}{
+		Years:       counts,
+		UnknownYear: unknown,
+	}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// getPaddleByDBID handles the API request for fetching a paddle by its
+// numeric primary key rather than its paddle_id
+func getPaddleByDBID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		respondWithError(w, "Invalid internal ID: must be a positive integer", http.StatusBadRequest)
+		return
+	}
+
+	units, err := parseUnits(r.URL.Query().Get("units"))
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	paddle, err := store.GetPaddleByDBID(id)
+	if errors.Is(err, ErrPaddleNotFound) {
+		respondWithError(w, fmt.Sprintf("No paddle with internal ID %d", id), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Error retrieving paddle by internal ID: %v", err)
+		respondWithError(w, "Failed to retrieve paddle data", http.StatusInternalServerError)
+		return
+	}
+
+	// Respond exactly like the details endpoint, with averaged performance
+	paddle, err = loadPaddleDetails(paddle.ID)
+	if err != nil {
+		log.Printf("Error retrieving paddle details by internal ID: %v", err)
+		respondWithError(w, "Failed to retrieve paddle data", http.StatusInternalServerError)
+		return
+	}
+	paddle.convertUnits(units)
+
+	if err := json.NewEncoder(w).Encode(newPaddleResponse(paddle, nil)); err != nil {
+		log.Printf("Error encoding paddle: %v", err)
+	}
+}
+
+// getPaddleBySKU handles the API request for looking up a paddle by manufacturer SKU
+func getPaddleBySKU(w http.ResponseWriter, r *http.Request) {
+	sku := mux.Vars(r)["sku"]
+	if err := validateQueryText("sku", sku); err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid SKU: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	units, err := parseUnits(r.URL.Query().Get("units"))
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	paddle, err := GetPaddleBySKU(sku)
+	if errors.Is(err, ErrPaddleNotFound) {
+		respondWithError(w, fmt.Sprintf("No paddle with SKU %s", sku), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Error retrieving paddle by SKU: %v", err)
+		respondWithError(w, "Failed to retrieve paddle data", http.StatusInternalServerError)
+		return
 	}
+	paddle.convertUnits(units)
 
-	if err := json.NewEncoder(w).Encode(simplePaddles); err != nil {
+	if err := json.NewEncoder(w).Encode(newPaddleResponse(paddle, nil)); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 }
 
+// IDCheck reports whether a paddle ID is well-formed and, if so, whether it is
+// taken. Error explains a malformed ID.
+type IDCheck struct {
+	ID          string `json:"id"`
+	ValidFormat bool   `json:"valid_format"`
+	Exists      bool   `json:"exists"`
+	Error       string `json:"error,omitempty"`
+}
+
+// checkPaddleID handles the API request for checking a paddle ID's format and
+// existence in one call. It always answers 200, so clients can tell a
+// malformed ID from a missing paddle.
+func checkPaddleID(w http.ResponseWriter, r *http.Request) {
+	check := IDCheck{ID: paddleIDFromRequest(r)}
+
+	if err := validatePaddleID(check.ID); err != nil {
+		check.Error = err.Error()
+	} else {
+		check.ValidFormat = true
+		exists, err := store.PaddleExists(check.ID)
+		if err != nil {
+			log.Printf("Error checking for existing paddle: %v", err)
+			respondWithError(w, "Failed to check paddle ID", http.StatusInternalServerError)
+			return
+		}
+		check.Exists = exists
+	}
+
+	if err := json.NewEncoder(w).Encode(check); err != nil {
+		log.Printf("Error encoding paddle ID check: %v", err)
+	}
+}
+
+// loadPaddleDetails reads a paddle with what the details endpoint shows
+// beside it: performance averaged across measurements, spec ranges and tags.
+// Any failure to read the paddle itself is reported as ErrPaddleNotFound.
+func loadPaddleDetails(paddleId string) (*Paddle, error) {
+	paddle, err := store.GetPaddleByID(paddleId)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPaddleNotFound, err)
+	}
+
+	// Show the mean across all measurements when a paddle has been tested more than once
+	agg, err := store.GetAggregatedPerformance(paddleId)
+	if err != nil {
+		return nil, fmt.Errorf("aggregating performance: %w", err)
+	}
+	paddle.Performance = agg.Performance
+	paddle.PerformanceSamples = agg.SampleCount
+	paddle.Control = paddle.ControlRating()
+
+	// Show manufacturer-quoted ranges next to the measured specs
+	paddle.SpecRanges, err = store.GetSpecRanges(paddleId)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving spec ranges: %w", err)
+	}
+
+	paddle.Tags, err = store.GetTags(paddleId)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving tags: %w", err)
+	}
+	return paddle, nil
+}
+
 // getPaddleDetails handles the API request for fetching complete paddle details
 func getPaddleDetails(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	paddleId := vars["id"]
+	paddleId := paddleIDFromRequest(r)
 
 	// Validate the paddle ID
 	if err := validatePaddleID(paddleId); err != nil {
@@ -198,16 +672,50 @@ func getPaddleDetails(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	paddle, err := GetPaddleByID(paddleId)
+	// Parse the optional sparse fieldset before hitting the database
+	var fields []string
+	if raw, ok := r.URL.Query()["fields"]; ok {
+		var err error
+		fields, err = parseFields(strings.Join(raw, ","))
+		if err != nil {
+			respondWithError(w, fmt.Sprintf("Invalid fields: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	units, err := parseUnits(r.URL.Query().Get("units"))
 	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	paddle, err := loadPaddleDetails(paddleId)
+	if errors.Is(err, ErrPaddleNotFound) {
 		log.Printf("Error retrieving paddle: %v", err)
 		respondWithError(w, "Paddle not found", http.StatusNotFound)
 		return
 	}
+	if err != nil {
+		log.Printf("Error retrieving paddle details: %v", err)
+		respondWithError(w, "Failed to retrieve paddle details", http.StatusInternalServerError)
+		return
+	}
+	paddle.convertUnits(units)
+
+	// Return only the requested sections when a fieldset was given
+	var response interface{} = newPaddleResponse(paddle, nil)
+	if fields != nil {
+		response, err = selectFields(paddle, fields)
+		if err != nil {
+			respondWithError(w, fmt.Sprintf("Invalid fields: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
 
-	// Return the complete paddle data (including specs and performance)
-	if err := json.NewEncoder(w).Encode(paddle); err != nil {
+	// Return the paddle data (complete unless a fieldset was requested)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	paddleCounters.served.Add(1)
 }
@@ -3,11 +3,16 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
-	"time"
 
 	"github.com/gorilla/mux"
 )
@@ -20,20 +25,146 @@ func setupTestRouter() *mux.Router {
 	return router
 }
 
+// setupTestDB initializes and empties the database for a test, skipping the
+// test when no database is reachable or resets are not allowed. With
+// TEST_REQUIRE_DB=true it fails instead, so CI cannot pass by skipping
+func setupTestDB(t *testing.T) {
+	t.Helper()
+	skip := t.Skipf
+	if os.Getenv("TEST_REQUIRE_DB") == "true" {
+		skip = t.Fatalf
+	}
+	cfg, err := loadDBConfig()
+	if err != nil {
+		t.Fatalf("Invalid database configuration: %v", err)
+	}
+	if err := InitDB(cfg); err != nil {
+		skip("Skipping test, database unavailable: %v", err)
+	}
+	t.Cleanup(CloseDB)
+
+	// Start every test from empty tables, so tests can reuse paddle names
+	if allowed, err := loadResetAllowed(); err != nil || !allowed {
+		skip("Skipping test, set ENV=test or ALLOW_RESET=true to let tests reset the database")
+	}
+	if err := ResetData(); err != nil {
+		t.Fatalf("Failed to reset the database: %v", err)
+	}
+}
+
+// setupTestStore swaps in an empty in-memory store for the duration of a test,
+// so handler tests run without a database
+func setupTestStore(t *testing.T) {
+	t.Helper()
+	previous := store
+	store = newMemoryStore()
+	t.Cleanup(func() { store = previous })
+}
+
+// testSpecs and testPerformance are the valid specs and performance most
+// tests upload, so a test only spells out the fields it cares about
+var (
+	testSpecs = Specs{
+		Shape: Hybrid, Surface: "Composite", AverageWeight: 220.0, Core: 15.0,
+		PaddleLength: 16.5, PaddleWidth: 7.5, GripLength: 4.5, GripType: "Comfort", GripCircumference: 4.0,
+	}
+	testPerformance = Performance{Power: 75.0, Pop: 70.0, Spin: 3000.0, TwistWeight: 200.0, SwingWeight: 220.0, BalancePoint: 30.0}
+)
+
+// testPaddleInput returns a valid upload for brand and model with the shared
+// test specs and performance
+func testPaddleInput(brand, model string) *PaddleInput {
+	return &PaddleInput{
+		Metadata:    Metadata{Brand: brand, Model: model},
+		Specs:       testSpecs,
+		Performance: testPerformance,
+	}
+}
+
+// saveTestPaddle saves input through the current store, failing the test on
+// error, and returns the saved paddle
+func saveTestPaddle(t *testing.T, input *PaddleInput) *Paddle {
+	t.Helper()
+	paddle := input.ToPaddle()
+	if _, err := store.SavePaddle(paddle); err != nil {
+		t.Fatalf("Failed to save test paddle: %v", err)
+	}
+	return paddle
+}
+
+// addTestMeasurement records another measurement of a saved paddle, with
+// the shared test performance but for power
+func addTestMeasurement(t *testing.T, paddleID string, power float64) {
+	t.Helper()
+	_, err := DB.Exec(`
+		INSERT INTO paddle_performance (paddle_spec_id, power, pop, spin, twist_weight, swing_weight, balance_point)
+		SELECT s.id, $2, $3, $4, $5, $6, $7
+		FROM paddle_specs s JOIN paddles p ON p.id = s.paddle_id
+		WHERE p.paddle_id = $1
+	`, paddleID, power, testPerformance.Pop, testPerformance.Spin, testPerformance.TwistWeight,
+		testPerformance.SwingWeight, testPerformance.BalancePoint)
+	if err != nil {
+		t.Fatalf("Failed to add a measurement: %v", err)
+	}
+}
+
+// TestNotFoundHandler tests that unknown routes return the JSON error body
+func TestNotFoundHandler(t *testing.T) {
+	router := setupTestRouter()
+	router.NotFoundHandler = withCommonHeaders(notFound)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/does-not-exist", nil))
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Handler returned wrong status code: got %v want %v", rr.Code, http.StatusNotFound)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+
+	var body errorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Response is not a JSON error: %v (%s)", err, rr.Body.String())
+	}
+	if body.Code != http.StatusNotFound || body.Error != "Not Found" || body.Message == "" {
+		t.Errorf("Unexpected error body: %+v", body)
+	}
+}
+
+// TestMethodNotAllowedHandler tests that a disallowed method returns a JSON 405 with an Allow header
+func TestMethodNotAllowedHandler(t *testing.T) {
+	router := mux.NewRouter()
+	router.HandleFunc("/api/paddles/{id}", getPaddleDetails).Methods("GET")
+	router.HandleFunc("/api/paddles/{id}/performance", updatePaddlePerformance).Methods("PUT")
+	router.MethodNotAllowedHandler = withCommonHeaders(methodNotAllowed(router))
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/paddles/engage-pursuit-mx-6-0", nil))
+
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Handler returned wrong status code: got %v want %v", rr.Code, http.StatusMethodNotAllowed)
+	}
+	if allow := rr.Header().Get("Allow"); allow != "GET" {
+		t.Errorf("Allow = %q, want %q", allow, "GET")
+	}
+
+	var body errorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Response is not a JSON error: %v (%s)", err, rr.Body.String())
+	}
+	if body.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Unexpected error body: %+v", body)
+	}
+}
+
 // TestUploadPaddleStats tests the uploadPaddleStats handler
 func TestUploadPaddleStats(t *testing.T) {
-	// Initialize the database for testing
-	if err := InitDB(); err != nil {
-		t.Fatalf("Failed to initialize database: %v", err)
-	}
-	defer CloseDB()
+	setupTestStore(t)
 
 	// Create a router with the handler
 	router := setupTestRouter()
 
-	// Add a timestamp to make the model unique for each test run
-	uniqueModelSuffix := fmt.Sprintf("Test-%d", time.Now().UnixNano())
-
 	// Test cases
 	tests := []struct {
 		name           string
@@ -46,7 +177,7 @@ func TestUploadPaddleStats(t *testing.T) {
 			requestBody: map[string]interface{}{
 				"metadata": map[string]interface{}{
 					"brand": "Engage",
-					"model": "Pursuit MX 6.0 " + uniqueModelSuffix,
+					"model": "Pursuit MX 6.0",
 				},
 				"specs": map[string]interface{}{
 					"shape":              "Hybrid",
@@ -75,7 +206,7 @@ func TestUploadPaddleStats(t *testing.T) {
 			name: "Missing brand",
 			requestBody: map[string]interface{}{
 				"metadata": map[string]interface{}{
-					"model": "Pursuit MX 6.0 " + uniqueModelSuffix,
+					"model": "Pursuit MX 6.0",
 				},
 				"specs": map[string]interface{}{
 					"shape":              "Hybrid",
@@ -105,7 +236,7 @@ func TestUploadPaddleStats(t *testing.T) {
 			requestBody: map[string]interface{}{
 				"metadata": map[string]interface{}{
 					"brand": "Engage",
-					"model": "Pursuit MX 6.0 " + uniqueModelSuffix,
+					"model": "Pursuit MX 6.0",
 				},
 				"specs": map[string]interface{}{
 					"shape":              "InvalidShape",
@@ -166,49 +297,597 @@ func TestUploadPaddleStats(t *testing.T) {
 	}
 }
 
-// TestGetPaddleStats tests the getPaddleStats handler
-func TestGetPaddleStats(t *testing.T) {
-	// Initialize the database for testing
-	if err := InitDB(); err != nil {
-		t.Fatalf("Failed to initialize database: %v", err)
+// TestUploadPaddleStatsUpsert tests the insert and update branches of ?upsert=true
+func TestUploadPaddleStatsUpsert(t *testing.T) {
+	setupTestStore(t)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/paddles", uploadPaddleStats).Methods("POST")
+
+	input := testPaddleInput("Engage", "Pursuit MX 6.0")
+
+	post := func(query string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(input)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/paddles"+query, bytes.NewBuffer(body)))
+		return rr
 	}
-	defer CloseDB()
 
-	// Create a router with the handler
-	router := setupTestRouter()
+	// A new paddle is inserted
+	if rr := post("?upsert=true"); rr.Code != http.StatusCreated {
+		t.Fatalf("Insert branch returned %d, want %d: %s", rr.Code, http.StatusCreated, rr.Body.String())
+	}
 
-	// Add a timestamp to make the model unique for each test run
-	uniqueModelSuffix := fmt.Sprintf("Test-%d", time.Now().UnixNano())
+	// Without upsert, the duplicate is still rejected
+	if rr := post(""); rr.Code != http.StatusConflict {
+		t.Errorf("Duplicate without upsert returned %d, want %d", rr.Code, http.StatusConflict)
+	}
 
-	// First, create a paddle to retrieve
-	paddleInput := &PaddleInput{
-		Metadata: Metadata{
-			Brand: "Engage",
-			Model: "Pursuit MX 6.0 " + uniqueModelSuffix,
-		},
-		Specs: Specs{
-			Shape:             Hybrid,
-			Surface:           "Composite",
-			AverageWeight:     220.0,
-			Core:              15.0,
-			PaddleLength:      16.5,
-			PaddleWidth:       7.5,
-			GripLength:        4.5,
-			GripType:          "Comfort",
-			GripCircumference: 4.0,
-		},
+	// With upsert, the existing paddle is updated
+	input.Performance.Power = 80.0
+	input.Specs.Core = 16.0
+	if rr := post("?upsert=true"); rr.Code != http.StatusOK {
+		t.Fatalf("Update branch returned %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	paddle, err := store.GetPaddleByID(generatePaddleID("Engage", "Pursuit MX 6.0"))
+	if err != nil {
+		t.Fatalf("Failed to read upserted paddle: %v", err)
+	}
+	if paddle.Performance.Power != 80.0 || paddle.Specs.Core != 16.0 {
+		t.Errorf("Upserted paddle has power %v and core %v, want 80 and 16", paddle.Performance.Power, paddle.Specs.Core)
+	}
+
+	if rr := post("?upsert=maybe"); rr.Code != http.StatusBadRequest {
+		t.Errorf("Invalid upsert value returned %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+// TestPaddleResponseShape tests that creating a paddle under API version 2
+// returns the same response as getting it afterwards
+func TestPaddleResponseShape(t *testing.T) {
+	setupTestStore(t)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/paddles", uploadPaddleStats).Methods("POST")
+	router.HandleFunc("/api/paddles/{id}", getPaddleDetails).Methods("GET")
+
+	body, _ := json.Marshal(testPaddleInput("Engage", "Pursuit MX 6.0"))
+	req := httptest.NewRequest("POST", "/api/paddles", bytes.NewBuffer(body))
+	req.Header.Set("Accept-Version", "2")
+	create := httptest.NewRecorder()
+	router.ServeHTTP(create, req)
+	if create.Code != http.StatusCreated {
+		t.Fatalf("Create returned %d, want %d: %s", create.Code, http.StatusCreated, create.Body.String())
+	}
+
+	get := httptest.NewRecorder()
+	router.ServeHTTP(get, httptest.NewRequest("GET", "/api/paddles/engage-pursuit-mx-6.0", nil))
+	if get.Code != http.StatusOK {
+		t.Fatalf("Get returned %d, want %d: %s", get.Code, http.StatusOK, get.Body.String())
+	}
+
+	var created, got map[string]json.RawMessage
+	if err := json.Unmarshal(create.Body.Bytes(), &created); err != nil {
+		t.Fatalf("Failed to decode create response: %v", err)
+	}
+	if err := json.Unmarshal(get.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Failed to decode get response: %v", err)
+	}
+
+	for _, key := range []string{"db_id", "paddle_id", "id", "links", "metadata", "specs", "performance"} {
+		if _, ok := created[key]; !ok {
+			t.Errorf("Create response is missing %q", key)
+		}
+	}
+	if len(created) != len(got) {
+		t.Errorf("Create response has %d fields, get has %d", len(created), len(got))
+	}
+	for key, value := range created {
+		if string(got[key]) != string(value) {
+			t.Errorf("%s is %s on create but %s on get", key, value, got[key])
+		}
+	}
+	if string(created["display_name"]) != `"Engage Pursuit MX 6.0"` {
+		t.Errorf("display_name = %s, want \"Engage Pursuit MX 6.0\"", created["display_name"])
+	}
+	if string(created["id"]) != `"engage-pursuit-mx-6.0"` || string(created["db_id"]) != "1" {
+		t.Errorf("Create response has id %s and db_id %s, want the paddle ID and 1", created["id"], created["db_id"])
+	}
+}
+
+// TestLegacyCreateResponse tests that clients without Accept-Version still get
+// the database ID as the create response's id
+func TestLegacyCreateResponse(t *testing.T) {
+	setupTestStore(t)
+
+	body, _ := json.Marshal(testPaddleInput("Engage", "Pursuit MX 6.0"))
+	rr := httptest.NewRecorder()
+	uploadPaddleStats(rr, httptest.NewRequest("POST", "/api/paddles", bytes.NewBuffer(body)))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Create returned %d, want %d: %s", rr.Code, http.StatusCreated, rr.Body.String())
+	}
+
+	var created map[string]json.RawMessage
+	if err := json.Unmarshal(rr.Body.Bytes(), &created); err != nil {
+		t.Fatalf("Failed to decode create response: %v", err)
+	}
+	if string(created["id"]) != "1" || string(created["paddle_id"]) != `"engage-pursuit-mx-6.0"` {
+		t.Errorf("Version 1 create response has id %s and paddle_id %s, want 1 and the paddle ID", created["id"], created["paddle_id"])
+	}
+}
+
+// TestUploadPaddleValidationErrorCode tests that validation failures include their error code
+func TestUploadPaddleValidationErrorCode(t *testing.T) {
+	setupTestStore(t)
+
+	body := `{"metadata": {"brand": "Engage", "model": "Pursuit MX 6.0"}, "specs": {"shape": "Round"}}`
+	rr := httptest.NewRecorder()
+	uploadPaddleStats(rr, httptest.NewRequest("POST", "/api/paddles", bytes.NewBufferString(body)))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+
+	var response errorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.ErrorCode != "SHAPE_INVALID" || response.Code != http.StatusBadRequest {
+		t.Errorf("response = %+v, want error_code SHAPE_INVALID and code 400", response)
+	}
+}
+
+// TestUploadPaddleSymbolBrand tests that a brand and model too short of
+// letters and digits to form a paddle ID are rejected before saving
+func TestUploadPaddleSymbolBrand(t *testing.T) {
+	setupTestStore(t)
+
+	body, _ := json.Marshal(testPaddleInput("!!!", "X"))
+	rr := httptest.NewRecorder()
+	uploadPaddleStats(rr, httptest.NewRequest("POST", "/api/paddles", bytes.NewBuffer(body)))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d: %s", rr.Code, http.StatusBadRequest, rr.Body.String())
+	}
+	var response errorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.ErrorCode != "PADDLE_ID_INVALID" || !strings.Contains(response.Message, `got "x"`) {
+		t.Errorf("response = %+v, want error_code PADDLE_ID_INVALID naming the ID \"x\"", response)
+	}
+}
+
+// TestGetPaddlesListSurfaceFilter tests that the surface filter returns only matching paddles
+func TestGetPaddlesListSurfaceFilter(t *testing.T) {
+	setupTestStore(t)
+
+	for i, surface := range []string{"Carbon Fiber", "Fiberglass", "carbon fiber", "Kevlar"} {
+		paddle := &Paddle{
+			ID:       fmt.Sprintf("engage-surface-%d", i),
+			Metadata: Metadata{Brand: "Engage", Model: fmt.Sprintf("Surface %d", i)},
+			Specs: Specs{
+				Shape: Hybrid, Surface: surface, AverageWeight: 220.0, Core: 15.0,
+				PaddleLength: 16.5, PaddleWidth: 7.5, GripLength: 4.5, GripType: "Comfort", GripCircumference: 4.0,
+			},
+			Performance: testPerformance,
+		}
+		if _, err := store.SavePaddle(paddle); err != nil {
+			t.Fatalf("SavePaddle() error: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		wantCode int
+		wantIDs  []string
+	}{
+		{name: "Single surface", query: "surface=Carbon+Fiber", wantCode: http.StatusOK, wantIDs: []string{"engage-surface-0", "engage-surface-2"}},
+		{name: "Surface list", query: "surface=Fiberglass,Kevlar", wantCode: http.StatusOK, wantIDs: []string{"engage-surface-1", "engage-surface-3"}},
+		{name: "Unknown surface", query: "surface=Wood", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			getPaddlesList(rr, httptest.NewRequest("GET", "/api/paddles?"+tt.query, nil))
+
+			if rr.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rr.Code, tt.wantCode, rr.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				if !strings.Contains(rr.Body.String(), "Carbon Fiber, Raw Carbon") {
+					t.Errorf("error does not list the allowed surfaces: %s", rr.Body.String())
+				}
+				return
+			}
+
+			var paddles []struct {
+				ID string `json:"id"`
+			}
+			if err := json.Unmarshal(rr.Body.Bytes(), &paddles); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			var ids []string
+			for _, p := range paddles {
+				ids = append(ids, p.ID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.wantIDs, ",") {
+				t.Errorf("paddles = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+// TestUploadPaddleStub tests creating a metadata-only stub and completing it with a full upsert
+func TestUploadPaddleStub(t *testing.T) {
+	setupTestStore(t)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/paddles", uploadPaddleStats).Methods("POST")
+	router.HandleFunc("/api/admin/paddles/stub", uploadPaddleStub).Methods("POST")
+
+	post := func(path string, input PaddleInput) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(input)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("POST", path, bytes.NewBuffer(body)))
+		return rr
+	}
+
+	stub := PaddleInput{Metadata: Metadata{Brand: "Engage", Model: "Pursuit MX 6.0"}}
+
+	// The public upload still requires every section
+	if rr := post("/api/paddles", stub); rr.Code != http.StatusBadRequest {
+		t.Errorf("Metadata-only public upload returned %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+
+	if rr := post("/api/admin/paddles/stub", stub); rr.Code != http.StatusCreated {
+		t.Fatalf("Stub upload returned %d, want %d: %s", rr.Code, http.StatusCreated, rr.Body.String())
+	}
+
+	// Stubs stay hidden from reads until they are complete
+	paddleId := generatePaddleID("Engage", "Pursuit MX 6.0")
+	if _, err := store.GetPaddleByID(paddleId); !errors.Is(err, ErrPaddleNotFound) {
+		t.Errorf("GetPaddleByID() on a stub error = %v, want ErrPaddleNotFound", err)
+	}
+
+	full := stub
+	full.Specs = Specs{
+		Shape: Hybrid, Surface: "Composite", AverageWeight: 220.0, Core: 15.0,
+		PaddleLength: 16.5, PaddleWidth: 7.5, GripLength: 4.5, GripType: "Comfort", GripCircumference: 4.0,
+	}
+	full.Performance = Performance{Power: 75.0, Pop: 70.0, Spin: 3000.0, TwistWeight: 200.0, SwingWeight: 220.0, BalancePoint: 30.0}
+	if rr := post("/api/paddles?upsert=true", full); rr.Code != http.StatusOK {
+		t.Fatalf("Completing the stub returned %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	paddle, err := store.GetPaddleByID(paddleId)
+	if err != nil {
+		t.Fatalf("Failed to read completed stub: %v", err)
+	}
+	if paddle.Specs.Core != 15.0 || paddle.Performance.Power != 75.0 {
+		t.Errorf("Completed stub has core %v and power %v, want 15 and 75", paddle.Specs.Core, paddle.Performance.Power)
+	}
+}
+
+// TestPerformanceStddevRoundTrip tests that stddevs sent on upload come back
+// from the details endpoint, and that omitted ones stay omitted
+func TestPerformanceStddevRoundTrip(t *testing.T) {
+	setupTestStore(t)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/paddles", uploadPaddleStats).Methods("POST")
+	router.HandleFunc("/api/paddles/{id}", getPaddleDetails).Methods("GET")
+
+	powerStddev, spinStddev := 2.5, 120.0
+	input := PaddleInput{
+		Metadata: Metadata{Brand: "Engage", Model: "Pursuit MX 6.0"},
+		Specs:    testSpecs,
 		Performance: Performance{
-			Power:        75.0,
-			Pop:          70.0,
-			Spin:         3000.0,
-			TwistWeight:  200.0,
-			SwingWeight:  220.0,
-			BalancePoint: 30.0,
+			Power: 75.0, Pop: 70.0, Spin: 3000.0, TwistWeight: 200.0, SwingWeight: 220.0, BalancePoint: 30.0,
+			PowerStddev: &powerStddev, SpinStddev: &spinStddev,
 		},
 	}
+	body, _ := json.Marshal(input)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/paddles", bytes.NewBuffer(body)))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Upload returned %d, want %d: %s", rr.Code, http.StatusCreated, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/paddles/"+generatePaddleID("Engage", "Pursuit MX 6.0"), nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Details returned %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	var paddle Paddle
+	if err := json.Unmarshal(rr.Body.Bytes(), &paddle); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	got := paddle.Performance
+	if got.PowerStddev == nil || *got.PowerStddev != powerStddev {
+		t.Errorf("power_stddev = %v, want %v", got.PowerStddev, powerStddev)
+	}
+	if got.SpinStddev == nil || *got.SpinStddev != spinStddev {
+		t.Errorf("spin_stddev = %v, want %v", got.SpinStddev, spinStddev)
+	}
+	if got.PopStddev != nil || got.TwistWeightStddev != nil || got.SwingWeightStddev != nil || got.BalancePointStddev != nil {
+		t.Errorf("Omitted stddevs came back set: %+v", got)
+	}
+	if strings.Contains(rr.Body.String(), "pop_stddev") {
+		t.Errorf("Response includes pop_stddev although none was stored: %s", rr.Body.String())
+	}
+}
+
+// TestEdgeGuardHandleTypeRoundTrip tests that the optional construction
+// details sent on upload come back from the details endpoint
+func TestEdgeGuardHandleTypeRoundTrip(t *testing.T) {
+	setupTestStore(t)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/paddles", uploadPaddleStats).Methods("POST")
+	router.HandleFunc("/api/paddles/{id}", getPaddleDetails).Methods("GET")
+
+	input := testPaddleInput("Engage", "Pursuit MX 6.0")
+	input.Specs.EdgeGuard, input.Specs.HandleType = "Edgeless", "Foam-filled"
+	body, _ := json.Marshal(input)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/paddles", bytes.NewBuffer(body)))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Upload returned %d, want %d: %s", rr.Code, http.StatusCreated, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/paddles/"+generatePaddleID("Engage", "Pursuit MX 6.0"), nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Details returned %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	var paddle Paddle
+	if err := json.Unmarshal(rr.Body.Bytes(), &paddle); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if paddle.Specs.EdgeGuard != "Edgeless" || paddle.Specs.HandleType != "Foam-filled" {
+		t.Errorf("edge_guard = %q, handle_type = %q; want Edgeless and Foam-filled", paddle.Specs.EdgeGuard, paddle.Specs.HandleType)
+	}
+}
+
+// TestSurfaceSidesRoundTrip tests that a paddle with a different surface on
+// each side keeps both, and that a single-surface paddle gets its surface on
+// both sides only when it is in the surface enum
+func TestSurfaceSidesRoundTrip(t *testing.T) {
+	setupTestStore(t)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/paddles", uploadPaddleStats).Methods("POST")
+	router.HandleFunc("/api/paddles/{id}", getPaddleDetails).Methods("GET")
+
+	upload := func(model, surface, front, back string) Specs {
+		t.Helper()
+		input := testPaddleInput("Engage", model)
+		input.Specs.Surface, input.Specs.SurfaceFront, input.Specs.SurfaceBack = surface, front, back
+		body, _ := json.Marshal(input)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/paddles", bytes.NewBuffer(body)))
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("Upload returned %d, want %d: %s", rr.Code, http.StatusCreated, rr.Body.String())
+		}
+
+		rr = httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/paddles/"+generatePaddleID("Engage", model), nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Details returned %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+		}
+		var paddle Paddle
+		if err := json.Unmarshal(rr.Body.Bytes(), &paddle); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return paddle.Specs
+	}
+
+	specs := upload("Pursuit MX 6.0", "Carbon Fiber", "Raw Carbon", "Fiberglass")
+	if specs.Surface != "Carbon Fiber" || specs.SurfaceFront != "Raw Carbon" || specs.SurfaceBack != "Fiberglass" {
+		t.Errorf("surface = %q, front = %q, back = %q; want Carbon Fiber, Raw Carbon and Fiberglass", specs.Surface, specs.SurfaceFront, specs.SurfaceBack)
+	}
+
+	specs = upload("Pursuit Pro", "carbon fiber", "", "")
+	if specs.SurfaceFront != "Carbon Fiber" || specs.SurfaceBack != "Carbon Fiber" {
+		t.Errorf("front = %q, back = %q; want Carbon Fiber on both sides", specs.SurfaceFront, specs.SurfaceBack)
+	}
+
+	specs = upload("Pursuit EX", "Textured Wood", "", "")
+	if specs.Surface != "Textured Wood" || specs.SurfaceFront != "" || specs.SurfaceBack != "" {
+		t.Errorf("surface = %q, front = %q, back = %q; want Textured Wood and no sides", specs.Surface, specs.SurfaceFront, specs.SurfaceBack)
+	}
+}
+
+// TestGetPaddleDetailsNormalizesID tests that mixed-case and accented IDs
+// resolve to the canonical paddle
+func TestGetPaddleDetailsNormalizesID(t *testing.T) {
+	setupTestStore(t)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/paddles/{id}", getPaddleDetails).Methods("GET")
+
+	paddle := saveTestPaddle(t, testPaddleInput("Engage", "Pursuit MX 6.0"))
+
+	for _, id := range []string{"ENGAGE-PURSUIT-MX-6.0", "Engage-Pursuit-mx-6.0", "Éngagé-Pursuït-MX-6.0"} {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/paddles/"+url.PathEscape(id), nil))
+		if rr.Code != http.StatusOK {
+			t.Errorf("GET %q returned %d, want %d", id, rr.Code, http.StatusOK)
+			continue
+		}
+
+		var got Paddle
+		if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if got.ID != paddle.ID {
+			t.Errorf("GET %q returned paddle %q, want %q", id, got.ID, paddle.ID)
+		}
+	}
+}
+
+// TestCheckPaddleID tests checking malformed, missing and existing paddle IDs
+func TestCheckPaddleID(t *testing.T) {
+	setupTestStore(t)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/paddles/{id}/check", checkPaddleID).Methods("GET")
+
+	paddle := &Paddle{ID: generatePaddleID("Engage", "Pursuit MX 6.0"), Metadata: Metadata{Brand: "Engage", Model: "Pursuit MX 6.0"}}
+	if _, err := store.SavePaddle(paddle); err != nil {
+		t.Fatalf("Failed to save test paddle: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		id   string
+		want IDCheck
+	}{
+		{name: "Existing", id: "engage-pursuit-mx-6.0", want: IDCheck{ID: "engage-pursuit-mx-6.0", ValidFormat: true, Exists: true}},
+		{name: "Existing in another case", id: "Engage-Pursuit-MX-6.0", want: IDCheck{ID: "engage-pursuit-mx-6.0", ValidFormat: true, Exists: true}},
+		{name: "Well-formed but missing", id: "engage-pursuit-ex-6.0", want: IDCheck{ID: "engage-pursuit-ex-6.0", ValidFormat: true}},
+		{name: "Malformed", id: strings.Repeat("a", maxPaddleIDLength+1), want: IDCheck{ID: strings.Repeat("a", maxPaddleIDLength+1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/paddles/"+url.PathEscape(tt.id)+"/check", nil))
+			if rr.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+			}
+
+			var got IDCheck
+			if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if !tt.want.ValidFormat && got.Error == "" {
+				t.Errorf("Expected an error explaining the malformed ID")
+			}
+			got.Error = ""
+			if got != tt.want {
+				t.Errorf("check = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestGetPaddleByDBID tests lookup by numeric primary key, including non-numeric IDs
+func TestGetPaddleByDBID(t *testing.T) {
+	setupTestStore(t)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/paddles/internal/{id}", getPaddleByDBID).Methods("GET")
+	router.HandleFunc("/api/paddles/{id}", getPaddleDetails).Methods("GET")
+
+	paddle := testPaddleInput("Engage", "Pursuit MX 6.0").ToPaddle()
+	dbID, err := store.SavePaddle(paddle)
+	if err != nil {
+		t.Fatalf("Failed to save test paddle: %v", err)
+	}
+	if _, err := store.AddTags(paddle.ID, []string{"beginner-friendly"}); err != nil {
+		t.Fatalf("Failed to tag test paddle: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		id         string
+		wantStatus int
+	}{
+		{name: "Valid ID", id: strconv.Itoa(dbID), wantStatus: http.StatusOK},
+		{name: "Unknown ID", id: strconv.Itoa(dbID + 1), wantStatus: http.StatusNotFound},
+		{name: "Non-numeric ID", id: paddle.ID, wantStatus: http.StatusBadRequest},
+		{name: "Zero", id: "0", wantStatus: http.StatusBadRequest},
+		{name: "Negative", id: "-1", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/paddles/internal/"+tt.id, nil))
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rr.Code, tt.wantStatus, rr.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var got Paddle
+			if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if got.ID != paddle.ID || got.Specs.Core != paddle.Specs.Core || got.Performance.Power != paddle.Performance.Power {
+				t.Errorf("GET internal/%s = %+v, want paddle %s", tt.id, got, paddle.ID)
+			}
+
+			// The same body as the details endpoint, tags and sample count included
+			details := httptest.NewRecorder()
+			router.ServeHTTP(details, httptest.NewRequest("GET", "/api/paddles/"+paddle.ID, nil))
+			if rr.Body.String() != details.Body.String() {
+				t.Errorf("GET internal/%s = %s, want the details body %s", tt.id, rr.Body.String(), details.Body.String())
+			}
+		})
+	}
+}
+
+// TestSavePaddleDuplicate tests that SavePaddle rejects a paddle whose ID
+// is taken, whatever its model is called
+func TestSavePaddleDuplicate(t *testing.T) {
+	setupTestDB(t)
+
+	input := testPaddleInput("Engage", "Test-Pursuit")
+	if _, err := SavePaddle(input.ToPaddle()); err != nil {
+		t.Fatalf("SavePaddle() error: %v", err)
+	}
+	if _, err := SavePaddle(input.ToPaddle()); !errors.Is(err, ErrPaddleExists) {
+		t.Errorf("SavePaddle() of a duplicate error = %v, want ErrPaddleExists", err)
+	}
+}
+
+// TestUpsertPaddle tests both branches of the Postgres upsert
+func TestUpsertPaddle(t *testing.T) {
+	setupTestDB(t)
+
+	input := testPaddleInput("Engage", "Pursuit MX 6.0")
+
+	firstID, created, err := UpsertPaddle(input.ToPaddle())
+	if err != nil || !created {
+		t.Fatalf("UpsertPaddle() insert = (%d, %v, %v), want created", firstID, created, err)
+	}
+
+	input.Performance.Power = 80.0
+	secondID, created, err := UpsertPaddle(input.ToPaddle())
+	if err != nil || created || secondID != firstID {
+		t.Fatalf("UpsertPaddle() update = (%d, %v, %v), want (%d, false, nil)", secondID, created, err, firstID)
+	}
+
+	agg, err := GetAggregatedPerformance(input.ToPaddle().ID)
+	if err != nil {
+		t.Fatalf("Failed to read upserted performance: %v", err)
+	}
+	if agg.SampleCount != 1 || agg.Power != 80.0 {
+		t.Errorf("Upserted performance = %+v, want a single measurement with power 80", agg)
+	}
+}
+
+// TestGetPaddleStats tests the getPaddleStats handler
+func TestGetPaddleStats(t *testing.T) {
+	setupTestStore(t)
+
+	// Create a router with the handler
+	router := setupTestRouter()
+
+	// First, create a paddle to retrieve
+	paddleInput := testPaddleInput("Engage", "Pursuit MX 6.0")
 
 	paddle := paddleInput.ToPaddle()
-	_, err := SavePaddle(paddle)
+	_, err := store.SavePaddle(paddle)
 	if err != nil {
 		t.Fatalf("Failed to save test paddle: %v", err)
 	}
@@ -233,8 +912,9 @@ func TestGetPaddleStats(t *testing.T) {
 			expectedBody:   "Failed to retrieve paddle data",
 		},
 		{
-			name:           "Empty paddle ID",
-			paddleID:       "",
+			// A blank segment, since the router never matches an empty one
+			name:           "Blank paddle ID",
+			paddleID:       "%20",
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   "paddle ID is required",
 		},
@@ -266,3 +946,191 @@ func TestGetPaddleStats(t *testing.T) {
 		})
 	}
 }
+
+// TestGetPaddleBySKU tests looking up a paddle by manufacturer SKU
+func TestGetPaddleBySKU(t *testing.T) {
+	setupTestDB(t)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/paddles/by-sku/{sku}", getPaddleBySKU).Methods("GET")
+
+	input := testPaddleInput("Engage", "Pursuit MX 6.0")
+	input.Metadata.SKU = "ENG-PMX6"
+	input.Metadata.ProductURL = "https://engagepickleball.com/products/pursuit-mx-6-0"
+	paddle := input.ToPaddle()
+	if _, err := SavePaddle(paddle); err != nil {
+		t.Fatalf("Failed to save paddle: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/paddles/by-sku/"+input.Metadata.SKU, nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+
+	var got Paddle
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if got.ID != paddle.ID || got.Metadata.SKU != input.Metadata.SKU || got.Metadata.ProductURL != input.Metadata.ProductURL {
+		t.Errorf("Unexpected paddle: %+v", got.Metadata)
+	}
+
+	// Unknown SKUs return 404
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/paddles/by-sku/NO-SUCH-SKU", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Handler returned wrong status code for unknown SKU: got %v want %v", rr.Code, http.StatusNotFound)
+	}
+}
+
+// TestUpdatePaddlePerformance tests the updatePaddlePerformance handler
+func TestUpdatePaddlePerformance(t *testing.T) {
+	setupTestDB(t)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/paddles/{id}/performance", updatePaddlePerformance).Methods("PUT")
+
+	paddleInput := testPaddleInput("Engage", "Pursuit MX 6.0")
+
+	paddle := paddleInput.ToPaddle()
+	if _, err := SavePaddle(paddle); err != nil {
+		t.Fatalf("Failed to save test paddle: %v", err)
+	}
+
+	retested := Performance{
+		Power:        80.0,
+		Pop:          72.0,
+		Spin:         2800.0,
+		TwistWeight:  205.0,
+		SwingWeight:  118.0,
+		BalancePoint: 23.5,
+	}
+	jsonBody, err := json.Marshal(retested)
+	if err != nil {
+		t.Fatalf("Failed to marshal request body: %v", err)
+	}
+
+	req, err := http.NewRequest("PUT", "/api/paddles/"+paddle.ID+"/performance", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Handler returned wrong status code: got %v want %v, body %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	updated, err := GetPaddleByID(paddle.ID)
+	if err != nil {
+		t.Fatalf("Failed to retrieve updated paddle: %v", err)
+	}
+
+	if updated.Performance != retested {
+		t.Errorf("Performance not updated: got %+v want %+v", updated.Performance, retested)
+	}
+	if updated.Specs != paddle.Specs {
+		t.Errorf("Specs changed after performance update: got %+v want %+v", updated.Specs, paddle.Specs)
+	}
+	if updated.Metadata != paddle.Metadata {
+		t.Errorf("Metadata changed after performance update: got %+v want %+v", updated.Metadata, paddle.Metadata)
+	}
+
+	// Invalid performance is rejected before touching the database
+	req, _ = http.NewRequest("PUT", "/api/paddles/"+paddle.ID+"/performance", bytes.NewBufferString(`{"power": 150}`))
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Handler returned wrong status code for invalid performance: got %v want %v", rr.Code, http.StatusBadRequest)
+	}
+
+	// Unknown paddles return 404
+	req, _ = http.NewRequest("PUT", "/api/paddles/NONEXISTENT-ID/performance", bytes.NewBuffer(jsonBody))
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Handler returned wrong status code for unknown paddle: got %v want %v", rr.Code, http.StatusNotFound)
+	}
+}
+
+// TestUpdatePaddlePerformanceLatestOnly tests that replacing the performance
+// of a paddle with several measurements changes only the latest one
+func TestUpdatePaddlePerformanceLatestOnly(t *testing.T) {
+	setupTestDB(t)
+
+	paddle := testPaddleInput("Engage", "Pursuit MX 6.0").ToPaddle()
+	if _, err := SavePaddle(paddle); err != nil {
+		t.Fatalf("Failed to save test paddle: %v", err)
+	}
+	if _, err := DB.Exec(`
+		INSERT INTO paddle_performance (paddle_spec_id, power, pop, spin, twist_weight, swing_weight, balance_point)
+		SELECT s.id, 65, 60, 2500, 190, 210, 29
+		FROM paddle_specs s JOIN paddles p ON p.id = s.paddle_id
+		WHERE p.paddle_id = $1
+	`, paddle.ID); err != nil {
+		t.Fatalf("Failed to add a second measurement: %v", err)
+	}
+
+	retested := testPerformance
+	retested.Power = 85
+	if err := UpdatePaddlePerformance(paddle.ID, &retested); err != nil {
+		t.Fatalf("UpdatePaddlePerformance() error: %v", err)
+	}
+
+	var powers []float64
+	rows, err := DB.Query(`
+		SELECT pp.power
+		FROM paddle_performance pp
+		JOIN paddle_specs s ON s.id = pp.paddle_spec_id
+		JOIN paddles p ON p.id = s.paddle_id
+		WHERE p.paddle_id = $1
+		ORDER BY pp.id
+	`, paddle.ID)
+	if err != nil {
+		t.Fatalf("Failed to query measurements: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var power float64
+		if err := rows.Scan(&power); err != nil {
+			t.Fatalf("Failed to scan measurement: %v", err)
+		}
+		powers = append(powers, power)
+	}
+	if !slices.Equal(powers, []float64{75, 85}) {
+		t.Errorf("measured powers = %v, want the first kept and the latest replaced: [75 85]", powers)
+	}
+}
+
+// TestGetPaddleCountsByYear tests counting paddles per year, including unknown years
+func TestGetPaddleCountsByYear(t *testing.T) {
+	setupTestDB(t)
+
+	for i, year := range []*int{intPtr(2021), intPtr(2023), intPtr(2023), nil} {
+		paddle := &Paddle{
+			ID:       fmt.Sprintf("year-test-%d", i),
+			Metadata: Metadata{Brand: "Year", Model: fmt.Sprintf("Test %d", i), Year: year},
+			Specs: Specs{
+				Shape: Hybrid, Surface: "Carbon", AverageWeight: 220, Core: 16, PaddleLength: 16.5,
+				PaddleWidth: 7.5, GripLength: 5.25, GripType: "Standard", GripCircumference: 4.25,
+			},
+			Performance: Performance{Power: 50, Pop: 50, Spin: 2000, TwistWeight: 6, SwingWeight: 115, BalancePoint: 23},
+		}
+		if _, err := SavePaddle(paddle); err != nil {
+			t.Fatalf("Failed to save paddle: %v", err)
+		}
+	}
+
+	counts, unknown, err := GetPaddleCountsByYear()
+	if err != nil {
+		t.Fatalf("GetPaddleCountsByYear() returned error: %v", err)
+	}
+	want := []YearCount{{Year: 2021, Count: 1}, {Year: 2023, Count: 2}}
+	if !slices.Equal(counts, want) {
+		t.Errorf("GetPaddleCountsByYear() = %+v, want %+v", counts, want)
+	}
+	if unknown != 1 {
+		t.Errorf("Unknown year count = %d, want 1", unknown)
+	}
+}
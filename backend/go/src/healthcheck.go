@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Defaults for the readiness probe's database ping
+const (
+	defaultHealthPingTimeoutMS = 2000
+	defaultHealthPingRetries   = 0
+	healthPingRetryDelay       = 100 * time.Millisecond
+)
+
+// Reasons the readiness probe gives for an unreachable database
+const (
+	pingReasonNotConnected = "not connected"
+	pingReasonTimeout      = "timeout"
+	pingReasonRefused      = "connection refused"
+	pingReasonError        = "error"
+)
+
+// healthPingTimeout bounds a whole readiness probe, retries included, so a
+// half-up database cannot hang it. healthPingRetries is how many more pings
+// follow a failed one. Set via HEALTH_PING_TIMEOUT_MS and HEALTH_PING_RETRIES.
+var (
+	healthPingTimeout = time.Duration(defaultHealthPingTimeoutMS) * time.Millisecond
+	healthPingRetries = defaultHealthPingRetries
+)
+
+// healthCheckConfig holds the database ping settings
+type healthCheckConfig struct {
+	PingTimeout time.Duration
+	PingRetries int
+}
+
+// loadHealthCheckConfig reads the database ping settings from the environment
+func loadHealthCheckConfig() (healthCheckConfig, error) {
+	timeoutMS, err := strconv.Atoi(getEnv("HEALTH_PING_TIMEOUT_MS", strconv.Itoa(defaultHealthPingTimeoutMS)))
+	if err != nil || timeoutMS <= 0 {
+		return healthCheckConfig{}, fmt.Errorf("HEALTH_PING_TIMEOUT_MS must be a positive integer")
+	}
+
+	retries, err := strconv.Atoi(getEnv("HEALTH_PING_RETRIES", strconv.Itoa(defaultHealthPingRetries)))
+	if err != nil || retries < 0 {
+		return healthCheckConfig{}, fmt.Errorf("HEALTH_PING_RETRIES must be a non-negative integer")
+	}
+
+	return healthCheckConfig{PingTimeout: time.Duration(timeoutMS) * time.Millisecond, PingRetries: retries}, nil
+}
+
+// pinger is implemented by *sql.DB
+type pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// pingDatabase pings db up to 1+retries times, all within ctx, and returns
+// an empty reason when a ping succeeds. Otherwise the reason describes the
+// last failure.
+func pingDatabase(ctx context.Context, db pinger, retries int) string {
+	if db == nil {
+		return pingReasonNotConnected
+	}
+
+	var err error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return pingFailureReason(ctx.Err())
+			case <-time.After(healthPingRetryDelay):
+			}
+		}
+
+		if err = pingWithin(ctx, db); err == nil {
+			return ""
+		}
+	}
+	return pingFailureReason(err)
+}
+
+// pingWithin pings db but stops waiting once ctx is done, even if the driver
+// ignores the context, so a probe never outlasts its deadline
+func pingWithin(ctx context.Context, db pinger) error {
+	result := make(chan error, 1)
+	go func() { result <- db.PingContext(ctx) }()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// pingFailureReason classifies a ping error as a timeout, a refused
+// connection, or any other error
+func pingFailureReason(err error) string {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return pingReasonTimeout
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return pingReasonRefused
+	default:
+		return pingReasonError
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// fakePinger fails its first failures pings with err, or blocks until
+// released when hang is set
+type fakePinger struct {
+	failures int
+	err      error
+	hang     chan struct{}
+	pings    int
+}
+
+func (f *fakePinger) PingContext(ctx context.Context) error {
+	f.pings++
+	if f.hang != nil {
+		// Ignore ctx, like a driver stuck on a half-up database
+		<-f.hang
+		return nil
+	}
+	if f.pings <= f.failures {
+		return f.err
+	}
+	return nil
+}
+
+// refusedError returns the error of dialing a port nothing listens on
+func refusedError(t *testing.T) error {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	conn, err := net.Dial("tcp", addr)
+	if err == nil {
+		conn.Close()
+		t.Skip("Dialing a closed port succeeded")
+	}
+	return err
+}
+
+// TestPingDatabase tests the reasons and retries of the readiness ping
+func TestPingDatabase(t *testing.T) {
+	refused := refusedError(t)
+
+	tests := []struct {
+		name      string
+		db        *fakePinger
+		retries   int
+		want      string
+		wantPings int
+	}{
+		{name: "Healthy", db: &fakePinger{}, want: "", wantPings: 1},
+		{name: "Refused", db: &fakePinger{failures: 1, err: refused}, want: pingReasonRefused, wantPings: 1},
+		{name: "Other error", db: &fakePinger{failures: 1, err: errors.New("password authentication failed")}, want: pingReasonError, wantPings: 1},
+		{name: "Recovers on retry", db: &fakePinger{failures: 2, err: refused}, retries: 2, want: "", wantPings: 3},
+		{name: "Retries exhausted", db: &fakePinger{failures: 5, err: refused}, retries: 2, want: pingReasonRefused, wantPings: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pingDatabase(context.Background(), tt.db, tt.retries); got != tt.want {
+				t.Errorf("pingDatabase() = %q, want %q", got, tt.want)
+			}
+			if tt.db.pings != tt.wantPings {
+				t.Errorf("pinged %d times, want %d", tt.db.pings, tt.wantPings)
+			}
+		})
+	}
+
+	if got := pingDatabase(context.Background(), nil, 0); got != pingReasonNotConnected {
+		t.Errorf("pingDatabase(nil) = %q, want %q", got, pingReasonNotConnected)
+	}
+}
+
+// TestPingDatabaseTimeout tests that a ping stuck on a database ignoring the
+// context gives up as soon as the context times out, retries included
+func TestPingDatabaseTimeout(t *testing.T) {
+	db := &fakePinger{hang: make(chan struct{})}
+	defer close(db.hang)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+
+	start := time.Now()
+	if got := pingDatabase(ctx, db, 3); got != pingReasonTimeout {
+		t.Errorf("pingDatabase() = %q, want %q", got, pingReasonTimeout)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("pingDatabase() took %v after its context timed out", elapsed)
+	}
+}
+
+// TestReadyzReason tests that an unhealthy readiness response says why
+func TestReadyzReason(t *testing.T) {
+	previous := DB
+	DB = nil
+	defer func() { DB = previous }()
+
+	rr := httptest.NewRecorder()
+	readyz(rr, httptest.NewRequest("GET", "/readyz", nil))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusServiceUnavailable)
+	}
+
+	var body map[string]string
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if body["status"] != "database unavailable" || body["reason"] != pingReasonNotConnected {
+		t.Errorf("body = %v, want status database unavailable and reason %q", body, pingReasonNotConnected)
+	}
+}
+
+// TestLoadHealthCheckConfig tests reading the ping settings from the environment
+func TestLoadHealthCheckConfig(t *testing.T) {
+	t.Setenv("HEALTH_PING_TIMEOUT_MS", "500")
+	t.Setenv("HEALTH_PING_RETRIES", "2")
+	cfg, err := loadHealthCheckConfig()
+	if err != nil {
+		t.Fatalf("loadHealthCheckConfig() error: %v", err)
+	}
+	if cfg.PingTimeout != 500*time.Millisecond || cfg.PingRetries != 2 {
+		t.Errorf("timeout %v and retries %d, want 500ms and 2", cfg.PingTimeout, cfg.PingRetries)
+	}
+
+	for _, env := range []struct{ key, value string }{
+		{"HEALTH_PING_TIMEOUT_MS", "0"},
+		{"HEALTH_PING_TIMEOUT_MS", "soon"},
+		{"HEALTH_PING_RETRIES", "-1"},
+	} {
+		t.Run(env.key+"="+env.value, func(t *testing.T) {
+			t.Setenv("HEALTH_PING_TIMEOUT_MS", "500")
+			t.Setenv("HEALTH_PING_RETRIES", "0")
+			t.Setenv(env.key, env.value)
+			if _, err := loadHealthCheckConfig(); err == nil {
+				t.Errorf("loadHealthCheckConfig() accepted %s=%s", env.key, env.value)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Defaults for the number of histogram buckets
+const (
+	defaultHistogramBuckets = 10
+	maxHistogramBuckets     = 100
+)
+
+// HistogramBucket counts the paddles whose metric falls in [Min, Max). The
+// last bucket also includes its Max, so the largest value is counted.
+type HistogramBucket struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Count int     `json:"count"`
+}
+
+// Histogram is the distribution of a metric across the dataset. Reason
+// explains when fewer buckets than requested are returned.
+type Histogram struct {
+	Metric      string            `json:"metric"`
+	SampleCount int               `json:"sample_count"`
+	Buckets     []HistogramBucket `json:"buckets"`
+	Reason      string            `json:"reason,omitempty"`
+}
+
+// parseHistogramParams reads and validates the metric name and bucket count,
+// defaulting to defaultHistogramBuckets
+func parseHistogramParams(query url.Values) (string, int, error) {
+	metric := query.Get("metric")
+	if _, ok := performanceMetricColumns[metric]; !ok {
+		return "", 0, fmt.Errorf("metric must be one of %s", strings.Join(performanceMetrics, ", "))
+	}
+
+	buckets := defaultHistogramBuckets
+	if raw := query.Get("buckets"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxHistogramBuckets {
+			return "", 0, fmt.Errorf("buckets must be an integer between 1 and %d", maxHistogramBuckets)
+		}
+		buckets = n
+	}
+	return metric, buckets, nil
+}
+
+// histogram splits the range of values into n equal-width buckets and counts
+// the values in each. With no values there are no buckets, and when every
+// value is the same they all go in a single zero-width bucket.
+func histogram(metric string, values []float64, n int) Histogram {
+	result := Histogram{Metric: metric, SampleCount: len(values), Buckets: []HistogramBucket{}}
+
+	if len(values) == 0 {
+		result.Reason = "no paddles have been measured"
+		return result
+	}
+
+	lo, hi := slices.Min(values), slices.Max(values)
+	if lo == hi {
+		result.Buckets = append(result.Buckets, HistogramBucket{Min: lo, Max: hi, Count: len(values)})
+		result.Reason = fmt.Sprintf("%s is the same for every paddle, so there is a single bucket", metric)
+		return result
+	}
+
+	width := (hi - lo) / float64(n)
+	for i := 0; i < n; i++ {
+		bucket := HistogramBucket{Min: lo + float64(i)*width, Max: lo + float64(i+1)*width}
+		if i == n-1 {
+			// Avoid floating point drift leaving the maximum outside the last bucket
+			bucket.Max = hi
+		}
+		result.Buckets = append(result.Buckets, bucket)
+	}
+
+	for _, v := range values {
+		i := min(int((v-lo)/width), n-1)
+		result.Buckets[i].Count++
+	}
+	return result
+}
+
+// GetMetricValues returns the metric of every published paddle, averaged across each
+// paddle's measurements like the details endpoint
+func GetMetricValues(metric string) ([]float64, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	// The column name comes from the performanceMetricColumns whitelist
+	rows, err := timedQuery(ctx, DB, "get_metric_values", fmt.Sprintf(`
+		SELECT
+			AVG(%s)
+		FROM
+			paddles p
+		JOIN
+			paddle_specs s ON p.id = s.paddle_id
+		JOIN
+			paddle_performance perf ON s.id = perf.paddle_spec_id
+		WHERE
+			p.status = 'published'
+		GROUP BY
+			p.id
+	`, performanceMetricColumns[metric]))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []float64
+	for rows.Next() {
+		var v float64
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, rows.Err()
+}
+
+// getHistogram handles the API request for the distribution of a metric
+func getHistogram(w http.ResponseWriter, r *http.Request) {
+	metric, buckets, err := parseHistogramParams(r.URL.Query())
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	values, err := GetMetricValues(metric)
+	if err != nil {
+		log.Printf("Error retrieving metric values for histogram: %v", err)
+		respondWithError(w, "Failed to retrieve paddle metrics", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(histogram(metric, values, buckets)); err != nil {
+		log.Printf("Error encoding histogram: %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"net/url"
+	"slices"
+	"testing"
+)
+
+// TestHistogram tests bucket boundaries and counts, including the edge values
+func TestHistogram(t *testing.T) {
+	result := histogram("power", []float64{60, 62, 65, 70, 71, 80}, 4)
+
+	want := []HistogramBucket{
+		{Min: 60, Max: 65, Count: 2},
+		{Min: 65, Max: 70, Count: 1},
+		{Min: 70, Max: 75, Count: 2},
+		{Min: 75, Max: 80, Count: 1},
+	}
+	if !slices.Equal(result.Buckets, want) {
+		t.Errorf("histogram() buckets = %+v, want %+v", result.Buckets, want)
+	}
+	if result.SampleCount != 6 || result.Reason != "" {
+		t.Errorf("histogram() = %+v, want 6 samples and no reason", result)
+	}
+}
+
+// TestHistogramCountsEveryValue tests that uneven widths still count the maximum in the last bucket
+func TestHistogramCountsEveryValue(t *testing.T) {
+	values := []float64{0.1, 0.2, 0.3, 0.8, 0.9, 1.0}
+	result := histogram("pop", values, 3)
+
+	total := 0
+	for _, bucket := range result.Buckets {
+		total += bucket.Count
+	}
+	if total != len(values) || len(result.Buckets) != 3 {
+		t.Errorf("histogram() = %+v, want 3 buckets counting all %d values", result.Buckets, len(values))
+	}
+	if last := result.Buckets[2]; last.Max != 1.0 || last.Count != 3 {
+		t.Errorf("last bucket = %+v, want max 1.0 holding 0.8, 0.9 and 1.0", last)
+	}
+}
+
+// TestHistogramDegenerate tests the empty and constant-column cases
+func TestHistogramDegenerate(t *testing.T) {
+	result := histogram("spin", nil, 10)
+	if len(result.Buckets) != 0 || result.Buckets == nil || result.Reason == "" {
+		t.Errorf("Empty dataset gave %+v, want an empty bucket list with a reason", result)
+	}
+
+	result = histogram("spin", []float64{2500, 2500, 2500}, 10)
+	want := []HistogramBucket{{Min: 2500, Max: 2500, Count: 3}}
+	if !slices.Equal(result.Buckets, want) || result.Reason == "" {
+		t.Errorf("Constant column gave %+v, want a single bucket of 3 with a reason", result)
+	}
+}
+
+// TestParseHistogramParams tests metric and bucket count validation
+func TestParseHistogramParams(t *testing.T) {
+	metric, buckets, err := parseHistogramParams(url.Values{"metric": {"power"}})
+	if err != nil || metric != "power" || buckets != defaultHistogramBuckets {
+		t.Errorf("parseHistogramParams() = %q, %d, %v; want power, %d", metric, buckets, err, defaultHistogramBuckets)
+	}
+
+	if _, buckets, err := parseHistogramParams(url.Values{"metric": {"spin"}, "buckets": {"25"}}); err != nil || buckets != 25 {
+		t.Errorf("parseHistogramParams() buckets = %d, %v; want 25", buckets, err)
+	}
+
+	for _, query := range []url.Values{
+		{},
+		{"metric": {"control"}},
+		{"metric": {"power; DROP TABLE paddles"}},
+		{"metric": {"power"}, "buckets": {"0"}},
+		{"metric": {"power"}, "buckets": {"101"}},
+		{"metric": {"power"}, "buckets": {"ten"}},
+	} {
+		if _, _, err := parseHistogramParams(query); err == nil {
+			t.Errorf("parseHistogramParams(%v) expected an error", query)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrVersionNotFound is returned when a paddle has no snapshot with the requested version
+var ErrVersionNotFound = errors.New("version not found")
+
+// currentVersion is the version identifier for the live paddle rather than a snapshot
+const currentVersion = "current"
+
+// FieldChange is a single field that differs between two versions of a paddle
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// diffIgnoredFields describe the record rather than the paddle, so they are left out of diffs
+var diffIgnoredFields = map[string]bool{
+	"id":         true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+// recordPaddleSnapshot stores the current state of a paddle in paddle_history as
+// its next version. The snapshot holds the latest measurement, the one
+// UpdatePaddlePerformance replaces. It runs inside tx so the snapshot and the change it precedes
+// commit together. The paddle row stays locked until tx ends, so concurrent
+// changes to the same paddle take their versions one after the other.
+func recordPaddleSnapshot(ctx context.Context, tx *sql.Tx, paddleId string) error {
+	var dbID int
+	err := timedQueryRow(ctx, tx, "lock_paddle", "SELECT id FROM paddles WHERE paddle_id = $1 FOR UPDATE", paddleId).Scan(&dbID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Nothing to keep; the change that follows reports the missing paddle
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error locking paddle for snapshot: %w", err)
+	}
+
+	paddle, err := scanFullPaddle(timedQueryRow(ctx, tx, "snapshot_paddle", fullPaddleQuery+`
+		WHERE 
+			p.paddle_id = $1
+		ORDER BY 
+			perf.id DESC
+		LIMIT 1
+	`, paddleId))
+	if errors.Is(err, sql.ErrNoRows) {
+		// A stub has no specs or performance yet, so there is nothing to keep
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading paddle for snapshot: %w", err)
+	}
+
+	snapshot, err := json.Marshal(paddle)
+	if err != nil {
+		return err
+	}
+
+	_, err = timedExec(ctx, tx, "insert_paddle_snapshot", `
+		INSERT INTO paddle_history (paddle_id, version, snapshot)
+		SELECT $1, COALESCE(MAX(version), 0) + 1, $2
+		FROM paddle_history
+		WHERE paddle_id = $1
+	`, dbID, snapshot)
+	return err
+}
+
+// GetPaddleSnapshot returns version of a paddle as recorded in paddle_history
+func GetPaddleSnapshot(paddleId string, version int) (*Paddle, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	var snapshot []byte
+	err := timedQueryRow(ctx, DB, "get_paddle_snapshot", `
+		SELECT h.snapshot
+		FROM paddle_history h
+		JOIN paddles p ON p.id = h.paddle_id
+		WHERE p.paddle_id = $1 AND h.version = $2
+	`, paddleId, version).Scan(&snapshot)
+	if err == sql.ErrNoRows {
+		return nil, ErrVersionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	paddle := &Paddle{}
+	if err := json.Unmarshal(snapshot, paddle); err != nil {
+		return nil, fmt.Errorf("error decoding snapshot: %w", err)
+	}
+	return paddle, nil
+}
+
+// parseVersion parses a version identifier: "current", or a snapshot number
+// written as "v3" or "3". It returns 0 for the current version.
+func parseVersion(raw string) (int, error) {
+	if raw == currentVersion {
+		return 0, nil
+	}
+
+	version, err := strconv.Atoi(strings.TrimPrefix(raw, "v"))
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("version %q must be %q or a number such as v1", raw, currentVersion)
+	}
+	return version, nil
+}
+
+// getPaddleVersion returns a snapshot of a paddle, or the live paddle for version 0
+func getPaddleVersion(paddleId string, version int) (*Paddle, error) {
+	if version == 0 {
+		return store.GetPaddleByID(paddleId)
+	}
+	return GetPaddleSnapshot(paddleId, version)
+}
+
+// diffPaddles compares two paddles field by field and returns every field
+// that differs, keyed by its JSON path (e.g. "performance.power"), in path order
+func diffPaddles(a, b *Paddle) ([]FieldChange, error) {
+	oldFields, err := flattenPaddle(a)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := flattenPaddle(b)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]bool{}
+	for field := range oldFields {
+		fields[field] = true
+	}
+	for field := range newFields {
+		fields[field] = true
+	}
+
+	changes := []FieldChange{}
+	for field := range fields {
+		if diffIgnoredFields[field] {
+			continue
+		}
+		if !reflect.DeepEqual(oldFields[field], newFields[field]) {
+			changes = append(changes, FieldChange{Field: field, Old: oldFields[field], New: newFields[field]})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes, nil
+}
+
+// flattenPaddle converts a paddle to its JSON fields keyed by dotted path
+func flattenPaddle(paddle *Paddle) (map[string]interface{}, error) {
+	data, err := json.Marshal(paddle)
+	if err != nil {
+		return nil, err
+	}
+
+	var nested map[string]interface{}
+	if err := json.Unmarshal(data, &nested); err != nil {
+		return nil, err
+	}
+
+	flat := map[string]interface{}{}
+	var walk func(prefix string, value map[string]interface{})
+	walk = func(prefix string, value map[string]interface{}) {
+		for key, v := range value {
+			if child, ok := v.(map[string]interface{}); ok {
+				walk(prefix+key+".", child)
+				continue
+			}
+			flat[prefix+key] = v
+		}
+	}
+	walk("", nested)
+
+	return flat, nil
+}
+
+// getPaddleHistoryDiff handles the API request for the changes between two versions of a paddle
+func getPaddleHistoryDiff(w http.ResponseWriter, r *http.Request) {
+	paddleId := paddleIDFromRequest(r)
+	if err := validatePaddleID(paddleId); err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid paddle ID: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	rawFrom, rawTo := query.Get("from"), query.Get("to")
+	if rawFrom == "" {
+		respondWithError(w, "from is required", http.StatusBadRequest)
+		return
+	}
+	if rawTo == "" {
+		rawTo = currentVersion
+	}
+
+	from, err := parseVersion(rawFrom)
+	if err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid from: %v", err), http.StatusBadRequest)
+		return
+	}
+	to, err := parseVersion(rawTo)
+	if err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid to: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	versions := make([]*Paddle, 0, 2)
+	for _, v := range []struct {
+		raw     string
+		version int
+	}{{rawFrom, from}, {rawTo, to}} {
+		paddle, err := getPaddleVersion(paddleId, v.version)
+		if errors.Is(err, ErrVersionNotFound) {
+			respondWithError(w, fmt.Sprintf("Paddle %s has no version %s", paddleId, v.raw), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Printf("Error retrieving paddle version %s: %v", v.raw, err)
+			respondWithError(w, "Paddle not found", http.StatusNotFound)
+			return
+		}
+		versions = append(versions, paddle)
+	}
+
+	changes, err := diffPaddles(versions[0], versions[1])
+	if err != nil {
+		log.Printf("Error diffing paddle versions: %v", err)
+		respondWithError(w, "Failed to compare paddle versions", http.StatusInternalServerError)
+		return
+	}
+
+	response := struct {
+		ID      string        `json:"id"`
+		From    string        `json:"from"`
+		To      string        `json:"to"`
+		Changes []FieldChange `json:"changes"`
+	}{
+		ID:      paddleId,
+		From:    rawFrom,
+		To:      rawTo,
+		Changes: changes,
+	}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
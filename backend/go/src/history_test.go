@@ -0,0 +1,172 @@
+package main
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// TestDiffPaddles tests the field-by-field diff between two paddles
+func TestDiffPaddles(t *testing.T) {
+	before := &Paddle{
+		ID:          "engage-pursuit-mx-6-0",
+		Metadata:    Metadata{Brand: "Engage", Model: "Pursuit MX 6.0"},
+		Specs:       Specs{Shape: Hybrid, Surface: "Raw Carbon", Core: 16},
+		Performance: Performance{Power: 60, Pop: 40, Spin: 2000, TwistWeight: 6.0, SwingWeight: 110, BalancePoint: 23},
+	}
+	before.Control = before.ControlRating()
+
+	after := *before
+	after.Performance.Power = 70
+	after.Performance.Spin = 2100
+	after.Control = after.ControlRating()
+	after.UpdatedAt = NewTime(after.UpdatedAt.Time.AddDate(0, 0, 1))
+
+	changes, err := diffPaddles(before, &after)
+	if err != nil {
+		t.Fatalf("diffPaddles() returned error: %v", err)
+	}
+
+	want := []FieldChange{
+		{Field: "control", Old: 48.0, New: 42.0},
+		{Field: "performance.power", Old: 60.0, New: 70.0},
+		{Field: "performance.spin", Old: 2000.0, New: 2100.0},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("diffPaddles() = %+v, want %+v", changes, want)
+	}
+
+	// Identical paddles have no changes
+	changes, err = diffPaddles(before, before)
+	if err != nil {
+		t.Fatalf("diffPaddles() returned error: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("diffPaddles() of identical paddles = %+v, want none", changes)
+	}
+}
+
+// TestDiffPaddlesOptionalFields tests fields that are only present in one version
+func TestDiffPaddlesOptionalFields(t *testing.T) {
+	year := 2023
+	a := &Paddle{Metadata: Metadata{Brand: "Engage", Model: "Pursuit MX 6.0"}}
+	b := &Paddle{Metadata: Metadata{Brand: "Engage", Model: "Pursuit MX 6.0", Year: &year, SKU: "ENG-PMX6"}}
+
+	changes, err := diffPaddles(a, b)
+	if err != nil {
+		t.Fatalf("diffPaddles() returned error: %v", err)
+	}
+
+	want := []FieldChange{
+		{Field: "metadata.sku", Old: nil, New: "ENG-PMX6"},
+		{Field: "metadata.year", Old: nil, New: 2023.0},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("diffPaddles() = %+v, want %+v", changes, want)
+	}
+}
+
+// TestParseVersion tests parsing history version identifiers
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    int
+		wantErr bool
+	}{
+		{raw: "current", want: 0},
+		{raw: "v1", want: 1},
+		{raw: "12", want: 12},
+		{raw: "v0", wantErr: true},
+		{raw: "latest", wantErr: true},
+		{raw: "v-2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := parseVersion(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseVersion(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseVersion(%q) = %d, want %d", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRecordPaddleSnapshotConcurrent tests that concurrent performance
+// updates each record their own history version
+func TestRecordPaddleSnapshotConcurrent(t *testing.T) {
+	setupTestDB(t)
+
+	paddle := testPaddleInput("Engage", "Pursuit MX 6.0").ToPaddle()
+	if _, err := SavePaddle(paddle); err != nil {
+		t.Fatalf("Failed to save paddle: %v", err)
+	}
+
+	const updates = 8
+	var wg sync.WaitGroup
+	for i := 0; i < updates; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			performance := testPerformance
+			performance.Power = 50 + float64(i)
+			if err := UpdatePaddlePerformance(paddle.ID, &performance); err != nil {
+				t.Errorf("UpdatePaddlePerformance() error: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	var count, maxVersion int
+	err := DB.QueryRow(`
+		SELECT COUNT(*), COALESCE(MAX(h.version), 0)
+		FROM paddle_history h JOIN paddles p ON p.id = h.paddle_id
+		WHERE p.paddle_id = $1
+	`, paddle.ID).Scan(&count, &maxVersion)
+	if err != nil {
+		t.Fatalf("Failed to count history: %v", err)
+	}
+	if count != updates || maxVersion != updates {
+		t.Errorf("history has %d versions up to v%d, want %d up to v%d", count, maxVersion, updates, updates)
+	}
+}
+
+// TestRecordPaddleSnapshotLatestMeasurement tests that the snapshot taken
+// before a performance update holds the measurement the update replaces
+func TestRecordPaddleSnapshotLatestMeasurement(t *testing.T) {
+	setupTestDB(t)
+
+	paddle := testPaddleInput("Engage", "Pursuit MX 6.0").ToPaddle()
+	if _, err := SavePaddle(paddle); err != nil {
+		t.Fatalf("Failed to save paddle: %v", err)
+	}
+	if _, err := DB.Exec(`
+		INSERT INTO paddle_performance (paddle_spec_id, power, pop, spin, twist_weight, swing_weight, balance_point)
+		SELECT s.id, 65, 60, 2500, 190, 210, 29
+		FROM paddle_specs s JOIN paddles p ON p.id = s.paddle_id
+		WHERE p.paddle_id = $1
+	`, paddle.ID); err != nil {
+		t.Fatalf("Failed to add a second measurement: %v", err)
+	}
+
+	retested := testPerformance
+	retested.Power = 85
+	if err := UpdatePaddlePerformance(paddle.ID, &retested); err != nil {
+		t.Fatalf("UpdatePaddlePerformance() error: %v", err)
+	}
+
+	var power float64
+	err := DB.QueryRow(`
+		SELECT (h.snapshot->'performance'->>'power')::float
+		FROM paddle_history h JOIN paddles p ON p.id = h.paddle_id
+		WHERE p.paddle_id = $1 AND h.version = 1
+	`, paddle.ID).Scan(&power)
+	if err != nil {
+		t.Fatalf("Failed to read the snapshot: %v", err)
+	}
+	if power != 65 {
+		t.Errorf("snapshot power = %v, want 65 from the replaced latest measurement", power)
+	}
+}
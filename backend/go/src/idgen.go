@@ -0,0 +1,73 @@
+package main
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Supported values for ID_STRATEGY
+const (
+	idStrategyBrandModel = "brand-model"
+	idStrategyUUID       = "uuid"
+)
+
+// IDGenerator produces the paddle ID for new paddles. Every ID it returns
+// must pass validatePaddleID.
+type IDGenerator interface {
+	GenerateID(metadata Metadata) string
+}
+
+// idGenerator is the IDGenerator used by ToPaddle, set via ID_STRATEGY
+var idGenerator IDGenerator = brandModelIDGenerator{}
+
+// loadIDGenerator selects the ID generator from the ID_STRATEGY environment variable
+func loadIDGenerator() (IDGenerator, error) {
+	switch strategy := strings.ToLower(getEnv("ID_STRATEGY", idStrategyBrandModel)); strategy {
+	case idStrategyBrandModel:
+		return brandModelIDGenerator{}, nil
+	case idStrategyUUID:
+		return uuidIDGenerator{}, nil
+	default:
+		return nil, fmt.Errorf("ID_STRATEGY must be %q or %q, got %q", idStrategyBrandModel, idStrategyUUID, strategy)
+	}
+}
+
+// brandModelIDGenerator derives the ID from the brand and model, such as
+// "engage-pursuit-mx-6.0". The same paddle always gets the same ID, which is
+// what duplicate detection on upload and upserts rely on.
+type brandModelIDGenerator struct{}
+
+func (brandModelIDGenerator) GenerateID(metadata Metadata) string {
+	return generatePaddleID(metadata.Brand, metadata.Model)
+}
+
+// uuidIDGenerator gives every paddle a random version 4 UUID. IDs never
+// collide, but uploading the same paddle twice creates two paddles; upserts
+// find their paddle with upsertTargetID instead.
+type uuidIDGenerator struct{}
+
+func (uuidIDGenerator) GenerateID(metadata Metadata) string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// upsertTargetID returns the ID an upsert of paddle should update. IDs
+// derived from the brand and model already name the existing paddle. Random
+// IDs never do, so the oldest paddle with the same brand and model is used,
+// and a new paddle keeps its generated ID.
+func upsertTargetID(paddle *Paddle) (string, error) {
+	if _, random := idGenerator.(uuidIDGenerator); !random {
+		return paddle.ID, nil
+	}
+
+	id, err := store.FindPaddleIDByName(paddle.Metadata.Brand, paddle.Metadata.Model)
+	if errors.Is(err, ErrPaddleNotFound) {
+		return paddle.ID, nil
+	}
+	return id, err
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+// uuidPattern matches a lowercase version 4 UUID
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+// TestIDGenerators tests that each generator produces valid IDs, unique for different paddles
+func TestIDGenerators(t *testing.T) {
+	paddles := []Metadata{
+		{Brand: "Engage", Model: "Pursuit MX 6.0"},
+		{Brand: "Selkirk", Model: "Vanguard Power Air"},
+		{Brand: "JOOLA", Model: "Ben Johns Hyperion CFS 16"},
+	}
+
+	for name, generator := range map[string]IDGenerator{
+		idStrategyBrandModel: brandModelIDGenerator{},
+		idStrategyUUID:       uuidIDGenerator{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			seen := map[string]bool{}
+			for _, metadata := range paddles {
+				id := generator.GenerateID(metadata)
+				if err := validatePaddleID(id); err != nil {
+					t.Errorf("GenerateID(%+v) = %q, which is invalid: %v", metadata, id, err)
+				}
+				if seen[id] {
+					t.Errorf("GenerateID(%+v) = %q, already generated for another paddle", metadata, id)
+				}
+				seen[id] = true
+			}
+		})
+	}
+}
+
+// TestBrandModelIDGenerator tests that the default generator keeps the brand-model format
+func TestBrandModelIDGenerator(t *testing.T) {
+	metadata := Metadata{Brand: "Engage", Model: "Pursuit MX 6.0"}
+	if got := (brandModelIDGenerator{}).GenerateID(metadata); got != "engage-pursuit-mx-6.0" {
+		t.Errorf("GenerateID() = %q, want engage-pursuit-mx-6.0", got)
+	}
+}
+
+// TestUUIDIDGenerator tests that UUIDs are well-formed and differ for the same paddle
+func TestUUIDIDGenerator(t *testing.T) {
+	metadata := Metadata{Brand: "Engage", Model: "Pursuit MX 6.0"}
+	first, second := (uuidIDGenerator{}).GenerateID(metadata), (uuidIDGenerator{}).GenerateID(metadata)
+
+	for _, id := range []string{first, second} {
+		if !uuidPattern.MatchString(id) {
+			t.Errorf("GenerateID() = %q, want a version 4 UUID", id)
+		}
+	}
+	if first == second {
+		t.Errorf("GenerateID() returned %q twice for the same paddle", first)
+	}
+}
+
+// TestLoadIDGenerator tests selecting the generator from ID_STRATEGY and that ToPaddle uses it
+func TestLoadIDGenerator(t *testing.T) {
+	defer func() { idGenerator = brandModelIDGenerator{} }()
+
+	var err error
+	t.Setenv("ID_STRATEGY", "UUID")
+	if idGenerator, err = loadIDGenerator(); err != nil {
+		t.Fatalf("loadIDGenerator() returned error: %v", err)
+	}
+	input := &PaddleInput{Metadata: Metadata{Brand: "Engage", Model: "Pursuit MX 6.0"}}
+	if id := input.ToPaddle().ID; !uuidPattern.MatchString(id) {
+		t.Errorf("ToPaddle().ID = %q with ID_STRATEGY=uuid, want a UUID", id)
+	}
+
+	t.Setenv("ID_STRATEGY", "")
+	if idGenerator, err = loadIDGenerator(); err != nil {
+		t.Fatalf("loadIDGenerator() returned error: %v", err)
+	}
+	if id := input.ToPaddle().ID; id != "engage-pursuit-mx-6.0" {
+		t.Errorf("ToPaddle().ID = %q by default, want engage-pursuit-mx-6.0", id)
+	}
+
+	t.Setenv("ID_STRATEGY", "sequential")
+	if _, err := loadIDGenerator(); err == nil {
+		t.Error("loadIDGenerator() should fail with an unknown strategy")
+	}
+}
+
+// TestUpsertWithUUIDs tests that under ID_STRATEGY=uuid an upsert updates the
+// paddle with the same brand and model instead of inserting a duplicate
+func TestUpsertWithUUIDs(t *testing.T) {
+	setupTestStore(t)
+	idGenerator = uuidIDGenerator{}
+	defer func() { idGenerator = brandModelIDGenerator{} }()
+
+	upsert := func(input *PaddleInput) (int, string) {
+		t.Helper()
+		body, _ := json.Marshal(input)
+		rr := httptest.NewRecorder()
+		uploadPaddleStats(rr, httptest.NewRequest("POST", "/api/paddles?upsert=true", bytes.NewReader(body)))
+		var response struct {
+			ID string `json:"paddle_id"`
+		}
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to decode response %s: %v", rr.Body.String(), err)
+		}
+		return rr.Code, response.ID
+	}
+
+	code, created := upsert(testPaddleInput("Engage", "Pursuit MX 6.0"))
+	if code != http.StatusCreated || !uuidPattern.MatchString(created) {
+		t.Fatalf("First upsert = %d %q, want 201 with a UUID", code, created)
+	}
+
+	// The same paddle, named in different case, updates the first one
+	input := testPaddleInput("ENGAGE", "pursuit mx 6.0")
+	input.Performance.Power = 80
+	code, updated := upsert(input)
+	if code != http.StatusOK || updated != created {
+		t.Errorf("Second upsert = %d %q, want 200 updating %q", code, updated, created)
+	}
+	if count, _ := store.CountPaddles(); count != 1 {
+		t.Errorf("store has %d paddles after two upserts of one paddle, want 1", count)
+	}
+	if paddle, _ := store.GetPaddleByID(created); paddle == nil || paddle.Performance.Power != 80 {
+		t.Errorf("paddle after upsert = %+v, want power 80", paddle)
+	}
+
+	// Another model is a new paddle
+	if code, other := upsert(testPaddleInput("Engage", "Pursuit Pro")); code != http.StatusCreated || other == created {
+		t.Errorf("Upsert of another model = %d %q, want 201 with a new ID", code, other)
+	}
+}
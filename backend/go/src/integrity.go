@@ -0,0 +1,131 @@
+package main
+
+import "fmt"
+
+// integrityCheck is a named diagnostic query that returns the IDs of inconsistent rows
+type integrityCheck struct {
+	Name        string
+	Description string
+	Run         func() ([]int, error)
+}
+
+// IntegrityCheckResult is the outcome of a single integrity check
+type IntegrityCheckResult struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	IDs         []int  `json:"ids"`
+}
+
+// IntegrityReport lists the results of every integrity check. Stubs are
+// paddles still without specs or performance; they are saved that way on
+// purpose, so they are listed for review but do not make the report fail.
+type IntegrityReport struct {
+	OK     bool                   `json:"ok"`
+	Checks []IntegrityCheckResult `json:"checks"`
+	Stubs  []int                  `json:"stubs"`
+}
+
+// integrityChecks are run in order by RunIntegrityChecks
+var integrityChecks = []integrityCheck{
+	{
+		Name:        "specs_without_paddle",
+		Description: "paddle_specs rows whose paddle_id does not reference a paddle",
+		Run:         checkSpecsWithoutPaddle,
+	},
+	{
+		Name:        "performance_without_specs",
+		Description: "paddle_performance rows whose paddle_spec_id does not reference specs",
+		Run:         checkPerformanceWithoutSpecs,
+	},
+}
+
+// RunIntegrityChecks runs every integrity check and collects the results
+func RunIntegrityChecks() (*IntegrityReport, error) {
+	report := &IntegrityReport{OK: true, Checks: make([]IntegrityCheckResult, 0, len(integrityChecks))}
+
+	for _, check := range integrityChecks {
+		ids, err := check.Run()
+		if err != nil {
+			return nil, fmt.Errorf("integrity check %s failed: %w", check.Name, err)
+		}
+		if len(ids) > 0 {
+			report.OK = false
+		}
+		report.Checks = append(report.Checks, IntegrityCheckResult{
+			Name:        check.Name,
+			Description: check.Description,
+			IDs:         ids,
+		})
+	}
+
+	stubs, err := findStubs()
+	if err != nil {
+		return nil, fmt.Errorf("listing stubs failed: %w", err)
+	}
+	report.Stubs = stubs
+
+	return report, nil
+}
+
+// findStubs finds paddles with no specs, or with specs but no performance
+// measurements, which is how stubs are stored until they are completed
+func findStubs() ([]int, error) {
+	return queryIDs("integrity_stubs", `
+		SELECT p.id
+		FROM paddles p
+		LEFT JOIN paddle_specs s ON s.paddle_id = p.id
+		LEFT JOIN paddle_performance perf ON perf.paddle_spec_id = s.id
+		GROUP BY p.id
+		HAVING COUNT(perf.id) = 0
+		ORDER BY p.id
+	`)
+}
+
+// checkSpecsWithoutPaddle finds specs that are not attached to an existing paddle
+func checkSpecsWithoutPaddle() ([]int, error) {
+	return queryIDs("integrity_specs_without_paddle", `
+		SELECT s.id
+		FROM paddle_specs s
+		LEFT JOIN paddles p ON p.id = s.paddle_id
+		WHERE p.id IS NULL
+		ORDER BY s.id
+	`)
+}
+
+// checkPerformanceWithoutSpecs finds performance rows that are not attached to existing specs
+func checkPerformanceWithoutSpecs() ([]int, error) {
+	return queryIDs("integrity_performance_without_specs", `
+		SELECT perf.id
+		FROM paddle_performance perf
+		LEFT JOIN paddle_specs s ON s.id = perf.paddle_spec_id
+		WHERE s.id IS NULL
+		ORDER BY perf.id
+	`)
+}
+
+// queryIDs runs a query returning a single integer column and collects the values
+func queryIDs(label, query string, args ...interface{}) ([]int, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	rows, err := timedQuery(ctx, DB, label, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// listRangeUnit is the Range unit of the paddle list, as in Range: paddles=0-49
+const listRangeUnit = "paddles"
+
+// listRange is the inclusive span of list positions asked for by a Range header
+type listRange struct {
+	first, last int
+}
+
+// parseListRange reads a Range header such as "paddles=0-49". It reports
+// false when there is no header or it uses another unit, which HTTP lets
+// servers ignore. Ranges longer than the max page size are shortened.
+func parseListRange(header string) (listRange, bool, error) {
+	spec, ok := strings.CutPrefix(header, listRangeUnit+"=")
+	if !ok {
+		return listRange{}, false, nil
+	}
+	if strings.Contains(spec, ",") {
+		return listRange{}, false, errors.New("only one range is supported")
+	}
+
+	rawFirst, rawLast, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return listRange{}, false, fmt.Errorf("range must look like %s=0-49", listRangeUnit)
+	}
+	first, err := strconv.Atoi(rawFirst)
+	if err != nil || first < 0 {
+		return listRange{}, false, errors.New("range start must be a non-negative integer")
+	}
+	last, err := strconv.Atoi(rawLast)
+	if err != nil || last < first {
+		return listRange{}, false, errors.New("range end must be an integer no less than the start")
+	}
+
+	return listRange{first: first, last: min(last, first+maxPageSize-1)}, true, nil
+}
+
+// respondRangeNotSatisfiable rejects a range that starts past the end of the
+// list, telling the client how long the list is
+func respondRangeNotSatisfiable(w http.ResponseWriter, total int) {
+	w.Header().Set("Content-Range", fmt.Sprintf("%s */%d", listRangeUnit, total))
+	respondWithError(w, fmt.Sprintf("Range not satisfiable: there are %d paddles", total), http.StatusRequestedRangeNotSatisfiable)
+}
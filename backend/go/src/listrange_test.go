@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// TestParseListRange tests reading and validating the Range header
+func TestParseListRange(t *testing.T) {
+	tests := []struct {
+		header     string
+		want       listRange
+		wantRanged bool
+		wantErr    bool
+	}{
+		{header: ""},
+		{header: "bytes=0-99"},
+		{header: "paddles=0-49", want: listRange{0, 49}, wantRanged: true},
+		{header: "paddles=10-10", want: listRange{10, 10}, wantRanged: true},
+		// Capped at the max page size of 100
+		{header: "paddles=0-999", want: listRange{0, 99}, wantRanged: true},
+		{header: "paddles=5-1", wantErr: true},
+		{header: "paddles=-49", wantErr: true},
+		{header: "paddles=0-", wantErr: true},
+		{header: "paddles=a-b", wantErr: true},
+		{header: "paddles=0-9,20-29", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			got, ranged, err := parseListRange(tt.header)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseListRange(%q) error = %v, wantErr %v", tt.header, err, tt.wantErr)
+			}
+			if got != tt.want || ranged != tt.wantRanged {
+				t.Errorf("parseListRange(%q) = %+v, %v; want %+v, %v", tt.header, got, ranged, tt.want, tt.wantRanged)
+			}
+		})
+	}
+}
+
+// TestGetPaddlesListRange tests that a Range header returns the slice with
+// 206, and that a range past the end returns 416 with the list length
+func TestGetPaddlesListRange(t *testing.T) {
+	setupTestStore(t)
+
+	for i := range 5 {
+		paddle := &Paddle{
+			ID:          fmt.Sprintf("engage-range-%d", i),
+			Metadata:    Metadata{Brand: "Engage", Model: fmt.Sprintf("Range %d", i)},
+			Specs:       testSpecs,
+			Performance: testPerformance,
+		}
+		if _, err := store.SavePaddle(paddle); err != nil {
+			t.Fatalf("SavePaddle() error: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name             string
+		query            string
+		rangeHeader      string
+		wantCode         int
+		wantContentRange string
+		wantIDs          []string
+	}{
+		{name: "No range", query: "limit=2", wantCode: http.StatusOK, wantIDs: []string{"engage-range-0", "engage-range-1"}},
+		{name: "Range", rangeHeader: "paddles=1-2", wantCode: http.StatusPartialContent, wantContentRange: "paddles 1-2/5", wantIDs: []string{"engage-range-1", "engage-range-2"}},
+		{name: "Range past the end", rangeHeader: "paddles=3-49", wantCode: http.StatusPartialContent, wantContentRange: "paddles 3-4/5", wantIDs: []string{"engage-range-3", "engage-range-4"}},
+		{name: "Filtered range", query: "brand=Selkirk", rangeHeader: "paddles=0-49", wantCode: http.StatusRequestedRangeNotSatisfiable, wantContentRange: "paddles */0"},
+		{name: "Out of bounds", rangeHeader: "paddles=5-9", wantCode: http.StatusRequestedRangeNotSatisfiable, wantContentRange: "paddles */5"},
+		{name: "Malformed", rangeHeader: "paddles=9-5", wantCode: http.StatusBadRequest},
+		{name: "Range and limit", query: "limit=2", rangeHeader: "paddles=0-1", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/paddles?"+tt.query, nil)
+			if tt.rangeHeader != "" {
+				req.Header.Set("Range", tt.rangeHeader)
+			}
+			rr := httptest.NewRecorder()
+			getPaddlesList(rr, req)
+
+			if rr.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rr.Code, tt.wantCode, rr.Body.String())
+			}
+			if got := rr.Header().Get("Content-Range"); got != tt.wantContentRange {
+				t.Errorf("Content-Range = %q, want %q", got, tt.wantContentRange)
+			}
+			if tt.wantIDs == nil {
+				return
+			}
+
+			var paddles []struct {
+				ID string `json:"id"`
+			}
+			if err := json.Unmarshal(rr.Body.Bytes(), &paddles); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			var ids []string
+			for _, p := range paddles {
+				ids = append(ids, p.ID)
+			}
+			if !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("paddles = %v, want %v", ids, tt.wantIDs)
+			}
+			if got := rr.Header().Get("Accept-Ranges"); got != "paddles" {
+				t.Errorf("Accept-Ranges = %q, want paddles", got)
+			}
+		})
+	}
+}
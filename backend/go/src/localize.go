@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/text/language"
+)
+
+// supportedLanguages are the languages validation messages can be returned in.
+// English comes first, so it is used when nothing else matches.
+var supportedLanguages = []language.Tag{language.English, language.Spanish}
+
+var languageMatcher = language.NewMatcher(supportedLanguages)
+
+// requestLanguage returns the supported language that best matches the
+// request's Accept-Language header, defaulting to English
+func requestLanguage(r *http.Request) language.Tag {
+	_, i := language.MatchStrings(languageMatcher, r.Header.Get("Accept-Language"))
+	return supportedLanguages[i]
+}
+
+// validationErrorPrefixes start a failed validation's message in each language
+var validationErrorPrefixes = map[language.Tag]string{
+	language.English: "Validation error",
+	language.Spanish: "Error de validación",
+}
+
+// validationTranslations maps each validation error code to its message
+// format in languages other than English, which lives with the validators.
+// A translated format takes the same arguments, in the same order, as the
+// English one. Codes are the same in every language.
+var validationTranslations = map[language.Tag]map[string]string{
+	language.Spanish: {
+		"AVERAGE_WEIGHT_NOT_POSITIVE":     "el peso medio debe ser mayor que 0",
+		"BALANCE_POINT_NOT_POSITIVE":      "el punto de equilibrio debe ser mayor que 0",
+		"BRAND_REQUIRED":                  "la marca es obligatoria",
+		"CORE_NOT_POSITIVE":               "el núcleo debe ser mayor que 0",
+		"CORE_OUT_OF_RANGE":               "el núcleo debe estar entre %gmm y %gmm",
+		"CORE_UNIT_INVALID":               "el núcleo debe indicarse en %s",
+		"CSV_NUMBER_INVALID":              "%s debe ser un número válido",
+		"CUSTOM_RULE_FAILED":              "%v",
+		"EDGE_GUARD_INVALID":              "protector de borde no válido: debe ser uno de %v",
+		"GRIP_CIRCUMFERENCE_NOT_POSITIVE": "la circunferencia del grip debe ser mayor que 0",
+		"GRIP_LENGTH_NOT_POSITIVE":        "la longitud del grip debe ser mayor que 0",
+		"GRIP_LONGER_THAN_PADDLE":         "la longitud del grip (%v) debe ser menor que la longitud de la pala (%v)",
+		"GRIP_TYPE_REQUIRED":              "el tipo de grip es obligatorio",
+		"HANDLE_TYPE_INVALID":             "tipo de mango no válido: debe ser uno de %v",
+		"MODEL_REQUIRED":                  "el modelo es obligatorio",
+		"PADDLE_ID_INVALID":               "la marca y el modelo deben dar un ID de pala de %d a %d letras, dígitos, '.', '+' o '-', se obtuvo %q",
+		"PADDLE_LENGTH_NOT_POSITIVE":      "la longitud de la pala debe ser mayor que 0",
+		"PADDLE_WIDTH_NOT_POSITIVE":       "el ancho de la pala debe ser mayor que 0",
+		"POP_OUT_OF_RANGE":                "el pop debe estar entre %g y %g",
+		"POP_POWER_GAP":                   "la potencia (%g) y el pop (%g) difieren en %g, más de los %g esperados",
+		"POWER_OUT_OF_RANGE":              "la potencia debe estar entre %g y %g",
+		"PRICE_NOT_POSITIVE":              "el precio debe ser un número positivo",
+		"PRODUCT_URL_INVALID":             "product_url debe ser una URL http o https absoluta",
+		"SHAPE_INVALID":                   "forma no válida: debe ser una de %v",
+		"SKU_TOO_LONG":                    "el sku debe tener como máximo %d caracteres",
+		"SKU_WHITESPACE":                  "el sku no debe tener espacios al principio ni al final",
+		"SPECS_REQUIRED":                  "especificaciones no válidas: las especificaciones son obligatorias cuando se indican el rendimiento o los rangos",
+		"SPEC_OUTSIDE_RANGE":              "%s medido (%g) está fuera del rango indicado %g–%g",
+		"SPEC_RANGE_INVERTED":             "el mínimo del rango de %s (%g) no debe superar el máximo (%g)",
+		"SPEC_RANGE_UNKNOWN":              "rango de especificación desconocido %q: debe ser uno de %v",
+		"SPIN_NEGATIVE":                   "el spin no debe ser negativo",
+		"STDDEV_NEGATIVE":                 "%s_stddev no debe ser negativo",
+		"SURFACE_REQUIRED":                "la superficie es obligatoria",
+		"SURFACE_SIDES_INCOMPLETE":        "surface_front y surface_back deben indicarse juntos",
+		"SURFACE_SIDE_INVALID":            "%s no válido: debe ser uno de %v",
+		"SWING_WEIGHT_NOT_POSITIVE":       "el swing weight debe ser mayor que 0",
+		"TAGS_REQUIRED":                   "se requiere al menos una etiqueta",
+		"TAG_EMPTY":                       "las etiquetas no deben estar vacías",
+		"TAG_INVALID":                     "la etiqueta %q no debe contener comas",
+		"TAG_TOO_LONG":                    "la etiqueta %q debe tener como máximo %d caracteres",
+		"TEXT_INVALID_UTF8":               "%s debe ser UTF-8 válido",
+		"TEXT_NOT_PRINTABLE":              "%s contiene el carácter no imprimible %U en la posición %d",
+		"TWIST_WEIGHT_NOT_POSITIVE":       "el twist weight debe ser mayor que 0",
+		"YEAR_OUT_OF_RANGE":               "el año debe estar entre %d y %d",
+	},
+}
+
+// localizeError returns the message of err in lang. A ValidationError with a
+// translation for its code is replaced by the translated message, dropping the
+// English context it was wrapped in; anything else keeps its English message.
+func localizeError(err error, lang language.Tag) string {
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		return err.Error()
+	}
+	format, ok := validationTranslations[lang][verr.Code]
+	if !ok {
+		return err.Error()
+	}
+	return fmt.Sprintf(format, verr.args...)
+}
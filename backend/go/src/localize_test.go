@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+// TestRequestLanguage tests that Accept-Language picks a supported language, defaulting to English
+func TestRequestLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   language.Tag
+	}{
+		{"", language.English},
+		{"es", language.Spanish},
+		{"es-MX,es;q=0.9,en;q=0.8", language.Spanish},
+		{"fr-FR", language.English},
+		{"en-US,es;q=0.5", language.English},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept-Language", tt.header)
+		if got := requestLanguage(r); got != tt.want {
+			t.Errorf("requestLanguage(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+// TestLocalizeError tests that validation errors are translated with their arguments
+func TestLocalizeError(t *testing.T) {
+	err := fmt.Errorf("invalid metadata: %w", newValidationError("YEAR_OUT_OF_RANGE", "year must be between %d and %d", 2000, 2030))
+
+	if got, want := localizeError(err, language.Spanish), "el año debe estar entre 2000 y 2030"; got != want {
+		t.Errorf("Spanish message = %q, want %q", got, want)
+	}
+	if got := localizeError(err, language.English); got != err.Error() {
+		t.Errorf("English message = %q, want %q", got, err.Error())
+	}
+	if plain := fmt.Errorf("not a validation error"); localizeError(plain, language.Spanish) != plain.Error() {
+		t.Errorf("non-validation error was translated")
+	}
+}
+
+// TestValidationErrorPrefixes tests that every translated language has a message prefix
+func TestValidationErrorPrefixes(t *testing.T) {
+	if len(validationTranslations[language.Spanish]) == 0 {
+		t.Fatal("no Spanish translations")
+	}
+	for lang := range validationTranslations {
+		if _, ok := validationErrorPrefixes[lang]; !ok {
+			t.Errorf("no validation error prefix for %v", lang)
+		}
+	}
+}
+
+// TestUploadPaddleLocalizedValidationError tests that a supported Accept-Language gets a translated message with the same code
+func TestUploadPaddleLocalizedValidationError(t *testing.T) {
+	setupTestStore(t)
+
+	body := `{"metadata": {"model": "Pursuit MX 6.0"}, "specs": {"shape": "Round"}}`
+	for _, tt := range []struct {
+		acceptLanguage string
+		wantMessage    string
+	}{
+		{"", "Validation error: invalid metadata: brand is required"},
+		{"es-ES", "Error de validación: la marca es obligatoria"},
+	} {
+		req := httptest.NewRequest("POST", "/api/paddles", bytes.NewBufferString(body))
+		req.Header.Set("Accept-Language", tt.acceptLanguage)
+		rr := httptest.NewRecorder()
+		uploadPaddleStats(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", rr.Code, http.StatusBadRequest)
+		}
+		var response errorResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if response.Message != tt.wantMessage || response.ErrorCode != "BRAND_REQUIRED" {
+			t.Errorf("Accept-Language %q: response = %+v, want message %q and error_code BRAND_REQUIRED", tt.acceptLanguage, response, tt.wantMessage)
+		}
+	}
+}
@@ -1,22 +1,88 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/gorilla/mux"
-	"github.com/rs/cors"
 )
 
+// shutdownTimeout bounds how long in-flight requests may run after SIGINT or SIGTERM
+const shutdownTimeout = 30 * time.Second
+
 func main() {
+	// Read and validate every setting before touching the database
+	cfg, err := LoadConfig()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	cfg.apply()
+
 	// Initialize database
 	log.Println("Initializing database connection...")
-	if err := InitDB(); err != nil {
+	if err := InitDB(cfg.DB); err != nil {
 		log.Fatalf("Error initializing database: %v", err)
 	}
 	log.Println("Database connection established successfully")
 	defer CloseDB()
 
+	// Background goroutines stop when the process receives SIGINT or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	var background sync.WaitGroup
+
+	// Publish paddle events written to the outbox to registered webhooks, and
+	// deliver them to each webhook on its own schedule. With webhooks disabled
+	// events are discarded as they are published, and published events are
+	// pruned either way.
+	if enabledFeatures.Enabled(featureWebhooks) {
+		hooks := newWebhookPublisher()
+		publisher = hooks
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			runWebhookDeliverer(ctx, cfg.OutboxPollInterval, hooks)
+		}()
+	}
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		runOutboxPoller(ctx, cfg.OutboxPollInterval, cfg.OutboxRetention)
+	}()
+
+	// Keep the dataset stats behind relative metrics such as radar scaling current
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		runDatasetStatsRefresher(ctx, cfg.StatsRefreshInterval)
+	}()
+
+	// Seed the homepage counters, then keep correcting them against the database
+	if err := reconcilePaddleCounters(); err != nil {
+		log.Printf("Error initializing paddle counters: %v", err)
+	}
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		runPaddleCountReconciler(ctx, cfg.CountReconcileInterval)
+	}()
+
+	// Periodically log connection pool usage to help diagnose pool exhaustion
+	if cfg.PoolStatsInterval > 0 {
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			runPoolMonitor(ctx, DB, cfg.PoolStatsInterval, poolStatsLogger())
+		}()
+	}
+
 	// Create router
 	router := mux.NewRouter()
 
@@ -25,15 +91,148 @@ func main() {
 		w.Write([]byte("Server is working!"))
 	}).Methods("GET")
 
+	// Liveness and readiness probes
+	router.HandleFunc("/healthz", withCommonHeaders(healthz)).Methods("GET")
+	router.HandleFunc("/healthz/detail", withCommonHeaders(healthzDetail)).Methods("GET")
+	router.HandleFunc("/readyz", withCommonHeaders(readyz)).Methods("GET")
+
+	// Field descriptions for building upload forms
+	router.HandleFunc("/api/schema", withCommonHeaders(withCacheControl(cacheStatic, getSchema))).Methods("GET")
+
+	// Brands with their paddle counts, for filter dropdowns
+	router.HandleFunc("/api/brands", withCommonHeaders(withCacheControl(cacheStatic, getBrands))).Methods("GET")
+
+	// Recommended grip circumference for a hand length, with the paddles that have it
+	router.HandleFunc("/api/grip-size", withCommonHeaders(getGripSize)).Methods("GET")
+
+	// Paddle counters for the homepage, served from memory
+	router.HandleFunc("/api/stats/summary", withCommonHeaders(getStatsSummary)).Methods("GET")
+
+	// Optional features, such as analytics and webhooks, enabled via FEATURES
+	registerFeatureRoutes(router, enabledFeatures)
+
 	// Add your API routes
 	// Get all paddles with basic info for cards
-	router.HandleFunc("/api/paddles", withCommonHeaders(getPaddlesList)).Methods("GET")
+	router.HandleFunc("/api/paddles", withCommonHeaders(withCacheControl(cacheList, getPaddlesList))).Methods("GET")
+
+	// Stream the full catalog for clients syncing every paddle
+	router.HandleFunc("/api/paddles/stream", withCommonHeaders(streamPaddles)).Methods("GET")
+
+	// The published catalog grouped by brand or shape, streamed as a JSON object
+	router.HandleFunc("/api/paddles/grouped", withCommonHeaders(streamGroupedPaddles)).Methods("GET")
+
+	// Count paddles per release year. Fixed /api/paddles/... paths must be
+	// registered before /api/paddles/{id} so they are not captured as an ID.
+	router.HandleFunc("/api/paddles/by-year", withCommonHeaders(getPaddleCountsByYear)).Methods("GET")
+
+	// Search box suggestions by brand or model
+	router.HandleFunc("/api/paddles/suggest", withCommonHeaders(getPaddleSuggestions)).Methods("GET")
+
+	// Find existing paddles that look like a brand and model
+	router.HandleFunc("/api/paddles/duplicates", withCommonHeaders(getDuplicates)).Methods("GET")
+
+	// Recommend paddles within a tolerance of performance targets
+	router.HandleFunc("/api/paddles/recommend", withCommonHeaders(getRecommendedPaddles)).Methods("GET")
+
+	// The regulation-legal paddle nearest a partial spec and performance target
+	router.HandleFunc("/api/paddles/match", withCommonHeaders(matchPaddle)).Methods("POST")
+
+	// The paddle of the week, the same for every user until it expires
+	router.HandleFunc("/api/paddles/featured", withCommonHeaders(getFeaturedPaddle)).Methods("GET")
+
+	// Paddles at or above a percentile of one performance metric
+	router.HandleFunc("/api/paddles/elite", withCommonHeaders(getElitePaddles)).Methods("GET")
+
+	// Counts per brand, shape and surface still available under the list filters
+	router.HandleFunc("/api/paddles/facets", withCommonHeaders(getFacets)).Methods("GET")
+
+	// Count and min/max/mean of every numeric field across a filtered set of paddles
+	router.HandleFunc("/api/paddles/stats", withCommonHeaders(getSubsetStats)).Methods("GET")
+
+	// Average the specs and performance of a filtered set of paddles
+	router.HandleFunc("/api/paddles/average", withCommonHeaders(getAveragePaddle)).Methods("GET")
+
+	// New arrivals: paddles added in the last N days
+	router.HandleFunc("/api/paddles/recent", withCommonHeaders(getRecentPaddles)).Methods("GET")
+
+	// Paddles created or updated since a cursor, for incremental sync
+	router.HandleFunc("/api/paddles/changes", withCommonHeaders(getPaddleChanges)).Methods("GET")
+
+	// Performance metrics only, for many paddles at once
+	router.HandleFunc("/api/paddles/performance", withCommonHeaders(getPerformanceByIDs)).Methods("GET")
+
+	// The comparison table for many paddles, with only the fields the grid shows
+	router.HandleFunc("/api/paddles/compare-fields", withCommonHeaders(getCompareFields)).Methods("GET")
+
+	// Look up a paddle by manufacturer SKU
+	router.HandleFunc("/api/paddles/by-sku/{sku}", withCommonHeaders(getPaddleBySKU)).Methods("GET")
+
+	// Paddles ranked by a weighted composite score
+	router.HandleFunc("/api/paddles/ranked", withCommonHeaders(getRankedPaddles)).Methods("GET")
+
+	// Look up a paddle by its numeric primary key, for internal tools
+	router.HandleFunc("/api/paddles/internal/{id}", withCommonHeaders(getPaddleByDBID)).Methods("GET")
 
 	// Get complete details for a specific paddle
 	router.HandleFunc("/api/paddles/{id}", withCommonHeaders(getPaddleDetails)).Methods("GET")
 
 	// Upload paddle stats endpoint
 	router.HandleFunc("/api/paddles", withCommonHeaders(uploadPaddleStats)).Methods("POST")
+	router.HandleFunc("/api/paddles/bulk", withCommonHeaders(bulkUploadPaddles)).Methods("POST")
+
+	// Check a spreadsheet of paddles row by row without saving anything
+	router.HandleFunc("/api/paddles/validate-csv", withCommonHeaders(validatePaddlesCSV)).Methods("POST")
+
+	// Replace the performance measurements of a paddle after re-testing
+	router.HandleFunc("/api/paddles/{id}/performance", withCommonHeaders(updatePaddlePerformance)).Methods("PUT")
+
+	// Check an ID's format and whether it exists in one call
+	router.HandleFunc("/api/paddles/{id}/check", withCommonHeaders(checkPaddleID)).Methods("GET")
+
+	// Printable PDF spec sheet
+	router.HandleFunc("/api/paddles/{id}/sheet.pdf", withCommonHeaders(getPaddleSpecSheet)).Methods("GET")
+
+	// Radar chart payload with metrics scaled against the dataset
+	router.HandleFunc("/api/paddles/{id}/radar", withCommonHeaders(getPaddleRadar)).Methods("GET")
+
+	// Standard scores of each metric against the dataset
+	router.HandleFunc("/api/paddles/{id}/zscores", withCommonHeaders(getPaddleZScores)).Methods("GET")
+
+	// Compare two versions of a paddle from its history
+	router.HandleFunc("/api/paddles/{id}/history/diff", withCommonHeaders(getPaddleHistoryDiff)).Methods("GET")
+
+	// Performance per dollar, ranked within the paddle's price bracket
+	router.HandleFunc("/api/paddles/{id}/value", withCommonHeaders(getPaddleValue)).Methods("GET")
+
+	// Paddle details with its nearest neighbors for detail pages
+	router.HandleFunc("/api/paddles/{id}/with-similar", withCommonHeaders(getPaddleWithSimilar)).Methods("GET")
+
+	// Clone a paddle into a new variant
+	router.HandleFunc("/api/paddles/{id}/clone", withCommonHeaders(clonePaddle)).Methods("POST")
+
+	// Curator tags
+	router.HandleFunc("/api/paddles/{id}/tags", withCommonHeaders(addPaddleTags)).Methods("POST")
+	router.HandleFunc("/api/paddles/{id}/tags/{tag}", withCommonHeaders(removePaddleTag)).Methods("DELETE")
+
+	// Make a draft paddle public (requires the API key)
+	router.HandleFunc("/api/paddles/{id}/publish", withCommonHeaders(requireAPIKey(publishPaddle))).Methods("POST")
+	router.HandleFunc("/api/paddles/{id}/source", withCommonHeaders(requireAPIKey(getPaddleSource))).Methods("GET")
+
+	// Admin endpoints (require the API key)
+	router.HandleFunc("/api/admin/integrity", withCommonHeaders(requireAPIKey(getIntegrityReport))).Methods("GET")
+	router.HandleFunc("/api/admin/dump", withCommonHeaders(requireAPIKey(getSQLDump))).Methods("GET")
+	router.HandleFunc("/api/admin/paddles/stub", withCommonHeaders(requireAPIKey(uploadPaddleStub))).Methods("POST")
+	router.HandleFunc("/api/admin/refresh-stats", withCommonHeaders(requireAPIKey(refreshStats))).Methods("POST")
+	router.HandleFunc("/api/admin/drain", withCommonHeaders(requireAPIKey(drainServer))).Methods("POST")
+	router.HandleFunc("/api/admin/reset", withCommonHeaders(requireAPIKey(resetData))).Methods("POST")
+	router.HandleFunc("/api/admin/paddles", withCommonHeaders(requireAPIKey(bulkUpdatePaddles))).Methods("PATCH")
+	router.HandleFunc("/api/admin/featured", withCommonHeaders(requireAPIKey(setFeaturedPaddle))).Methods("PUT")
+	router.HandleFunc("/api/admin/db/activity", withCommonHeaders(requireAPIKey(getDBActivity))).Methods("GET")
+	router.HandleFunc("/api/admin/db/cancel/{pid}", withCommonHeaders(requireAPIKey(cancelDBQuery))).Methods("POST")
+
+	// Unknown routes and wrong methods get the same JSON error body as other errors
+	router.NotFoundHandler = withCommonHeaders(notFound)
+	router.MethodNotAllowedHandler = withCommonHeaders(methodNotAllowed(router))
 
 	// Add logging middleware
 	router.Use(func(next http.Handler) http.Handler {
@@ -43,18 +242,51 @@ func main() {
 		})
 	})
 
-	// Enable CORS
-	c := cors.New(cors.Options{
-		AllowedOrigins:   []string{"https://pickleball-db.vercel.app", "https://pickleball-db.com"}, // Your frontend URLs
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"*"},
-		AllowCredentials: true,
-	})
+	// Reject requests with too many query parameters or oversized headers
+	router.Use(limitRequests)
+
+	// Log request and response bodies when LOG_BODIES is enabled
+	router.Use(logRequestBodies)
+
+	// Refuse new requests once draining
+	router.Use(serverDrainer.middleware)
+
+	// Refuse requests beyond MAX_CONCURRENT_REQUESTS with 503, to protect the database
+	if cfg.MaxConcurrentRequests > 0 {
+		router.Use(newConcurrencyLimiter(cfg.MaxConcurrentRequests).middleware)
+	}
+
+	// Reject write requests that are not JSON
+	router.Use(requireJSONContentType)
 
 	// Use the CORS middleware
-	handler := c.Handler(router)
+	handler := newCORSHandler(cfg.CORS, router)
+
+	// Recover panics outermost, so a failing handler or middleware costs one request, not the server
+	handler = recoverPanics(handler)
+
+	// Stop accepting connections on shutdown and let in-flight requests finish
+	server := &http.Server{Addr: ":8080", Handler: handler}
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+		<-ctx.Done()
+		log.Println("Shutting down server...")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Error shutting down server: %v", err)
+		}
+	}()
 
 	// Start the server with CORS enabled
 	log.Println("Server starting on :8080")
-	log.Fatal(http.ListenAndServe(":8080", handler))
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+
+	// Wait for the server and background goroutines before closing the database
+	<-shutdownDone
+	background.Wait()
+	log.Println("Server stopped")
 }
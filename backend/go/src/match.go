@@ -0,0 +1,274 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// USAPA dimension rules, in inches: a paddle may be at most 17 long, and its
+// length plus width may not exceed 24. Thickness and weight are unrestricted.
+const (
+	maxLegalPaddleLength   = 17.0
+	maxLegalLengthAndWidth = 24.0
+)
+
+// isRegulationLegal reports whether specs meet the USAPA dimension rules
+func isRegulationLegal(specs *Specs) bool {
+	return specs.PaddleLength <= maxLegalPaddleLength &&
+		specs.PaddleLength+specs.PaddleWidth <= maxLegalLengthAndWidth
+}
+
+// MatchTarget is the body of a match request. Specs and Performance hold
+// the desired values of any rangeable spec and performance metric, in the
+// units paddles are stored in. Weights optionally weight those fields
+// against each other; an unweighted field counts 1.
+type MatchTarget struct {
+	Specs       map[string]float64 `json:"specs"`
+	Performance map[string]float64 `json:"performance"`
+	Weights     map[string]float64 `json:"weights"`
+}
+
+// validate checks that the target names known fields with positive values,
+// and that every weight is positive and belongs to a target field
+func (t *MatchTarget) validate() error {
+	if len(t.Specs)+len(t.Performance) == 0 {
+		return fmt.Errorf("at least one spec or performance target is required")
+	}
+
+	for _, group := range []struct {
+		name   string
+		fields []string
+		values map[string]float64
+	}{
+		{"specs", rangeableSpecs, t.Specs},
+		{"performance", performanceMetrics, t.Performance},
+	} {
+		for field, value := range group.values {
+			if !slices.Contains(group.fields, field) {
+				return fmt.Errorf("unknown %s target %q: must be one of %s", group.name, field, strings.Join(group.fields, ", "))
+			}
+			if math.IsNaN(value) || math.IsInf(value, 0) || value <= 0 {
+				return fmt.Errorf("%s target %s must be a positive number", group.name, field)
+			}
+		}
+	}
+
+	for field, weight := range t.Weights {
+		_, isSpec := t.Specs[field]
+		_, isMetric := t.Performance[field]
+		if !isSpec && !isMetric {
+			return fmt.Errorf("weight %q does not name a target field", field)
+		}
+		if math.IsNaN(weight) || math.IsInf(weight, 0) || weight <= 0 {
+			return fmt.Errorf("weight %s must be a positive number", field)
+		}
+	}
+	return nil
+}
+
+// matchField is one target field with a way to read it from a paddle
+type matchField struct {
+	target float64
+	weight float64
+	value  func(*Paddle) float64
+}
+
+// fields lists the target's fields in a stable order
+func (t *MatchTarget) fields() []matchField {
+	var fields []matchField
+	weight := func(field string) float64 {
+		if w, ok := t.Weights[field]; ok {
+			return w
+		}
+		return 1
+	}
+	for _, spec := range rangeableSpecs {
+		if target, ok := t.Specs[spec]; ok {
+			fields = append(fields, matchField{target, weight(spec), func(p *Paddle) float64 {
+				value, _ := specValue(&p.Specs, spec)
+				return value
+			}})
+		}
+	}
+	for _, metric := range performanceMetrics {
+		if target, ok := t.Performance[metric]; ok {
+			fields = append(fields, matchField{target, weight(metric), func(p *Paddle) float64 {
+				return metricValue(&p.Performance, metric)
+			}})
+		}
+	}
+	return fields
+}
+
+// scoredPaddle is a paddle with its distance from a target
+type scoredPaddle struct {
+	paddle   *Paddle
+	distance float64
+}
+
+// rankByDistance returns the paddles nearest the target first. Each field's
+// difference is divided by that field's spread across the paddles, so grams
+// and RPM weigh alike, and the distance is the weighted root mean square of
+// those. Ties keep paddle ID order.
+func rankByDistance(paddles []*Paddle, target *MatchTarget) []scoredPaddle {
+	fields := target.fields()
+	spreads := make([]float64, len(fields))
+	totalWeight := 0.0
+	for i, field := range fields {
+		totalWeight += field.weight
+		low, high := math.Inf(1), math.Inf(-1)
+		for _, paddle := range paddles {
+			value := field.value(paddle)
+			low, high = min(low, value), max(high, value)
+		}
+		// Fall back to the target itself when every paddle has the same value
+		spreads[i] = high - low
+		if spreads[i] == 0 {
+			spreads[i] = field.target
+		}
+		if spreads[i] == 0 {
+			spreads[i] = 1
+		}
+	}
+
+	scored := make([]scoredPaddle, 0, len(paddles))
+	for _, paddle := range paddles {
+		sum := 0.0
+		for i, field := range fields {
+			diff := (field.value(paddle) - field.target) / spreads[i]
+			sum += field.weight * diff * diff
+		}
+		scored = append(scored, scoredPaddle{paddle, math.Sqrt(sum / totalWeight)})
+	}
+
+	slices.SortFunc(scored, func(a, b scoredPaddle) int {
+		if c := cmp.Compare(a.distance, b.distance); c != 0 {
+			return c
+		}
+		return strings.Compare(a.paddle.ID, b.paddle.ID)
+	})
+	return scored
+}
+
+// nearestLegalPaddle returns the regulation-legal paddle closest to the
+// target, and its distance, with spreads taken across the legal paddles.
+// It returns nil when no paddle is legal.
+func nearestLegalPaddle(paddles []*Paddle, target *MatchTarget) (*Paddle, float64) {
+	var legal []*Paddle
+	for _, paddle := range paddles {
+		if isRegulationLegal(&paddle.Specs) {
+			legal = append(legal, paddle)
+		}
+	}
+	if len(legal) == 0 {
+		return nil, 0
+	}
+
+	nearest := rankByDistance(legal, target)[0]
+	return nearest.paddle, nearest.distance
+}
+
+// GetMatchCandidates returns every published paddle with its name, specs and
+// performance averaged across measurements, like the details endpoint
+func GetMatchCandidates() ([]*Paddle, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	rows, err := timedQuery(ctx, DB, "get_match_candidates", `
+		SELECT
+			p.paddle_id, p.brand, p.model, p.year,
+			s.average_weight, s.core, s.paddle_length, s.paddle_width,
+			s.grip_length, s.grip_circumference,
+			AVG(perf.power), AVG(perf.pop), AVG(perf.spin),
+			AVG(perf.twist_weight), AVG(perf.swing_weight), AVG(perf.balance_point)
+		FROM
+			paddles p
+		JOIN
+			paddle_specs s ON p.id = s.paddle_id
+		JOIN
+			paddle_performance perf ON s.id = perf.paddle_spec_id
+		WHERE
+			p.status = 'published'
+		GROUP BY
+			p.id, s.id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	paddles := []*Paddle{}
+	for rows.Next() {
+		paddle := &Paddle{}
+		err := rows.Scan(
+			&paddle.ID, &paddle.Metadata.Brand, &paddle.Metadata.Model, &paddle.Metadata.Year,
+			&paddle.Specs.AverageWeight, &paddle.Specs.Core, &paddle.Specs.PaddleLength,
+			&paddle.Specs.PaddleWidth, &paddle.Specs.GripLength, &paddle.Specs.GripCircumference,
+			&paddle.Performance.Power, &paddle.Performance.Pop, &paddle.Performance.Spin,
+			&paddle.Performance.TwistWeight, &paddle.Performance.SwingWeight, &paddle.Performance.BalancePoint,
+		)
+		if err != nil {
+			return nil, err
+		}
+		paddle.Control = paddle.ControlRating()
+		paddles = append(paddles, paddle)
+	}
+	return paddles, rows.Err()
+}
+
+// MatchResult is the paddle nearest to a match target
+type MatchResult struct {
+	Distance float64        `json:"distance"`
+	Paddle   PaddleResponse `json:"paddle"`
+}
+
+// matchPaddle handles the API request for the regulation-legal paddle
+// closest to a partial spec and performance target
+func matchPaddle(w http.ResponseWriter, r *http.Request) {
+	var target MatchTarget
+	if err := decodeJSONBody(r.Body, &target); err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	if err := target.validate(); err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid target: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	candidates, err := store.GetMatchCandidates()
+	if err != nil {
+		log.Printf("Error retrieving paddles to match: %v", err)
+		respondWithError(w, "Failed to match paddle", http.StatusInternalServerError)
+		return
+	}
+
+	nearest, distance := nearestLegalPaddle(candidates, &target)
+	if nearest == nil {
+		respondWithError(w, "No regulation-legal paddle found", http.StatusNotFound)
+		return
+	}
+
+	paddle, err := store.GetPaddleByID(nearest.ID)
+	if errors.Is(err, ErrPaddleNotFound) {
+		respondWithError(w, "No regulation-legal paddle found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Error retrieving matched paddle: %v", err)
+		respondWithError(w, "Failed to match paddle", http.StatusInternalServerError)
+		return
+	}
+	paddle.convertUnits(metricUnits)
+
+	result := MatchResult{Distance: roundTo(distance, 4), Paddle: newPaddleResponse(paddle, nil)}
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Printf("Error encoding matched paddle: %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// TestIsRegulationLegal tests the USAPA length and length-plus-width limits
+func TestIsRegulationLegal(t *testing.T) {
+	tests := []struct {
+		name          string
+		length, width float64
+		want          bool
+	}{
+		{name: "Standard", length: 16.5, width: 7.5, want: true},
+		{name: "At both limits", length: 17, width: 7, want: true},
+		{name: "Too long", length: 17.25, width: 6.5, want: false},
+		{name: "Too wide for its length", length: 16.5, width: 7.75, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specs := &Specs{PaddleLength: tt.length, PaddleWidth: tt.width}
+			if got := isRegulationLegal(specs); got != tt.want {
+				t.Errorf("isRegulationLegal(%g x %g) = %v, want %v", tt.length, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMatchTargetValidate tests rejecting empty, unknown and non-positive targets
+func TestMatchTargetValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  MatchTarget
+		wantErr bool
+	}{
+		{name: "Spec and metric", target: MatchTarget{Specs: map[string]float64{"average_weight": 225}, Performance: map[string]float64{"power": 80}}},
+		{name: "Weighted", target: MatchTarget{Performance: map[string]float64{"spin": 2500}, Weights: map[string]float64{"spin": 2}}},
+		{name: "Empty", target: MatchTarget{}, wantErr: true},
+		{name: "Unknown spec", target: MatchTarget{Specs: map[string]float64{"grip_type": 1}}, wantErr: true},
+		{name: "Spec named as metric", target: MatchTarget{Performance: map[string]float64{"core": 16}}, wantErr: true},
+		{name: "Zero", target: MatchTarget{Performance: map[string]float64{"power": 0}}, wantErr: true},
+		{name: "Infinite", target: MatchTarget{Performance: map[string]float64{"power": math.Inf(1)}}, wantErr: true},
+		{name: "Weight without target", target: MatchTarget{Performance: map[string]float64{"power": 80}, Weights: map[string]float64{"spin": 1}}, wantErr: true},
+		{name: "Negative weight", target: MatchTarget{Performance: map[string]float64{"power": 80}, Weights: map[string]float64{"power": -1}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.target.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestNearestLegalPaddle tests that the closest paddle wins only when it is
+// legal, and that weights change which paddle is closest
+func TestNearestLegalPaddle(t *testing.T) {
+	newPaddle := func(id string, length, width, weight, power, spin float64) *Paddle {
+		return &Paddle{
+			ID:          id,
+			Specs:       Specs{PaddleLength: length, PaddleWidth: width, AverageWeight: weight},
+			Performance: Performance{Power: power, Spin: spin},
+		}
+	}
+	paddles := []*Paddle{
+		// An exact match, but 17.5 inches long
+		newPaddle("oversize-exact", 17.5, 6.5, 225, 80, 2500),
+		newPaddle("engage-power", 16.5, 7.5, 226, 82, 2400),
+		newPaddle("selkirk-spin", 16.5, 7.5, 215, 70, 2480),
+		newPaddle("joola-heavy", 16.0, 7.5, 240, 60, 2000),
+	}
+
+	target := &MatchTarget{
+		Specs:       map[string]float64{"average_weight": 225},
+		Performance: map[string]float64{"power": 80, "spin": 2500},
+	}
+	nearest, distance := nearestLegalPaddle(paddles, target)
+	if nearest == nil || nearest.ID != "engage-power" {
+		t.Fatalf("nearestLegalPaddle() = %v, want engage-power", nearest)
+	}
+	if distance <= 0 {
+		t.Errorf("distance = %g, want a positive distance for an inexact match", distance)
+	}
+
+	// Weighting spin heavily favors the spin paddle
+	target.Weights = map[string]float64{"spin": 10}
+	if nearest, _ := nearestLegalPaddle(paddles, target); nearest == nil || nearest.ID != "selkirk-spin" {
+		t.Errorf("nearestLegalPaddle() with spin weighted = %v, want selkirk-spin", nearest)
+	}
+
+	if nearest, _ := nearestLegalPaddle(paddles[:1], target); nearest != nil {
+		t.Errorf("nearestLegalPaddle() of only illegal paddles = %v, want nil", nearest)
+	}
+}
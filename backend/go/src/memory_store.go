@@ -0,0 +1,627 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memoryStore is an in-memory Store for hermetic tests. Paddles are kept in
+// insertion order, which stands in for Postgres' id order.
+type memoryStore struct {
+	mu       sync.RWMutex
+	paddles  map[string]*Paddle
+	dbIDs    map[string]int
+	order    []string
+	featured *FeaturedSelection
+	// uploads holds the latest upload body by database ID, which survives renames
+	uploads map[int]RawUpload
+	// tombstones holds the paddle IDs given up by renames, by old ID
+	tombstones map[string]PaddleTombstone
+}
+
+// newMemoryStore returns an empty in-memory store
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		paddles:    map[string]*Paddle{},
+		dbIDs:      map[string]int{},
+		uploads:    map[int]RawUpload{},
+		tombstones: map[string]PaddleTombstone{},
+	}
+}
+
+// complete returns the stored paddle if it has performance. Postgres reads
+// join the specs and performance, so stubs without them stay hidden.
+func (m *memoryStore) complete(paddleId string) (*Paddle, bool) {
+	paddle, ok := m.paddles[paddleId]
+	if !ok || paddle.Performance == (Performance{}) {
+		return nil, false
+	}
+	return paddle, true
+}
+
+// copyPaddle returns a copy of paddle that shares no mutable state with it
+func copyPaddle(paddle *Paddle) *Paddle {
+	c := *paddle
+	if paddle.Metadata.Year != nil {
+		year := *paddle.Metadata.Year
+		c.Metadata.Year = &year
+	}
+	if paddle.Metadata.Price != nil {
+		price := *paddle.Metadata.Price
+		c.Metadata.Price = &price
+	}
+	for _, stddev := range c.Performance.stddevs() {
+		if *stddev != nil {
+			v := **stddev
+			*stddev = &v
+		}
+	}
+	c.SpecRanges = maps.Clone(paddle.SpecRanges)
+	c.Tags = slices.Clone(paddle.Tags)
+	return &c
+}
+
+// recordUpload keeps the upload body of a saved paddle apart from the paddle,
+// like the Postgres store's paddle_raw_uploads table
+func (m *memoryStore) recordUpload(stored *Paddle) {
+	if stored.UploadBody != nil {
+		m.uploads[stored.DBID] = RawUpload{Body: slices.Clone(stored.UploadBody), UploadedAt: now()}
+		stored.UploadBody = nil
+	}
+}
+
+func (m *memoryStore) GetPaddleByID(paddleId string) (*Paddle, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	paddle, ok := m.complete(NormalizePaddleID(paddleId))
+	if !ok {
+		return nil, ErrPaddleNotFound
+	}
+	return copyPaddle(paddle), nil
+}
+
+func (m *memoryStore) GetPaddleByDBID(id int) (*Paddle, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if id < 1 || id > len(m.order) {
+		return nil, ErrPaddleNotFound
+	}
+	paddle, ok := m.complete(m.order[id-1])
+	if !ok {
+		return nil, ErrPaddleNotFound
+	}
+	return copyPaddle(paddle), nil
+}
+
+func (m *memoryStore) PaddleExists(paddleId string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	_, ok := m.paddles[NormalizePaddleID(paddleId)]
+	return ok, nil
+}
+
+func (m *memoryStore) FindPaddleIDByName(brand, model string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, id := range m.order {
+		paddle := m.paddles[id]
+		if strings.EqualFold(paddle.Metadata.Brand, brand) && strings.EqualFold(paddle.Metadata.Model, model) {
+			return id, nil
+		}
+	}
+	return "", ErrPaddleNotFound
+}
+
+func (m *memoryStore) CountPaddles() (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	count := 0
+	for _, paddle := range m.paddles {
+		if paddle.Status == StatusPublished {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *memoryStore) SavePaddle(paddle *Paddle) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.save(paddle)
+}
+
+// save inserts a new paddle; the caller must hold the write lock
+func (m *memoryStore) save(paddle *Paddle) (int, error) {
+	if _, exists := m.paddles[paddle.ID]; exists {
+		return 0, fmt.Errorf("%w: %s", ErrPaddleExists, paddle.ID)
+	}
+	if maxPaddles > 0 && len(m.order) >= maxPaddles {
+		return 0, fmt.Errorf("%w: limit is %d", ErrQuotaExceeded, maxPaddles)
+	}
+
+	stored := copyPaddle(paddle)
+	if stored.Status == "" {
+		// Mirror the column default
+		stored.Status = StatusPublished
+	}
+	stored.CreatedAt = NewTime(now())
+	stored.UpdatedAt = stored.CreatedAt
+	stored.Control = stored.ControlRating()
+
+	m.order = append(m.order, paddle.ID)
+	stored.DBID = len(m.order)
+	m.paddles[paddle.ID] = stored
+	m.dbIDs[paddle.ID] = stored.DBID
+	m.recordUpload(stored)
+
+	// Hand back what the database would return to the caller
+	paddle.DBID, paddle.CreatedAt, paddle.UpdatedAt = stored.DBID, stored.CreatedAt, stored.UpdatedAt
+	return stored.DBID, nil
+}
+
+func (m *memoryStore) UpsertPaddle(paddle *Paddle) (int, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Check and write under one lock, so concurrent upserts of a new paddle
+	// insert it once and update it after
+	existing, ok := m.paddles[paddle.ID]
+	if !ok {
+		id, err := m.save(paddle)
+		return id, err == nil, err
+	}
+
+	replaced := copyPaddle(paddle)
+	replaced.CreatedAt = existing.CreatedAt
+	replaced.Tags = existing.Tags
+	replaced.Status = existing.Status
+	replaced.DBID = existing.DBID
+	replaced.UpdatedAt = NewTime(now())
+	paddle.Status, paddle.DBID = existing.Status, existing.DBID
+	paddle.CreatedAt, paddle.UpdatedAt = replaced.CreatedAt, replaced.UpdatedAt
+	replaced.Control = replaced.ControlRating()
+	m.paddles[paddle.ID] = replaced
+	m.recordUpload(replaced)
+	return m.dbIDs[paddle.ID], false, nil
+}
+
+func (m *memoryStore) UpdatePaddlePerformance(paddleId string, performance *Performance) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	paddle, ok := m.complete(paddleId)
+	if !ok {
+		return ErrPaddleNotFound
+	}
+	paddle.Performance = *performance
+	paddle.Control = paddle.ControlRating()
+	paddle.UpdatedAt = NewTime(now())
+	return nil
+}
+
+func (m *memoryStore) PublishPaddle(paddleId string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	paddle, ok := m.paddles[paddleId]
+	if !ok {
+		return false, ErrPaddleNotFound
+	}
+	if paddle.Status == StatusPublished {
+		return false, nil
+	}
+	paddle.Status = StatusPublished
+	paddle.UpdatedAt = NewTime(now())
+	return true, nil
+}
+
+func (m *memoryStore) GetAggregatedPerformance(paddleId string) (*AggregatedPerformance, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	paddle, ok := m.complete(paddleId)
+	if !ok {
+		return nil, ErrPaddleNotFound
+	}
+	agg := averagePerformance([]Performance{paddle.Performance})
+	return &agg, nil
+}
+
+func (m *memoryStore) GetPerformanceByIDs(paddleIds []string) (map[string]Performance, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	performance := map[string]Performance{}
+	for _, id := range paddleIds {
+		if paddle, ok := m.complete(id); ok {
+			performance[id] = paddle.Performance
+		}
+	}
+	return performance, nil
+}
+
+func (m *memoryStore) GetCompareRows(paddleIds []string) (map[string]CompareRow, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	rows := map[string]CompareRow{}
+	for _, id := range paddleIds {
+		if paddle, ok := m.complete(id); ok {
+			rows[id] = newCompareRow(paddle)
+		}
+	}
+	return rows, nil
+}
+
+func (m *memoryStore) GetSpecRanges(paddleId string) (map[string]SpecRange, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ranges := map[string]SpecRange{}
+	if paddle, ok := m.paddles[paddleId]; ok {
+		maps.Copy(ranges, paddle.SpecRanges)
+	}
+	return ranges, nil
+}
+
+func (m *memoryStore) GetTags(paddleId string) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tags := []string{}
+	if paddle, ok := m.paddles[paddleId]; ok {
+		tags = append(tags, paddle.Tags...)
+	}
+	return tags, nil
+}
+
+func (m *memoryStore) AddTags(paddleId string, tags []string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	paddle, ok := m.paddles[paddleId]
+	if !ok {
+		return nil, ErrPaddleNotFound
+	}
+	for _, tag := range tags {
+		if !slices.Contains(paddle.Tags, tag) {
+			paddle.Tags = append(paddle.Tags, tag)
+			paddle.UpdatedAt = NewTime(now())
+		}
+	}
+	slices.Sort(paddle.Tags)
+	return slices.Clone(paddle.Tags), nil
+}
+
+func (m *memoryStore) RemoveTag(paddleId, tag string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	paddle, ok := m.paddles[paddleId]
+	if !ok {
+		return nil, ErrPaddleNotFound
+	}
+	i := slices.Index(paddle.Tags, tag)
+	if i < 0 {
+		return nil, ErrTagNotFound
+	}
+	paddle.Tags = slices.Delete(paddle.Tags, i, i+1)
+	paddle.UpdatedAt = NewTime(now())
+	return slices.Clone(paddle.Tags), nil
+}
+
+func (m *memoryStore) GetPaddlesPage(filter paddleFilter, sort listSort, limit, offset int) ([]*Paddle, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	matching := []*Paddle{}
+	for _, id := range m.order {
+		if paddle, ok := m.complete(id); ok && filter.matches(paddle) {
+			matching = append(matching, paddle)
+		}
+	}
+	sort.sortPaddles(matching)
+
+	paddles := []*Paddle{}
+	for _, paddle := range matching[min(offset, len(matching)):min(offset+limit, len(matching))] {
+		paddles = append(paddles, copyPaddle(paddle))
+	}
+	return paddles, nil
+}
+
+func (m *memoryStore) CountPaddlesMatching(filter paddleFilter) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	count := 0
+	for _, id := range m.order {
+		if paddle, ok := m.complete(id); ok && filter.matches(paddle) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *memoryStore) GetPaddleChanges(since time.Time, after string, limit int) ([]*Paddle, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	changed := []*Paddle{}
+	for _, id := range m.order {
+		paddle, ok := m.complete(id)
+		if !ok || paddle.Status != StatusPublished {
+			continue
+		}
+		if updated := paddle.UpdatedAt.Time; updated.After(since) || (updated.Equal(since) && after != "" && id > after) {
+			changed = append(changed, copyPaddle(paddle))
+		}
+	}
+	slices.SortFunc(changed, func(a, b *Paddle) int {
+		if c := a.UpdatedAt.Compare(b.UpdatedAt.Time); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return changed[:min(limit, len(changed))], nil
+}
+
+func (m *memoryStore) GetPaddleTombstones(since time.Time, after string, limit int) ([]PaddleTombstone, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tombstones := []PaddleTombstone{}
+	for id, tombstone := range m.tombstones {
+		if deleted := tombstone.DeletedAt.Time; deleted.After(since) || (deleted.Equal(since) && after != "" && id > after) {
+			tombstones = append(tombstones, tombstone)
+		}
+	}
+	slices.SortFunc(tombstones, compareTombstones)
+	return tombstones[:min(limit, len(tombstones))], nil
+}
+
+func (m *memoryStore) GetElitePaddles(metric string, threshold float64, limit, offset int) ([]*Paddle, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	elite := []*Paddle{}
+	for _, id := range m.order {
+		paddle, ok := m.complete(id)
+		if ok && paddle.Status == StatusPublished && metricValue(&paddle.Performance, metric) >= threshold {
+			elite = append(elite, copyPaddle(paddle))
+		}
+	}
+	slices.SortFunc(elite, func(a, b *Paddle) int {
+		if c := cmp.Compare(metricValue(&b.Performance, metric), metricValue(&a.Performance, metric)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return elite[min(offset, len(elite)):min(offset+limit, len(elite))], nil
+}
+
+func (m *memoryStore) GetBrandCounts() ([]BrandCount, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	byBrand := map[string]int{}
+	for _, paddle := range m.paddles {
+		if paddle.Status == StatusPublished {
+			byBrand[paddle.Metadata.Brand]++
+		}
+	}
+
+	counts := []BrandCount{}
+	for brand, count := range byBrand {
+		counts = append(counts, BrandCount{Brand: brand, Count: count})
+	}
+	slices.SortFunc(counts, func(a, b BrandCount) int { return strings.Compare(a.Brand, b.Brand) })
+	return counts, nil
+}
+
+func (m *memoryStore) GetDatasetStats() (*DatasetStats, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var means []Performance
+	for _, id := range m.order {
+		if paddle, ok := m.complete(id); ok && paddle.Status == StatusPublished {
+			means = append(means, paddle.Performance)
+		}
+	}
+	return summarizePerformance(means), nil
+}
+
+func (m *memoryStore) GetSubsetStats(filter paddleFilter) (*SubsetStats, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var matching []*Paddle
+	for _, id := range m.order {
+		if paddle, ok := m.complete(id); ok && filter.matches(paddle) {
+			matching = append(matching, paddle)
+		}
+	}
+	return summarizePaddles(matching), nil
+}
+
+func (m *memoryStore) GetFacets(filter paddleFilter) (*Facets, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := &Facets{}
+	facets := facetsOf(filter, result)
+	// Values are grouped case-insensitively, keeping the first in sort order
+	byValue := make([]map[string]*FacetCount, len(facets))
+	for i := range facets {
+		byValue[i] = map[string]*FacetCount{}
+	}
+	for _, id := range m.order {
+		paddle, ok := m.complete(id)
+		if !ok {
+			continue
+		}
+		if filter.matches(paddle) {
+			result.Total++
+		}
+		for i, f := range facets {
+			if !f.filter.matches(paddle) {
+				continue
+			}
+			value := f.value(paddle)
+			count, ok := byValue[i][strings.ToLower(value)]
+			if !ok {
+				count = &FacetCount{Value: value}
+				byValue[i][strings.ToLower(value)] = count
+			}
+			count.Value = min(count.Value, value)
+			count.Count++
+		}
+	}
+
+	for i, f := range facets {
+		counts := []FacetCount{}
+		for _, count := range byValue[i] {
+			counts = append(counts, *count)
+		}
+		sortFacetCounts(counts)
+		*f.counts = counts
+	}
+	return result, nil
+}
+
+func (m *memoryStore) BulkUpdatePaddles(filter paddleFilter, update BulkUpdate) (*BulkUpdateResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, id := range m.order {
+		if paddle := m.paddles[id]; filter.matches(paddle) {
+			if err := update.validatePaddle(paddle); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	result := &BulkUpdateResult{Renamed: []IDChange{}, Collisions: []IDChange{}}
+	updatedAt := NewTime(now())
+	for i, id := range slices.Clone(m.order) {
+		paddle := m.paddles[id]
+		if !filter.matches(paddle) {
+			continue
+		}
+		result.Matched++
+
+		newID := updatedPaddleID(id, Metadata{Brand: update.Brand, Model: paddle.Metadata.Model})
+		if newID != id {
+			if _, taken := m.paddles[newID]; taken {
+				result.Collisions = append(result.Collisions, IDChange{From: id, To: newID})
+				continue
+			}
+			delete(m.paddles, id)
+			delete(m.dbIDs, id)
+			m.paddles[newID] = paddle
+			m.dbIDs[newID] = paddle.DBID
+			m.order[i] = newID
+			// The featured row references the paddle, not its ID
+			if m.featured != nil && m.featured.PaddleID == id {
+				m.featured.PaddleID = newID
+			}
+			if paddle.Status == StatusPublished {
+				m.tombstones[id] = PaddleTombstone{ID: id, ReplacedBy: newID, DeletedAt: updatedAt, Deleted: true}
+			}
+			result.Renamed = append(result.Renamed, IDChange{From: id, To: newID})
+		}
+		paddle.ID = newID
+		paddle.Metadata.Brand = update.Brand
+		paddle.UpdatedAt = updatedAt
+		result.Updated++
+	}
+	return result, nil
+}
+
+func (m *memoryStore) GetMatchCandidates() ([]*Paddle, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	paddles := []*Paddle{}
+	for _, id := range m.order {
+		if paddle, ok := m.complete(id); ok && paddle.Status == StatusPublished {
+			paddles = append(paddles, copyPaddle(paddle))
+		}
+	}
+	return paddles, nil
+}
+
+func (m *memoryStore) GetFeatured() (*FeaturedSelection, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.featured == nil {
+		return nil, nil
+	}
+	selection := *m.featured
+	return &selection, nil
+}
+
+func (m *memoryStore) SetFeatured(selection FeaturedSelection) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.paddles[selection.PaddleID]; !ok {
+		return ErrPaddleNotFound
+	}
+	m.featured = &selection
+	return nil
+}
+
+func (m *memoryStore) GetPublishedPaddleIDs() ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ids := []string{}
+	for _, id := range m.order {
+		if paddle, ok := m.complete(id); ok && paddle.Status == StatusPublished {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+func (m *memoryStore) GetRawUpload(paddleId string) (*RawUpload, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	paddle, ok := m.paddles[paddleId]
+	if !ok {
+		return nil, ErrPaddleNotFound
+	}
+	upload, ok := m.uploads[paddle.DBID]
+	if !ok {
+		return nil, nil
+	}
+	upload.Body = slices.Clone(upload.Body)
+	return &upload, nil
+}
+
+func (m *memoryStore) ResetData() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.paddles = map[string]*Paddle{}
+	m.dbIDs = map[string]int{}
+	m.order = nil
+	m.featured = nil
+	m.uploads = map[int]RawUpload{}
+	m.tombstones = map[string]PaddleTombstone{}
+	return nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// TestMemoryStore tests saving, duplicate detection, paging and updates in the in-memory store
+func TestMemoryStore(t *testing.T) {
+	m := newMemoryStore()
+
+	for i := 1; i <= 3; i++ {
+		paddle := (&PaddleInput{
+			Metadata:    Metadata{Brand: "Engage", Model: fmt.Sprintf("Pursuit %d", i)},
+			Performance: Performance{Power: 60, Pop: 40},
+		}).ToPaddle()
+		id, err := m.SavePaddle(paddle)
+		if err != nil {
+			t.Fatalf("SavePaddle() returned error: %v", err)
+		}
+		if id != i {
+			t.Errorf("SavePaddle() id = %d, want %d", id, i)
+		}
+	}
+
+	duplicate := (&PaddleInput{Metadata: Metadata{Brand: "Engage", Model: "Pursuit 1"}}).ToPaddle()
+	if _, err := m.SavePaddle(duplicate); !errors.Is(err, ErrPaddleExists) {
+		t.Errorf("SavePaddle() of a duplicate error = %v, want ErrPaddleExists", err)
+	}
+
+	page, err := m.GetPaddlesPage(paddleFilter{}, defaultListSort, 2, 1)
+	if err != nil {
+		t.Fatalf("GetPaddlesPage() returned error: %v", err)
+	}
+	if len(page) != 2 || page[0].Metadata.Model != "Pursuit 2" || page[1].Metadata.Model != "Pursuit 3" {
+		t.Errorf("GetPaddlesPage(2, 1) returned unexpected paddles: %+v", page)
+	}
+
+	id := generatePaddleID("Engage", "Pursuit 2")
+	if err := m.UpdatePaddlePerformance(id, &Performance{Power: 80, Pop: 50}); err != nil {
+		t.Fatalf("UpdatePaddlePerformance() returned error: %v", err)
+	}
+	paddle, err := m.GetPaddleByID(id)
+	if err != nil {
+		t.Fatalf("GetPaddleByID() returned error: %v", err)
+	}
+	if paddle.Performance.Power != 80 || paddle.Control != paddle.ControlRating() {
+		t.Errorf("Updated paddle = %+v, want power 80 with recomputed control", paddle.Performance)
+	}
+
+	// Returned paddles are copies; changing them does not change the store
+	paddle.Performance.Power = 0
+	if again, _ := m.GetPaddleByID(id); again.Performance.Power != 80 {
+		t.Errorf("Store was modified through a returned paddle")
+	}
+
+	if _, err := m.GetPaddleByID("missing"); !errors.Is(err, ErrPaddleNotFound) {
+		t.Errorf("GetPaddleByID() of a missing paddle error = %v, want ErrPaddleNotFound", err)
+	}
+}
+
+// TestMemoryStoreConcurrentUpsert tests that concurrent upserts of a new
+// paddle insert it exactly once and update it for the rest
+func TestMemoryStoreConcurrentUpsert(t *testing.T) {
+	m := newMemoryStore()
+
+	const upserts = 20
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	created := 0
+	for i := 0; i < upserts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, inserted, err := m.UpsertPaddle(testPaddleInput("Engage", "Pursuit MX 6.0").ToPaddle())
+			if err != nil {
+				t.Errorf("UpsertPaddle() error: %v", err)
+				return
+			}
+			if inserted {
+				mu.Lock()
+				created++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if created != 1 {
+		t.Errorf("%d upserts inserted the paddle, want 1", created)
+	}
+	if count, err := m.CountPaddles(); err != nil || count != 1 {
+		t.Errorf("CountPaddles() = %d, %v; want 1", count, err)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import "fmt"
+
+// performanceMetrics are the performance fields that can be named in query
+// parameters, in display order
+var performanceMetrics = []string{"power", "pop", "spin", "twist_weight", "swing_weight", "balance_point"}
+
+// performanceMetricColumns maps each metric name to its column in fullPaddleQuery.
+// Only names in this map may ever be interpolated into SQL.
+var performanceMetricColumns = map[string]string{
+	"power":         "perf.power",
+	"pop":           "perf.pop",
+	"spin":          "perf.spin",
+	"twist_weight":  "perf.twist_weight",
+	"swing_weight":  "perf.swing_weight",
+	"balance_point": "perf.balance_point",
+}
+
+// metricMeanColumn returns a subquery for the mean of a metric across the
+// measurements of the paddle whose specs are s in the current row. The metric
+// must be a key of performanceMetricColumns.
+func metricMeanColumn(metric string) string {
+	return fmt.Sprintf("(SELECT AVG(%s) FROM paddle_performance perf WHERE perf.paddle_spec_id = s.id)", performanceMetricColumns[metric])
+}
+
+// metricValue returns the value of the named performance metric
+func metricValue(performance *Performance, metric string) float64 {
+	switch metric {
+	case "power":
+		return performance.Power
+	case "pop":
+		return performance.Pop
+	case "spin":
+		return performance.Spin
+	case "twist_weight":
+		return performance.TwistWeight
+	case "swing_weight":
+		return performance.SwingWeight
+	case "balance_point":
+		return performance.BalancePoint
+	}
+	return 0
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Default for LOG_BODIES_MAX_BYTES
+const defaultLogBodiesMaxBytes = 2048
+
+var (
+	// logBodies enables request/response body logging, set via LOG_BODIES=true
+	logBodies = false
+	// logBodiesMaxBytes caps how much of each body is logged
+	logBodiesMaxBytes = defaultLogBodiesMaxBytes
+)
+
+// bodyLoggingConfig holds the body logging settings
+type bodyLoggingConfig struct {
+	Enabled  bool
+	MaxBytes int
+}
+
+// loadBodyLoggingConfig reads the body logging settings from the environment
+func loadBodyLoggingConfig() (bodyLoggingConfig, error) {
+	enabled, err := strconv.ParseBool(getEnv("LOG_BODIES", "false"))
+	if err != nil {
+		return bodyLoggingConfig{}, fmt.Errorf("LOG_BODIES must be true or false")
+	}
+
+	maxBytes, err := strconv.Atoi(getEnv("LOG_BODIES_MAX_BYTES", strconv.Itoa(defaultLogBodiesMaxBytes)))
+	if err != nil || maxBytes <= 0 {
+		return bodyLoggingConfig{}, fmt.Errorf("LOG_BODIES_MAX_BYTES must be a positive integer")
+	}
+
+	return bodyLoggingConfig{Enabled: enabled, MaxBytes: maxBytes}, nil
+}
+
+// Defaults for MAX_QUERY_PARAMS and MAX_HEADER_BYTES. The busiest legitimate
+// requests, filtered and weighted lists, send about a dozen parameters.
+const (
+	defaultMaxQueryParams = 50
+	defaultMaxHeaderBytes = 16384
+)
+
+var (
+	// maxQueryParams caps the number of query parameters in a request
+	maxQueryParams = defaultMaxQueryParams
+	// maxHeaderBytes caps the total size of a request's header names and values
+	maxHeaderBytes = defaultMaxHeaderBytes
+)
+
+// requestLimitConfig holds the query parameter and header size limits
+type requestLimitConfig struct {
+	MaxQueryParams int
+	MaxHeaderBytes int
+}
+
+// loadRequestLimitConfig reads the query parameter and header size limits from the environment
+func loadRequestLimitConfig() (requestLimitConfig, error) {
+	params, err := strconv.Atoi(getEnv("MAX_QUERY_PARAMS", strconv.Itoa(defaultMaxQueryParams)))
+	if err != nil || params <= 0 {
+		return requestLimitConfig{}, fmt.Errorf("MAX_QUERY_PARAMS must be a positive integer")
+	}
+
+	headerBytes, err := strconv.Atoi(getEnv("MAX_HEADER_BYTES", strconv.Itoa(defaultMaxHeaderBytes)))
+	if err != nil || headerBytes <= 0 {
+		return requestLimitConfig{}, fmt.Errorf("MAX_HEADER_BYTES must be a positive integer")
+	}
+
+	return requestLimitConfig{MaxQueryParams: params, MaxHeaderBytes: headerBytes}, nil
+}
+
+// countQueryParams counts the parameters in a raw query string without
+// parsing it. A repeated name counts once per value.
+func countQueryParams(rawQuery string) int {
+	count := 0
+	for _, param := range strings.Split(rawQuery, "&") {
+		if param != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// headerSize returns the total length of a request's header names and values
+func headerSize(header http.Header) int {
+	size := 0
+	for name, values := range header {
+		for _, value := range values {
+			size += len(name) + len(value)
+		}
+	}
+	return size
+}
+
+// limitRequests rejects requests with more than maxQueryParams query
+// parameters or more than maxHeaderBytes of headers with 400, before the
+// handler parses them
+func limitRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if n := countQueryParams(r.URL.RawQuery); n > maxQueryParams {
+			w.Header().Set("Content-Type", "application/json")
+			respondWithError(w, fmt.Sprintf("Too many query parameters: %d, at most %d are allowed", n, maxQueryParams), http.StatusBadRequest)
+			return
+		}
+
+		if size := headerSize(r.Header); size > maxHeaderBytes {
+			w.Header().Set("Content-Type", "application/json")
+			respondWithError(w, fmt.Sprintf("Request headers too large: %d bytes, at most %d are allowed", size, maxHeaderBytes), http.StatusBadRequest)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// bodyMediaTypes lists the routes whose request body is not JSON, with the
+// media type they accept instead
+var bodyMediaTypes = map[string]string{
+	"/api/paddles/validate-csv": "text/csv",
+}
+
+// requireJSONContentType rejects write requests whose body is not JSON (or the
+// route's type in bodyMediaTypes) with 415 Unsupported Media Type. Parameters
+// such as charset are allowed, and bodyless writes (e.g. a POST that only
+// triggers an action) pass through.
+func requireJSONContentType(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+		default:
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		contentType := r.Header.Get("Content-Type")
+		if contentType == "" && r.ContentLength == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		want := "application/json"
+		if bodyType, ok := bodyMediaTypes[r.URL.Path]; ok {
+			want = bodyType
+		}
+
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || mediaType != want {
+			w.Header().Set("Content-Type", "application/json")
+			respondWithError(w, "Content-Type must be "+want, http.StatusUnsupportedMediaType)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// bodyRecorder is a ResponseWriter that keeps a capped copy of the response body
+type bodyRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+	limit  int
+	size   int
+}
+
+func (rec *bodyRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *bodyRecorder) Write(p []byte) (int, error) {
+	rec.size += len(p)
+	if remaining := rec.limit - rec.body.Len(); remaining > 0 {
+		rec.body.Write(p[:min(len(p), remaining)])
+	}
+	return rec.ResponseWriter.Write(p)
+}
+
+// Flush passes flushes through so streaming handlers keep working
+func (rec *bodyRecorder) Flush() {
+	if flusher, ok := rec.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// logRequestBodies logs request and response bodies, truncated to
+// logBodiesMaxBytes, when LOG_BODIES is enabled. The request body is
+// restored so the handler can still read it.
+func logRequestBodies(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !logBodies {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var requestBody []byte
+		if r.Body != nil {
+			var err error
+			requestBody, err = io.ReadAll(r.Body)
+			r.Body.Close()
+			if err != nil {
+				respondWithError(w, "Failed to read request body", http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(requestBody))
+		}
+
+		rec := &bodyRecorder{ResponseWriter: w, status: http.StatusOK, limit: logBodiesMaxBytes}
+		next.ServeHTTP(rec, r)
+
+		log.Printf("DEBUG: %s %s request body: %s", r.Method, r.URL.Path, truncateBody(requestBody, len(requestBody), logBodiesMaxBytes))
+		log.Printf("DEBUG: %s %s response %d body: %s", r.Method, r.URL.Path, rec.status, truncateBody(rec.body.Bytes(), rec.size, logBodiesMaxBytes))
+	})
+}
+
+// truncateBody returns body as a string, cut to at most limit bytes.
+// size is the full body length, which may exceed len(body) when only
+// a prefix was kept.
+func truncateBody(body []byte, size, limit int) string {
+	if size <= limit {
+		return string(body)
+	}
+	return fmt.Sprintf("%s... (%d bytes truncated)", body[:min(len(body), limit)], size-limit)
+}
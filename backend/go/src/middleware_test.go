@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestRequireJSONContentType tests the requireJSONContentType middleware
+func TestRequireJSONContentType(t *testing.T) {
+	handler := requireJSONContentType(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		contentType    string
+		body           string
+		expectedStatus int
+	}{
+		{name: "JSON post", method: "POST", contentType: "application/json", body: "{}", expectedStatus: http.StatusOK},
+		{name: "JSON with charset", method: "PUT", contentType: "application/json; charset=utf-8", body: "{}", expectedStatus: http.StatusOK},
+		{name: "Text post", method: "POST", contentType: "text/plain", body: "{}", expectedStatus: http.StatusUnsupportedMediaType},
+		{name: "Form post", method: "PATCH", contentType: "application/x-www-form-urlencoded", body: "a=b", expectedStatus: http.StatusUnsupportedMediaType},
+		{name: "Missing content type with body", method: "POST", contentType: "", body: "{}", expectedStatus: http.StatusUnsupportedMediaType},
+		{name: "Bodyless post", method: "POST", contentType: "", body: "", expectedStatus: http.StatusOK},
+		{name: "GET ignores content type", method: "GET", contentType: "text/plain", body: "", expectedStatus: http.StatusOK},
+		{name: "CSV on the CSV route", method: "POST", path: "/api/paddles/validate-csv", contentType: "text/csv; charset=utf-8", body: "brand\n", expectedStatus: http.StatusOK},
+		{name: "JSON on the CSV route", method: "POST", path: "/api/paddles/validate-csv", contentType: "application/json", body: "{}", expectedStatus: http.StatusUnsupportedMediaType},
+		{name: "CSV elsewhere", method: "POST", contentType: "text/csv", body: "brand\n", expectedStatus: http.StatusUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := tt.path
+			if path == "" {
+				path = "/api/paddles"
+			}
+			req := httptest.NewRequest(tt.method, path, bytes.NewBufferString(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+			want := "Content-Type must be application/json"
+			if bodyType, ok := bodyMediaTypes[path]; ok {
+				want = "Content-Type must be " + bodyType
+			}
+			if rr.Code == http.StatusUnsupportedMediaType && !bytes.Contains(rr.Body.Bytes(), []byte(want)) {
+				t.Errorf("Expected JSON error body, got %s", rr.Body.String())
+			}
+		})
+	}
+}
+
+// TestLogRequestBodies tests that bodies are logged and the handler can still read the request
+func TestLogRequestBodies(t *testing.T) {
+	logBodies, logBodiesMaxBytes = true, 16
+	defer func() { logBodies, logBodiesMaxBytes = false, defaultLogBodiesMaxBytes }()
+
+	buf := captureLog(t)
+
+	var received string
+	handler := logRequestBodies(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"status":"created"}`))
+	}))
+
+	requestBody := `{"brand":"Engage","model":"Pursuit MX 6.0"}`
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/api/paddles", bytes.NewBufferString(requestBody)))
+
+	if received != requestBody {
+		t.Errorf("Handler read %q, want %q", received, requestBody)
+	}
+	if rr.Body.String() != `{"status":"created"}` {
+		t.Errorf("Client received %q, want the full response", rr.Body.String())
+	}
+
+	logged := buf.String()
+	if !strings.Contains(logged, `request body: {"brand":"Engage... (27 bytes truncated)`) {
+		t.Errorf("Expected truncated request body in log, got %q", logged)
+	}
+	if !strings.Contains(logged, `response 201 body: {"status":"creat... (4 bytes truncated)`) {
+		t.Errorf("Expected response status and body in log, got %q", logged)
+	}
+}
+
+// TestLogRequestBodiesDisabled tests that nothing is logged by default
+func TestLogRequestBodiesDisabled(t *testing.T) {
+	buf := captureLog(t)
+
+	handler := logRequestBodies(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/api/paddles", bytes.NewBufferString("{}")))
+
+	if buf.Len() != 0 {
+		t.Errorf("Expected no log output with body logging disabled, got %q", buf.String())
+	}
+}
+
+// TestLimitRequests tests that requests with too many query parameters or
+// oversized headers are rejected before reaching the handler
+func TestLimitRequests(t *testing.T) {
+	maxQueryParams, maxHeaderBytes = 3, 64
+	defer func() { maxQueryParams, maxHeaderBytes = defaultMaxQueryParams, defaultMaxHeaderBytes }()
+
+	reached := false
+	handler := limitRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name           string
+		target         string
+		header         string
+		expectedStatus int
+	}{
+		{"Within limits", "/api/paddles?brand=Engage&min_power=5&sort=power", "", http.StatusOK},
+		{"Empty parameters ignored", "/api/paddles?brand=Engage&&&sort=power", "", http.StatusOK},
+		{"Too many parameters", "/api/paddles?brand=a&brand=b&brand=c&brand=d", "", http.StatusBadRequest},
+		{"Headers too large", "/api/paddles", strings.Repeat("x", 65), http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reached = false
+			req := httptest.NewRequest("GET", tt.target, nil)
+			if tt.header != "" {
+				req.Header.Set("X-Padding", tt.header)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+			if reached != (tt.expectedStatus == http.StatusOK) {
+				t.Errorf("Handler reached = %v, want %v", reached, !reached)
+			}
+		})
+	}
+}
+
+// TestLoadRequestLimitConfig tests parsing of the request limit settings
+func TestLoadRequestLimitConfig(t *testing.T) {
+	t.Setenv("MAX_QUERY_PARAMS", "20")
+	t.Setenv("MAX_HEADER_BYTES", "4096")
+	cfg, err := loadRequestLimitConfig()
+	if err != nil {
+		t.Fatalf("loadRequestLimitConfig() error = %v", err)
+	}
+	if cfg.MaxQueryParams != 20 || cfg.MaxHeaderBytes != 4096 {
+		t.Errorf("Limits = %d, %d, want 20, 4096", cfg.MaxQueryParams, cfg.MaxHeaderBytes)
+	}
+
+	t.Setenv("MAX_QUERY_PARAMS", "0")
+	if _, err := loadRequestLimitConfig(); err == nil {
+		t.Error("Expected an error for MAX_QUERY_PARAMS=0")
+	}
+}
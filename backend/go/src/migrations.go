@@ -0,0 +1,397 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// migration is a versioned schema change applied once by runMigrations. Data
+// changes that need Go, such as normalizing IDs, set Func instead of SQL.
+type migration struct {
+	Version int
+	Name    string
+	SQL     string
+	Func    func(tx *sql.Tx) error
+}
+
+// migrations are applied in order. Never edit or reorder an applied
+// migration; append a new one instead.
+var migrations = []migration{
+	{
+		Version: 1,
+		Name:    "add_common_query_indexes",
+		SQL: `
+			-- Brand filtering and brand listings
+			CREATE INDEX IF NOT EXISTS idx_paddles_brand ON paddles (brand);
+			-- Shape filtering
+			CREATE INDEX IF NOT EXISTS idx_paddle_specs_shape ON paddle_specs (shape);
+			-- Sorting and range queries on the most used performance metrics
+			CREATE INDEX IF NOT EXISTS idx_paddle_performance_power ON paddle_performance (power);
+			CREATE INDEX IF NOT EXISTS idx_paddle_performance_spin ON paddle_performance (spin);
+			CREATE INDEX IF NOT EXISTS idx_paddle_performance_swing_weight ON paddle_performance (swing_weight);
+		`,
+	},
+	{
+		Version: 2,
+		Name:    "add_paddle_year",
+		SQL: `
+			ALTER TABLE paddles ADD COLUMN IF NOT EXISTS year INTEGER;
+		`,
+	},
+	{
+		Version: 3,
+		Name:    "add_paddle_updated_at",
+		SQL: `
+			ALTER TABLE paddles ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP;
+			UPDATE paddles SET updated_at = created_at;
+		`,
+	},
+	{
+		Version: 4,
+		Name:    "add_paddle_sku_and_product_url",
+		SQL: `
+			ALTER TABLE paddles ADD COLUMN IF NOT EXISTS sku VARCHAR(64);
+			ALTER TABLE paddles ADD COLUMN IF NOT EXISTS product_url TEXT;
+			CREATE INDEX IF NOT EXISTS idx_paddles_sku ON paddles (sku);
+		`,
+	},
+	{
+		Version: 5,
+		Name:    "add_paddle_history",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS paddle_history (
+				id SERIAL PRIMARY KEY,
+				paddle_id INTEGER NOT NULL REFERENCES paddles(id) ON DELETE CASCADE,
+				version INTEGER NOT NULL,
+				snapshot JSONB NOT NULL,
+				recorded_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+				UNIQUE (paddle_id, version)
+			);
+		`,
+	},
+	{
+		Version: 6,
+		Name:    "add_paddle_spec_ranges",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS paddle_spec_ranges (
+				id SERIAL PRIMARY KEY,
+				paddle_id INTEGER NOT NULL REFERENCES paddles(id) ON DELETE CASCADE,
+				field VARCHAR(50) NOT NULL,
+				min_value FLOAT NOT NULL,
+				max_value FLOAT NOT NULL,
+				UNIQUE (paddle_id, field)
+			);
+		`,
+	},
+	{
+		Version: 7,
+		Name:    "add_outbox",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS outbox (
+				id SERIAL PRIMARY KEY,
+				event_type VARCHAR(50) NOT NULL,
+				payload JSONB NOT NULL,
+				created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+				published_at TIMESTAMPTZ
+			);
+			CREATE INDEX IF NOT EXISTS idx_outbox_unpublished ON outbox (id) WHERE published_at IS NULL;
+		`,
+	},
+	{
+		Version: 8,
+		Name:    "add_webhooks",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS webhooks (
+				id SERIAL PRIMARY KEY,
+				url TEXT NOT NULL,
+				secret VARCHAR(128) NOT NULL,
+				created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+			);
+		`,
+	},
+	{
+		Version: 9,
+		Name:    "add_performance_stddev",
+		SQL: `
+			ALTER TABLE paddle_performance
+				ADD COLUMN IF NOT EXISTS power_stddev FLOAT,
+				ADD COLUMN IF NOT EXISTS pop_stddev FLOAT,
+				ADD COLUMN IF NOT EXISTS spin_stddev FLOAT,
+				ADD COLUMN IF NOT EXISTS twist_weight_stddev FLOAT,
+				ADD COLUMN IF NOT EXISTS swing_weight_stddev FLOAT,
+				ADD COLUMN IF NOT EXISTS balance_point_stddev FLOAT;
+		`,
+	},
+	{
+		Version: 10,
+		Name:    "add_paddle_tags",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS paddle_tags (
+				id SERIAL PRIMARY KEY,
+				paddle_id INTEGER NOT NULL REFERENCES paddles(id) ON DELETE CASCADE,
+				tag VARCHAR(50) NOT NULL,
+				UNIQUE (paddle_id, tag)
+			);
+			CREATE INDEX IF NOT EXISTS idx_paddle_tags_tag ON paddle_tags (tag);
+		`,
+	},
+	{
+		Version: 11,
+		Name:    "add_paddle_price",
+		SQL: `
+			ALTER TABLE paddles ADD COLUMN IF NOT EXISTS price FLOAT;
+		`,
+	},
+	{
+		Version: 12,
+		Name:    "add_paddle_edge_guard_and_handle_type",
+		SQL: `
+			ALTER TABLE paddle_specs
+				ADD COLUMN IF NOT EXISTS edge_guard VARCHAR(50),
+				ADD COLUMN IF NOT EXISTS handle_type VARCHAR(50);
+		`,
+	},
+	{
+		Version: 13,
+		Name:    "add_paddle_status",
+		SQL: `
+			ALTER TABLE paddles ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'published';
+		`,
+	},
+	{
+		Version: 14,
+		Name:    "add_paddle_surface_sides",
+		SQL: `
+			ALTER TABLE paddle_specs
+				ADD COLUMN IF NOT EXISTS surface_front VARCHAR(50),
+				ADD COLUMN IF NOT EXISTS surface_back VARCHAR(50);
+			-- The sides only take the surface enum, so backfill them from
+			-- surfaces that match it, in its spelling, and leave the rest NULL.
+			UPDATE paddle_specs s SET surface_front = k.surface, surface_back = k.surface
+			FROM (VALUES ('Carbon Fiber'), ('Raw Carbon'), ('Fiberglass'), ('Graphite'), ('Kevlar'), ('Composite')) AS k(surface)
+			WHERE s.surface_front IS NULL AND LOWER(s.surface) = LOWER(k.surface);
+		`,
+	},
+	{
+		Version: 15,
+		Name:    "add_featured_paddle",
+		SQL: `
+			-- A single row holding the paddle of the week
+			CREATE TABLE IF NOT EXISTS featured (
+				slot INTEGER PRIMARY KEY DEFAULT 1 CHECK (slot = 1),
+				paddle_id INTEGER NOT NULL REFERENCES paddles(id) ON DELETE CASCADE,
+				valid_until TIMESTAMPTZ NOT NULL,
+				manual BOOLEAN NOT NULL DEFAULT FALSE,
+				selected_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+			);
+		`,
+	},
+	{
+		Version: 16,
+		Name:    "add_paddle_raw_uploads",
+		SQL: `
+			-- The exact bytes of each upload, so it is not reformatted and
+			-- bodies that are not valid UTF-8, which the JSON decoder
+			-- accepts, are kept too
+			CREATE TABLE IF NOT EXISTS paddle_raw_uploads (
+				id SERIAL PRIMARY KEY,
+				paddle_id INTEGER NOT NULL REFERENCES paddles(id) ON DELETE CASCADE,
+				body BYTEA NOT NULL,
+				uploaded_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+			);
+			CREATE INDEX IF NOT EXISTS idx_paddle_raw_uploads_paddle_id ON paddle_raw_uploads (paddle_id);
+		`,
+	},
+	{
+		Version: 17,
+		Name:    "normalize_paddle_ids",
+		Func:    normalizeStoredPaddleIDs,
+	},
+	{
+		Version: 18,
+		Name:    "add_paddle_tombstones",
+		SQL: `
+			-- Paddle IDs that no longer exist, for the changes feed. A bulk
+			-- update that renames a paddle leaves its old ID here.
+			CREATE TABLE IF NOT EXISTS paddle_tombstones (
+				paddle_id VARCHAR(100) PRIMARY KEY,
+				replaced_by VARCHAR(100),
+				deleted_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+			CREATE INDEX IF NOT EXISTS idx_paddle_tombstones_deleted_at ON paddle_tombstones (deleted_at, paddle_id);
+		`,
+	},
+	{
+		Version: 19,
+		Name:    "add_webhook_deliveries",
+		SQL: `
+			-- A poller claims outbox events until claimed_until, so it can
+			-- publish them outside a transaction without another taking them
+			ALTER TABLE outbox ADD COLUMN IF NOT EXISTS claimed_until TIMESTAMPTZ;
+			-- Published events are pruned once they are old enough
+			CREATE INDEX IF NOT EXISTS idx_outbox_published_at ON outbox (published_at) WHERE published_at IS NOT NULL;
+			-- Each event owed to each webhook, tried on its own schedule so a
+			-- failing webhook holds up only its own deliveries
+			CREATE TABLE IF NOT EXISTS webhook_deliveries (
+				event_id INTEGER NOT NULL REFERENCES outbox(id) ON DELETE CASCADE,
+				webhook_id INTEGER NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+				tries INTEGER NOT NULL DEFAULT 0,
+				next_try_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				last_error TEXT,
+				delivered_at TIMESTAMPTZ,
+				dead_at TIMESTAMPTZ,
+				PRIMARY KEY (event_id, webhook_id)
+			);
+			CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_try_at)
+				WHERE delivered_at IS NULL AND dead_at IS NULL;
+		`,
+	},
+}
+
+// normalizeStoredPaddleIDs rewrites every stored paddle ID that lookups can
+// no longer reach, such as one with accents, capitals or characters IDs may
+// not contain, into its canonical form. IDs already in that form are kept.
+// When a canonical ID is taken, the older paddles get it first and the rest
+// get a numeric suffix, such as engage-pursuit-2.
+func normalizeStoredPaddleIDs(tx *sql.Tx) error {
+	rows, err := tx.Query("SELECT id, paddle_id FROM paddles ORDER BY id")
+	if err != nil {
+		return err
+	}
+
+	type storedID struct {
+		dbID int
+		id   string
+	}
+	var stale []storedID
+	taken := map[string]bool{}
+	for rows.Next() {
+		var row storedID
+		if err := rows.Scan(&row.dbID, &row.id); err != nil {
+			rows.Close()
+			return err
+		}
+		if canonicalPaddleID(row.id) == row.id {
+			taken[row.id] = true
+		} else {
+			stale = append(stale, row)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, row := range stale {
+		base := canonicalPaddleID(row.id)
+		if base == "" {
+			base = fmt.Sprintf("paddle-%d", row.dbID)
+		}
+		newID := base
+		for n := 2; taken[newID]; n++ {
+			newID = fmt.Sprintf("%s-%d", base, n)
+		}
+		taken[newID] = true
+
+		if _, err := tx.Exec("UPDATE paddles SET paddle_id = $1 WHERE id = $2", newID, row.dbID); err != nil {
+			return err
+		}
+		log.Printf("Renamed paddle %q to %q", row.id, newID)
+	}
+	return nil
+}
+
+// runMigrations creates the schema_migrations table and applies any
+// migrations that have not been applied yet, each in its own transaction
+func runMigrations() error {
+	_, err := DB.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name VARCHAR(100) NOT NULL,
+			applied_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	for _, m := range migrations {
+		if err := applyMigration(m); err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// applyMigration applies a single migration unless it is already recorded
+func applyMigration(m migration) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var applied bool
+	err = tx.QueryRow("SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)", m.Version).Scan(&applied)
+	if err != nil {
+		return err
+	}
+	if applied {
+		return nil
+	}
+
+	if m.Func != nil {
+		err = m.Func(tx)
+	} else {
+		_, err = tx.Exec(m.SQL)
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", m.Version, m.Name); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	log.Printf("Applied migration %d (%s)", m.Version, m.Name)
+	return nil
+}
+
+// latestMigrationVersion is the schema version this build expects once all migrations have run
+func latestMigrationVersion() int {
+	if len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+// GetSchemaVersion returns the highest applied migration version. ok is false
+// when the database is unavailable or schema_migrations does not exist yet.
+func GetSchemaVersion() (version int, ok bool, err error) {
+	if DB == nil {
+		return 0, false, nil
+	}
+
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	var exists bool
+	err = timedQueryRow(ctx, DB, "schema_migrations_exists",
+		"SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists)
+	if err != nil || !exists {
+		return 0, false, err
+	}
+
+	err = timedQueryRow(ctx, DB, "get_schema_version",
+		"SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version)
+	if err != nil {
+		return 0, false, err
+	}
+	return version, true, nil
+}
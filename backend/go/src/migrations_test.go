@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// TestMigrationsOrdered tests that migration versions are unique and ascending
+func TestMigrationsOrdered(t *testing.T) {
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			t.Errorf("migration %q has version %d, want %d", m.Name, m.Version, i+1)
+		}
+		if m.Name == "" || (m.SQL == "") == (m.Func == nil) {
+			t.Errorf("migration %d needs a name and exactly one of SQL or Func", m.Version)
+		}
+	}
+}
+
+// TestRunMigrationsIdempotent tests that running migrations twice is a no-op
+func TestRunMigrationsIdempotent(t *testing.T) {
+	setupTestDB(t)
+
+	if err := runMigrations(); err != nil {
+		t.Fatalf("Second runMigrations() failed: %v", err)
+	}
+
+	var count int
+	if err := DB.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&count); err != nil {
+		t.Fatalf("Failed to count migrations: %v", err)
+	}
+	if count != len(migrations) {
+		t.Errorf("schema_migrations has %d rows, want %d", count, len(migrations))
+	}
+}
+
+// TestTimestampsHaveTimeZone tests that every timestamp column stores an
+// instant, so values do not depend on the server's time zone
+func TestTimestampsHaveTimeZone(t *testing.T) {
+	setupTestDB(t)
+
+	rows, err := DB.Query(`
+		SELECT table_name, column_name FROM information_schema.columns
+		WHERE table_schema = current_schema() AND data_type = 'timestamp without time zone'
+	`)
+	if err != nil {
+		t.Fatalf("Failed to query columns: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var table, column string
+		if err := rows.Scan(&table, &column); err != nil {
+			t.Fatalf("Failed to scan column: %v", err)
+		}
+		t.Errorf("%s.%s is a TIMESTAMP without time zone", table, column)
+	}
+}
+
+// TestInitSchemaConcurrent simulates several instances starting at once: each
+// initializes the schema concurrently and all must succeed
+func TestInitSchemaConcurrent(t *testing.T) {
+	setupTestDB(t)
+
+	const instances = 4
+	errs := make([]error, instances)
+	var wg sync.WaitGroup
+	for i := range instances {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = initSchema()
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("initSchema() in instance %d failed: %v", i, err)
+		}
+	}
+
+	var count int
+	if err := DB.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&count); err != nil {
+		t.Fatalf("Failed to count migrations: %v", err)
+	}
+	if count != len(migrations) {
+		t.Errorf("schema_migrations has %d rows, want %d", count, len(migrations))
+	}
+}
+
+// BenchmarkIndexedQueries compares the common filter and sort queries with and
+// without the migration indexes on a large seeded dataset. Everything runs in
+// a transaction that is rolled back, leaving the database untouched.
+func BenchmarkIndexedQueries(b *testing.B) {
+	cfg, err := loadDBConfig()
+	if err != nil {
+		b.Fatalf("Invalid database configuration: %v", err)
+	}
+	if err := InitDB(cfg); err != nil {
+		b.Skipf("Skipping benchmark, database unavailable: %v", err)
+	}
+	defer CloseDB()
+
+	tx, err := DB.Begin()
+	if err != nil {
+		b.Fatalf("Failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	// Seed 50k paddles across 50 brands
+	_, err = tx.Exec(`
+		WITH p AS (
+			INSERT INTO paddles (paddle_id, brand, model)
+			SELECT 'bench-' || g, 'Brand ' || (g % 50), 'Model ' || g
+			FROM generate_series(1, 50000) g
+			RETURNING id
+		), s AS (
+			INSERT INTO paddle_specs (
+				paddle_id, shape, surface, average_weight, core, paddle_length,
+				paddle_width, grip_length, grip_type, grip_circumference
+			)
+			SELECT id, (ARRAY['Elongated', 'Hybrid', 'Wide-body'])[id % 3 + 1], 'Carbon', 220, 16, 16.5, 7.5, 5.25, 'Standard', 4.25
+			FROM p
+			RETURNING id
+		)
+		INSERT INTO paddle_performance (paddle_spec_id, power, pop, spin, twist_weight, swing_weight, balance_point)
+		SELECT id, random() * 100, random() * 100, random() * 3000, 6, 115, 23 FROM s
+	`)
+	if err != nil {
+		b.Fatalf("Failed to seed benchmark data: %v", err)
+	}
+	if _, err := tx.Exec("ANALYZE paddles; ANALYZE paddle_specs; ANALYZE paddle_performance"); err != nil {
+		b.Fatalf("Failed to analyze tables: %v", err)
+	}
+
+	queries := map[string]string{
+		"brand": "SELECT COUNT(*) FROM paddles WHERE brand = 'Brand 7'",
+		"shape": "SELECT COUNT(*) FROM paddle_specs WHERE shape = 'Hybrid'",
+		"power": "SELECT id FROM paddle_performance ORDER BY power DESC LIMIT 20",
+		"spin":  "SELECT id FROM paddle_performance ORDER BY spin DESC LIMIT 20",
+	}
+
+	run := func(b *testing.B) {
+		for name, query := range queries {
+			b.Run(name, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					var discard int
+					rows, err := tx.Query(query)
+					if err != nil {
+						b.Fatalf("Query failed: %v", err)
+					}
+					for rows.Next() {
+						rows.Scan(&discard)
+					}
+					rows.Close()
+				}
+			})
+		}
+	}
+
+	b.Run("indexed", run)
+
+	for _, index := range []string{
+		"idx_paddles_brand", "idx_paddle_specs_shape", "idx_paddle_performance_power",
+		"idx_paddle_performance_spin", "idx_paddle_performance_swing_weight",
+	} {
+		if _, err := tx.Exec(fmt.Sprintf("DROP INDEX IF EXISTS %s", index)); err != nil {
+			b.Fatalf("Failed to drop index %s: %v", index, err)
+		}
+	}
+
+	b.Run("unindexed", run)
+}
+
+// TestNormalizeStoredPaddleIDs tests that stored IDs lookups cannot reach are
+// rewritten in canonical form, with older paddles winning collisions
+func TestNormalizeStoredPaddleIDs(t *testing.T) {
+	setupTestDB(t)
+
+	tx, err := DB.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	stored := []string{"Engagé-Migration-Pursuit", "engage-migration-pursuit", "joola-migration-4 1/8", "JOOLA-Migration-4-1-8"}
+	dbIDs := make([]int, len(stored))
+	for i, id := range stored {
+		err := tx.QueryRow("INSERT INTO paddles (paddle_id, brand, model) VALUES ($1, 'Test', 'Test') RETURNING id", id).Scan(&dbIDs[i])
+		if err != nil {
+			t.Fatalf("Failed to insert %q: %v", id, err)
+		}
+	}
+
+	if err := normalizeStoredPaddleIDs(tx); err != nil {
+		t.Fatalf("normalizeStoredPaddleIDs() error: %v", err)
+	}
+
+	// The second ID is already canonical and keeps its ID
+	want := []string{"engage-migration-pursuit-2", "engage-migration-pursuit", "joola-migration-4-1-8", "joola-migration-4-1-8-2"}
+	for i, dbID := range dbIDs {
+		var got string
+		if err := tx.QueryRow("SELECT paddle_id FROM paddles WHERE id = $1", dbID).Scan(&got); err != nil {
+			t.Fatalf("Failed to read paddle %d: %v", dbID, err)
+		}
+		if got != want[i] {
+			t.Errorf("%q normalized to %q, want %q", stored[i], got, want[i])
+		}
+	}
+}
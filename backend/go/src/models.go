@@ -2,13 +2,25 @@ package main
 
 import (
 	"fmt"
+	"math"
 	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 // PaddleIdentifier represents the identifying information of a paddle
 type Metadata struct {
 	Brand string `json:"brand"`
 	Model string `json:"model"`
+	// Year is the release year, when known
+	Year *int `json:"year,omitempty"`
+	// SKU is the manufacturer's product code, used by retailers to map their catalog
+	SKU string `json:"sku,omitempty"`
+	// ProductURL links to the manufacturer's product page
+	ProductURL string `json:"product_url,omitempty"`
+	// Price is the retail price in USD, when known
+	Price *float64 `json:"price,omitempty"`
 }
 
 // PaddleShape represents the shape of a paddle
@@ -20,17 +32,58 @@ const (
 	WideBody  PaddleShape = "Wide-body"
 )
 
+// paddleShapes lists every valid PaddleShape, shared by validation and the schema endpoint
+var paddleShapes = []PaddleShape{Elongated, Hybrid, WideBody}
+
+// PaddleStatus is the publication state of a paddle. Drafts are left out of
+// public listings until they are published.
+type PaddleStatus string
+
+const (
+	StatusDraft     PaddleStatus = "draft"
+	StatusPublished PaddleStatus = "published"
+)
+
+// paddleSurfaces lists the face materials paddles can be filtered by
+var paddleSurfaces = []string{"Carbon Fiber", "Raw Carbon", "Fiberglass", "Graphite", "Kevlar", "Composite"}
+
+// paddleEdgeGuards lists the valid edge guard types. Edgeless paddles have no guard.
+var paddleEdgeGuards = []string{"Standard", "Low-profile", "Edgeless"}
+
+// paddleHandleTypes lists the valid handle constructions
+var paddleHandleTypes = []string{"Hollow", "Foam-filled", "Unibody"}
+
+// normalizeSurface returns the canonical spelling of a known surface, matched case-insensitively
+func normalizeSurface(raw string) (string, bool) {
+	for _, surface := range paddleSurfaces {
+		if strings.EqualFold(raw, surface) {
+			return surface, true
+		}
+	}
+	return "", false
+}
+
 // Specs represents the specifications of a paddle
 type Specs struct {
 	Shape             PaddleShape `json:"shape"`
 	Surface           string      `json:"surface"`
 	AverageWeight     float64     `json:"average_weight"`
-	Core              float64     `json:"core"`
+	Core              float64     `json:"core"` // core thickness, stored in millimetres
+	CoreUnit          string      `json:"core_unit,omitempty"`
 	PaddleLength      float64     `json:"paddle_length"`
 	PaddleWidth       float64     `json:"paddle_width"`
 	GripLength        float64     `json:"grip_length"`
 	GripType          string      `json:"grip_type"`
 	GripCircumference float64     `json:"grip_circumference"`
+	// EdgeGuard and HandleType are optional construction details, one of
+	// paddleEdgeGuards and paddleHandleTypes when given
+	EdgeGuard  string `json:"edge_guard,omitempty"`
+	HandleType string `json:"handle_type,omitempty"`
+	// SurfaceFront and SurfaceBack describe paddles with a different face on
+	// each side, each one of paddleSurfaces. Surface stays the headline
+	// material. Single-surface paddles get Surface on both sides.
+	SurfaceFront string `json:"surface_front,omitempty"`
+	SurfaceBack  string `json:"surface_back,omitempty"`
 }
 
 // Performance represents the performance metrics of a paddle
@@ -41,6 +94,47 @@ type Performance struct {
 	TwistWeight  float64 `json:"twist_weight"`
 	SwingWeight  float64 `json:"swing_weight"`
 	BalancePoint float64 `json:"balance_point"`
+
+	// Optional standard deviations of the metrics above, for showing
+	// measurement uncertainty. Nil when not measured.
+	PowerStddev        *float64 `json:"power_stddev,omitempty"`
+	PopStddev          *float64 `json:"pop_stddev,omitempty"`
+	SpinStddev         *float64 `json:"spin_stddev,omitempty"`
+	TwistWeightStddev  *float64 `json:"twist_weight_stddev,omitempty"`
+	SwingWeightStddev  *float64 `json:"swing_weight_stddev,omitempty"`
+	BalancePointStddev *float64 `json:"balance_point_stddev,omitempty"`
+}
+
+// stddevs returns the stddev fields keyed by the JSON name of their metric
+func (p *Performance) stddevs() map[string]**float64 {
+	return map[string]**float64{
+		"power":         &p.PowerStddev,
+		"pop":           &p.PopStddev,
+		"spin":          &p.SpinStddev,
+		"twist_weight":  &p.TwistWeightStddev,
+		"swing_weight":  &p.SwingWeightStddev,
+		"balance_point": &p.BalancePointStddev,
+	}
+}
+
+// performanceColumns are the paddle_performance columns matching performanceScanDest
+const performanceColumns = `perf.power, perf.pop, perf.spin, perf.twist_weight, perf.swing_weight, perf.balance_point,
+			perf.power_stddev, perf.pop_stddev, perf.spin_stddev, perf.twist_weight_stddev,
+			perf.swing_weight_stddev, perf.balance_point_stddev`
+
+// performanceScanDest returns scan destinations matching performanceColumns
+func performanceScanDest(p *Performance) []interface{} {
+	return []interface{}{
+		&p.Power, &p.Pop, &p.Spin, &p.TwistWeight, &p.SwingWeight, &p.BalancePoint,
+		&p.PowerStddev, &p.PopStddev, &p.SpinStddev, &p.TwistWeightStddev,
+		&p.SwingWeightStddev, &p.BalancePointStddev,
+	}
+}
+
+// AggregatedPerformance is the mean of all performance measurements of a paddle
+type AggregatedPerformance struct {
+	Performance
+	SampleCount int `json:"sample_count"`
 }
 
 // PaddleInput represents the input data for creating a paddle
@@ -48,6 +142,8 @@ type PaddleInput struct {
 	Metadata    Metadata    `json:"metadata"`
 	Specs       Specs       `json:"specs"`
 	Performance Performance `json:"performance"`
+	// SpecRanges are optional manufacturer-quoted ranges, keyed by spec field
+	SpecRanges map[string]SpecRange `json:"spec_ranges,omitempty"`
 }
 
 // Paddle represents a paddle with its specs and performance
@@ -56,35 +152,182 @@ type Paddle struct {
 	Metadata    Metadata    `json:"metadata"`
 	Specs       Specs       `json:"specs"`
 	Performance Performance `json:"performance"`
+	// DBID is the database primary key, exposed only through PaddleResponse
+	DBID int `json:"-"`
+	// SpecRanges are manufacturer-quoted ranges alongside the measured specs
+	SpecRanges map[string]SpecRange `json:"spec_ranges,omitempty"`
+	// Tags are curator-assigned labels such as "beginner-friendly"
+	Tags []string `json:"tags,omitempty"`
+	// PerformanceSamples is the number of measurements averaged into Performance,
+	// set only when the performance is aggregated
+	PerformanceSamples int `json:"performance_samples,omitempty"`
+	// Status is draft while a curator stages the paddle, published once it is public
+	Status PaddleStatus `json:"status,omitempty"`
+	// Control is derived from power and pop, see ControlRating
+	Control   float64 `json:"control"`
+	CreatedAt Time    `json:"created_at,omitzero"`
+	UpdatedAt Time    `json:"updated_at,omitzero"`
+	// UploadBody is the exact JSON the paddle was uploaded with, stored with
+	// it for auditing when set
+	UploadBody []byte `json:"-"`
 }
 
-// ToPaddle converts a PaddleInput to a Paddle by generating an ID
+// ToPaddle converts a PaddleInput to a Paddle, with an ID from the configured idGenerator
 func (input *PaddleInput) ToPaddle() *Paddle {
 	paddle := &Paddle{
 		Metadata:    input.Metadata,
 		Specs:       input.Specs,
 		Performance: input.Performance,
+		SpecRanges:  input.SpecRanges,
+		Status:      StatusPublished,
+	}
+
+	// Drop float noise such as 220.00000001 before the values are stored
+	roundSpecs(&paddle.Specs)
+	roundPerformance(&paddle.Performance)
+
+	// A single surface covers both sides, but the sides are held to the
+	// surface enum, so a free-text surface leaves them unset
+	if paddle.Specs.SurfaceFront == "" && paddle.Specs.SurfaceBack == "" {
+		if surface, ok := normalizeSurface(paddle.Specs.Surface); ok {
+			paddle.Specs.SurfaceFront = surface
+			paddle.Specs.SurfaceBack = surface
+		}
 	}
 
-	// Generate ID based on metadata
-	paddle.ID = generatePaddleID(paddle.Metadata.Brand, paddle.Metadata.Model)
+	paddle.ID = idGenerator.GenerateID(paddle.Metadata)
+	paddle.Control = paddle.ControlRating()
 	return paddle
 }
 
-// generatePaddleID creates a paddle ID from brand and model
+// DisplayName is the label every client shows for a paddle: the brand and
+// model, followed by the year in parentheses when it is known, such as
+// "Engage Pursuit MX 6.0 (2023)". Runs of whitespace collapse to one space.
+func (p *Paddle) DisplayName() string {
+	name := strings.Join(strings.Fields(p.Metadata.Brand+" "+p.Metadata.Model), " ")
+	if p.Metadata.Year != nil {
+		name += fmt.Sprintf(" (%d)", *p.Metadata.Year)
+	}
+	return name
+}
+
+// Weights used by ControlRating. Power dominates because it reflects the
+// whole swing; pop only measures the initial response off the face.
+const (
+	controlPowerWeight = 0.6
+	controlPopWeight   = 0.4
+)
+
+// controlColumn computes ControlRating in SQL from the mean power and pop of
+// the measurements of specs s, for queries that do not load the measurements
+var controlColumn = fmt.Sprintf(
+	"(SELECT GREATEST(0, LEAST(100, 100 - (%v * AVG(perf.power) + %v * AVG(perf.pop)))) FROM paddle_performance perf WHERE perf.paddle_spec_id = s.id)",
+	controlPowerWeight, controlPopWeight)
+
+// ControlRating derives a 0-100 control score from power and pop:
+//
+//	control = 100 - (0.6 * power + 0.4 * pop)
+//
+// clamped to the 0-100 range. Low-power, low-pop paddles score high.
+func (p *Paddle) ControlRating() float64 {
+	control := 100 - (controlPowerWeight*p.Performance.Power + controlPopWeight*p.Performance.Pop)
+	return math.Max(0, math.Min(100, control))
+}
+
+// averagePerformance returns the mean of a set of performance measurements.
+// A single measurement keeps its stddevs. Several are pooled per metric, see
+// pooledStddev.
+func averagePerformance(measurements []Performance) AggregatedPerformance {
+	var agg AggregatedPerformance
+	if len(measurements) == 0 {
+		return agg
+	}
+	if len(measurements) == 1 {
+		agg.Performance = measurements[0]
+		agg.SampleCount = 1
+		return agg
+	}
+
+	for _, m := range measurements {
+		agg.Power += m.Power
+		agg.Pop += m.Pop
+		agg.Spin += m.Spin
+		agg.TwistWeight += m.TwistWeight
+		agg.SwingWeight += m.SwingWeight
+		agg.BalancePoint += m.BalancePoint
+	}
+
+	n := float64(len(measurements))
+	agg.Power /= n
+	agg.Pop /= n
+	agg.Spin /= n
+	agg.TwistWeight /= n
+	agg.SwingWeight /= n
+	agg.BalancePoint /= n
+	agg.SampleCount = len(measurements)
+
+	for metric, stddev := range agg.stddevs() {
+		*stddev = pooledStddev(measurements, metric)
+	}
+
+	return agg
+}
+
+// pooledStddev combines the stddevs the measurements give for metric as the
+// root mean square, the pooled stddev of equally sized samples. Measurements
+// without one are left out, and nil is returned when none has one.
+func pooledStddev(measurements []Performance, metric string) *float64 {
+	var sum float64
+	var n int
+	for _, m := range measurements {
+		if stddev := *m.stddevs()[metric]; stddev != nil {
+			sum += *stddev * *stddev
+			n++
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	pooled := math.Sqrt(sum / float64(n))
+	return &pooled
+}
+
+// generatePaddleID creates a paddle ID from brand and model. Characters that
+// are not allowed in an ID, such as the slash in "4 1/8", become hyphens, and
+// runs of hyphens collapse, so the result has the form validatePaddleID
+// expects. Its length depends on the input, which validateMetadata checks.
 func generatePaddleID(brand, model string) string {
-	// Format: BRAND-MODEL
-	paddleID := fmt.Sprintf("%s-%s",
-		formatIDComponent(brand),
-		formatIDComponent(model),
-	)
-	return paddleID
-}
-
-// formatIDComponent formats a string to be used in a paddle ID
-// by converting to uppercase and replacing spaces with hyphens
-func formatIDComponent(s string) string {
-	s = strings.ToLower(s)
-	s = strings.ReplaceAll(s, " ", "-")
-	return s
+	// Format: brand-model
+	return canonicalPaddleID(fmt.Sprintf("%s-%s", brand, model))
+}
+
+// canonicalPaddleID normalizes id and turns each run of characters that may
+// not appear in an ID into a single hyphen
+func canonicalPaddleID(id string) string {
+	id = NormalizePaddleID(id)
+	return strings.Join(strings.FieldsFunc(id, func(r rune) bool { return !isPaddleIDRune(r) }), "-")
+}
+
+// isPaddleIDRune reports whether r may appear in a segment of a paddle ID
+func isPaddleIDRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '+'
+}
+
+// NormalizePaddleID puts a paddle ID into its canonical form: accents are
+// stripped, letters lowercased, surrounding whitespace trimmed and inner
+// spaces replaced with hyphens. IDs are
+// generated in this form, and lookups normalize the requested ID the same way
+// so "ENGAGE-Pursuit-MX" and "engagé-pursuit-mx" find "engage-pursuit-mx".
+func NormalizePaddleID(id string) string {
+	// Decompose accented letters, then drop the combining marks
+	var b strings.Builder
+	for _, r := range norm.NFD.String(id) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	id = strings.TrimSpace(strings.ToLower(norm.NFC.String(b.String())))
+	return strings.ReplaceAll(id, " ", "-")
 }
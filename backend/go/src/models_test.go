@@ -0,0 +1,166 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// TestAveragePerformance tests averaging two measurement rows
+func TestAveragePerformance(t *testing.T) {
+	measurements := []Performance{
+		{Power: 70, Pop: 60, Spin: 2800, TwistWeight: 6.0, SwingWeight: 110, BalancePoint: 23.0},
+		{Power: 80, Pop: 70, Spin: 3000, TwistWeight: 7.0, SwingWeight: 120, BalancePoint: 24.0},
+	}
+
+	agg := averagePerformance(measurements)
+
+	want := Performance{Power: 75, Pop: 65, Spin: 2900, TwistWeight: 6.5, SwingWeight: 115, BalancePoint: 23.5}
+	if agg.Performance != want {
+		t.Errorf("averagePerformance() = %+v, want %+v", agg.Performance, want)
+	}
+	if agg.SampleCount != 2 {
+		t.Errorf("SampleCount = %d, want 2", agg.SampleCount)
+	}
+}
+
+// TestAveragePerformanceSingle tests that a single measurement is returned unchanged
+func TestAveragePerformanceSingle(t *testing.T) {
+	single := Performance{Power: 75, Pop: 70, Spin: 3000, TwistWeight: 200, SwingWeight: 220, BalancePoint: 30}
+
+	agg := averagePerformance([]Performance{single})
+	if agg.Performance != single || agg.SampleCount != 1 {
+		t.Errorf("averagePerformance() = %+v, want %+v with 1 sample", agg, single)
+	}
+
+	if empty := averagePerformance(nil); empty.SampleCount != 0 {
+		t.Errorf("SampleCount for no measurements = %d, want 0", empty.SampleCount)
+	}
+}
+
+// TestAveragePerformancePoolsStddevs tests that the stddevs of several
+// measurements are pooled, skipping measurements without one
+func TestAveragePerformancePoolsStddevs(t *testing.T) {
+	three, four := 3.0, 4.0
+	measurements := []Performance{
+		{Power: 70, PowerStddev: &three, SpinStddev: &three},
+		{Power: 80, PowerStddev: &four},
+	}
+
+	agg := averagePerformance(measurements)
+
+	// sqrt((9 + 16) / 2)
+	if agg.PowerStddev == nil || math.Abs(*agg.PowerStddev-math.Sqrt(12.5)) > 1e-9 {
+		t.Errorf("PowerStddev = %v, want %v", agg.PowerStddev, math.Sqrt(12.5))
+	}
+	if agg.SpinStddev == nil || *agg.SpinStddev != 3 {
+		t.Errorf("SpinStddev = %v, want 3 from the one measurement that has it", agg.SpinStddev)
+	}
+	if agg.PopStddev != nil {
+		t.Errorf("PopStddev = %v, want nil when no measurement has one", *agg.PopStddev)
+	}
+}
+
+// TestControlRating tests the control formula against fixed inputs
+func TestControlRating(t *testing.T) {
+	tests := []struct {
+		name  string
+		power float64
+		pop   float64
+		want  float64
+	}{
+		{name: "Balanced", power: 50, pop: 50, want: 50},
+		{name: "Power paddle", power: 90, pop: 80, want: 14},
+		{name: "Control paddle", power: 20, pop: 30, want: 76},
+		{name: "Zero power and pop", power: 0, pop: 0, want: 100},
+		{name: "Maximum power and pop", power: 100, pop: 100, want: 0},
+		{name: "Clamped above 100", power: -10, pop: -10, want: 100},
+		{name: "Clamped below 0", power: 150, pop: 150, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Paddle{Performance: Performance{Power: tt.power, Pop: tt.pop}}
+			if got := p.ControlRating(); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("ControlRating() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDisplayName tests the display name with and without a year and with
+// untidy whitespace in the metadata
+func TestDisplayName(t *testing.T) {
+	year := 2023
+	tests := []struct {
+		name  string
+		brand string
+		model string
+		year  *int
+		want  string
+	}{
+		{name: "With year", brand: "Engage", model: "Pursuit MX 6.0", year: &year, want: "Engage Pursuit MX 6.0 (2023)"},
+		{name: "Without year", brand: "Engage", model: "Pursuit MX 6.0", want: "Engage Pursuit MX 6.0"},
+		{name: "Extra whitespace", brand: "  Engage ", model: "Pursuit   MX\t6.0 ", year: &year, want: "Engage Pursuit MX 6.0 (2023)"},
+		{name: "Missing model", brand: "Engage", want: "Engage"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Paddle{Metadata: Metadata{Brand: tt.brand, Model: tt.model, Year: tt.year}}
+			if got := p.DisplayName(); got != tt.want {
+				t.Errorf("DisplayName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNormalizePaddleID tests that IDs are lowercased, unaccented and hyphenated
+func TestNormalizePaddleID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want string
+	}{
+		{name: "Canonical", id: "engage-pursuit-mx-6.0", want: "engage-pursuit-mx-6.0"},
+		{name: "Upper case", id: "ENGAGE-PURSUIT-MX-6.0", want: "engage-pursuit-mx-6.0"},
+		{name: "Mixed case", id: "Engage-Pursuit-MX-6.0", want: "engage-pursuit-mx-6.0"},
+		{name: "Accented", id: "Éngagé-Pursuït-MX-6.0", want: "engage-pursuit-mx-6.0"},
+		{name: "Decomposed accent", id: "Éngage-pursuit", want: "engage-pursuit"},
+		{name: "Spaces", id: "Engage Pursuit MX", want: "engage-pursuit-mx"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizePaddleID(tt.id); got != tt.want {
+				t.Errorf("NormalizePaddleID(%q) = %q, want %q", tt.id, got, tt.want)
+			}
+		})
+	}
+
+	if got := generatePaddleID("Sélkirk", "Vanguard Power Air"); got != "selkirk-vanguard-power-air" {
+		t.Errorf("generatePaddleID() = %q, want selkirk-vanguard-power-air", got)
+	}
+}
+
+// TestGeneratePaddleID tests that generated IDs only contain allowed characters
+func TestGeneratePaddleID(t *testing.T) {
+	tests := []struct {
+		brand, model string
+		want         string
+	}{
+		{brand: "Engage", model: "Pursuit MX 6.0", want: "engage-pursuit-mx-6.0"},
+		{brand: "Vatic Pro", model: "BALLR+", want: "vatic-pro-ballr+"},
+		{brand: "Gamma", model: "Gravity Tour 2025 Paddle 4 1/8", want: "gamma-gravity-tour-2025-paddle-4-1-8"},
+		{brand: "Onix", model: "Z5 (Women's) - Graphite", want: "onix-z5-women-s-graphite"},
+	}
+
+	for _, tt := range tests {
+		got := generatePaddleID(tt.brand, tt.model)
+		if got != tt.want {
+			t.Errorf("generatePaddleID(%q, %q) = %q, want %q", tt.brand, tt.model, got, tt.want)
+		}
+		if err := validatePaddleID(got); err != nil {
+			t.Errorf("generatePaddleID(%q, %q) = %q fails validation: %v", tt.brand, tt.model, got, err)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Outbox event types, written whenever a paddle is saved or changed. Paddles
+// are never deleted, but an ID is when a bulk update renames the paddle: the
+// old ID gets paddle.deleted and the new one paddle.created.
+const (
+	eventPaddleCreated = "paddle.created"
+	eventPaddleUpdated = "paddle.updated"
+	eventPaddleDeleted = "paddle.deleted"
+)
+
+// Outbox polling defaults, overridable via OUTBOX_POLL_MS. A poll may wait on
+// slow publishers, so it gets outboxPollTimeout rather than the query timeout,
+// and its claim on the events outlasts that, in case the server stops before
+// releasing them.
+const (
+	defaultOutboxPollMS = 5000
+	outboxBatchSize     = 100
+	outboxPollTimeout   = 30 * time.Second
+	outboxClaimTimeout  = 2 * outboxPollTimeout
+)
+
+// defaultOutboxRetentionHours is how long published events are kept,
+// overridable via OUTBOX_RETENTION_HOURS
+const defaultOutboxRetentionHours = 7 * 24
+
+// OutboxEvent is a pending event read from the outbox table
+type OutboxEvent struct {
+	ID        int             `json:"id"`
+	Type      string          `json:"type"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// paddleDeletion is the payload of a paddle.deleted event. ReplacedBy is the
+// paddle's new ID when it was renamed.
+type paddleDeletion struct {
+	ID         string `json:"id"`
+	ReplacedBy string `json:"replaced_by,omitempty"`
+}
+
+// Publisher delivers outbox events downstream. Publish must return only once
+// the event has been delivered, because the event is then marked published.
+// Delivery is at least once: an event whose publish fails, or that fails to
+// be marked published, is delivered again, so implementations should be
+// idempotent on OutboxEvent.ID.
+type Publisher interface {
+	Publish(ctx context.Context, event OutboxEvent) error
+}
+
+// publisher is the Publisher the outbox poller delivers to. It discards
+// events until main installs webhooks.
+var publisher Publisher = discardPublisher{}
+
+// discardPublisher accepts every event without sending it anywhere, so with
+// webhooks disabled events are marked published and pruned rather than
+// piling up in the outbox
+type discardPublisher struct{}
+
+func (discardPublisher) Publish(ctx context.Context, event OutboxEvent) error {
+	return nil
+}
+
+// loadOutboxPollInterval reads the outbox poll interval from the environment
+func loadOutboxPollInterval() (time.Duration, error) {
+	pollMS, err := strconv.Atoi(getEnv("OUTBOX_POLL_MS", strconv.Itoa(defaultOutboxPollMS)))
+	if err != nil || pollMS <= 0 {
+		return 0, fmt.Errorf("OUTBOX_POLL_MS must be a positive integer")
+	}
+	return time.Duration(pollMS) * time.Millisecond, nil
+}
+
+// loadOutboxRetention reads how long published outbox events are kept from the environment
+func loadOutboxRetention() (time.Duration, error) {
+	hours, err := strconv.Atoi(getEnv("OUTBOX_RETENTION_HOURS", strconv.Itoa(defaultOutboxRetentionHours)))
+	if err != nil || hours <= 0 {
+		return 0, fmt.Errorf("OUTBOX_RETENTION_HOURS must be a positive integer")
+	}
+	return time.Duration(hours) * time.Hour, nil
+}
+
+// enqueueEvent writes an event to the outbox inside tx, so it is stored if
+// and only if the change it describes is committed
+func enqueueEvent(ctx context.Context, tx *sql.Tx, eventType string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = timedExec(ctx, tx, "insert_outbox_event",
+		"INSERT INTO outbox (event_type, payload) VALUES ($1, $2)", eventType, data)
+	if err != nil {
+		return fmt.Errorf("error writing %s event to outbox: %w", eventType, err)
+	}
+	return nil
+}
+
+// enqueueStoredPaddleEvent writes an event carrying the paddle as currently
+// stored in tx, for changes that only touch part of a paddle
+func enqueueStoredPaddleEvent(ctx context.Context, tx *sql.Tx, eventType, paddleId string) error {
+	paddle, err := scanFullPaddle(timedQueryRow(ctx, tx, "read_paddle_for_event", fullPaddleQuery+`
+		WHERE 
+			p.paddle_id = $1
+		ORDER BY 
+			perf.id
+		LIMIT 1
+	`, paddleId))
+	if err != nil {
+		return fmt.Errorf("error reading paddle for %s event: %w", eventType, err)
+	}
+	return enqueueEvent(ctx, tx, eventType, paddle)
+}
+
+// claimPendingEvents claims up to outboxBatchSize unpublished events that no
+// other poller holds, in id order, until outboxClaimTimeout from now. The
+// claim commits at once, so publishing them holds no locks.
+func claimPendingEvents(ctx context.Context) ([]OutboxEvent, error) {
+	rows, err := timedQuery(ctx, DB, "claim_pending_outbox_events", `
+		UPDATE outbox o
+		SET claimed_until = CURRENT_TIMESTAMP + make_interval(secs => $2)
+		FROM (
+			SELECT id
+			FROM outbox
+			WHERE published_at IS NULL
+				AND (claimed_until IS NULL OR claimed_until < CURRENT_TIMESTAMP)
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		) due
+		WHERE o.id = due.id
+		RETURNING o.id, o.event_type, o.payload, o.created_at
+	`, outboxBatchSize, outboxClaimTimeout.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []OutboxEvent
+	for rows.Next() {
+		var event OutboxEvent
+		if err := rows.Scan(&event.ID, &event.Type, &event.Payload, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// RETURNING does not keep the subquery's order
+	slices.SortFunc(events, func(a, b OutboxEvent) int { return a.ID - b.ID })
+	return events, nil
+}
+
+// releaseEvents drops the claim on events, so the next poll takes them again
+// rather than waiting for the claim to run out
+func releaseEvents(events []OutboxEvent) error {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	ids := make([]int64, len(events))
+	for i, event := range events {
+		ids[i] = int64(event.ID)
+	}
+	_, err := timedExec(ctx, DB, "release_outbox_events",
+		"UPDATE outbox SET claimed_until = NULL WHERE id = ANY($1)", pq.Array(ids))
+	return err
+}
+
+// publishPendingEvents claims up to outboxBatchSize unpublished events, then
+// publishes them in id order and marks each one published. It stops at the
+// first failed delivery, keeping the events published so far and releasing
+// the rest for the next poll, and returns how many were published. Claims
+// let several servers poll the same outbox.
+func publishPendingEvents(ctx context.Context) (int, error) {
+	events, err := claimPendingEvents(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, event := range events {
+		if err := publisher.Publish(ctx, event); err != nil {
+			err = fmt.Errorf("error publishing outbox event %d: %w", event.ID, err)
+			if releaseErr := releaseEvents(events[i:]); releaseErr != nil {
+				err = errors.Join(err, fmt.Errorf("error releasing outbox events: %w", releaseErr))
+			}
+			return i, err
+		}
+
+		// Marking uses its own context, so an event delivered just before the
+		// poll times out is not delivered again
+		markCtx, cancel := queryContext()
+		_, err := timedExec(markCtx, DB, "mark_outbox_event_published",
+			"UPDATE outbox SET published_at = CURRENT_TIMESTAMP, claimed_until = NULL WHERE id = $1", event.ID)
+		cancel()
+		if err != nil {
+			return i, err
+		}
+	}
+	return len(events), nil
+}
+
+// pruneOutbox deletes events published more than retention ago, keeping
+// those a webhook is still owed, and returns how many were deleted. Their
+// finished webhook deliveries go with them.
+func pruneOutbox(ctx context.Context, retention time.Duration) (int64, error) {
+	result, err := timedExec(ctx, DB, "prune_outbox", `
+		DELETE FROM outbox o
+		WHERE o.published_at < CURRENT_TIMESTAMP - make_interval(secs => $1)
+			AND NOT EXISTS (
+				SELECT 1 FROM webhook_deliveries d
+				WHERE d.event_id = o.id AND d.delivered_at IS NULL AND d.dead_at IS NULL
+			)
+	`, retention.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// runOutboxPoller publishes pending outbox events every interval until ctx is
+// done, then prunes events published more than retention ago. A poll already
+// running when ctx is done is finished, so shutdown waits for its deliveries
+// instead of abandoning them.
+func runOutboxPoller(ctx context.Context, interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pollCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), outboxPollTimeout)
+			if _, err := publishPendingEvents(pollCtx); err != nil {
+				log.Printf("Error publishing outbox events: %v", err)
+			}
+			if _, err := pruneOutbox(pollCtx, retention); err != nil {
+				log.Printf("Error pruning outbox events: %v", err)
+			}
+			cancel()
+		}
+	}
+}
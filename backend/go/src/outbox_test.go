@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// recordingPublisher records the events it is asked to publish, failing
+// every one with err when it is set
+type recordingPublisher struct {
+	events []OutboxEvent
+	err    error
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event OutboxEvent) error {
+	p.events = append(p.events, event)
+	return p.err
+}
+
+// countOutboxEvents counts the paddle.created events written for a paddle ID
+func countOutboxEvents(t *testing.T, paddleId string) int {
+	t.Helper()
+	var count int
+	err := DB.QueryRow(
+		"SELECT COUNT(*) FROM outbox WHERE event_type = $1 AND payload->>'id' = $2",
+		eventPaddleCreated, paddleId,
+	).Scan(&count)
+	if err != nil {
+		t.Fatalf("Failed to count outbox events: %v", err)
+	}
+	return count
+}
+
+// TestSavePaddleOutbox tests that a saved paddle writes exactly one outbox
+// event, a rolled-back save writes none, and the poller publishes the event
+func TestSavePaddleOutbox(t *testing.T) {
+	setupTestDB(t)
+
+	saved := testPaddleInput("Engage", "Outbox Test").ToPaddle()
+	if _, err := SavePaddle(saved); err != nil {
+		t.Fatalf("SavePaddle() error: %v", err)
+	}
+	if got := countOutboxEvents(t, saved.ID); got != 1 {
+		t.Errorf("Successful save wrote %d outbox events, want 1", got)
+	}
+
+	// A shape too long for its column fails after the paddles row is inserted
+	failed := testPaddleInput("Engage", "Outbox Test Rollback").ToPaddle()
+	failed.Specs.Shape = PaddleShape(strings.Repeat("x", 51))
+	if _, err := SavePaddle(failed); err == nil {
+		t.Fatal("SavePaddle() with an oversized shape succeeded, want an error")
+	}
+	if got := countOutboxEvents(t, failed.ID); got != 0 {
+		t.Errorf("Rolled-back save wrote %d outbox events, want 0", got)
+	}
+
+	recorder := &recordingPublisher{}
+	previous := publisher
+	publisher = recorder
+	t.Cleanup(func() { publisher = previous })
+
+	// Other tests may have left events behind, so drain until ours is published
+	for {
+		published, err := publishPendingEvents(context.Background())
+		if err != nil {
+			t.Fatalf("publishPendingEvents() error: %v", err)
+		}
+		if published == 0 {
+			break
+		}
+	}
+
+	var delivered bool
+	for _, event := range recorder.events {
+		if event.Type == eventPaddleCreated && strings.Contains(string(event.Payload), saved.ID) {
+			delivered = true
+		}
+	}
+	if !delivered {
+		t.Errorf("Event for %s was not published", saved.ID)
+	}
+
+	var unpublished int
+	err := DB.QueryRow("SELECT COUNT(*) FROM outbox WHERE published_at IS NULL AND payload->>'id' = $1", saved.ID).Scan(&unpublished)
+	if err != nil {
+		t.Fatalf("Failed to count unpublished events: %v", err)
+	}
+	if unpublished != 0 {
+		t.Errorf("%d events for %s are still unpublished", unpublished, saved.ID)
+	}
+}
+
+// TestFailedPublishStaysPending tests that an event whose delivery fails is
+// not marked published, so a later poll sends it again
+func TestFailedPublishStaysPending(t *testing.T) {
+	setupTestDB(t)
+
+	saved := saveTestPaddle(t, testPaddleInput("Engage", "Outbox Failure"))
+
+	recorder := &recordingPublisher{err: errors.New("endpoint down")}
+	previous := publisher
+	publisher = recorder
+	t.Cleanup(func() { publisher = previous })
+
+	if _, err := publishPendingEvents(context.Background()); err == nil {
+		t.Fatal("publishPendingEvents() with a failing publisher succeeded, want an error")
+	}
+
+	var unpublished int
+	err := DB.QueryRow("SELECT COUNT(*) FROM outbox WHERE published_at IS NULL AND payload->>'id' = $1", saved.ID).Scan(&unpublished)
+	if err != nil {
+		t.Fatalf("Failed to count unpublished events: %v", err)
+	}
+	if unpublished != 1 {
+		t.Errorf("%d events for %s are unpublished after a failed delivery, want 1", unpublished, saved.ID)
+	}
+}
+
+// TestBulkRenameOutbox tests that renaming a paddle deletes its old ID and
+// creates the new one in the outbox
+func TestBulkRenameOutbox(t *testing.T) {
+	setupTestDB(t)
+
+	saved := saveTestPaddle(t, testPaddleInput("Engaage", "Outbox Rename"))
+	result, err := BulkUpdatePaddles(paddleFilter{Brand: "Engaage"}, BulkUpdate{Brand: "Engage"})
+	if err != nil {
+		t.Fatalf("BulkUpdatePaddles() error: %v", err)
+	}
+	if len(result.Renamed) != 1 {
+		t.Fatalf("renamed = %v, want one rename", result.Renamed)
+	}
+	renamed := result.Renamed[0].To
+
+	var replacedBy string
+	err = DB.QueryRow("SELECT payload->>'replaced_by' FROM outbox WHERE event_type = $1 AND payload->>'id' = $2",
+		eventPaddleDeleted, saved.ID).Scan(&replacedBy)
+	if err != nil {
+		t.Fatalf("Failed to read the %s event: %v", eventPaddleDeleted, err)
+	}
+	if replacedBy != renamed {
+		t.Errorf("replaced_by = %q, want %q", replacedBy, renamed)
+	}
+	if got := countOutboxEvents(t, renamed); got != 1 {
+		t.Errorf("Rename wrote %d %s events for %s, want 1", got, eventPaddleCreated, renamed)
+	}
+}
+
+// TestPublishWithoutWebhooks tests that with webhooks disabled events are
+// discarded as they are published, rather than left pending
+func TestPublishWithoutWebhooks(t *testing.T) {
+	setupTestDB(t)
+
+	saved := saveTestPaddle(t, testPaddleInput("Engage", "Outbox Disabled"))
+
+	previous := publisher
+	publisher = discardPublisher{}
+	t.Cleanup(func() { publisher = previous })
+
+	for {
+		published, err := publishPendingEvents(context.Background())
+		if err != nil {
+			t.Fatalf("publishPendingEvents() error: %v", err)
+		}
+		if published == 0 {
+			break
+		}
+	}
+
+	var unpublished int
+	err := DB.QueryRow("SELECT COUNT(*) FROM outbox WHERE published_at IS NULL AND payload->>'id' = $1", saved.ID).Scan(&unpublished)
+	if err != nil {
+		t.Fatalf("Failed to count unpublished events: %v", err)
+	}
+	if unpublished != 0 {
+		t.Errorf("%d events for %s are unpublished without webhooks, want 0", unpublished, saved.ID)
+	}
+}
+
+// TestPruneOutbox tests that events published longer ago than the retention
+// are deleted, unless a webhook is still owed them
+func TestPruneOutbox(t *testing.T) {
+	setupTestDB(t)
+
+	pruned := saveTestPaddle(t, testPaddleInput("Engage", "Outbox Pruned"))
+	owed := saveTestPaddle(t, testPaddleInput("Engage", "Outbox Owed"))
+	recent := saveTestPaddle(t, testPaddleInput("Engage", "Outbox Recent"))
+
+	hook, err := CreateWebhook("https://example.com/hook", "s3cret")
+	if err != nil {
+		t.Fatalf("CreateWebhook() error: %v", err)
+	}
+	t.Cleanup(func() { DeleteWebhook(hook.ID) })
+
+	_, err = DB.Exec(`
+		UPDATE outbox SET published_at = CURRENT_TIMESTAMP - INTERVAL '2 days'
+		WHERE payload->>'id' = ANY($1)
+	`, pq.Array([]string{pruned.ID, owed.ID}))
+	if err != nil {
+		t.Fatalf("Failed to backdate events: %v", err)
+	}
+	_, err = DB.Exec("UPDATE outbox SET published_at = CURRENT_TIMESTAMP WHERE payload->>'id' = $1", recent.ID)
+	if err != nil {
+		t.Fatalf("Failed to publish event: %v", err)
+	}
+	_, err = DB.Exec(`
+		INSERT INTO webhook_deliveries (event_id, webhook_id)
+		SELECT id, $2 FROM outbox WHERE payload->>'id' = $1
+	`, owed.ID, hook.ID)
+	if err != nil {
+		t.Fatalf("Failed to record a pending delivery: %v", err)
+	}
+
+	if _, err := pruneOutbox(context.Background(), 24*time.Hour); err != nil {
+		t.Fatalf("pruneOutbox() error: %v", err)
+	}
+
+	for id, want := range map[string]int{pruned.ID: 0, owed.ID: 1, recent.ID: 1} {
+		if got := countOutboxEvents(t, id); got != want {
+			t.Errorf("%d events left for %s, want %d", got, id, want)
+		}
+	}
+}
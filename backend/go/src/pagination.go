@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// Default page size settings, overridable via DEFAULT_PAGE_SIZE and MAX_PAGE_SIZE
+const (
+	fallbackDefaultPageSize = 20
+	fallbackMaxPageSize     = 100
+)
+
+var (
+	defaultPageSize = fallbackDefaultPageSize
+	maxPageSize     = fallbackMaxPageSize
+)
+
+// paginationConfig holds the page size settings
+type paginationConfig struct {
+	DefaultPageSize int
+	MaxPageSize     int
+}
+
+// loadPaginationConfig reads the page size settings from the environment and
+// validates them
+func loadPaginationConfig() (paginationConfig, error) {
+	def, err := strconv.Atoi(getEnv("DEFAULT_PAGE_SIZE", strconv.Itoa(fallbackDefaultPageSize)))
+	if err != nil {
+		return paginationConfig{}, fmt.Errorf("invalid DEFAULT_PAGE_SIZE: %w", err)
+	}
+
+	maxSize, err := strconv.Atoi(getEnv("MAX_PAGE_SIZE", strconv.Itoa(fallbackMaxPageSize)))
+	if err != nil {
+		return paginationConfig{}, fmt.Errorf("invalid MAX_PAGE_SIZE: %w", err)
+	}
+
+	if err := validatePageSizes(def, maxSize); err != nil {
+		return paginationConfig{}, err
+	}
+	return paginationConfig{DefaultPageSize: def, MaxPageSize: maxSize}, nil
+}
+
+// validatePageSizes checks that both page sizes are positive and default <= max
+func validatePageSizes(def, maxSize int) error {
+	if def <= 0 {
+		return errors.New("DEFAULT_PAGE_SIZE must be greater than 0")
+	}
+	if maxSize <= 0 {
+		return errors.New("MAX_PAGE_SIZE must be greater than 0")
+	}
+	if def > maxSize {
+		return fmt.Errorf("DEFAULT_PAGE_SIZE (%d) must not exceed MAX_PAGE_SIZE (%d)", def, maxSize)
+	}
+	return nil
+}
+
+// clampLimit returns the page size to use for a requested limit.
+// Values below 1 fall back to the default page size and values above
+// the maximum are capped at the maximum.
+func clampLimit(requested int) int {
+	if requested < 1 {
+		return defaultPageSize
+	}
+	if requested > maxPageSize {
+		return maxPageSize
+	}
+	return requested
+}
+
+// parsePagination reads the limit and offset query parameters from a request
+func parsePagination(r *http.Request) (limit, offset int, err error) {
+	query := r.URL.Query()
+
+	if raw := query.Get("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil {
+			return 0, 0, errors.New("limit must be an integer")
+		}
+	}
+
+	if raw := query.Get("offset"); raw != "" {
+		offset, err = strconv.Atoi(raw)
+		if err != nil {
+			return 0, 0, errors.New("offset must be an integer")
+		}
+		if offset < 0 {
+			return 0, 0, errors.New("offset must not be negative")
+		}
+	}
+
+	return clampLimit(limit), offset, nil
+}
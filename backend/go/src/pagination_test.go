@@ -0,0 +1,98 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+// TestClampLimit tests the clampLimit function
+func TestClampLimit(t *testing.T) {
+	defaultPageSize, maxPageSize = 20, 100
+	defer func() { defaultPageSize, maxPageSize = fallbackDefaultPageSize, fallbackMaxPageSize }()
+
+	tests := []struct {
+		name      string
+		requested int
+		want      int
+	}{
+		{name: "Within range", requested: 50, want: 50},
+		{name: "Equal to max", requested: 100, want: 100},
+		{name: "Above max", requested: 500, want: 100},
+		{name: "Zero", requested: 0, want: 20},
+		{name: "Negative", requested: -5, want: 20},
+		{name: "One", requested: 1, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clampLimit(tt.requested); got != tt.want {
+				t.Errorf("clampLimit(%d) = %d, want %d", tt.requested, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLoadPaginationConfig tests loading page sizes from the environment
+func TestLoadPaginationConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		defaultSize string
+		maxSize     string
+		wantErr     bool
+	}{
+		{name: "Defaults", wantErr: false},
+		{name: "Custom values", defaultSize: "10", maxSize: "50", wantErr: false},
+		{name: "Default exceeds max", defaultSize: "60", maxSize: "50", wantErr: true},
+		{name: "Zero max", maxSize: "0", wantErr: true},
+		{name: "Negative default", defaultSize: "-1", wantErr: true},
+		{name: "Non-numeric", maxSize: "lots", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DEFAULT_PAGE_SIZE", tt.defaultSize)
+			t.Setenv("MAX_PAGE_SIZE", tt.maxSize)
+
+			_, err := loadPaginationConfig()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("loadPaginationConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestParsePagination tests reading limit and offset from the query string
+func TestParsePagination(t *testing.T) {
+	defaultPageSize, maxPageSize = 20, 100
+	defer func() { defaultPageSize, maxPageSize = fallbackDefaultPageSize, fallbackMaxPageSize }()
+
+	tests := []struct {
+		name       string
+		query      string
+		wantLimit  int
+		wantOffset int
+		wantErr    bool
+	}{
+		{name: "No params", query: "", wantLimit: 20, wantOffset: 0},
+		{name: "Limit and offset", query: "?limit=5&offset=10", wantLimit: 5, wantOffset: 10},
+		{name: "Limit above max", query: "?limit=1000", wantLimit: 100, wantOffset: 0},
+		{name: "Non-numeric limit", query: "?limit=abc", wantErr: true},
+		{name: "Negative offset", query: "?offset=-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/paddles"+tt.query, nil)
+			limit, offset, err := parsePagination(req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePagination() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if limit != tt.wantLimit || offset != tt.wantOffset {
+				t.Errorf("parsePagination() = (%d, %d), want (%d, %d)", limit, offset, tt.wantLimit, tt.wantOffset)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// maxPerformanceIDs caps how many paddles one performance request may ask for
+const maxPerformanceIDs = 100
+
+// parsePerformanceIDs reads the comma-separated ids parameter, which may also
+// be repeated. Duplicates are dropped and first-seen order is kept.
+func parsePerformanceIDs(raw []string) ([]string, error) {
+	var ids []string
+	seen := map[string]bool{}
+	for _, value := range raw {
+		for _, id := range strings.Split(value, ",") {
+			id = strings.TrimSpace(id)
+			if id == "" || seen[id] {
+				continue
+			}
+			if err := validateQueryText("id", id); err != nil {
+				return nil, err
+			}
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("at least one id is required")
+	}
+	if len(ids) > maxPerformanceIDs {
+		return nil, fmt.Errorf("at most %d ids are allowed, got %d", maxPerformanceIDs, len(ids))
+	}
+	return ids, nil
+}
+
+// GetPerformanceByIDs returns the mean performance of each requested paddle in
+// one query, reading only the performance columns. Unknown IDs are left out.
+func GetPerformanceByIDs(paddleIds []string) (map[string]Performance, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	rows, err := timedQuery(ctx, DB, "get_performance_by_ids", `
+		SELECT 
+			p.paddle_id, `+performanceColumns+`
+		FROM 
+			paddles p
+		JOIN 
+			paddle_specs s ON p.id = s.paddle_id
+		JOIN 
+			paddle_performance perf ON s.id = perf.paddle_spec_id
+		WHERE 
+			p.paddle_id = ANY($1)
+		ORDER BY 
+			perf.id
+	`, pq.Array(paddleIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	measurements := map[string][]Performance{}
+	for rows.Next() {
+		var id string
+		var m Performance
+		err := rows.Scan(append([]interface{}{&id}, performanceScanDest(&m)...)...)
+		if err != nil {
+			return nil, err
+		}
+		measurements[id] = append(measurements[id], m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	performance := make(map[string]Performance, len(measurements))
+	for id, ms := range measurements {
+		performance[id] = averagePerformance(ms).Performance
+	}
+	return performance, nil
+}
+
+// getPerformanceByIDs handles the API request for the performance metrics of many paddles
+func getPerformanceByIDs(w http.ResponseWriter, r *http.Request) {
+	ids, err := parsePerformanceIDs(r.URL.Query()["ids"])
+	if err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid ids: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	performance, err := store.GetPerformanceByIDs(ids)
+	if err != nil {
+		log.Printf("Error retrieving performance by IDs: %v", err)
+		respondWithError(w, "Failed to retrieve paddle performance", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(performance); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
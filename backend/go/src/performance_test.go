@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestParsePerformanceIDs tests parsing and capping the ids parameter
+func TestParsePerformanceIDs(t *testing.T) {
+	tooMany := make([]string, maxPerformanceIDs+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("paddle-%d", i)
+	}
+
+	tests := []struct {
+		name    string
+		raw     []string
+		want    []string
+		wantErr string
+	}{
+		{name: "Comma separated", raw: []string{"a-1,b-2"}, want: []string{"a-1", "b-2"}},
+		{name: "Repeated and duplicated", raw: []string{"a-1", "b-2, a-1"}, want: []string{"a-1", "b-2"}},
+		{name: "Missing", raw: nil, wantErr: "at least one id is required"},
+		{name: "Only commas", raw: []string{" , ,"}, wantErr: "at least one id is required"},
+		{name: "At the cap", raw: []string{strings.Join(tooMany[:maxPerformanceIDs], ",")}, want: tooMany[:maxPerformanceIDs]},
+		{name: "Over the cap", raw: []string{strings.Join(tooMany, ",")}, wantErr: "at most 100 ids"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePerformanceIDs(tt.raw)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parsePerformanceIDs() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePerformanceIDs() unexpected error: %v", err)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("parsePerformanceIDs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestGetPerformanceByIDs tests that only performance data is returned, keyed by paddle ID
+func TestGetPerformanceByIDs(t *testing.T) {
+	setupTestStore(t)
+
+	performance := Performance{Power: 75, Pop: 70, Spin: 3000, TwistWeight: 6.5, SwingWeight: 115, BalancePoint: 23}
+	paddle := &Paddle{
+		ID:          "engage-pursuit-mx-6.0",
+		Metadata:    Metadata{Brand: "Engage", Model: "Pursuit MX 6.0", SKU: "EN-PMX6"},
+		Specs:       testSpecs,
+		Performance: performance,
+	}
+	if _, err := store.SavePaddle(paddle); err != nil {
+		t.Fatalf("SavePaddle() error: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/api/paddles/performance?ids=engage-pursuit-mx-6.0,missing-paddle", nil)
+	rr := httptest.NewRecorder()
+	getPerformanceByIDs(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	var raw map[string]map[string]interface{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &raw); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(raw) != 1 {
+		t.Fatalf("response has %d paddles, want 1: %v", len(raw), raw)
+	}
+	for field := range raw["engage-pursuit-mx-6.0"] {
+		if _, ok := performanceMetricColumns[field]; !ok {
+			t.Errorf("response contains non-performance field %q", field)
+		}
+	}
+
+	var got map[string]Performance
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if got["engage-pursuit-mx-6.0"] != performance {
+		t.Errorf("performance = %+v, want %+v", got["engage-pursuit-mx-6.0"], performance)
+	}
+}
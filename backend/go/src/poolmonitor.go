@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+)
+
+// defaultPoolStatsMS is how often the connection pool is sampled, overridable
+// via POOL_STATS_MS. 0 turns the monitor off.
+const defaultPoolStatsMS = 60000
+
+// loadPoolStatsInterval reads the pool sampling interval from the environment
+func loadPoolStatsInterval() (time.Duration, error) {
+	intervalMS, err := strconv.Atoi(getEnv("POOL_STATS_MS", strconv.Itoa(defaultPoolStatsMS)))
+	if err != nil || intervalMS < 0 {
+		return 0, fmt.Errorf("POOL_STATS_MS must be a non-negative integer")
+	}
+	return time.Duration(intervalMS) * time.Millisecond, nil
+}
+
+// runPoolMonitor samples db.Stats() every interval and passes each sample to
+// record, until ctx is cancelled
+func runPoolMonitor(ctx context.Context, db *sql.DB, interval time.Duration, record func(sql.DBStats)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			record(db.Stats())
+		}
+	}
+}
+
+// poolStatsLogger returns a record function for runPoolMonitor that logs each
+// sample. A rising wait count means requests are queueing for a connection,
+// the first sign of pool exhaustion, so it is called out separately.
+func poolStatsLogger() func(sql.DBStats) {
+	var lastWaitCount int64
+	return func(stats sql.DBStats) {
+		log.Printf("DB pool: open=%d in_use=%d idle=%d max_open=%d wait_count=%d wait_duration=%s",
+			stats.OpenConnections, stats.InUse, stats.Idle, stats.MaxOpenConnections,
+			stats.WaitCount, stats.WaitDuration)
+
+		if waits := stats.WaitCount - lastWaitCount; waits > 0 {
+			log.Printf("DB pool: %d requests waited for a connection since the last sample", waits)
+		}
+		lastWaitCount = stats.WaitCount
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+// TestRunPoolMonitor tests that pool stats are sampled at least once and that
+// the monitor returns once its context is cancelled
+func TestRunPoolMonitor(t *testing.T) {
+	// sql.Open does not connect, so the pool can be sampled without a database
+	db, err := sql.Open("postgres", "host=localhost dbname=unused sslmode=disable")
+	if err != nil {
+		t.Fatalf("sql.Open() error: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(7)
+
+	samples := make(chan sql.DBStats, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		runPoolMonitor(ctx, db, 10*time.Millisecond, func(stats sql.DBStats) {
+			select {
+			case samples <- stats:
+			default:
+			}
+		})
+	}()
+
+	select {
+	case stats := <-samples:
+		if stats.MaxOpenConnections != 7 {
+			t.Errorf("MaxOpenConnections = %d, want 7", stats.MaxOpenConnections)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no pool stats collected within 1s")
+	}
+
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("runPoolMonitor did not return after cancel")
+	}
+}
+
+// TestLoadPoolStatsInterval tests reading the sampling interval from the environment
+func TestLoadPoolStatsInterval(t *testing.T) {
+	t.Setenv("POOL_STATS_MS", "250")
+	if interval, err := loadPoolStatsInterval(); err != nil || interval != 250*time.Millisecond {
+		t.Errorf("loadPoolStatsInterval() = %s, %v; want 250ms", interval, err)
+	}
+
+	t.Setenv("POOL_STATS_MS", "0")
+	if interval, err := loadPoolStatsInterval(); err != nil || interval != 0 {
+		t.Errorf("loadPoolStatsInterval() = %s, %v; want the monitor off", interval, err)
+	}
+
+	t.Setenv("POOL_STATS_MS", "-1")
+	if _, err := loadPoolStatsInterval(); err == nil {
+		t.Error("loadPoolStatsInterval() with a negative interval expected an error")
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Default decimal places kept for stored floats, overridable via FLOAT_PRECISION
+// and SPIN_PRECISION. Spin is measured in whole RPM; everything else keeps 2 decimals.
+const (
+	defaultFloatPrecision = 2
+	defaultSpinPrecision  = 0
+)
+
+// maxPrecision is the most decimals worth keeping; beyond it float64 noise shows through
+const maxPrecision = 6
+
+var (
+	floatPrecision = defaultFloatPrecision
+	spinPrecision  = defaultSpinPrecision
+)
+
+// precisionConfig holds the decimals kept in responses
+type precisionConfig struct {
+	Float int
+	Spin  int
+}
+
+// loadPrecisionConfig reads the float precision settings from the environment
+func loadPrecisionConfig() (precisionConfig, error) {
+	general, err := parsePrecision("FLOAT_PRECISION", defaultFloatPrecision)
+	if err != nil {
+		return precisionConfig{}, err
+	}
+	spin, err := parsePrecision("SPIN_PRECISION", defaultSpinPrecision)
+	if err != nil {
+		return precisionConfig{}, err
+	}
+	return precisionConfig{Float: general, Spin: spin}, nil
+}
+
+// parsePrecision reads a number of decimal places between 0 and maxPrecision
+func parsePrecision(key string, fallback int) (int, error) {
+	decimals, err := strconv.Atoi(getEnv(key, strconv.Itoa(fallback)))
+	if err != nil || decimals < 0 || decimals > maxPrecision {
+		return 0, fmt.Errorf("%s must be an integer between 0 and %d", key, maxPrecision)
+	}
+	return decimals, nil
+}
+
+// roundTo rounds v to the given number of decimal places, halves away from zero
+func roundTo(v float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(v*scale) / scale
+}
+
+// roundSpecs rounds every float spec to floatPrecision
+func roundSpecs(specs *Specs) {
+	specs.AverageWeight = roundTo(specs.AverageWeight, floatPrecision)
+	specs.Core = roundTo(specs.Core, floatPrecision)
+	specs.PaddleLength = roundTo(specs.PaddleLength, floatPrecision)
+	specs.PaddleWidth = roundTo(specs.PaddleWidth, floatPrecision)
+	specs.GripLength = roundTo(specs.GripLength, floatPrecision)
+	specs.GripCircumference = roundTo(specs.GripCircumference, floatPrecision)
+}
+
+// roundPerformance rounds spin to spinPrecision and the other metrics to floatPrecision
+func roundPerformance(performance *Performance) {
+	performance.Power = roundTo(performance.Power, floatPrecision)
+	performance.Pop = roundTo(performance.Pop, floatPrecision)
+	performance.Spin = roundTo(performance.Spin, spinPrecision)
+	performance.TwistWeight = roundTo(performance.TwistWeight, floatPrecision)
+	performance.SwingWeight = roundTo(performance.SwingWeight, floatPrecision)
+	performance.BalancePoint = roundTo(performance.BalancePoint, floatPrecision)
+
+	// Stddevs share the precision of their metric
+	for metric, stddev := range performance.stddevs() {
+		if *stddev == nil {
+			continue
+		}
+		decimals := floatPrecision
+		if metric == "spin" {
+			decimals = spinPrecision
+		}
+		rounded := roundTo(**stddev, decimals)
+		*stddev = &rounded
+	}
+}
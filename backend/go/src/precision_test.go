@@ -0,0 +1,81 @@
+package main
+
+import (
+	"testing"
+)
+
+// TestRoundTo tests rounding to a number of decimal places
+func TestRoundTo(t *testing.T) {
+	tests := []struct {
+		value    float64
+		decimals int
+		want     float64
+	}{
+		{value: 220.00000001, decimals: 2, want: 220},
+		{value: 7.456, decimals: 2, want: 7.46},
+		{value: 7.454, decimals: 2, want: 7.45},
+		{value: 2849.6, decimals: 0, want: 2850},
+		{value: -0.125, decimals: 2, want: -0.13},
+		{value: 16.5, decimals: 0, want: 17},
+	}
+
+	for _, tt := range tests {
+		if got := roundTo(tt.value, tt.decimals); got != tt.want {
+			t.Errorf("roundTo(%v, %d) = %v, want %v", tt.value, tt.decimals, got, tt.want)
+		}
+	}
+}
+
+// TestToPaddleRoundsFloats tests that ToPaddle stores clean values, with spin in whole RPM
+func TestToPaddleRoundsFloats(t *testing.T) {
+	input := &PaddleInput{
+		Metadata:    Metadata{Brand: "Engage", Model: "Pursuit MX 6.0"},
+		Specs:       Specs{AverageWeight: 220.00000001, Core: 16.0049, PaddleLength: 16.505, GripCircumference: 4.249999},
+		Performance: Performance{Power: 75.555, Pop: 70.1, Spin: 2999.7, SwingWeight: 110.333333},
+	}
+
+	paddle := input.ToPaddle()
+
+	got := []float64{
+		paddle.Specs.AverageWeight, paddle.Specs.Core, paddle.Specs.PaddleLength, paddle.Specs.GripCircumference,
+		paddle.Performance.Power, paddle.Performance.Pop, paddle.Performance.Spin, paddle.Performance.SwingWeight,
+	}
+	want := []float64{220, 16, 16.51, 4.25, 75.56, 70.1, 3000, 110.33}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("field %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	// The input itself is left untouched
+	if input.Specs.AverageWeight != 220.00000001 {
+		t.Errorf("ToPaddle modified its input: %v", input.Specs.AverageWeight)
+	}
+}
+
+// TestLoadPrecisionConfig tests loading precision settings from the environment
+func TestLoadPrecisionConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		general string
+		spin    string
+		wantErr bool
+	}{
+		{name: "Defaults"},
+		{name: "Custom values", general: "3", spin: "1"},
+		{name: "Negative", general: "-1", wantErr: true},
+		{name: "Too precise", spin: "10", wantErr: true},
+		{name: "Non-numeric", general: "two", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("FLOAT_PRECISION", tt.general)
+			t.Setenv("SPIN_PRECISION", tt.spin)
+
+			if _, err := loadPrecisionConfig(); (err != nil) != tt.wantErr {
+				t.Errorf("loadPrecisionConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// paddleQuotaLockKey is the advisory lock that serializes quota checks, so
+// two concurrent inserts cannot both see room for the last paddle
+const paddleQuotaLockKey = 0x70616464
+
+// maxPaddles caps how many paddles may be stored, set via MAX_PADDLES.
+// 0 means unlimited.
+var maxPaddles int
+
+// ErrQuotaExceeded is returned when saving a new paddle would exceed maxPaddles
+var ErrQuotaExceeded = errors.New("paddle quota exceeded")
+
+// loadMaxPaddles reads the paddle quota from the environment. Zero means no quota.
+func loadMaxPaddles() (int, error) {
+	raw := getEnv("MAX_PADDLES", "")
+	if raw == "" {
+		return 0, nil
+	}
+
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit <= 0 {
+		return 0, fmt.Errorf("MAX_PADDLES must be a positive integer")
+	}
+	return limit, nil
+}
+
+// quotaExceededMessage is the error shown to clients when the quota is reached
+func quotaExceededMessage() string {
+	return fmt.Sprintf("This deployment is limited to %d paddles and the limit has been reached", maxPaddles)
+}
+
+// checkPaddleQuota returns ErrQuotaExceeded when adding one paddle would exceed
+// maxPaddles. It locks inside tx, so the lock is held until the insert commits
+// or rolls back.
+func checkPaddleQuota(ctx context.Context, tx *sql.Tx) error {
+	if maxPaddles == 0 {
+		return nil
+	}
+
+	if _, err := timedExec(ctx, tx, "lock_paddle_quota", "SELECT pg_advisory_xact_lock($1)", paddleQuotaLockKey); err != nil {
+		return fmt.Errorf("error locking paddle quota: %w", err)
+	}
+
+	var count int
+	if err := timedQueryRow(ctx, tx, "count_paddles_for_quota", "SELECT COUNT(*) FROM paddles").Scan(&count); err != nil {
+		return fmt.Errorf("error counting paddles: %w", err)
+	}
+	if count >= maxPaddles {
+		return fmt.Errorf("%w: limit is %d", ErrQuotaExceeded, maxPaddles)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestLoadMaxPaddles tests parsing MAX_PADDLES
+func TestLoadMaxPaddles(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{value: "", want: 0},
+		{value: "25", want: 25},
+		{value: "0", wantErr: true},
+		{value: "-3", wantErr: true},
+		{value: "many", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("MAX_PADDLES", tt.value)
+			limit, err := loadMaxPaddles()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadMaxPaddles() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && limit != tt.want {
+				t.Errorf("loadMaxPaddles() = %d, want %d", limit, tt.want)
+			}
+		})
+	}
+}
+
+// TestUploadPaddleQuota tests that the upload after the quota is reached is rejected,
+// while upserting an existing paddle is still allowed
+func TestUploadPaddleQuota(t *testing.T) {
+	setupTestStore(t)
+	maxPaddles = 2
+	t.Cleanup(func() { maxPaddles = 0 })
+
+	post := func(model, query string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(testPaddleInput("Engage", model))
+		rr := httptest.NewRecorder()
+		uploadPaddleStats(rr, httptest.NewRequest("POST", "/api/paddles"+query, bytes.NewBuffer(body)))
+		return rr
+	}
+
+	for i := range maxPaddles {
+		if rr := post(fmt.Sprintf("Quota %d", i), ""); rr.Code != http.StatusCreated {
+			t.Fatalf("Upload %d returned %d, want %d: %s", i+1, rr.Code, http.StatusCreated, rr.Body.String())
+		}
+	}
+
+	rr := post("Quota 2", "")
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("Upload over the quota returned %d, want %d: %s", rr.Code, http.StatusForbidden, rr.Body.String())
+	}
+	if !strings.Contains(rr.Body.String(), "limited to 2 paddles") {
+		t.Errorf("Quota error does not state the limit: %s", rr.Body.String())
+	}
+
+	if rr := post("Quota 2", "?upsert=true"); rr.Code != http.StatusForbidden {
+		t.Errorf("Upsert of a new paddle over the quota returned %d, want %d", rr.Code, http.StatusForbidden)
+	}
+	if rr := post("Quota 0", "?upsert=true"); rr.Code != http.StatusOK {
+		t.Errorf("Upsert of an existing paddle at the quota returned %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+}
+
+// TestBulkUploadPaddleQuota tests that bulk items beyond the quota fail individually
+func TestBulkUploadPaddleQuota(t *testing.T) {
+	setupTestStore(t)
+	maxPaddles = 2
+	t.Cleanup(func() { maxPaddles = 0 })
+
+	body, _ := json.Marshal([]*PaddleInput{testPaddleInput("Engage", "Bulk 0"), testPaddleInput("Engage", "Bulk 1"), testPaddleInput("Engage", "Bulk 2")})
+	rr := httptest.NewRecorder()
+	bulkUploadPaddles(rr, httptest.NewRequest("POST", "/api/paddles/bulk", bytes.NewBuffer(body)))
+
+	if rr.Code != http.StatusMultiStatus {
+		t.Fatalf("status = %d, want %d: %s", rr.Code, http.StatusMultiStatus, rr.Body.String())
+	}
+
+	var result BulkResult
+	if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	wantStatuses := []int{http.StatusCreated, http.StatusCreated, http.StatusForbidden}
+	for i, item := range result.Results {
+		if item.Status != wantStatuses[i] {
+			t.Errorf("item %d status = %d, want %d", i, item.Status, wantStatuses[i])
+		}
+	}
+}
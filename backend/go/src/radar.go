@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// metricBounds is the dataset range of one performance metric
+type metricBounds struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// RadarMetric is one axis of a radar chart
+type RadarMetric struct {
+	Metric string  `json:"metric"`
+	Value  float64 `json:"value"`
+	Scaled float64 `json:"scaled"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+}
+
+// scaleMetric maps value onto 0–100 relative to bounds, clamping values
+// outside them. When every paddle has the same value the range is empty and
+// the value is placed in the middle, at 50.
+func scaleMetric(value float64, bounds metricBounds) float64 {
+	if bounds.Max <= bounds.Min {
+		return 50
+	}
+	scaled := (value - bounds.Min) / (bounds.Max - bounds.Min) * 100
+	return max(0, min(100, scaled))
+}
+
+// buildRadar scales each performance metric against its dataset bounds, in performanceMetrics order
+func buildRadar(performance *Performance, bounds map[string]metricBounds) []RadarMetric {
+	radar := make([]RadarMetric, 0, len(performanceMetrics))
+	for _, metric := range performanceMetrics {
+		value := metricValue(performance, metric)
+		b := bounds[metric]
+		radar = append(radar, RadarMetric{
+			Metric: metric,
+			Value:  value,
+			Scaled: scaleMetric(value, b),
+			Min:    b.Min,
+			Max:    b.Max,
+		})
+	}
+	return radar
+}
+
+// getPaddleRadar handles the API request for a paddle's radar chart payload
+func getPaddleRadar(w http.ResponseWriter, r *http.Request) {
+	paddleId := paddleIDFromRequest(r)
+	if err := validatePaddleID(paddleId); err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid paddle ID: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// Use the same averaged performance as the details endpoint
+	agg, err := store.GetAggregatedPerformance(paddleId)
+	if errors.Is(err, ErrPaddleNotFound) {
+		respondWithError(w, "Paddle not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Error retrieving paddle performance for radar: %v", err)
+		respondWithError(w, "Failed to retrieve paddle performance", http.StatusInternalServerError)
+		return
+	}
+
+	// Scale against the cached dataset stats, refreshed in the background
+	stats, err := currentDatasetStats()
+	if err != nil {
+		log.Printf("Error retrieving dataset stats: %v", err)
+		respondWithError(w, "Failed to retrieve dataset bounds", http.StatusInternalServerError)
+		return
+	}
+
+	response := struct {
+		ID      string        `json:"id"`
+		Metrics []RadarMetric `json:"metrics"`
+	}{
+		ID:      paddleId,
+		Metrics: buildRadar(&agg.Performance, stats.bounds()),
+	}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"testing"
+)
+
+// TestScaleMetric tests scaling values onto 0–100 against fixed bounds
+func TestScaleMetric(t *testing.T) {
+	bounds := metricBounds{Min: 40, Max: 90}
+
+	tests := []struct {
+		name   string
+		value  float64
+		bounds metricBounds
+		want   float64
+	}{
+		{name: "Minimum", value: 40, bounds: bounds, want: 0},
+		{name: "Maximum", value: 90, bounds: bounds, want: 100},
+		{name: "Midpoint", value: 65, bounds: bounds, want: 50},
+		{name: "Below range clamps", value: 30, bounds: bounds, want: 0},
+		{name: "Above range clamps", value: 95, bounds: bounds, want: 100},
+		{name: "Empty range", value: 70, bounds: metricBounds{Min: 70, Max: 70}, want: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scaleMetric(tt.value, tt.bounds); got != tt.want {
+				t.Errorf("scaleMetric(%v, %+v) = %v, want %v", tt.value, tt.bounds, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestBuildRadar tests that every metric is returned raw and scaled, in order
+func TestBuildRadar(t *testing.T) {
+	performance := &Performance{Power: 60, Pop: 40, Spin: 2500, TwistWeight: 6.5, SwingWeight: 115, BalancePoint: 23}
+	bounds := map[string]metricBounds{
+		"power":         {Min: 40, Max: 80},
+		"pop":           {Min: 40, Max: 80},
+		"spin":          {Min: 2000, Max: 3000},
+		"twist_weight":  {Min: 5.5, Max: 7.5},
+		"swing_weight":  {Min: 100, Max: 120},
+		"balance_point": {Min: 22, Max: 24},
+	}
+
+	radar := buildRadar(performance, bounds)
+	want := []float64{50, 0, 50, 50, 75, 50}
+	if len(radar) != len(performanceMetrics) {
+		t.Fatalf("buildRadar() returned %d metrics, want %d", len(radar), len(performanceMetrics))
+	}
+	for i, m := range radar {
+		if m.Metric != performanceMetrics[i] {
+			t.Errorf("metric %d = %q, want %q", i, m.Metric, performanceMetrics[i])
+		}
+		if m.Scaled != want[i] {
+			t.Errorf("%s scaled = %v, want %v", m.Metric, m.Scaled, want[i])
+		}
+		if m.Value != metricValue(performance, m.Metric) {
+			t.Errorf("%s raw value = %v, want %v", m.Metric, m.Value, metricValue(performance, m.Metric))
+		}
+	}
+}
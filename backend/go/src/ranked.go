@@ -0,0 +1,206 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// rankMetrics are the metrics a ranking can weight: the performance metrics
+// plus the derived control rating
+var rankMetrics = append(slices.Clone(performanceMetrics), "control")
+
+// rankValue returns the value of a rank metric for a paddle
+func rankValue(paddle *Paddle, metric string) float64 {
+	if metric == "control" {
+		return paddle.Control
+	}
+	return metricValue(&paddle.Performance, metric)
+}
+
+// parseRankWeights reads w_<metric> parameters. A negative weight ranks lower
+// values higher. At least one weight must be non-zero, and unknown w_
+// parameters are rejected so a typo is not silently ignored.
+func parseRankWeights(query url.Values) (map[string]float64, error) {
+	weights := map[string]float64{}
+	for key := range query {
+		metric, ok := strings.CutPrefix(key, "w_")
+		if !ok {
+			continue
+		}
+		if !slices.Contains(rankMetrics, metric) {
+			return nil, fmt.Errorf("unknown weight %q: must be one of w_%s", key, strings.Join(rankMetrics, ", w_"))
+		}
+
+		weight, err := strconv.ParseFloat(query.Get(key), 64)
+		if err != nil || math.IsNaN(weight) || math.IsInf(weight, 0) {
+			return nil, fmt.Errorf("%s must be a number", key)
+		}
+		if weight != 0 {
+			weights[metric] = weight
+		}
+	}
+
+	if len(weights) == 0 {
+		return nil, fmt.Errorf("at least one non-zero weight is required: w_%s", strings.Join(rankMetrics, ", w_"))
+	}
+	return weights, nil
+}
+
+// RankedPaddle is a paddle with its composite score in a ranking
+type RankedPaddle struct {
+	Rank        int         `json:"rank"`
+	ID          string      `json:"id"`
+	Metadata    Metadata    `json:"metadata"`
+	Performance Performance `json:"performance"`
+	Control     float64     `json:"control"`
+	Score       float64     `json:"score"`
+}
+
+// rankPaddles scores each paddle and returns them best first. Each weighted
+// performance metric is scaled to 0–100 against the dataset bounds with
+// scaleMetric, as the radar chart does; the control rating is already 0–100
+// and is used as is. The score is the weighted mean of those, so it stays
+// within 0–100. Ties keep paddle ID order.
+func rankPaddles(paddles []*Paddle, weights map[string]float64, bounds map[string]metricBounds) []RankedPaddle {
+	totalWeight := 0.0
+	for _, weight := range weights {
+		totalWeight += math.Abs(weight)
+	}
+
+	ranked := make([]RankedPaddle, 0, len(paddles))
+	for _, paddle := range paddles {
+		score := 0.0
+		for metric, weight := range weights {
+			b := bounds[metric]
+			if metric == "control" {
+				b = metricBounds{Min: 0, Max: 100}
+			}
+			scaled := scaleMetric(rankValue(paddle, metric), b)
+			if weight < 0 {
+				scaled = 100 - scaled
+			}
+			score += math.Abs(weight) * scaled
+		}
+
+		ranked = append(ranked, RankedPaddle{
+			ID:          paddle.ID,
+			Metadata:    paddle.Metadata,
+			Performance: paddle.Performance,
+			Control:     paddle.Control,
+			Score:       roundTo(score/totalWeight, 2),
+		})
+	}
+
+	slices.SortFunc(ranked, func(a, b RankedPaddle) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	for i := range ranked {
+		ranked[i].Rank = i + 1
+	}
+	return ranked
+}
+
+// GetRankingCandidates returns every paddle matching the filter with its
+// performance averaged across measurements, like the details endpoint
+func GetRankingCandidates(filter paddleFilter) ([]*Paddle, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	where, args := filter.where()
+	rows, err := timedQuery(ctx, DB, "get_ranking_candidates", `
+		SELECT
+			p.paddle_id, p.brand, p.model, p.year, p.price,
+			AVG(perf.power), AVG(perf.pop), AVG(perf.spin),
+			AVG(perf.twist_weight), AVG(perf.swing_weight), AVG(perf.balance_point)
+		FROM
+			paddles p
+		JOIN
+			paddle_specs s ON p.id = s.paddle_id
+		JOIN
+			paddle_performance perf ON s.id = perf.paddle_spec_id
+		`+where+`
+		GROUP BY
+			p.id
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	paddles := []*Paddle{}
+	for rows.Next() {
+		paddle := &Paddle{}
+		err := rows.Scan(
+			&paddle.ID, &paddle.Metadata.Brand, &paddle.Metadata.Model, &paddle.Metadata.Year, &paddle.Metadata.Price,
+			&paddle.Performance.Power, &paddle.Performance.Pop, &paddle.Performance.Spin,
+			&paddle.Performance.TwistWeight, &paddle.Performance.SwingWeight, &paddle.Performance.BalancePoint,
+		)
+		if err != nil {
+			return nil, err
+		}
+		paddle.Control = paddle.ControlRating()
+		paddles = append(paddles, paddle)
+	}
+	return paddles, rows.Err()
+}
+
+// getRankedPaddles handles the API request for paddles ranked by a weighted
+// composite score
+func getRankedPaddles(w http.ResponseWriter, r *http.Request) {
+	weights, err := parseRankWeights(r.URL.Query())
+	if err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid weights: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid pagination: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	filter, err := parsePaddleFilter(r.URL.Query())
+	if err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid filter: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	paddles, err := GetRankingCandidates(filter)
+	if err != nil {
+		log.Printf("Error retrieving paddles to rank: %v", err)
+		respondWithError(w, "Failed to retrieve ranked paddles", http.StatusInternalServerError)
+		return
+	}
+
+	// Scale against the cached dataset stats, refreshed in the background
+	stats, err := currentDatasetStats()
+	if err != nil {
+		log.Printf("Error retrieving dataset stats: %v", err)
+		respondWithError(w, "Failed to retrieve ranked paddles", http.StatusInternalServerError)
+		return
+	}
+
+	// Every paddle is scored before paging, so ranks are stable across pages
+	ranked := rankPaddles(paddles, weights, stats.bounds())
+	ranked = ranked[min(offset, len(ranked)):min(offset+limit, len(ranked))]
+
+	response := struct {
+		Weights map[string]float64 `json:"weights"`
+		Paddles []RankedPaddle     `json:"paddles"`
+	}{Weights: weights, Paddles: ranked}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding ranked paddles: %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+// TestParseRankWeights tests reading and validating w_ parameters
+func TestParseRankWeights(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    map[string]float64
+		wantErr bool
+	}{
+		{name: "Weights", query: "w_power=1&w_spin=2&w_control=0.5", want: map[string]float64{"power": 1, "spin": 2, "control": 0.5}},
+		{name: "Negative weight", query: "w_swing_weight=-1", want: map[string]float64{"swing_weight": -1}},
+		{name: "Zero weights dropped", query: "w_power=1&w_pop=0", want: map[string]float64{"power": 1}},
+		{name: "Other parameters ignored", query: "w_power=1&limit=5&brand=Engage", want: map[string]float64{"power": 1}},
+		{name: "No weights", query: "limit=5", wantErr: true},
+		{name: "Only zero weights", query: "w_power=0", wantErr: true},
+		{name: "Unknown metric", query: "w_speed=1", wantErr: true},
+		{name: "Not a number", query: "w_power=high", wantErr: true},
+		{name: "Infinite", query: "w_power=Inf", wantErr: true},
+		{name: "NaN", query: "w_power=NaN", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, _ := url.ParseQuery(tt.query)
+			got, err := parseRankWeights(query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseRankWeights() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseRankWeights() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRankPaddles tests that changing the weights reorders the ranking
+func TestRankPaddles(t *testing.T) {
+	newPaddle := func(id string, power, pop, spin float64) *Paddle {
+		paddle := &Paddle{ID: id, Performance: Performance{Power: power, Pop: pop, Spin: spin}}
+		paddle.Control = paddle.ControlRating()
+		return paddle
+	}
+	paddles := []*Paddle{
+		newPaddle("engage-power", 90, 80, 2000),
+		newPaddle("selkirk-spin", 60, 60, 3000),
+		newPaddle("joola-balanced", 75, 70, 2500),
+	}
+
+	bounds := map[string]metricBounds{"power": {Min: 60, Max: 90}, "spin": {Min: 2000, Max: 3000}}
+
+	ids := func(ranked []RankedPaddle) []string {
+		var ids []string
+		for _, r := range ranked {
+			ids = append(ids, r.ID)
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name       string
+		weights    map[string]float64
+		wantIDs    []string
+		wantScores []float64
+	}{
+		{
+			name:       "Power",
+			weights:    map[string]float64{"power": 1},
+			wantIDs:    []string{"engage-power", "joola-balanced", "selkirk-spin"},
+			wantScores: []float64{100, 50, 0},
+		},
+		{
+			name:       "Spin",
+			weights:    map[string]float64{"spin": 1},
+			wantIDs:    []string{"selkirk-spin", "joola-balanced", "engage-power"},
+			wantScores: []float64{100, 50, 0},
+		},
+		{
+			name:       "Spin outweighs power",
+			weights:    map[string]float64{"power": 1, "spin": 3},
+			wantIDs:    []string{"selkirk-spin", "joola-balanced", "engage-power"},
+			wantScores: []float64{75, 50, 25},
+		},
+		{
+			name:       "Negative power favors low power",
+			weights:    map[string]float64{"power": -1},
+			wantIDs:    []string{"selkirk-spin", "joola-balanced", "engage-power"},
+			wantScores: []float64{100, 50, 0},
+		},
+		{
+			name:       "Equal weights tie",
+			weights:    map[string]float64{"power": 1, "spin": 1},
+			wantIDs:    []string{"engage-power", "joola-balanced", "selkirk-spin"},
+			wantScores: []float64{50, 50, 50},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranked := rankPaddles(paddles, tt.weights, bounds)
+			if got := ids(ranked); !reflect.DeepEqual(got, tt.wantIDs) {
+				t.Fatalf("ranking = %v, want %v", got, tt.wantIDs)
+			}
+			for i, r := range ranked {
+				if r.Rank != i+1 {
+					t.Errorf("%s rank = %d, want %d", r.ID, r.Rank, i+1)
+				}
+				if r.Score != tt.wantScores[i] {
+					t.Errorf("%s score = %v, want %v", r.ID, r.Score, tt.wantScores[i])
+				}
+			}
+		})
+	}
+
+	if ranked := rankPaddles(nil, map[string]float64{"power": 1}, bounds); len(ranked) != 0 {
+		t.Errorf("rankPaddles() of no paddles = %v, want empty", ranked)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// RawUpload is the exact JSON body a paddle was last uploaded with
+type RawUpload struct {
+	Body       []byte
+	UploadedAt time.Time
+}
+
+// insertRawUpload stores the upload body of the paddle whose paddles row is
+// paddleDBID, inside the transaction saving it. Paddles saved without a body,
+// such as bulk uploads and clones, record nothing.
+func insertRawUpload(ctx context.Context, tx *sql.Tx, paddleDBID int, body []byte) error {
+	if body == nil {
+		return nil
+	}
+	_, err := timedExec(ctx, tx, "insert_raw_upload",
+		"INSERT INTO paddle_raw_uploads (paddle_id, body) VALUES ($1, $2)", paddleDBID, body)
+	return err
+}
+
+// GetRawUpload returns the latest upload body of a paddle, or nil when none
+// was recorded. It returns ErrPaddleNotFound for an unknown paddle.
+func GetRawUpload(paddleId string) (*RawUpload, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	var body []byte
+	var uploadedAt sql.NullTime
+	err := timedQueryRow(ctx, DB, "get_raw_upload", `
+		SELECT u.body, u.uploaded_at
+		FROM
+			paddles p
+		LEFT JOIN
+			paddle_raw_uploads u ON u.paddle_id = p.id
+		WHERE
+			p.paddle_id = $1
+		ORDER BY
+			u.id DESC NULLS LAST
+		LIMIT 1
+	`, paddleId).Scan(&body, &uploadedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPaddleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		return nil, nil
+	}
+	return &RawUpload{Body: body, UploadedAt: uploadedAt.Time}, nil
+}
+
+// getPaddleSource handles the admin request for the exact body a paddle was
+// last uploaded with, for debugging decoding and normalization. The body is
+// written back byte for byte, with the upload time as Last-Modified.
+func getPaddleSource(w http.ResponseWriter, r *http.Request) {
+	paddleId := paddleIDFromRequest(r)
+	if err := validatePaddleID(paddleId); err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid paddle ID: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	upload, err := store.GetRawUpload(paddleId)
+	if errors.Is(err, ErrPaddleNotFound) {
+		respondWithError(w, "Paddle not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Error retrieving upload of paddle %s: %v", paddleId, err)
+		respondWithError(w, "Failed to retrieve paddle source", http.StatusInternalServerError)
+		return
+	}
+	if upload == nil {
+		respondWithError(w, "No upload recorded for this paddle", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Last-Modified", upload.UploadedAt.UTC().Format(http.TimeFormat))
+	if _, err := w.Write(upload.Body); err != nil {
+		log.Printf("Error writing paddle source: %v", err)
+	}
+}
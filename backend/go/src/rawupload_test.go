@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// TestGetPaddleSource tests that the source endpoint returns the submitted
+// body byte for byte, formatting included, and the latest one after an upsert
+func TestGetPaddleSource(t *testing.T) {
+	setupTestStore(t)
+	apiKey = "test-key"
+	defer func() { apiKey = "" }()
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/paddles", uploadPaddleStats).Methods("POST")
+	router.HandleFunc("/api/paddles/{id}/source", requireAPIKey(getPaddleSource)).Methods("GET")
+
+	serve := func(method, target, body string, admin bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+		if admin {
+			req.Header.Set(apiKeyHeader, apiKey)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// Unusual key order and spacing, which decoding and re-encoding would lose
+	submitted := `{
+  "performance": {"power": 75.0, "pop": 70.0, "spin": 3000.0,
+                  "twist_weight": 200.0, "swing_weight": 220.0, "balance_point": 30.0},
+  "specs": {"shape": "Hybrid", "surface": "Composite", "average_weight": 220.00,
+            "core": 15.0, "paddle_length": 16.5, "paddle_width": 7.5,
+            "grip_length": 4.5, "grip_type": "Comfort", "grip_circumference": 4.0},
+  "metadata": {"model": "Pursuit MX 6.0", "brand": "  Engage  "}
+}
+`
+	rr := serve("POST", "/api/paddles", submitted, false)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Upload returned %d: %s", rr.Code, rr.Body.String())
+	}
+	var created struct {
+		PaddleID string `json:"paddle_id"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &created); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if rr := serve("GET", "/api/paddles/"+created.PaddleID+"/source", "", false); rr.Code != http.StatusUnauthorized {
+		t.Errorf("Source without the API key returned %d, want %d", rr.Code, http.StatusUnauthorized)
+	}
+
+	rr = serve("GET", "/api/paddles/"+created.PaddleID+"/source", "", true)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Source returned %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr.Body.String() != submitted {
+		t.Errorf("Source = %q, want the submitted body %q", rr.Body.String(), submitted)
+	}
+	if rr.Header().Get("Last-Modified") == "" {
+		t.Error("Source should carry the upload time as Last-Modified")
+	}
+
+	// An upsert records its own body, which becomes the source
+	resubmitted := `{"metadata": {"brand": "Engage", "model": "Pursuit MX 6.0"}, "specs": {"shape": "Hybrid", "surface": "Composite", "average_weight": 221.0, "core": 15.0, "paddle_length": 16.5, "paddle_width": 7.5, "grip_length": 4.5, "grip_type": "Comfort", "grip_circumference": 4.0}, "performance": {"power": 76.0, "pop": 70.0, "spin": 3000.0, "twist_weight": 200.0, "swing_weight": 220.0, "balance_point": 30.0}}`
+	if rr := serve("POST", "/api/paddles?upsert=true", resubmitted, false); rr.Code != http.StatusOK {
+		t.Fatalf("Upsert returned %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr := serve("GET", "/api/paddles/"+created.PaddleID+"/source", "", true); rr.Body.String() != resubmitted {
+		t.Errorf("Source after upsert = %q, want %q", rr.Body.String(), resubmitted)
+	}
+
+	// Paddles saved without an upload body have no source
+	stub := (&PaddleInput{Metadata: Metadata{Brand: "Joola", Model: "Perseus"}}).ToPaddle()
+	if _, err := store.SavePaddle(stub); err != nil {
+		t.Fatalf("Failed to save paddle: %v", err)
+	}
+	if rr := serve("GET", "/api/paddles/"+stub.ID+"/source", "", true); rr.Code != http.StatusNotFound {
+		t.Errorf("Source of a paddle without an upload returned %d, want %d", rr.Code, http.StatusNotFound)
+	}
+	if rr := serve("GET", "/api/paddles/missing-paddle/source", "", true); rr.Code != http.StatusNotFound {
+		t.Errorf("Source of an unknown paddle returned %d, want %d", rr.Code, http.StatusNotFound)
+	}
+}
+
+// TestSavePaddleStoresRawUpload tests that the Postgres store saves the upload
+// body with the paddle and returns the latest one
+func TestSavePaddleStoresRawUpload(t *testing.T) {
+	setupTestDB(t)
+
+	paddle := testPaddleInput("Engage", "Pursuit MX 6.0").ToPaddle()
+	paddle.UploadBody = []byte("{\n  \"metadata\": {\"brand\": \"Engage\"}\n}\n")
+	if _, err := SavePaddle(paddle); err != nil {
+		t.Fatalf("Failed to save paddle: %v", err)
+	}
+
+	upload, err := GetRawUpload(paddle.ID)
+	if err != nil {
+		t.Fatalf("Failed to get raw upload: %v", err)
+	}
+	if upload == nil || !bytes.Equal(upload.Body, paddle.UploadBody) {
+		t.Fatalf("raw upload = %v, want %q", upload, paddle.UploadBody)
+	}
+
+	// Bodies that are not valid UTF-8 are kept byte for byte
+	invalid := testPaddleInput("Engage", "Pursuit EX 6.0").ToPaddle()
+	invalid.UploadBody = []byte("{\"metadata\": {\"model\": \"Pursuit \xff\"}}")
+	if _, err := SavePaddle(invalid); err != nil {
+		t.Fatalf("Failed to save paddle with an invalid UTF-8 body: %v", err)
+	}
+	if upload, err := GetRawUpload(invalid.ID); err != nil || upload == nil || !bytes.Equal(upload.Body, invalid.UploadBody) {
+		t.Errorf("raw upload = %v, %v, want %q", upload, err, invalid.UploadBody)
+	}
+
+	if _, err := GetRawUpload("missing-paddle"); err != ErrPaddleNotFound {
+		t.Errorf("GetRawUpload of an unknown paddle error = %v, want %v", err, ErrPaddleNotFound)
+	}
+}
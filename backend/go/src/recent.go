@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Defaults for the recent paddles window in days
+const (
+	defaultRecentDays = 30
+	maxRecentDays     = 365
+)
+
+// now returns the current time; tests replace it to pin the clock
+var now = time.Now
+
+// parseRecentDays reads the days parameter, defaulting to defaultRecentDays
+// and capping it at maxRecentDays
+func parseRecentDays(raw string) (int, error) {
+	if raw == "" {
+		return defaultRecentDays, nil
+	}
+
+	days, err := strconv.Atoi(raw)
+	if err != nil || days <= 0 {
+		return 0, fmt.Errorf("days must be a positive integer")
+	}
+	return min(days, maxRecentDays), nil
+}
+
+// GetRecentPaddles returns published paddles added in the last days days, newest first.
+// Paddles created in the same instant fall back to id order, newest first.
+// Each paddle is returned once, with its mean performance.
+func GetRecentPaddles(days, limit int) ([]*Paddle, error) {
+	since := now().AddDate(0, 0, -days)
+	return queryPaddleMeans("get_recent_paddles", `
+		WHERE 
+			p.created_at > $1
+			AND p.status = 'published'
+		ORDER BY 
+			p.created_at DESC, p.id DESC
+		LIMIT $2
+	`, since, limit)
+}
+
+// getRecentPaddles handles the API request for paddles added in the last N days
+func getRecentPaddles(w http.ResponseWriter, r *http.Request) {
+	days, err := parseRecentDays(r.URL.Query().Get("days"))
+	if err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid days: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	limit, _, err := parsePagination(r)
+	if err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid pagination: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	paddles, err := GetRecentPaddles(days, limit)
+	if err != nil {
+		log.Printf("Error retrieving recent paddles: %v", err)
+		respondWithError(w, "Failed to retrieve recent paddles", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(paddles); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
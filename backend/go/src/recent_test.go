@@ -0,0 +1,71 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParseRecentDays tests validating and capping the days parameter
+func TestParseRecentDays(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    int
+		wantErr bool
+	}{
+		{raw: "", want: defaultRecentDays},
+		{raw: "7", want: 7},
+		{raw: "10000", want: maxRecentDays},
+		{raw: "0", wantErr: true},
+		{raw: "-3", wantErr: true},
+		{raw: "week", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := parseRecentDays(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseRecentDays(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseRecentDays(%q) = %d, want %d", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestGetRecentPaddles tests the window and ordering with paddles added at different times
+func TestGetRecentPaddles(t *testing.T) {
+	setupTestDB(t)
+
+	// Pin the clock so the backdated ages are exact
+	clock := time.Date(2100, 1, 31, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	ages := map[string]int{"new": 1, "older": 5, "old": 40}
+	ids := map[string]string{}
+	for name, age := range ages {
+		paddle := testPaddleInput("Engage", "Recent Test "+name).ToPaddle()
+		if _, err := SavePaddle(paddle); err != nil {
+			t.Fatalf("Failed to save paddle: %v", err)
+		}
+		ids[name] = paddle.ID
+
+		createdAt := clock.AddDate(0, 0, -age)
+		if _, err := DB.Exec("UPDATE paddles SET created_at = $1 WHERE paddle_id = $2", createdAt, paddle.ID); err != nil {
+			t.Fatalf("Failed to backdate paddle: %v", err)
+		}
+	}
+	paddles, err := GetRecentPaddles(30, 10)
+	if err != nil {
+		t.Fatalf("GetRecentPaddles() returned error: %v", err)
+	}
+
+	if len(paddles) != 2 || paddles[0].ID != ids["new"] || paddles[1].ID != ids["older"] {
+		got := make([]string, len(paddles))
+		for i, p := range paddles {
+			got[i] = p.ID
+		}
+		t.Errorf("GetRecentPaddles(30) = %v, want [%s %s]", got, ids["new"], ids["older"])
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// defaultTolerancePercent is the band around each target when no tolerance is given
+const defaultTolerancePercent = 10.0
+
+// recommendationTarget is the accepted range for a single performance metric
+type recommendationTarget struct {
+	Metric string
+	Target float64
+	Min    float64
+	Max    float64
+}
+
+// parseRecommendTargets reads target_<metric> parameters and their tolerances.
+// tolerance is a percentage of each target (default 10); tolerance_<metric>
+// sets an absolute band for that metric instead. Unspecified targets are skipped.
+func parseRecommendTargets(query url.Values) ([]recommendationTarget, error) {
+	tolerancePercent := defaultTolerancePercent
+	if raw := query.Get("tolerance"); raw != "" {
+		var err error
+		tolerancePercent, err = parsePositive("tolerance", raw)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var targets []recommendationTarget
+	for _, metric := range performanceMetrics {
+		raw := query.Get("target_" + metric)
+		if raw == "" {
+			continue
+		}
+
+		target, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("target_%s must be a number", metric)
+		}
+
+		band := target * tolerancePercent / 100
+		if rawBand := query.Get("tolerance_" + metric); rawBand != "" {
+			band, err = parsePositive("tolerance_"+metric, rawBand)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		targets = append(targets, recommendationTarget{
+			Metric: metric,
+			Target: target,
+			Min:    target - band,
+			Max:    target + band,
+		})
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("at least one target is required: target_%s", strings.Join(performanceMetrics, ", target_"))
+	}
+
+	return targets, nil
+}
+
+// parsePositive parses a float that must be greater than zero
+func parsePositive(name, raw string) (float64, error) {
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("%s must be a positive number", name)
+	}
+	return value, nil
+}
+
+// buildRecommendWhere builds a parameterized WHERE clause requiring every
+// target metric to fall within its band
+func buildRecommendWhere(targets []recommendationTarget) (string, []interface{}) {
+	conditions := make([]string, 0, len(targets))
+	args := make([]interface{}, 0, len(targets)*2)
+
+	for _, target := range targets {
+		column := performanceMetricColumns[target.Metric]
+		args = append(args, target.Min, target.Max)
+		conditions = append(conditions, fmt.Sprintf("%s BETWEEN $%d AND $%d", column, len(args)-1, len(args)))
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// GetRecommendedPaddles returns published paddles whose mean metrics fall within every target band
+func GetRecommendedPaddles(targets []recommendationTarget, limit int) ([]*Paddle, error) {
+	where, args := buildRecommendWhere(targets)
+	args = append(args, limit)
+	return queryPaddleMeans("get_recommended_paddles",
+		fmt.Sprintf("%s AND p.status = 'published' ORDER BY p.id LIMIT $%d", where, len(args)), args...)
+}
+
+// getRecommendedPaddles handles the API request for paddles matching performance targets
+func getRecommendedPaddles(w http.ResponseWriter, r *http.Request) {
+	targets, err := parseRecommendTargets(r.URL.Query())
+	if err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid targets: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	limit, _, err := parsePagination(r)
+	if err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid pagination: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	paddles, err := GetRecommendedPaddles(targets, limit)
+	if err != nil {
+		log.Printf("Error retrieving recommended paddles: %v", err)
+		respondWithError(w, "Failed to retrieve recommended paddles", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(paddles); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
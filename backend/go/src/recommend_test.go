@@ -0,0 +1,109 @@
+package main
+
+import (
+	"net/url"
+	"testing"
+)
+
+// TestParseRecommendTargets tests reading targets and tolerances from the query
+func TestParseRecommendTargets(t *testing.T) {
+	query, _ := url.ParseQuery("target_power=80&target_spin=2800&tolerance=10&tolerance_spin=100")
+	targets, err := parseRecommendTargets(query)
+	if err != nil {
+		t.Fatalf("parseRecommendTargets() returned error: %v", err)
+	}
+
+	want := []recommendationTarget{
+		{Metric: "power", Target: 80, Min: 72, Max: 88},
+		{Metric: "spin", Target: 2800, Min: 2700, Max: 2900},
+	}
+	if len(targets) != len(want) {
+		t.Fatalf("parseRecommendTargets() returned %d targets, want %d", len(targets), len(want))
+	}
+	for i := range want {
+		if targets[i] != want[i] {
+			t.Errorf("target %d = %+v, want %+v", i, targets[i], want[i])
+		}
+	}
+}
+
+// TestParseRecommendTargetsInvalid tests rejection of bad targets and tolerances
+func TestParseRecommendTargetsInvalid(t *testing.T) {
+	for _, raw := range []string{
+		"",
+		"tolerance=10",
+		"target_power=high",
+		"target_power=80&tolerance=0",
+		"target_power=80&tolerance=-5",
+		"target_power=80&tolerance_power=-1",
+	} {
+		query, _ := url.ParseQuery(raw)
+		if _, err := parseRecommendTargets(query); err == nil {
+			t.Errorf("parseRecommendTargets(%q) should fail", raw)
+		}
+	}
+}
+
+// TestBuildRecommendWhere tests that only provided targets are included and values are parameterized
+func TestBuildRecommendWhere(t *testing.T) {
+	where, args := buildRecommendWhere([]recommendationTarget{
+		{Metric: "power", Min: 72, Max: 88},
+		{Metric: "spin", Min: 2700, Max: 2900},
+	})
+
+	wantWhere := "WHERE perf.power BETWEEN $1 AND $2 AND perf.spin BETWEEN $3 AND $4"
+	if where != wantWhere {
+		t.Errorf("buildRecommendWhere() = %q, want %q", where, wantWhere)
+	}
+	if len(args) != 4 || args[0] != 72.0 || args[3] != 2900.0 {
+		t.Errorf("buildRecommendWhere() args = %v", args)
+	}
+}
+
+// TestGetRecommendedPaddles tests recommendations against a seeded set
+func TestGetRecommendedPaddles(t *testing.T) {
+	setupTestDB(t)
+
+	seed := map[string]Performance{
+		"match":      {Power: 81, Pop: 60, Spin: 2810, TwistWeight: 6, SwingWeight: 115, BalancePoint: 23},
+		"low-power":  {Power: 40, Pop: 60, Spin: 2800, TwistWeight: 6, SwingWeight: 115, BalancePoint: 23},
+		"low-spin":   {Power: 80, Pop: 60, Spin: 1500, TwistWeight: 6, SwingWeight: 115, BalancePoint: 23},
+		"edge-match": {Power: 88, Pop: 60, Spin: 2520, TwistWeight: 6, SwingWeight: 115, BalancePoint: 23},
+	}
+	for name, perf := range seed {
+		paddle := &Paddle{
+			ID:       "recommend-" + name,
+			Metadata: Metadata{Brand: "Recommend", Model: name},
+			Specs: Specs{
+				Shape: Hybrid, Surface: "Carbon", AverageWeight: 220, Core: 16, PaddleLength: 16.5,
+				PaddleWidth: 7.5, GripLength: 5.25, GripType: "Standard", GripCircumference: 4.25,
+			},
+			Performance: perf,
+		}
+		if _, err := SavePaddle(paddle); err != nil {
+			t.Fatalf("Failed to save paddle: %v", err)
+		}
+	}
+
+	query, _ := url.ParseQuery("target_power=80&target_spin=2800&tolerance=10")
+	targets, err := parseRecommendTargets(query)
+	if err != nil {
+		t.Fatalf("parseRecommendTargets() returned error: %v", err)
+	}
+
+	paddles, err := GetRecommendedPaddles(targets, maxPageSize)
+	if err != nil {
+		t.Fatalf("GetRecommendedPaddles() returned error: %v", err)
+	}
+
+	found := map[string]bool{}
+	for _, p := range paddles {
+		found[p.Metadata.Model] = true
+	}
+
+	for name, want := range map[string]bool{"match": true, "edge-match": true, "low-power": false, "low-spin": false} {
+		if found[name] != want {
+			t.Errorf("Paddle %q recommended = %v, want %v", name, found[name], want)
+		}
+	}
+}
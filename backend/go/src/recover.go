@@ -0,0 +1,79 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"runtime/debug"
+)
+
+// requestIDHeader carries the id that ties a client's request to the server logs
+const requestIDHeader = "X-Request-ID"
+
+// requestID returns the id the client sent in X-Request-ID, or a new random one
+func requestID(r *http.Request) string {
+	if id := r.Header.Get(requestIDHeader); id != "" {
+		return id
+	}
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// recoverPanics turns a panicking handler into a 500 for that request alone,
+// instead of crashing the server. The stack trace is logged with the request
+// id, which is also returned in X-Request-ID, and the client only sees the
+// standard error body.
+func recoverPanics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := requestID(r)
+		w.Header().Set(requestIDHeader, id)
+		pw := &panicWriter{ResponseWriter: w}
+
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// ErrAbortHandler deliberately aborts the response; let net/http handle it
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			log.Printf("panic serving %s %s (request %s): %v\n%s", r.Method, r.URL.Path, id, recovered, debug.Stack())
+			if pw.wroteHeader {
+				// The status is already on the wire, so the client just gets a cut-off response
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			respondWithError(w, "An unexpected error occurred; quote request "+id+" when reporting it", http.StatusInternalServerError)
+		}()
+
+		next.ServeHTTP(pw, r)
+	})
+}
+
+// panicWriter records whether the response has started, so recoverPanics
+// knows if it can still send an error
+type panicWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (pw *panicWriter) WriteHeader(status int) {
+	pw.wroteHeader = true
+	pw.ResponseWriter.WriteHeader(status)
+}
+
+func (pw *panicWriter) Write(p []byte) (int, error) {
+	pw.wroteHeader = true
+	return pw.ResponseWriter.Write(p)
+}
+
+// Flush passes flushes through so streaming handlers keep working
+func (pw *panicWriter) Flush() {
+	if flusher, ok := pw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
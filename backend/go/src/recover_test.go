@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestRecoverPanics tests that a panicking handler gets a 500 with the standard
+// error body and the server keeps serving later requests
+func TestRecoverPanics(t *testing.T) {
+	buf := captureLog(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/panic", func(w http.ResponseWriter, r *http.Request) {
+		var paddle *Paddle
+		w.Write([]byte(paddle.ID))
+	})
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+	server := httptest.NewServer(recoverPanics(mux))
+	defer server.Close()
+
+	req, _ := http.NewRequest("GET", server.URL+"/panic", nil)
+	req.Header.Set(requestIDHeader, "req-123")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request to panicking handler failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusInternalServerError)
+	}
+	if got := resp.Header.Get(requestIDHeader); got != "req-123" {
+		t.Errorf("%s = %q, want %q", requestIDHeader, got, "req-123")
+	}
+
+	var body errorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode error body: %v", err)
+	}
+	if body.Code != http.StatusInternalServerError || !strings.Contains(body.Message, "req-123") {
+		t.Errorf("error body = %+v, want code 500 quoting the request id", body)
+	}
+	if strings.Contains(body.Message, "nil pointer") {
+		t.Errorf("error body leaks the panic: %q", body.Message)
+	}
+
+	logged := buf.String()
+	if !strings.Contains(logged, "request req-123") || !strings.Contains(logged, "nil pointer dereference") || !strings.Contains(logged, "goroutine") {
+		t.Errorf("Expected the panic, request id and stack trace in the log, got %q", logged)
+	}
+
+	// The server must still be serving
+	resp, err = http.Get(server.URL + "/ok")
+	if err != nil {
+		t.Fatalf("Request after the panic failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status after the panic = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if resp.Header.Get(requestIDHeader) == "" {
+		t.Errorf("Expected a generated %s header", requestIDHeader)
+	}
+}
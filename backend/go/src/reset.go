@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// resetTables lists every table holding paddle data, children before the
+// paddles they reference. Truncating them together also resets their ids.
+var resetTables = []string{
+	"paddle_performance", "paddle_spec_ranges", "paddle_specs", "paddle_history",
+	"paddle_tags", "paddle_raw_uploads", "paddle_tombstones", "outbox", "webhooks", "featured", "paddles",
+}
+
+// resetAllowed reports whether POST /api/admin/reset may clear the data.
+// It is only set for test environments, ENV=test or ALLOW_RESET=true, and
+// never when ENV=production.
+var resetAllowed bool
+
+// loadResetAllowed reads ENV and ALLOW_RESET. Asking for resets in production is a
+// configuration error rather than something to quietly ignore.
+func loadResetAllowed() (bool, error) {
+	env := getEnv("ENV", "")
+	allow, err := strconv.ParseBool(getEnv("ALLOW_RESET", "false"))
+	if err != nil {
+		return false, fmt.Errorf("ALLOW_RESET must be true or false")
+	}
+
+	if env == "production" {
+		if allow {
+			return false, fmt.Errorf("ALLOW_RESET cannot be enabled when ENV=production")
+		}
+		return false, nil
+	}
+	return env == "test" || allow, nil
+}
+
+// ResetData truncates every paddle table and restarts their ids at 1
+func ResetData() error {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	query := "TRUNCATE TABLE " + strings.Join(resetTables, ", ") + " RESTART IDENTITY"
+	_, err := timedExec(ctx, DB, "reset_data", query)
+	return err
+}
+
+// resetData handles the admin request for clearing all paddle data, so CI
+// and local runs start from a clean slate
+func resetData(w http.ResponseWriter, r *http.Request) {
+	if !resetAllowed {
+		respondWithError(w, "Reset is disabled; set ENV=test or ALLOW_RESET=true outside production", http.StatusForbidden)
+		return
+	}
+
+	if err := store.ResetData(); err != nil {
+		log.Printf("Error resetting data: %v", err)
+		respondWithError(w, "Failed to reset data", http.StatusInternalServerError)
+		return
+	}
+	log.Printf("All paddle data was reset")
+
+	if _, err := refreshDatasetStats(); err != nil {
+		log.Printf("Error refreshing dataset stats after reset: %v", err)
+	}
+
+	if err := json.NewEncoder(w).Encode(map[string]string{"status": "reset"}); err != nil {
+		log.Printf("Error encoding reset response: %v", err)
+	}
+}
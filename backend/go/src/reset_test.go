@@ -0,0 +1,99 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestLoadResetAllowed tests which environments allow resetting the data
+func TestLoadResetAllowed(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		allow   string
+		want    bool
+		wantErr bool
+	}{
+		{name: "Unset", want: false},
+		{name: "Test environment", env: "test", want: true},
+		{name: "Flag", env: "development", allow: "true", want: true},
+		{name: "Flag off", env: "development", allow: "false", want: false},
+		{name: "Production", env: "production", want: false},
+		{name: "Production with flag", env: "production", allow: "true", wantErr: true},
+		{name: "Invalid flag", allow: "yes please", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ENV", tt.env)
+			t.Setenv("ALLOW_RESET", tt.allow)
+			allowed, err := loadResetAllowed()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("loadResetAllowed() accepted ENV=%q ALLOW_RESET=%q", tt.env, tt.allow)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadResetAllowed() error: %v", err)
+			}
+			if allowed != tt.want {
+				t.Errorf("loadResetAllowed() = %v, want %v", allowed, tt.want)
+			}
+		})
+	}
+}
+
+// TestResetData tests that a reset empties the list and restarts the ids,
+// and that nothing is cleared while resets are disabled
+func TestResetData(t *testing.T) {
+	setupTestStore(t)
+	defer func() { resetAllowed = false }()
+
+	save := func() int {
+		t.Helper()
+		paddle := testPaddleInput("Engage", "Pursuit MX 6.0").ToPaddle()
+		id, err := store.SavePaddle(paddle)
+		if err != nil {
+			t.Fatalf("SavePaddle() error: %v", err)
+		}
+		return id
+	}
+	list := func() string {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		getPaddlesList(rr, httptest.NewRequest("GET", "/api/paddles", nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("List returned %d: %s", rr.Code, rr.Body.String())
+		}
+		return strings.TrimSpace(rr.Body.String())
+	}
+	reset := func() int {
+		rr := httptest.NewRecorder()
+		resetData(rr, httptest.NewRequest("POST", "/api/admin/reset", nil))
+		return rr.Code
+	}
+
+	save()
+
+	resetAllowed = false
+	if code := reset(); code != http.StatusForbidden {
+		t.Errorf("Disabled reset returned %d, want %d", code, http.StatusForbidden)
+	}
+	if list() == "[]" {
+		t.Fatal("Disabled reset cleared the paddles")
+	}
+
+	resetAllowed = true
+	if code := reset(); code != http.StatusOK {
+		t.Fatalf("Reset returned %d, want %d", code, http.StatusOK)
+	}
+	if got := list(); got != "[]" {
+		t.Errorf("List after reset = %s, want []", got)
+	}
+	if id := save(); id != 1 {
+		t.Errorf("First id after reset = %d, want 1", id)
+	}
+}
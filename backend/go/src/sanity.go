@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// popPowerMode controls what happens when pop and power disagree
+type popPowerMode string
+
+const (
+	popPowerOff    popPowerMode = "off"
+	popPowerWarn   popPowerMode = "warn"
+	popPowerReject popPowerMode = "reject"
+)
+
+// defaultPopPowerMaxGap is the largest |power - pop| accepted without complaint,
+// overridable via POP_POWER_MAX_GAP
+const defaultPopPowerMaxGap = 40.0
+
+var (
+	// popPowerCheck is set via POP_POWER_CHECK and is off by default to avoid false positives
+	popPowerCheck  = popPowerOff
+	popPowerMaxGap = defaultPopPowerMaxGap
+)
+
+// popPowerConfig holds the pop/power sanity check settings
+type popPowerConfig struct {
+	Mode   popPowerMode
+	MaxGap float64
+}
+
+// loadPopPowerConfig reads the pop/power sanity check settings from the environment
+func loadPopPowerConfig() (popPowerConfig, error) {
+	mode := popPowerMode(getEnv("POP_POWER_CHECK", string(popPowerOff)))
+	switch mode {
+	case popPowerOff, popPowerWarn, popPowerReject:
+	default:
+		return popPowerConfig{}, fmt.Errorf("POP_POWER_CHECK must be one of %s, %s, %s", popPowerOff, popPowerWarn, popPowerReject)
+	}
+
+	maxGap, err := strconv.ParseFloat(getEnv("POP_POWER_MAX_GAP", strconv.FormatFloat(defaultPopPowerMaxGap, 'f', -1, 64)), 64)
+	if err != nil || maxGap <= 0 {
+		return popPowerConfig{}, fmt.Errorf("POP_POWER_MAX_GAP must be a positive number")
+	}
+
+	return popPowerConfig{Mode: mode, MaxGap: maxGap}, nil
+}
+
+// checkPopPower flags performance whose pop and power differ by more than
+// popPowerMaxGap, which usually means a data entry error. In warn mode the
+// problem is returned as a warning; in reject mode it is returned as an error.
+func checkPopPower(performance *Performance) (warnings []string, err error) {
+	if popPowerCheck == popPowerOff {
+		return nil, nil
+	}
+
+	gap := math.Abs(performance.Power - performance.Pop)
+	if gap <= popPowerMaxGap {
+		return nil, nil
+	}
+
+	err = newValidationError("POP_POWER_GAP", "power (%g) and pop (%g) differ by %g, more than the expected %g", performance.Power, performance.Pop, gap, popPowerMaxGap)
+	if popPowerCheck == popPowerReject {
+		return nil, err
+	}
+	return []string{err.Error()}, nil
+}
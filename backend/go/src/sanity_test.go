@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// TestCheckPopPower tests the pop/power sanity check in each mode
+func TestCheckPopPower(t *testing.T) {
+	defer func() { popPowerCheck, popPowerMaxGap = popPowerOff, defaultPopPowerMaxGap }()
+
+	consistent := &Performance{Power: 75, Pop: 70}
+	inconsistent := &Performance{Power: 95, Pop: 5}
+
+	tests := []struct {
+		name         string
+		mode         popPowerMode
+		performance  *Performance
+		wantWarnings int
+		wantErr      bool
+	}{
+		{name: "Off ignores a large gap", mode: popPowerOff, performance: inconsistent},
+		{name: "Warn accepts a small gap", mode: popPowerWarn, performance: consistent},
+		{name: "Warn flags a large gap", mode: popPowerWarn, performance: inconsistent, wantWarnings: 1},
+		{name: "Reject accepts a small gap", mode: popPowerReject, performance: consistent},
+		{name: "Reject fails a large gap", mode: popPowerReject, performance: inconsistent, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			popPowerCheck = tt.mode
+			warnings, err := checkPopPower(tt.performance)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkPopPower() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(warnings) != tt.wantWarnings {
+				t.Errorf("checkPopPower() warnings = %v, want %d", warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+// TestLoadPopPowerConfig tests loading the sanity check settings from the environment
+func TestLoadPopPowerConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		maxGap  string
+		wantErr bool
+	}{
+		{name: "Defaults"},
+		{name: "Warn with custom gap", mode: "warn", maxGap: "30"},
+		{name: "Reject", mode: "reject"},
+		{name: "Unknown mode", mode: "strict", wantErr: true},
+		{name: "Zero gap", maxGap: "0", wantErr: true},
+		{name: "Non-numeric gap", maxGap: "wide", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("POP_POWER_CHECK", tt.mode)
+			t.Setenv("POP_POWER_MAX_GAP", tt.maxGap)
+
+			if _, err := loadPopPowerConfig(); (err != nil) != tt.wantErr {
+				t.Errorf("loadPopPowerConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestPopPowerCheckBulk tests that bulk items go through the pop/power check
+// like single uploads
+func TestPopPowerCheckBulk(t *testing.T) {
+	setupTestStore(t)
+	defer func() { popPowerCheck, popPowerMaxGap = popPowerOff, defaultPopPowerMaxGap }()
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/paddles/bulk", bulkUploadPaddles).Methods("POST")
+
+	gapped := testPaddleInput("Engage", "Gapped")
+	gapped.Performance.Pop = 20
+	body, _ := json.Marshal([]*PaddleInput{gapped})
+	upload := func() *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/paddles/bulk", bytes.NewBuffer(body)))
+		return rr
+	}
+
+	popPowerCheck = popPowerReject
+	if rr := upload(); rr.Code != http.StatusBadRequest {
+		t.Errorf("bulk upload in reject mode returned %d, want %d: %s", rr.Code, http.StatusBadRequest, rr.Body.String())
+	}
+
+	popPowerCheck = popPowerWarn
+	rr := upload()
+	var result BulkResult
+	if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Failed to decode bulk result: %v", err)
+	}
+	if rr.Code != http.StatusCreated || len(result.Results) != 1 || len(result.Results[0].Warnings) != 1 {
+		t.Errorf("bulk upload in warn mode returned %d %+v, want 201 with one warning", rr.Code, result.Results)
+	}
+}
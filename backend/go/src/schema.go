@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// FieldSchema describes one PaddleInput field so clients can render and
+// validate forms without duplicating the server's rules
+type FieldSchema struct {
+	Name         string   `json:"name"`
+	Type         string   `json:"type"`
+	Unit         string   `json:"unit,omitempty"`
+	Required     bool     `json:"required"`
+	Min          *float64 `json:"min,omitempty"`
+	Max          *float64 `json:"max,omitempty"`
+	ExclusiveMin bool     `json:"exclusive_min,omitempty"`
+	MaxLength    int      `json:"max_length,omitempty"`
+	Format       string   `json:"format,omitempty"`
+	Enum         []string `json:"enum,omitempty"`
+}
+
+// bound returns a pointer for the optional Min and Max fields
+func bound(v float64) *float64 {
+	return &v
+}
+
+// positive describes a required number that must be greater than 0
+func positive(name, unit string) FieldSchema {
+	return FieldSchema{Name: name, Type: "number", Unit: unit, Required: true, Min: bound(0), ExclusiveMin: true}
+}
+
+// paddleSchema describes every field of an upload under the full validation
+// profile. The limits come from the same constants the validators use.
+func paddleSchema() []FieldSchema {
+	shapes := make([]string, len(paddleShapes))
+	for i, shape := range paddleShapes {
+		shapes[i] = string(shape)
+	}
+
+	fields := []FieldSchema{
+		{Name: "metadata.brand", Type: "string", Required: true},
+		{Name: "metadata.model", Type: "string", Required: true},
+		{Name: "metadata.year", Type: "integer", Min: bound(minPaddleYear), Max: bound(float64(maxPaddleYear()))},
+		{Name: "metadata.sku", Type: "string", MaxLength: maxSKULength},
+		{Name: "metadata.product_url", Type: "string", Format: "url"},
+		{Name: "metadata.price", Type: "number", Unit: "USD", Min: bound(0), ExclusiveMin: true},
+
+		{Name: "specs.shape", Type: "string", Required: true, Enum: shapes},
+		{Name: "specs.surface", Type: "string", Required: true},
+		positive("specs.average_weight", "g"),
+		{Name: "specs.core", Type: "number", Unit: coreUnitMM, Required: true, Min: bound(minCoreMM), Max: bound(maxCoreMM)},
+		positive("specs.paddle_length", "in"),
+		positive("specs.paddle_width", "in"),
+		positive("specs.grip_length", "in"),
+		{Name: "specs.grip_type", Type: "string", Required: true},
+		positive("specs.grip_circumference", "in"),
+		{Name: "specs.edge_guard", Type: "string", Enum: paddleEdgeGuards},
+		{Name: "specs.handle_type", Type: "string", Enum: paddleHandleTypes},
+		{Name: "specs.surface_front", Type: "string", Enum: paddleSurfaces},
+		{Name: "specs.surface_back", Type: "string", Enum: paddleSurfaces},
+
+		{Name: "performance.power", Type: "number", Required: true, Min: bound(minRating), Max: bound(maxRating)},
+		{Name: "performance.pop", Type: "number", Required: true, Min: bound(minRating), Max: bound(maxRating)},
+		{Name: "performance.spin", Type: "number", Unit: "rpm", Required: true, Min: bound(0)},
+		positive("performance.twist_weight", ""),
+		positive("performance.swing_weight", ""),
+		positive("performance.balance_point", ""),
+	}
+
+	// Optional standard deviations, in the unit of their metric
+	for _, metric := range performanceMetrics {
+		unit := ""
+		if metric == "spin" {
+			unit = "rpm"
+		}
+		fields = append(fields, FieldSchema{Name: "performance." + metric + "_stddev", Type: "number", Unit: unit, Min: bound(0)})
+	}
+	return fields
+}
+
+// getSchema handles the API request for the upload field schema
+func getSchema(w http.ResponseWriter, r *http.Request) {
+	response := struct {
+		Fields []FieldSchema `json:"fields"`
+	}{
+		Fields: paddleSchema(),
+	}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// TestGetSchema tests that the schema lists the shape and edge guard enum values
+func TestGetSchema(t *testing.T) {
+	rr := httptest.NewRecorder()
+	getSchema(rr, httptest.NewRequest("GET", "/api/schema", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusOK)
+	}
+
+	var response struct {
+		Fields []FieldSchema `json:"fields"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	i := slices.IndexFunc(response.Fields, func(f FieldSchema) bool { return f.Name == "specs.shape" })
+	if i < 0 {
+		t.Fatal("schema has no specs.shape field")
+	}
+	want := []string{"Elongated", "Hybrid", "Wide-body"}
+	if !slices.Equal(response.Fields[i].Enum, want) {
+		t.Errorf("specs.shape enum = %v, want %v", response.Fields[i].Enum, want)
+	}
+
+	i = slices.IndexFunc(response.Fields, func(f FieldSchema) bool { return f.Name == "specs.edge_guard" })
+	if i < 0 || response.Fields[i].Required || !slices.Equal(response.Fields[i].Enum, paddleEdgeGuards) {
+		t.Errorf("schema has no optional specs.edge_guard field with enum %v", paddleEdgeGuards)
+	}
+}
+
+// TestPaddleSchemaMatchesModel tests that every schema field is a real field of a paddle
+func TestPaddleSchemaMatchesModel(t *testing.T) {
+	year := 2024
+	paddle := &Paddle{
+		Metadata: Metadata{Year: &year, SKU: "EN-PMX6", ProductURL: "https://example.com", Price: bound(199.99)},
+		Specs:    Specs{EdgeGuard: "Standard", HandleType: "Hollow", SurfaceFront: "Raw Carbon", SurfaceBack: "Fiberglass"},
+	}
+	for _, stddev := range paddle.Performance.stddevs() {
+		*stddev = bound(1)
+	}
+	flat, err := flattenPaddle(paddle)
+	if err != nil {
+		t.Fatalf("flattenPaddle() error: %v", err)
+	}
+
+	for _, field := range paddleSchema() {
+		if _, ok := flat[field.Name]; !ok {
+			t.Errorf("schema field %q is not a paddle field", field.Name)
+		}
+	}
+}
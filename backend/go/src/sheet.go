@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// specSheetRow is one labelled line of a spec sheet table
+type specSheetRow struct {
+	Label string
+	Value string
+}
+
+// formatSheetNumber drops trailing zeros so 16.50 prints as 16.5
+func formatSheetNumber(v float64, unit string) string {
+	s := strconv.FormatFloat(v, 'f', -1, 64)
+	if unit != "" {
+		s += " " + unit
+	}
+	return s
+}
+
+// specSheetSpecs lists the specs rows, with any quoted range after the measured value
+func specSheetSpecs(p *Paddle) []specSheetRow {
+	measured := func(label, field string, v float64, unit string) specSheetRow {
+		value := formatSheetNumber(v, unit)
+		if r, ok := p.SpecRanges[field]; ok {
+			value += fmt.Sprintf(" (spec %s–%s)", formatSheetNumber(r.Min, ""), formatSheetNumber(r.Max, unit))
+		}
+		return specSheetRow{Label: label, Value: value}
+	}
+
+	rows := []specSheetRow{
+		{Label: "Shape", Value: string(p.Specs.Shape)},
+		{Label: "Surface", Value: p.Specs.Surface},
+		measured("Average weight", "average_weight", p.Specs.AverageWeight, "g"),
+		measured("Core thickness", "core", p.Specs.Core, coreUnitMM),
+		measured("Paddle length", "paddle_length", p.Specs.PaddleLength, "in"),
+		measured("Paddle width", "paddle_width", p.Specs.PaddleWidth, "in"),
+		measured("Grip length", "grip_length", p.Specs.GripLength, "in"),
+		{Label: "Grip type", Value: p.Specs.GripType},
+		measured("Grip circumference", "grip_circumference", p.Specs.GripCircumference, "in"),
+	}
+
+	// Construction details are optional, so only print the ones we know
+	if p.Specs.SurfaceFront != p.Specs.SurfaceBack {
+		rows = append(rows, specSheetRow{Label: "Surface (front / back)", Value: p.Specs.SurfaceFront + " / " + p.Specs.SurfaceBack})
+	}
+	if p.Specs.EdgeGuard != "" {
+		rows = append(rows, specSheetRow{Label: "Edge guard", Value: p.Specs.EdgeGuard})
+	}
+	if p.Specs.HandleType != "" {
+		rows = append(rows, specSheetRow{Label: "Handle", Value: p.Specs.HandleType})
+	}
+	return rows
+}
+
+// specSheetPerformance lists the performance rows, ending with the derived control rating
+func specSheetPerformance(p *Paddle) []specSheetRow {
+	return []specSheetRow{
+		{Label: "Power", Value: formatSheetNumber(p.Performance.Power, "")},
+		{Label: "Pop", Value: formatSheetNumber(p.Performance.Pop, "")},
+		{Label: "Spin", Value: formatSheetNumber(p.Performance.Spin, "rpm")},
+		{Label: "Twist weight", Value: formatSheetNumber(p.Performance.TwistWeight, "")},
+		{Label: "Swing weight", Value: formatSheetNumber(p.Performance.SwingWeight, "")},
+		{Label: "Balance point", Value: formatSheetNumber(p.Performance.BalancePoint, "")},
+		{Label: "Control", Value: formatSheetNumber(p.Control, "")},
+	}
+}
+
+// renderSpecSheet lays out a one-page printable spec sheet for a paddle
+func renderSpecSheet(p *Paddle) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "Letter", "")
+	pdf.SetTitle(p.Metadata.Brand+" "+p.Metadata.Model, true)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddPage()
+
+	// The core fonts are cp1252, so translate UTF-8 text such as en dashes
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetFont("Helvetica", "B", 22)
+	pdf.CellFormat(0, 12, tr(p.Metadata.Brand+" "+p.Metadata.Model), "", 1, "L", false, 0, "")
+
+	pdf.SetFont("Helvetica", "", 10)
+	details := []string{"ID: " + p.ID}
+	if p.Metadata.Year != nil {
+		details = append(details, fmt.Sprintf("Year: %d", *p.Metadata.Year))
+	}
+	if p.Metadata.SKU != "" {
+		details = append(details, "SKU: "+p.Metadata.SKU)
+	}
+	pdf.CellFormat(0, 6, tr(strings.Join(details, "    ")), "", 1, "L", false, 0, "")
+	if p.Metadata.ProductURL != "" {
+		pdf.CellFormat(0, 6, tr(p.Metadata.ProductURL), "", 1, "L", false, 0, p.Metadata.ProductURL)
+	}
+
+	table := func(title string, rows []specSheetRow) {
+		pdf.Ln(6)
+		pdf.SetFont("Helvetica", "B", 14)
+		pdf.CellFormat(0, 9, title, "B", 1, "L", false, 0, "")
+		for i, row := range rows {
+			pdf.SetFillColor(240, 240, 240)
+			fill := i%2 == 0
+			pdf.SetFont("Helvetica", "B", 11)
+			pdf.CellFormat(60, 8, tr(row.Label), "", 0, "L", fill, 0, "")
+			pdf.SetFont("Helvetica", "", 11)
+			pdf.CellFormat(0, 8, tr(row.Value), "", 1, "L", fill, 0, "")
+		}
+	}
+	table("Specs", specSheetSpecs(p))
+	table("Performance", specSheetPerformance(p))
+
+	if p.PerformanceSamples > 1 {
+		pdf.Ln(2)
+		pdf.SetFont("Helvetica", "I", 9)
+		pdf.CellFormat(0, 5, fmt.Sprintf("Performance is the mean of %d measurements.", p.PerformanceSamples), "", 1, "L", false, 0, "")
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// specSheetFilename derives a download filename from a paddle ID, keeping
+// only characters that are safe in a Content-Disposition header
+func specSheetFilename(paddleId string) string {
+	safe := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '-'
+	}, paddleId)
+	return safe + "-spec-sheet.pdf"
+}
+
+// getPaddleSpecSheet handles the API request for a printable PDF spec sheet
+func getPaddleSpecSheet(w http.ResponseWriter, r *http.Request) {
+	paddleId := paddleIDFromRequest(r)
+	if err := validatePaddleID(paddleId); err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid paddle ID: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	paddle, err := store.GetPaddleByID(paddleId)
+	if errors.Is(err, ErrPaddleNotFound) {
+		respondWithError(w, "Paddle not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Error retrieving paddle for spec sheet: %v", err)
+		respondWithError(w, "Failed to retrieve paddle data", http.StatusInternalServerError)
+		return
+	}
+
+	// Print the same averaged performance and quoted ranges as the details endpoint
+	agg, err := store.GetAggregatedPerformance(paddleId)
+	if err != nil {
+		log.Printf("Error aggregating paddle performance for spec sheet: %v", err)
+		respondWithError(w, "Failed to retrieve paddle performance", http.StatusInternalServerError)
+		return
+	}
+	paddle.Performance = agg.Performance
+	paddle.PerformanceSamples = agg.SampleCount
+	paddle.Control = paddle.ControlRating()
+
+	paddle.SpecRanges, err = store.GetSpecRanges(paddleId)
+	if err != nil {
+		log.Printf("Error retrieving spec ranges for spec sheet: %v", err)
+		respondWithError(w, "Failed to retrieve paddle spec ranges", http.StatusInternalServerError)
+		return
+	}
+
+	pdf, err := renderSpecSheet(paddle)
+	if err != nil {
+		log.Printf("Error rendering spec sheet: %v", err)
+		respondWithError(w, "Failed to render spec sheet", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", specSheetFilename(paddle.ID)))
+	w.Write(pdf)
+}
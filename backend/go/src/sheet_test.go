@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// TestRenderSpecSheet tests that the spec sheet is a non-empty PDF
+func TestRenderSpecSheet(t *testing.T) {
+	year := 2024
+	paddle := &Paddle{
+		ID:       "engage-pursuit-mx-6.0",
+		Metadata: Metadata{Brand: "Engage", Model: "Pursuit MX 6.0", Year: &year, SKU: "EN-PMX6", ProductURL: "https://example.com/pursuit"},
+		Specs: Specs{
+			Shape: Hybrid, Surface: "Raw Carbon", AverageWeight: 220.0, Core: 16.0,
+			PaddleLength: 16.5, PaddleWidth: 7.5, GripLength: 5.25, GripType: "Comfort", GripCircumference: 4.25,
+		},
+		Performance: Performance{Power: 75.0, Pop: 70.0, Spin: 2400.0, TwistWeight: 6.5, SwingWeight: 115.0, BalancePoint: 23.0},
+		SpecRanges:  map[string]SpecRange{"average_weight": {Min: 218, Max: 222}},
+	}
+
+	pdf, err := renderSpecSheet(paddle)
+	if err != nil {
+		t.Fatalf("renderSpecSheet() error: %v", err)
+	}
+	if !bytes.HasPrefix(pdf, []byte("%PDF-")) {
+		t.Errorf("renderSpecSheet() output starts with %q, want a PDF header", pdf[:min(len(pdf), 8)])
+	}
+	if !bytes.Contains(pdf, []byte("%%EOF")) {
+		t.Error("renderSpecSheet() output has no EOF trailer")
+	}
+}
+
+// TestSpecSheetFilename tests that download filenames keep only header-safe characters
+func TestSpecSheetFilename(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{id: "engage-pursuit-mx-6.0", want: "engage-pursuit-mx-6.0-spec-sheet.pdf"},
+		{id: `selkirk "vanguard"`, want: "selkirk--vanguard--spec-sheet.pdf"},
+	}
+
+	for _, tt := range tests {
+		if got := specSheetFilename(tt.id); got != tt.want {
+			t.Errorf("specSheetFilename(%q) = %q, want %q", tt.id, got, tt.want)
+		}
+	}
+}
+
+// TestGetPaddleSpecSheet tests the PDF response headers and the 404 for unknown paddles
+func TestGetPaddleSpecSheet(t *testing.T) {
+	setupTestStore(t)
+
+	paddle := &Paddle{
+		ID:          "engage-pursuit-mx-6.0",
+		Metadata:    Metadata{Brand: "Engage", Model: "Pursuit MX 6.0"},
+		Specs:       testSpecs,
+		Performance: testPerformance,
+	}
+	if _, err := store.SavePaddle(paddle); err != nil {
+		t.Fatalf("SavePaddle() error: %v", err)
+	}
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/paddles/{id}/sheet.pdf", withCommonHeaders(getPaddleSpecSheet)).Methods("GET")
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/paddles/engage-pursuit-mx-6.0/sheet.pdf", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	if got := rr.Header().Get("Content-Type"); got != "application/pdf" {
+		t.Errorf("Content-Type = %q, want application/pdf", got)
+	}
+	if got, want := rr.Header().Get("Content-Disposition"), `attachment; filename="engage-pursuit-mx-6.0-spec-sheet.pdf"`; got != want {
+		t.Errorf("Content-Disposition = %q, want %q", got, want)
+	}
+	if !bytes.HasPrefix(rr.Body.Bytes(), []byte("%PDF-")) {
+		t.Error("response body is not a PDF")
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/paddles/missing-paddle/sheet.pdf", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("missing paddle status = %d, want %d", rr.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// Number of similar paddles returned beside a paddle by default and at most
+const (
+	defaultSimilarCount = 5
+	maxSimilarCount     = 20
+)
+
+// parseSimilarCount reads the n query parameter
+func parseSimilarCount(raw string) (int, error) {
+	if raw == "" {
+		return defaultSimilarCount, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 1 || n > maxSimilarCount {
+		return 0, fmt.Errorf("n must be a whole number from 1 to %d", maxSimilarCount)
+	}
+	return n, nil
+}
+
+// similarityTarget is a match target on every rangeable spec and performance
+// metric of a paddle, weighted equally
+func similarityTarget(paddle *Paddle) *MatchTarget {
+	target := &MatchTarget{Specs: map[string]float64{}, Performance: map[string]float64{}}
+	for _, spec := range rangeableSpecs {
+		target.Specs[spec], _ = specValue(&paddle.Specs, spec)
+	}
+	for _, metric := range performanceMetrics {
+		target.Performance[metric] = metricValue(&paddle.Performance, metric)
+	}
+	return target
+}
+
+// SimilarPaddle is a neighbor of a paddle with its headline metrics
+type SimilarPaddle struct {
+	ID          string  `json:"id"`
+	DisplayName string  `json:"display_name"`
+	Distance    float64 `json:"distance"`
+	Power       float64 `json:"power"`
+	Pop         float64 `json:"pop"`
+	Spin        float64 `json:"spin"`
+	Control     float64 `json:"control"`
+}
+
+// nearestNeighbors returns up to n of the candidates nearest the paddle,
+// leaving the paddle itself out, scored like the match endpoint
+func nearestNeighbors(paddle *Paddle, candidates []*Paddle, n int) []SimilarPaddle {
+	others := make([]*Paddle, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.ID != paddle.ID {
+			others = append(others, candidate)
+		}
+	}
+
+	similar := []SimilarPaddle{}
+	for _, scored := range rankByDistance(others, similarityTarget(paddle)) {
+		if len(similar) == n {
+			break
+		}
+		similar = append(similar, SimilarPaddle{
+			ID:          scored.paddle.ID,
+			DisplayName: scored.paddle.DisplayName(),
+			Distance:    roundTo(scored.distance, 4),
+			Power:       scored.paddle.Performance.Power,
+			Pop:         scored.paddle.Performance.Pop,
+			Spin:        scored.paddle.Performance.Spin,
+			Control:     scored.paddle.Control,
+		})
+	}
+	return similar
+}
+
+// getPaddleWithSimilar handles the API request for a paddle's details and
+// its n nearest published neighbors, so detail pages need one round trip
+func getPaddleWithSimilar(w http.ResponseWriter, r *http.Request) {
+	paddleId := paddleIDFromRequest(r)
+	if err := validatePaddleID(paddleId); err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid paddle ID: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	n, err := parseSimilarCount(r.URL.Query().Get("n"))
+	if err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid n: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	units, err := parseUnits(r.URL.Query().Get("units"))
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	paddle, err := loadPaddleDetails(paddleId)
+	if errors.Is(err, ErrPaddleNotFound) {
+		log.Printf("Error retrieving paddle: %v", err)
+		respondWithError(w, "Paddle not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Error retrieving paddle details: %v", err)
+		respondWithError(w, "Failed to retrieve paddle details", http.StatusInternalServerError)
+		return
+	}
+
+	candidates, err := store.GetMatchCandidates()
+	if err != nil {
+		log.Printf("Error retrieving similar paddles: %v", err)
+		respondWithError(w, "Failed to retrieve similar paddles", http.StatusInternalServerError)
+		return
+	}
+	// Neighbors are found in stored units, before converting for display
+	similar := nearestNeighbors(paddle, candidates, n)
+	paddle.convertUnits(units)
+
+	response := struct {
+		Paddle  PaddleResponse  `json:"paddle"`
+		Similar []SimilarPaddle `json:"similar"`
+	}{Paddle: newPaddleResponse(paddle, nil), Similar: similar}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding paddle with similar paddles: %v", err)
+	}
+	paddleCounters.served.Add(1)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// TestParseSimilarCount tests validating the number of neighbors
+func TestParseSimilarCount(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    int
+		wantErr bool
+	}{
+		{raw: "", want: defaultSimilarCount},
+		{raw: "1", want: 1},
+		{raw: "20", want: 20},
+		{raw: "0", wantErr: true},
+		{raw: "21", wantErr: true},
+		{raw: "-3", wantErr: true},
+		{raw: "five", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := parseSimilarCount(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSimilarCount(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseSimilarCount(%q) = %d, want %d", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestGetPaddleWithSimilar tests that the paddle and its nearest neighbors
+// come back in one response, nearest first and without the paddle itself
+func TestGetPaddleWithSimilar(t *testing.T) {
+	setupTestStore(t)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/paddles/{id}/with-similar", getPaddleWithSimilar).Methods("GET")
+
+	// Spin 2000, 2100, ... 2500 with every other field equal
+	ids := []string{}
+	for i := range 6 {
+		input := testPaddleInput("Engage", fmt.Sprintf("Similar %d", i))
+		input.Performance.Spin = 2000 + 100*float64(i)
+		paddle := saveTestPaddle(t, input)
+		ids = append(ids, paddle.ID)
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/paddles/"+ids[2]+"/with-similar?n=3", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Handler returned wrong status code: got %v want %v, body %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	var response struct {
+		Paddle  Paddle          `json:"paddle"`
+		Similar []SimilarPaddle `json:"similar"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if response.Paddle.ID != ids[2] || response.Paddle.Performance.Spin != 2200 {
+		t.Errorf("paddle = %s with spin %v, want %s with spin 2200", response.Paddle.ID, response.Paddle.Performance.Spin, ids[2])
+	}
+	if len(response.Similar) != 3 {
+		t.Fatalf("got %d similar paddles, want 3", len(response.Similar))
+	}
+	for i, want := range []string{ids[1], ids[3], ids[0]} {
+		if got := response.Similar[i]; got.ID != want {
+			t.Errorf("similar[%d] = %s, want %s", i, got.ID, want)
+		}
+	}
+	if first := response.Similar[0]; first.DisplayName != "Engage Similar 1" || first.Spin != 2100 || first.Distance <= 0 {
+		t.Errorf("similar[0] = %+v, want Engage Similar 1 with spin 2100 and a positive distance", first)
+	}
+
+	// n is validated before the paddle is read
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/paddles/"+ids[2]+"/with-similar?n=0", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Handler returned wrong status code for n=0: got %v want %v", rr.Code, http.StatusBadRequest)
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/paddles/missing-paddle/with-similar", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Handler returned wrong status code for missing paddle: got %v want %v", rr.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// sortKey is one way the paddle list can be ordered. column is the SQL
+// expression for Postgres and compare orders paddles the same way for stores
+// that sort in memory. missing reports paddles without a value, which sort
+// last in both directions like Postgres' NULLS LAST.
+type sortKey struct {
+	column  string
+	compare func(a, b *Paddle) int
+	missing func(p *Paddle) bool
+}
+
+// listSortKeys are the accepted ?sort= keys besides id. Only columns in this
+// map may ever be interpolated into SQL.
+var listSortKeys = map[string]sortKey{
+	"created_at": {column: "p.created_at", compare: func(a, b *Paddle) int { return a.CreatedAt.Compare(b.CreatedAt.Time) }},
+	"updated_at": {column: "p.updated_at", compare: func(a, b *Paddle) int { return a.UpdatedAt.Compare(b.UpdatedAt.Time) }},
+	"brand": {column: "LOWER(p.brand)", compare: func(a, b *Paddle) int {
+		return strings.Compare(strings.ToLower(a.Metadata.Brand), strings.ToLower(b.Metadata.Brand))
+	}},
+	"model": {column: "LOWER(p.model)", compare: func(a, b *Paddle) int {
+		return strings.Compare(strings.ToLower(a.Metadata.Model), strings.ToLower(b.Metadata.Model))
+	}},
+	"year": {
+		column:  "p.year",
+		compare: func(a, b *Paddle) int { return cmp.Compare(*a.Metadata.Year, *b.Metadata.Year) },
+		missing: func(p *Paddle) bool { return p.Metadata.Year == nil },
+	},
+	"control": {column: controlColumn, compare: func(a, b *Paddle) int { return cmp.Compare(a.Control, b.Control) }},
+}
+
+// init adds a sort key per performance metric. Metrics sort by the mean
+// across a paddle's measurements, like the details endpoint.
+func init() {
+	for _, metric := range performanceMetrics {
+		listSortKeys[metric] = sortKey{
+			column: metricMeanColumn(metric),
+			compare: func(a, b *Paddle) int {
+				return cmp.Compare(metricValue(&a.Performance, metric), metricValue(&b.Performance, metric))
+			},
+		}
+	}
+}
+
+// listSort is a parsed ?sort= value
+type listSort struct {
+	Key  string
+	Desc bool
+}
+
+// defaultListSort is used when a list request has no ?sort=, set via DEFAULT_SORT
+var defaultListSort = listSort{Key: "id"}
+
+// parseListSort parses a sort key, prefixed with - for descending order
+func parseListSort(raw string) (listSort, error) {
+	raw = strings.TrimSpace(raw)
+	key, desc := strings.CutPrefix(raw, "-")
+	if _, ok := listSortKeys[key]; !ok && key != "id" {
+		keys := append([]string{"id"}, slices.Sorted(maps.Keys(listSortKeys))...)
+		return listSort{}, fmt.Errorf("unknown sort %q: must be one of %s, optionally prefixed with -", raw, strings.Join(keys, ", "))
+	}
+	return listSort{Key: key, Desc: desc}, nil
+}
+
+// loadDefaultListSort reads the default list order from the environment
+func loadDefaultListSort() (listSort, error) {
+	sort, err := parseListSort(getEnv("DEFAULT_SORT", "id"))
+	if err != nil {
+		return listSort{}, fmt.Errorf("invalid DEFAULT_SORT: %w", err)
+	}
+	return sort, nil
+}
+
+// orderBy builds the ORDER BY clause. Ties are broken by id so that offset
+// pagination never repeats or skips a paddle.
+func (s listSort) orderBy() string {
+	direction := "ASC"
+	if s.Desc {
+		direction = "DESC"
+	}
+	// Only allow-listed columns reach SQL: a key parseListSort would have
+	// rejected orders by id rather than interpolating an empty column
+	key, ok := listSortKeys[s.Key]
+	if !ok {
+		return "ORDER BY p.id " + direction
+	}
+	return fmt.Sprintf("ORDER BY %s %s NULLS LAST, p.id ASC", key.column, direction)
+}
+
+// sortPaddles orders paddles in memory like orderBy. paddles must be in id
+// order, which the stable sort keeps for ties.
+func (s listSort) sortPaddles(paddles []*Paddle) {
+	if s.Key == "id" {
+		if s.Desc {
+			slices.Reverse(paddles)
+		}
+		return
+	}
+
+	key := listSortKeys[s.Key]
+	slices.SortStableFunc(paddles, func(a, b *Paddle) int {
+		if key.missing != nil {
+			aMissing, bMissing := key.missing(a), key.missing(b)
+			switch {
+			case aMissing && bMissing:
+				return 0
+			case aMissing:
+				return 1
+			case bMissing:
+				return -1
+			}
+		}
+		if s.Desc {
+			return key.compare(b, a)
+		}
+		return key.compare(a, b)
+	})
+}
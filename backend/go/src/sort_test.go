@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// TestParseListSort tests parsing sort keys and building their ORDER BY clause
+func TestParseListSort(t *testing.T) {
+	tests := []struct {
+		raw         string
+		wantOrderBy string
+		wantErr     bool
+	}{
+		{raw: "id", wantOrderBy: "ORDER BY p.id ASC"},
+		{raw: "-id", wantOrderBy: "ORDER BY p.id DESC"},
+		{raw: "-created_at", wantOrderBy: "ORDER BY p.created_at DESC NULLS LAST, p.id ASC"},
+		{raw: "year", wantOrderBy: "ORDER BY p.year ASC NULLS LAST, p.id ASC"},
+		{raw: " brand ", wantOrderBy: "ORDER BY LOWER(p.brand) ASC NULLS LAST, p.id ASC"},
+		{raw: "-power", wantOrderBy: "ORDER BY (SELECT AVG(perf.power) FROM paddle_performance perf WHERE perf.paddle_spec_id = s.id) DESC NULLS LAST, p.id ASC"},
+		{raw: "control", wantOrderBy: "ORDER BY " + controlColumn + " ASC NULLS LAST, p.id ASC"},
+		{raw: "price", wantErr: true},
+		{raw: "--power", wantErr: true},
+		{raw: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			sort, err := parseListSort(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseListSort(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			}
+			if !tt.wantErr && sort.orderBy() != tt.wantOrderBy {
+				t.Errorf("orderBy() = %q, want %q", sort.orderBy(), tt.wantOrderBy)
+			}
+		})
+	}
+}
+
+// TestGetPaddlesListDefaultSort tests that DEFAULT_SORT orders the list when
+// no sort is requested, and that ?sort= overrides it
+func TestGetPaddlesListDefaultSort(t *testing.T) {
+	setupTestStore(t)
+	t.Cleanup(func() { defaultListSort = listSort{Key: "id"} })
+
+	year := 2024
+	for i, power := range []float64{70, 90, 80, 90} {
+		paddle := &Paddle{
+			ID:          fmt.Sprintf("engage-sort-%d", i),
+			Metadata:    Metadata{Brand: "Engage", Model: fmt.Sprintf("Sort %d", i)},
+			Specs:       testSpecs,
+			Performance: Performance{Power: power, Pop: 70.0, Spin: 3000.0, TwistWeight: 200.0, SwingWeight: 220.0, BalancePoint: 30.0},
+		}
+		if i == 1 {
+			paddle.Metadata.Year = &year
+		}
+		if _, err := store.SavePaddle(paddle); err != nil {
+			t.Fatalf("SavePaddle() error: %v", err)
+		}
+	}
+
+	t.Setenv("DEFAULT_SORT", "-power")
+	sort, err := loadDefaultListSort()
+	if err != nil {
+		t.Fatalf("loadDefaultListSort() error: %v", err)
+	}
+	defaultListSort = sort
+
+	tests := []struct {
+		name     string
+		query    string
+		wantCode int
+		wantIDs  []string
+	}{
+		// Equal power keeps id order, so paging stays stable
+		{name: "Default sort", query: "", wantCode: http.StatusOK, wantIDs: []string{"engage-sort-1", "engage-sort-3", "engage-sort-2", "engage-sort-0"}},
+		{name: "Default sort paged", query: "limit=2&offset=1", wantCode: http.StatusOK, wantIDs: []string{"engage-sort-3", "engage-sort-2"}},
+		{name: "Explicit sort", query: "sort=-id", wantCode: http.StatusOK, wantIDs: []string{"engage-sort-3", "engage-sort-2", "engage-sort-1", "engage-sort-0"}},
+		{name: "Missing years last", query: "sort=-year", wantCode: http.StatusOK, wantIDs: []string{"engage-sort-1", "engage-sort-0", "engage-sort-2", "engage-sort-3"}},
+		{name: "Control", query: "sort=-control", wantCode: http.StatusOK, wantIDs: []string{"engage-sort-0", "engage-sort-2", "engage-sort-1", "engage-sort-3"}},
+		{name: "Unknown sort", query: "sort=price", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			getPaddlesList(rr, httptest.NewRequest("GET", "/api/paddles?"+tt.query, nil))
+			if rr.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rr.Code, tt.wantCode, rr.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var paddles []struct {
+				ID      string  `json:"id"`
+				Control float64 `json:"control"`
+			}
+			if err := json.Unmarshal(rr.Body.Bytes(), &paddles); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			var ids []string
+			for _, p := range paddles {
+				ids = append(ids, p.ID)
+				// Cards carry the control rating of the paddle's performance
+				if p.ID == "engage-sort-0" && p.Control != 30 {
+					t.Errorf("%s control = %v, want 30", p.ID, p.Control)
+				}
+			}
+			if !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("paddles = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+
+	t.Setenv("DEFAULT_SORT", "price")
+	if _, err := loadDefaultListSort(); err == nil {
+		t.Error("loadDefaultListSort() with an unknown key expected an error")
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+)
+
+// SpecRange is a manufacturer-quoted range for a spec, such as a weight of 218–222g
+type SpecRange struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// rangeableSpecs are the numeric spec fields a manufacturer range can be quoted for
+var rangeableSpecs = []string{"average_weight", "core", "paddle_length", "paddle_width", "grip_length", "grip_circumference"}
+
+// specValue returns the measured value of a rangeable spec field
+func specValue(specs *Specs, field string) (float64, bool) {
+	switch field {
+	case "average_weight":
+		return specs.AverageWeight, true
+	case "core":
+		return specs.Core, true
+	case "paddle_length":
+		return specs.PaddleLength, true
+	case "paddle_width":
+		return specs.PaddleWidth, true
+	case "grip_length":
+		return specs.GripLength, true
+	case "grip_circumference":
+		return specs.GripCircumference, true
+	}
+	return 0, false
+}
+
+// validateSpecRanges checks that each quoted range names a rangeable spec, is
+// well ordered, and contains the measured value
+func validateSpecRanges(specs *Specs, ranges map[string]SpecRange) error {
+	// Check fields in a stable order so the same input always reports the same error
+	fields := make([]string, 0, len(ranges))
+	for field := range ranges {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		r := ranges[field]
+		measured, ok := specValue(specs, field)
+		if !ok {
+			return newValidationError("SPEC_RANGE_UNKNOWN", "unknown spec range %q: must be one of %v", field, rangeableSpecs)
+		}
+		if r.Min > r.Max {
+			return newValidationError("SPEC_RANGE_INVERTED", "%s range min (%g) must not exceed max (%g)", field, r.Min, r.Max)
+		}
+		if measured < r.Min || measured > r.Max {
+			return newValidationError("SPEC_OUTSIDE_RANGE", "measured %s (%g) is outside the quoted range %g–%g", field, measured, r.Min, r.Max)
+		}
+	}
+
+	return nil
+}
+
+// insertSpecRanges stores a paddle's quoted spec ranges inside tx
+func insertSpecRanges(ctx context.Context, tx *sql.Tx, paddleDBID int, ranges map[string]SpecRange) error {
+	for field, r := range ranges {
+		_, err := timedExec(ctx, tx, "insert_spec_range", `
+			INSERT INTO paddle_spec_ranges (paddle_id, field, min_value, max_value)
+			VALUES ($1, $2, $3, $4)
+		`, paddleDBID, field, r.Min, r.Max)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetSpecRanges returns the quoted spec ranges of a paddle, keyed by spec field.
+// Paddles without quoted ranges return an empty map.
+func GetSpecRanges(paddleId string) (map[string]SpecRange, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	rows, err := timedQuery(ctx, DB, "get_spec_ranges", `
+		SELECT r.field, r.min_value, r.max_value
+		FROM paddle_spec_ranges r
+		JOIN paddles p ON p.id = r.paddle_id
+		WHERE p.paddle_id = $1
+	`, paddleId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ranges := map[string]SpecRange{}
+	for rows.Next() {
+		var field string
+		var r SpecRange
+		if err := rows.Scan(&field, &r.Min, &r.Max); err != nil {
+			return nil, err
+		}
+		ranges[field] = r
+	}
+
+	return ranges, rows.Err()
+}
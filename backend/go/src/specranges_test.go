@@ -0,0 +1,46 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestValidateSpecRanges tests checking measured specs against quoted ranges
+func TestValidateSpecRanges(t *testing.T) {
+	specs := Specs{AverageWeight: 220, Core: 16, GripLength: 5.5}
+
+	tests := []struct {
+		name    string
+		ranges  map[string]SpecRange
+		wantErr string
+	}{
+		{name: "No ranges", ranges: nil},
+		{name: "Measured within range", ranges: map[string]SpecRange{"average_weight": {Min: 218, Max: 222}}},
+		{name: "Measured on the boundary", ranges: map[string]SpecRange{"core": {Min: 16, Max: 16}, "grip_length": {Min: 5.25, Max: 5.5}}},
+		{name: "Measured below range", ranges: map[string]SpecRange{"average_weight": {Min: 222, Max: 226}}, wantErr: "outside the quoted range"},
+		{name: "Measured above range", ranges: map[string]SpecRange{"core": {Min: 13, Max: 14}}, wantErr: "outside the quoted range"},
+		{name: "Inverted range", ranges: map[string]SpecRange{"average_weight": {Min: 222, Max: 218}}, wantErr: "must not exceed max"},
+		{name: "Unknown field", ranges: map[string]SpecRange{"shape": {Min: 1, Max: 2}}, wantErr: "unknown spec range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSpecRanges(&specs, tt.ranges)
+			if (err != nil) != (tt.wantErr != "") {
+				t.Fatalf("validateSpecRanges() error = %v, wantErr %q", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateSpecRanges() error = %v, expected to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestRangeableSpecsHaveValues tests that every rangeable spec maps to a field
+func TestRangeableSpecsHaveValues(t *testing.T) {
+	for _, field := range rangeableSpecs {
+		if _, ok := specValue(&Specs{}, field); !ok {
+			t.Errorf("rangeable spec %q has no value in specValue", field)
+		}
+	}
+}
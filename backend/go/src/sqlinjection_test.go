@@ -0,0 +1,255 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// injectionPayloads are classic SQL injection attempts fed to every query
+// parameter. None may ever be executed.
+var injectionPayloads = []string{
+	"'; DROP TABLE paddles; --",
+	"1 OR 1=1",
+	"' OR '1'='1",
+	"1; DELETE FROM paddles",
+	"brand) UNION SELECT * FROM pg_user --",
+	"p.id; --",
+	"\\'; DROP TABLE paddles; --",
+	"%' OR 1=1 --",
+}
+
+// safeSQLExpression matches the column expressions allowed into SQL: names,
+// parentheses, spaces, commas, comparisons and arithmetic, but never quotes
+// or semicolons. Comments are rejected separately.
+var safeSQLExpression = regexp.MustCompile(`^[A-Za-z0-9_.(), =*+-]+$`)
+
+// TestAllowListedColumnsAreSafe tests that every column expression that is
+// interpolated into SQL comes from a fixed allow-list of plain expressions
+func TestAllowListedColumnsAreSafe(t *testing.T) {
+	columns := map[string]string{}
+	for key, sort := range listSortKeys {
+		columns["sort "+key] = sort.column
+	}
+	for name, grouping := range paddleGroupings {
+		columns["grouping "+name] = grouping.Column
+	}
+	for metric, column := range performanceMetricColumns {
+		columns["metric "+metric] = column
+	}
+	for _, field := range subsetStatsFields {
+		columns["stats "+field.Name] = field.Column
+	}
+
+	for name, column := range columns {
+		if !safeSQLExpression.MatchString(column) || strings.Contains(column, "--") {
+			t.Errorf("%s column %q is not a plain SQL expression", name, column)
+		}
+	}
+}
+
+// TestInjectionPayloadsAreParameterized tests that free-text filters reach
+// the database only as query parameters
+func TestInjectionPayloadsAreParameterized(t *testing.T) {
+	for _, payload := range injectionPayloads {
+		for _, param := range []string{"brand", "shape", "tag"} {
+			filter, err := parsePaddleFilter(url.Values{param: {payload}})
+			if err != nil {
+				// Tags are split on commas, which the payloads don't contain
+				t.Errorf("%s=%q was rejected: %v", param, payload, err)
+				continue
+			}
+
+			where, args := filter.where()
+			if strings.Contains(where, payload) || strings.Contains(where, "DROP") || strings.Contains(where, "1=1") {
+				t.Errorf("%s=%q leaked into the clause %q", param, payload, where)
+			}
+			if !slices.ContainsFunc(args, func(arg interface{}) bool {
+				s, ok := arg.(string)
+				return ok && strings.EqualFold(s, strings.TrimSpace(payload))
+			}) && param != "tag" {
+				t.Errorf("%s=%q is not among the args %v", param, payload, args)
+			}
+		}
+
+		// Suggestions escape the LIKE wildcards and pass q as a parameter
+		if q, err := parseSuggestQuery(payload); err != nil || q != payload {
+			t.Errorf("parseSuggestQuery(%q) = %q, %v; want it unchanged", payload, q, err)
+		}
+
+		// Performance IDs are passed as one array parameter
+		if ids, err := parsePerformanceIDs([]string{payload}); err != nil || len(ids) != 1 || ids[0] != strings.TrimSpace(payload) {
+			t.Errorf("parsePerformanceIDs(%q) = %q, %v; want it unchanged", payload, ids, err)
+		}
+	}
+}
+
+// TestInjectionPayloadsAreRejected tests that parameters naming columns or
+// holding numbers reject injection payloads outright
+func TestInjectionPayloadsAreRejected(t *testing.T) {
+	parsers := map[string]func(payload string) error{
+		"sort": func(p string) error { _, err := parseListSort(p); return err },
+		"desc sort": func(p string) error {
+			_, err := parseListSort("-" + p)
+			return err
+		},
+		"by":      func(p string) error { _, err := parseGrouping(p); return err },
+		"units":   func(p string) error { _, err := parseUnits(p); return err },
+		"status":  func(p string) error { _, err := parsePaddleStatus(p); return err },
+		"fields":  func(p string) error { _, err := parseFields(p); return err },
+		"days":    func(p string) error { _, err := parseRecentDays(p); return err },
+		"min_ms":  func(p string) error { _, err := parseActivityMinMS(p); return err },
+		"version": func(p string) error { _, err := parseVersion(p); return err },
+		"id":      func(p string) error { return validatePaddleID(NormalizePaddleID(p)) },
+		"year": func(p string) error {
+			_, err := parsePaddleFilter(url.Values{"year": {p}})
+			return err
+		},
+		"surface": func(p string) error {
+			_, err := parsePaddleFilter(url.Values{"surface": {p}})
+			return err
+		},
+		"metric": func(p string) error {
+			_, _, err := parseHistogramParams(url.Values{"metric": {p}})
+			return err
+		},
+		"buckets": func(p string) error {
+			_, _, err := parseHistogramParams(url.Values{"metric": {"power"}, "buckets": {p}})
+			return err
+		},
+		"x": func(p string) error {
+			_, _, err := parseCorrelationMetrics(url.Values{"x": {p}, "y": {"spin"}})
+			return err
+		},
+		"target": func(p string) error {
+			_, err := parseRecommendTargets(url.Values{"target_power": {p}})
+			return err
+		},
+		"weight": func(p string) error {
+			_, err := parseRankWeights(url.Values{"w_power": {p}})
+			return err
+		},
+		"weight name": func(p string) error {
+			_, err := parseRankWeights(url.Values{"w_" + p: {"1"}})
+			return err
+		},
+		"limit": func(p string) error {
+			_, _, err := parsePagination(httptest.NewRequest("GET", "/?limit="+url.QueryEscape(p), nil))
+			return err
+		},
+		"hand_length_cm": func(p string) error {
+			_, _, err := parseGripSizeParams(httptest.NewRequest("GET", "/?hand_length_cm="+url.QueryEscape(p), nil))
+			return err
+		},
+	}
+
+	for name, parse := range parsers {
+		for _, payload := range injectionPayloads {
+			if err := parse(payload); err == nil {
+				t.Errorf("%s accepted %q", name, payload)
+			}
+		}
+	}
+}
+
+// TestValidateQueryText tests that text Postgres cannot take as a parameter
+// is rejected before it reaches the database
+func TestValidateQueryText(t *testing.T) {
+	tests := []struct {
+		value    string
+		wantCode string
+	}{
+		{value: "Engage", wantCode: ""},
+		{value: "'; DROP TABLE paddles; --", wantCode: ""},
+		{value: "Sélkirk", wantCode: ""},
+		{value: "Engage\x00", wantCode: "TEXT_NOT_PRINTABLE"},
+		{value: "Engage\xff", wantCode: "TEXT_INVALID_UTF8"},
+	}
+
+	for _, tt := range tests {
+		err := validateQueryText("brand", tt.value)
+		if got := validationCode(err); got != tt.wantCode {
+			t.Errorf("validateQueryText(%q) code = %q, want %q (err %v)", tt.value, got, tt.wantCode, err)
+		}
+	}
+
+	for _, raw := range []string{"brand=Engage%00", "shape=%FF", "tag=a%00b"} {
+		query, _ := url.ParseQuery(raw)
+		if _, err := parsePaddleFilter(query); err == nil {
+			t.Errorf("parsePaddleFilter(%s) accepted the value", raw)
+		}
+	}
+	if _, err := parseSuggestQuery("pur\x00"); err == nil {
+		t.Error("parseSuggestQuery accepted a NUL byte")
+	}
+	if _, err := parsePerformanceIDs([]string{"engage\x00"}); err == nil {
+		t.Error("parsePerformanceIDs accepted a NUL byte")
+	}
+}
+
+// TestSQLInjectionAgainstDatabase sends every payload through the endpoints
+// that build dynamic SQL and checks that none fails or drops the table
+func TestSQLInjectionAgainstDatabase(t *testing.T) {
+	setupTestDB(t)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/paddles", getPaddlesList).Methods("GET")
+	router.HandleFunc("/api/paddles/grouped", streamGroupedPaddles).Methods("GET")
+	router.HandleFunc("/api/paddles/suggest", getPaddleSuggestions).Methods("GET")
+	router.HandleFunc("/api/paddles/stats", getSubsetStats).Methods("GET")
+	router.HandleFunc("/api/paddles/facets", getFacets).Methods("GET")
+	router.HandleFunc("/api/paddles/average", getAveragePaddle).Methods("GET")
+	router.HandleFunc("/api/paddles/ranked", getRankedPaddles).Methods("GET")
+	router.HandleFunc("/api/paddles/elite", getElitePaddles).Methods("GET")
+	router.HandleFunc("/api/paddles/performance", getPerformanceByIDs).Methods("GET")
+	router.HandleFunc("/api/paddles/by-sku/{sku}", getPaddleBySKU).Methods("GET")
+	router.HandleFunc("/api/paddles/{id}", getPaddleDetails).Methods("GET")
+
+	var before int
+	if err := DB.QueryRow("SELECT COUNT(*) FROM paddles").Scan(&before); err != nil {
+		t.Fatalf("Failed to count paddles: %v", err)
+	}
+
+	for _, payload := range injectionPayloads {
+		escaped := url.QueryEscape(payload)
+		for _, target := range []string{
+			"/api/paddles?brand=" + escaped,
+			"/api/paddles?shape=" + escaped,
+			"/api/paddles?tag=" + escaped,
+			"/api/paddles?sort=" + escaped,
+			"/api/paddles?year=" + escaped,
+			"/api/paddles?limit=" + escaped,
+			"/api/paddles/grouped?by=" + escaped,
+			"/api/paddles/grouped?brand=" + escaped,
+			"/api/paddles/suggest?q=" + escaped,
+			"/api/paddles/stats?brand=" + escaped,
+			"/api/paddles/facets?brand=" + escaped,
+			"/api/paddles/average?shape=" + escaped,
+			"/api/paddles/ranked?w_power=1&brand=" + escaped,
+			"/api/paddles/elite?percentile=90&metric=" + escaped,
+			"/api/paddles/performance?ids=" + escaped,
+			"/api/paddles/by-sku/" + url.PathEscape(payload),
+			"/api/paddles/" + url.PathEscape(payload),
+		} {
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest("GET", target, nil))
+			if rr.Code >= http.StatusInternalServerError {
+				t.Errorf("GET %s returned %d: %s", target, rr.Code, rr.Body.String())
+			}
+		}
+	}
+
+	var after int
+	if err := DB.QueryRow("SELECT COUNT(*) FROM paddles").Scan(&after); err != nil {
+		t.Fatalf("Failed to count paddles after the payloads: %v", err)
+	}
+	if after != before {
+		t.Errorf("Paddle count changed from %d to %d", before, after)
+	}
+}
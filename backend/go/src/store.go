@@ -0,0 +1,175 @@
+package main
+
+import "time"
+
+// Store is the paddle storage used by the core handlers. The Postgres
+// implementation is the production default; tests can swap in an in-memory
+// store to run without a database.
+type Store interface {
+	GetPaddleByID(paddleId string) (*Paddle, error)
+	GetPaddleByDBID(id int) (*Paddle, error)
+	PaddleExists(paddleId string) (bool, error)
+	FindPaddleIDByName(brand, model string) (string, error)
+	CountPaddles() (int, error)
+	SavePaddle(paddle *Paddle) (int, error)
+	UpsertPaddle(paddle *Paddle) (int, bool, error)
+	UpdatePaddlePerformance(paddleId string, performance *Performance) error
+	PublishPaddle(paddleId string) (bool, error)
+	GetAggregatedPerformance(paddleId string) (*AggregatedPerformance, error)
+	GetPerformanceByIDs(paddleIds []string) (map[string]Performance, error)
+	GetCompareRows(paddleIds []string) (map[string]CompareRow, error)
+	GetSpecRanges(paddleId string) (map[string]SpecRange, error)
+	GetTags(paddleId string) ([]string, error)
+	AddTags(paddleId string, tags []string) ([]string, error)
+	RemoveTag(paddleId, tag string) ([]string, error)
+	GetPaddlesPage(filter paddleFilter, sort listSort, limit, offset int) ([]*Paddle, error)
+	CountPaddlesMatching(filter paddleFilter) (int, error)
+	GetPaddleChanges(since time.Time, after string, limit int) ([]*Paddle, error)
+	GetPaddleTombstones(since time.Time, after string, limit int) ([]PaddleTombstone, error)
+	GetElitePaddles(metric string, threshold float64, limit, offset int) ([]*Paddle, error)
+	GetBrandCounts() ([]BrandCount, error)
+	GetDatasetStats() (*DatasetStats, error)
+	GetSubsetStats(filter paddleFilter) (*SubsetStats, error)
+	GetFacets(filter paddleFilter) (*Facets, error)
+	GetMatchCandidates() ([]*Paddle, error)
+	BulkUpdatePaddles(filter paddleFilter, update BulkUpdate) (*BulkUpdateResult, error)
+	GetFeatured() (*FeaturedSelection, error)
+	SetFeatured(selection FeaturedSelection) error
+	GetPublishedPaddleIDs() ([]string, error)
+	GetRawUpload(paddleId string) (*RawUpload, error)
+	ResetData() error
+}
+
+// store is the Store the handlers read and write through
+var store Store = countingStore{postgresStore{}}
+
+// postgresStore implements Store with the package-level Postgres functions
+type postgresStore struct{}
+
+func (postgresStore) GetPaddleByID(paddleId string) (*Paddle, error) {
+	return GetPaddleByID(paddleId)
+}
+
+func (postgresStore) GetPaddleByDBID(id int) (*Paddle, error) {
+	return GetPaddleByDBID(id)
+}
+
+func (postgresStore) PaddleExists(paddleId string) (bool, error) {
+	return PaddleExists(paddleId)
+}
+
+func (postgresStore) FindPaddleIDByName(brand, model string) (string, error) {
+	return FindPaddleIDByName(brand, model)
+}
+
+func (postgresStore) CountPaddles() (int, error) {
+	return CountPaddles()
+}
+
+func (postgresStore) SavePaddle(paddle *Paddle) (int, error) {
+	return SavePaddle(paddle)
+}
+
+func (postgresStore) UpsertPaddle(paddle *Paddle) (int, bool, error) {
+	return UpsertPaddle(paddle)
+}
+
+func (postgresStore) UpdatePaddlePerformance(paddleId string, performance *Performance) error {
+	return UpdatePaddlePerformance(paddleId, performance)
+}
+
+func (postgresStore) PublishPaddle(paddleId string) (bool, error) {
+	return PublishPaddle(paddleId)
+}
+
+func (postgresStore) GetAggregatedPerformance(paddleId string) (*AggregatedPerformance, error) {
+	return GetAggregatedPerformance(paddleId)
+}
+
+func (postgresStore) GetPerformanceByIDs(paddleIds []string) (map[string]Performance, error) {
+	return GetPerformanceByIDs(paddleIds)
+}
+
+func (postgresStore) GetCompareRows(paddleIds []string) (map[string]CompareRow, error) {
+	return GetCompareRows(paddleIds)
+}
+
+func (postgresStore) GetSpecRanges(paddleId string) (map[string]SpecRange, error) {
+	return GetSpecRanges(paddleId)
+}
+
+func (postgresStore) GetTags(paddleId string) ([]string, error) {
+	return GetTags(paddleId)
+}
+
+func (postgresStore) AddTags(paddleId string, tags []string) ([]string, error) {
+	return AddTags(paddleId, tags)
+}
+
+func (postgresStore) RemoveTag(paddleId, tag string) ([]string, error) {
+	return RemoveTag(paddleId, tag)
+}
+
+func (postgresStore) GetPaddlesPage(filter paddleFilter, sort listSort, limit, offset int) ([]*Paddle, error) {
+	return GetPaddlesPage(filter, sort, limit, offset)
+}
+
+func (postgresStore) CountPaddlesMatching(filter paddleFilter) (int, error) {
+	return CountPaddlesMatching(filter)
+}
+
+func (postgresStore) GetPaddleChanges(since time.Time, after string, limit int) ([]*Paddle, error) {
+	return GetPaddleChanges(since, after, limit)
+}
+
+func (postgresStore) GetPaddleTombstones(since time.Time, after string, limit int) ([]PaddleTombstone, error) {
+	return GetPaddleTombstones(since, after, limit)
+}
+
+func (postgresStore) GetElitePaddles(metric string, threshold float64, limit, offset int) ([]*Paddle, error) {
+	return GetElitePaddles(metric, threshold, limit, offset)
+}
+
+func (postgresStore) GetBrandCounts() ([]BrandCount, error) {
+	return GetBrandCounts()
+}
+
+func (postgresStore) GetDatasetStats() (*DatasetStats, error) {
+	return GetDatasetStats()
+}
+
+func (postgresStore) GetSubsetStats(filter paddleFilter) (*SubsetStats, error) {
+	return GetSubsetStats(filter)
+}
+
+func (postgresStore) GetFacets(filter paddleFilter) (*Facets, error) {
+	return GetFacets(filter)
+}
+
+func (postgresStore) GetMatchCandidates() ([]*Paddle, error) {
+	return GetMatchCandidates()
+}
+
+func (postgresStore) BulkUpdatePaddles(filter paddleFilter, update BulkUpdate) (*BulkUpdateResult, error) {
+	return BulkUpdatePaddles(filter, update)
+}
+
+func (postgresStore) GetFeatured() (*FeaturedSelection, error) {
+	return GetFeatured()
+}
+
+func (postgresStore) SetFeatured(selection FeaturedSelection) error {
+	return SetFeatured(selection)
+}
+
+func (postgresStore) GetPublishedPaddleIDs() ([]string, error) {
+	return GetPublishedPaddleIDs()
+}
+
+func (postgresStore) GetRawUpload(paddleId string) (*RawUpload, error) {
+	return GetRawUpload(paddleId)
+}
+
+func (postgresStore) ResetData() error {
+	return ResetData()
+}
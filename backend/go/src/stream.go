@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// streamFlushEvery is how many paddles are written between flushes
+const streamFlushEvery = 100
+
+// paddleRows is the part of *sql.Rows used while streaming
+type paddleRows interface {
+	rowScanner
+	Next() bool
+	Err() error
+}
+
+// streamPaddles handles the API request for the published catalog as a streaming
+// JSON array. Paddles are encoded one row at a time, so memory use does not
+// grow with the size of the dataset. Each paddle is sent once, with its mean
+// performance.
+func streamPaddles(w http.ResponseWriter, r *http.Request) {
+	units, err := parseUnits(r.URL.Query().Get("units"))
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// The query is bound to the request rather than queryTimeout, since a full
+	// sync can legitimately outlast it; a client disconnect still cancels it
+	rows, err := timedQuery(r.Context(), DB, "stream_paddles", paddleMeansQuery+`
+		WHERE 
+			p.status = 'published'
+		ORDER BY 
+			p.id
+	`)
+	if err != nil {
+		log.Printf("Error starting paddle stream: %v", err)
+		respondWithError(w, "Failed to retrieve paddles data", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	w.Header().Set("Transfer-Encoding", "chunked")
+	count, err := writePaddleStream(w, rows, units)
+	if err != nil {
+		// The status and part of the body are already sent, so the best we can
+		// do is log and end with a well-formed (if short) array
+		log.Printf("Error streaming paddles after %d rows: %v", count, err)
+	}
+}
+
+// writePaddleStream writes rows as a JSON array, flushing every
+// streamFlushEvery paddles. The array is always closed, even when a row
+// fails; the error is returned along with the number of paddles written.
+func writePaddleStream(w http.ResponseWriter, rows paddleRows, units unitSystem) (int, error) {
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+
+	w.Write([]byte("["))
+	defer w.Write([]byte("]\n"))
+
+	count := 0
+	for rows.Next() {
+		paddle, err := scanFullPaddle(rows)
+		if err != nil {
+			return count, err
+		}
+		paddle.convertUnits(units)
+
+		if count > 0 {
+			w.Write([]byte(","))
+		}
+		if err := encoder.Encode(paddle); err != nil {
+			return count, err
+		}
+		count++
+
+		if flusher != nil && count%streamFlushEvery == 0 {
+			flusher.Flush()
+		}
+	}
+
+	return count, rows.Err()
+}
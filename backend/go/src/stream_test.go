@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakePaddleRows yields n paddles, then optionally fails
+type fakePaddleRows struct {
+	n, next int
+	failAt  int
+	err     error
+}
+
+func (f *fakePaddleRows) Next() bool {
+	if f.err != nil && f.next == f.failAt {
+		return false
+	}
+	f.next++
+	return f.next <= f.n
+}
+
+func (f *fakePaddleRows) Scan(dest ...interface{}) error {
+	*dest[0].(*string) = fmt.Sprintf("paddle-%d", f.next)
+	*dest[1].(*string) = "Engage"
+	*dest[2].(*string) = fmt.Sprintf("Model %d", f.next)
+	return nil
+}
+
+func (f *fakePaddleRows) Err() error {
+	if f.err != nil && f.next == f.failAt {
+		return f.err
+	}
+	return nil
+}
+
+// TestWritePaddleStream tests that a stream is a complete JSON array
+func TestWritePaddleStream(t *testing.T) {
+	tests := []struct {
+		name      string
+		rows      *fakePaddleRows
+		wantCount int
+		wantErr   bool
+	}{
+		{name: "Empty", rows: &fakePaddleRows{n: 0}, wantCount: 0},
+		{name: "Several flushes", rows: &fakePaddleRows{n: 250}, wantCount: 250},
+		{name: "Mid-stream error", rows: &fakePaddleRows{n: 250, failAt: 120, err: errors.New("connection reset")}, wantCount: 120, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			count, err := writePaddleStream(rr, tt.rows, metricUnits)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("writePaddleStream() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if count != tt.wantCount {
+				t.Errorf("writePaddleStream() count = %d, want %d", count, tt.wantCount)
+			}
+
+			// Even a failed stream must be a valid JSON array
+			var paddles []Paddle
+			if err := json.Unmarshal(rr.Body.Bytes(), &paddles); err != nil {
+				t.Fatalf("Stream is not a valid JSON array: %v", err)
+			}
+			if len(paddles) != tt.wantCount {
+				t.Errorf("Stream contains %d paddles, want %d", len(paddles), tt.wantCount)
+			}
+			if tt.wantCount > 0 && paddles[0].ID != "paddle-1" {
+				t.Errorf("First paddle ID = %q, want paddle-1", paddles[0].ID)
+			}
+		})
+	}
+}
+
+// benchmarkPaddleCount is the catalog size used by the streaming benchmarks
+const benchmarkPaddleCount = 10000
+
+// discardResponseWriter drops the body so benchmarks measure the encoder, not a buffer
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func (d *discardResponseWriter) Header() http.Header         { return d.header }
+func (d *discardResponseWriter) Write(p []byte) (int, error) { return len(p), nil }
+func (d *discardResponseWriter) WriteHeader(int)             {}
+
+// BenchmarkWritePaddleStream measures streaming the catalog row by row
+func BenchmarkWritePaddleStream(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		writePaddleStream(&discardResponseWriter{header: http.Header{}}, &fakePaddleRows{n: benchmarkPaddleCount}, metricUnits)
+	}
+}
+
+// BenchmarkEncodePaddleSlice measures the list endpoint's approach of
+// collecting every paddle into a slice before encoding it
+func BenchmarkEncodePaddleSlice(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rows := &fakePaddleRows{n: benchmarkPaddleCount}
+		var paddles []*Paddle
+		for rows.Next() {
+			paddle, _ := scanFullPaddle(rows)
+			paddles = append(paddles, paddle)
+		}
+		json.NewEncoder(&discardResponseWriter{header: http.Header{}}).Encode(paddles)
+	}
+}
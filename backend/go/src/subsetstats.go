@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// subsetStatsField is a numeric paddle field summarized by the stats endpoint
+type subsetStatsField struct {
+	Name string
+	// Column is the per-paddle SQL expression, over paddles p, specs s and
+	// performance perf grouped by paddle
+	Column string
+	// value reads the field from a paddle, reporting false when it is unset
+	value func(p *Paddle) (float64, bool)
+}
+
+// specField reads a required spec, which is always set
+func specField(name string, field func(s *Specs) float64) subsetStatsField {
+	return subsetStatsField{
+		Name:   name,
+		Column: "s." + name,
+		value:  func(p *Paddle) (float64, bool) { return field(&p.Specs), true },
+	}
+}
+
+// subsetStatsFields lists the fields summarized by the stats endpoint. Only
+// columns in this list may ever be interpolated into SQL.
+var subsetStatsFields = func() []subsetStatsField {
+	fields := []subsetStatsField{
+		{
+			Name:   "price",
+			Column: "p.price",
+			value: func(p *Paddle) (float64, bool) {
+				if p.Metadata.Price == nil {
+					return 0, false
+				}
+				return *p.Metadata.Price, true
+			},
+		},
+		specField("average_weight", func(s *Specs) float64 { return s.AverageWeight }),
+		specField("core", func(s *Specs) float64 { return s.Core }),
+		specField("paddle_length", func(s *Specs) float64 { return s.PaddleLength }),
+		specField("paddle_width", func(s *Specs) float64 { return s.PaddleWidth }),
+		specField("grip_length", func(s *Specs) float64 { return s.GripLength }),
+		specField("grip_circumference", func(s *Specs) float64 { return s.GripCircumference }),
+	}
+
+	// Performance is averaged across each paddle's measurements, like the details endpoint
+	for _, metric := range performanceMetrics {
+		fields = append(fields, subsetStatsField{
+			Name:   metric,
+			Column: fmt.Sprintf("AVG(%s)", performanceMetricColumns[metric]),
+			value:  func(p *Paddle) (float64, bool) { return metricValue(&p.Performance, metric), true },
+		})
+	}
+	return fields
+}()
+
+// SubsetStats summarizes the paddles matching a filter. A field is null when
+// no matching paddle has a value for it, as for every field of an empty set.
+type SubsetStats struct {
+	Count  int                    `json:"count"`
+	Fields map[string]*FieldStats `json:"fields"`
+}
+
+// summarizePaddles computes subset stats over a set of paddles
+func summarizePaddles(paddles []*Paddle) *SubsetStats {
+	stats := &SubsetStats{Count: len(paddles), Fields: map[string]*FieldStats{}}
+
+	for _, field := range subsetStatsFields {
+		var summary *FieldStats
+		n := 0
+		for _, paddle := range paddles {
+			value, ok := field.value(paddle)
+			if !ok {
+				continue
+			}
+			if summary == nil {
+				summary = &FieldStats{Min: value, Max: value}
+			}
+			summary.Min = min(summary.Min, value)
+			summary.Max = max(summary.Max, value)
+			summary.Mean += value
+			n++
+		}
+		if summary != nil {
+			summary.Mean /= float64(n)
+		}
+		stats.Fields[field.Name] = summary
+	}
+	return stats
+}
+
+// GetSubsetStats computes the min, max and mean of every numeric field across
+// the paddles matching filter in one query
+func GetSubsetStats(filter paddleFilter) (*SubsetStats, error) {
+	perPaddle := make([]string, len(subsetStatsFields))
+	columns := make([]string, len(subsetStatsFields))
+	for i, field := range subsetStatsFields {
+		perPaddle[i] = fmt.Sprintf("%s AS %s", field.Column, field.Name)
+		columns[i] = field.Name
+	}
+
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	where, args := filter.where()
+	var count int
+	fields := make([]nullableFieldStats, len(subsetStatsFields))
+	err := timedQueryRow(ctx, DB, "get_subset_stats", `
+		SELECT
+			COUNT(*), `+aggregateSelects(columns)+`
+		FROM (
+			SELECT
+				`+strings.Join(perPaddle, ", ")+`
+			FROM
+				paddles p
+			JOIN
+				paddle_specs s ON p.id = s.paddle_id
+			JOIN
+				paddle_performance perf ON s.id = perf.paddle_spec_id
+			`+where+`
+			GROUP BY
+				p.id, s.id
+		) paddle_values
+	`, args...).Scan(append([]interface{}{&count}, aggregateScanDest(fields)...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &SubsetStats{Count: count, Fields: map[string]*FieldStats{}}
+	for i, field := range subsetStatsFields {
+		stats.Fields[field.Name] = fields[i].stats()
+	}
+	return stats, nil
+}
+
+// getSubsetStats handles the API request for the stats of a filtered set of paddles
+func getSubsetStats(w http.ResponseWriter, r *http.Request) {
+	filter, err := parsePaddleFilter(r.URL.Query())
+	if err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid filter: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	stats, err := store.GetSubsetStats(filter)
+	if err != nil {
+		log.Printf("Error computing paddle stats: %v", err)
+		respondWithError(w, "Failed to compute paddle stats", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(stats); err != nil {
+		log.Printf("Error encoding paddle stats: %v", err)
+	}
+}
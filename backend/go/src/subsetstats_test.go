@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// getSubsetStatsResponse calls the stats handler and decodes the response
+func getSubsetStatsResponse(t *testing.T, query string) SubsetStats {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	getSubsetStats(rr, httptest.NewRequest("GET", "/api/paddles/stats"+query, nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	var stats SubsetStats
+	if err := json.Unmarshal(rr.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	return stats
+}
+
+// TestGetSubsetStats tests the aggregates of a filtered subset against values computed by hand
+func TestGetSubsetStats(t *testing.T) {
+	setupTestStore(t)
+
+	save := func(brand, model string, shape PaddleShape, weight, power, spin float64, price *float64) {
+		t.Helper()
+		input := &PaddleInput{
+			Metadata: Metadata{Brand: brand, Model: model, Price: price},
+			Specs: Specs{
+				Shape: shape, Surface: "Composite", AverageWeight: weight, Core: 16.0,
+				PaddleLength: 16.5, PaddleWidth: 7.5, GripLength: 4.5, GripType: "Comfort", GripCircumference: 4.0,
+			},
+			Performance: Performance{Power: power, Pop: 70.0, Spin: spin, TwistWeight: 200.0, SwingWeight: 220.0, BalancePoint: 30.0},
+		}
+		saveTestPaddle(t, input)
+	}
+
+	// The subset is the three Engage hybrids; the other two must be left out
+	save("Engage", "Pursuit MX 6.0", Hybrid, 220, 60, 2000, bound(150))
+	save("Engage", "Pursuit EX 6.0", Hybrid, 230, 75, 2600, bound(250))
+	save("Engage", "Pursuit Pro", Hybrid, 240, 90, 2900, nil)
+	save("Engage", "Pursuit Elongated", Elongated, 300, 99, 3500, bound(400))
+	save("Selkirk", "Vanguard Hybrid", Hybrid, 180, 10, 1000, bound(50))
+
+	stats := getSubsetStatsResponse(t, "?brand=engage&shape=Hybrid")
+	if stats.Count != 3 {
+		t.Fatalf("count = %d, want 3", stats.Count)
+	}
+
+	want := map[string]FieldStats{
+		"average_weight": {Min: 220, Max: 240, Mean: 230},
+		"power":          {Min: 60, Max: 90, Mean: 75},
+		"spin":           {Min: 2000, Max: 2900, Mean: 2500},
+		"core":           {Min: 16, Max: 16, Mean: 16},
+		// The paddle without a price is left out of the price stats
+		"price": {Min: 150, Max: 250, Mean: 200},
+	}
+	for name, w := range want {
+		got := stats.Fields[name]
+		if got == nil || math.Abs(got.Min-w.Min) > 1e-9 || math.Abs(got.Max-w.Max) > 1e-9 || math.Abs(got.Mean-w.Mean) > 1e-9 {
+			t.Errorf("%s = %+v, want %+v", name, got, w)
+		}
+	}
+	if len(stats.Fields) != len(subsetStatsFields) {
+		t.Errorf("got %d fields, want %d", len(stats.Fields), len(subsetStatsFields))
+	}
+}
+
+// TestGetSubsetStatsEmpty tests that a filter matching nothing returns a zero count and null fields
+func TestGetSubsetStatsEmpty(t *testing.T) {
+	setupTestStore(t)
+
+	stats := getSubsetStatsResponse(t, "?brand=Nobody")
+	if stats.Count != 0 || len(stats.Fields) != len(subsetStatsFields) {
+		t.Fatalf("stats = %+v, want count 0 with every field", stats)
+	}
+	for name, field := range stats.Fields {
+		if field != nil {
+			t.Errorf("%s = %+v, want null", name, field)
+		}
+	}
+}
+
+// TestGetSubsetStatsInvalidFilter tests that a bad filter is rejected
+func TestGetSubsetStatsInvalidFilter(t *testing.T) {
+	setupTestStore(t)
+
+	rr := httptest.NewRecorder()
+	getSubsetStats(rr, httptest.NewRequest("GET", "/api/paddles/stats?surface=Wood", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limits for paddle suggestions. Suggestions are requested on every
+// keystroke, so the response stays small.
+const (
+	maxSuggestions        = 10
+	maxSuggestQueryLength = 100
+)
+
+// Suggestion is a paddle matching a search box query, with just enough to
+// show it and link to its details
+type Suggestion struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// likeEscaper escapes the ILIKE wildcards, so a query matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// parseSuggestQuery reads and trims the q parameter
+func parseSuggestQuery(raw string) (string, error) {
+	q := strings.TrimSpace(raw)
+	if q == "" {
+		return "", fmt.Errorf("q is required")
+	}
+	if err := validateQueryText("q", q); err != nil {
+		return "", err
+	}
+	if utf8.RuneCountInString(q) > maxSuggestQueryLength {
+		return "", fmt.Errorf("q must be at most %d characters", maxSuggestQueryLength)
+	}
+	return q, nil
+}
+
+// SuggestPaddles returns up to limit paddles whose brand, model or full name
+// contains q, case-insensitively. Names starting with q come first, then
+// alphabetical order. Stubs and drafts are left out, since their details are hidden.
+func SuggestPaddles(q string, limit int) ([]Suggestion, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	escaped := likeEscaper.Replace(q)
+	rows, err := timedQuery(ctx, DB, "suggest_paddles", `
+		SELECT
+			p.paddle_id, p.brand || ' ' || p.model
+		FROM
+			paddles p
+		WHERE
+			(p.brand ILIKE $2 OR p.model ILIKE $2 OR p.brand || ' ' || p.model ILIKE $2)
+			AND p.status = 'published'
+			AND EXISTS (
+				SELECT 1
+				FROM paddle_specs s
+				JOIN paddle_performance perf ON s.id = perf.paddle_spec_id
+				WHERE s.paddle_id = p.id
+			)
+		ORDER BY
+			CASE WHEN p.brand ILIKE $1 OR p.model ILIKE $1 OR p.brand || ' ' || p.model ILIKE $1 THEN 0 ELSE 1 END,
+			LOWER(p.brand), LOWER(p.model), p.id
+		LIMIT $3
+	`, escaped+"%", "%"+escaped+"%", limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suggestions := []Suggestion{}
+	for rows.Next() {
+		var s Suggestion
+		if err := rows.Scan(&s.ID, &s.Name); err != nil {
+			return nil, err
+		}
+		suggestions = append(suggestions, s)
+	}
+	return suggestions, rows.Err()
+}
+
+// getPaddleSuggestions handles the API request for search box suggestions
+func getPaddleSuggestions(w http.ResponseWriter, r *http.Request) {
+	q, err := parseSuggestQuery(r.URL.Query().Get("q"))
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	suggestions, err := SuggestPaddles(q, maxSuggestions)
+	if err != nil {
+		log.Printf("Error retrieving paddle suggestions: %v", err)
+		respondWithError(w, "Failed to retrieve suggestions", http.StatusInternalServerError)
+		return
+	}
+
+	response := struct {
+		Suggestions []Suggestion `json:"suggestions"`
+	}{
+		Suggestions: suggestions,
+	}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding suggestions: %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParseSuggestQuery tests trimming and validating the q parameter
+func TestParseSuggestQuery(t *testing.T) {
+	if q, err := parseSuggestQuery("  pur "); err != nil || q != "pur" {
+		t.Errorf("parseSuggestQuery() = %q, %v; want pur", q, err)
+	}
+
+	for _, raw := range []string{"", "   ", strings.Repeat("a", maxSuggestQueryLength+1)} {
+		if _, err := parseSuggestQuery(raw); err == nil {
+			t.Errorf("parseSuggestQuery(%q) expected an error", raw)
+		}
+	}
+}
+
+// TestSuggestPaddles tests that prefix matches rank above substring matches
+func TestSuggestPaddles(t *testing.T) {
+	setupTestDB(t)
+
+	token := "Qz"
+	models := map[string]string{
+		"substring": "Alpha " + token,
+		"prefix":    token + " Pro",
+		"other":     "Pursuit",
+	}
+	ids := map[string]string{}
+	for name, model := range models {
+		paddle := testPaddleInput("Engage", model).ToPaddle()
+		if _, err := SavePaddle(paddle); err != nil {
+			t.Fatalf("Failed to save paddle: %v", err)
+		}
+		ids[name] = paddle.ID
+	}
+
+	suggestions, err := SuggestPaddles(strings.ToLower(token), maxSuggestions)
+	if err != nil {
+		t.Fatalf("SuggestPaddles() returned error: %v", err)
+	}
+
+	// Alphabetically "Alpha ..." comes first, so the order proves prefix ranking
+	if len(suggestions) != 2 || suggestions[0].ID != ids["prefix"] || suggestions[1].ID != ids["substring"] {
+		t.Errorf("SuggestPaddles(%q) = %+v, want [%s %s]", token, suggestions, ids["prefix"], ids["substring"])
+	}
+	if len(suggestions) > 0 && suggestions[0].Name != "Engage "+models["prefix"] {
+		t.Errorf("Name = %q, want %q", suggestions[0].Name, "Engage "+models["prefix"])
+	}
+
+	// Wildcards in the query match literally
+	if suggestions, err := SuggestPaddles(token+"%", maxSuggestions); err != nil || len(suggestions) != 0 {
+		t.Errorf("SuggestPaddles(%q) = %+v, %v; want no matches", token+"%", suggestions, err)
+	}
+}
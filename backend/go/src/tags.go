@@ -0,0 +1,236 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+)
+
+// maxTagLength is the longest tag accepted, matching the paddle_tags column
+const maxTagLength = 50
+
+// ErrTagNotFound is returned when removing a tag the paddle does not have
+var ErrTagNotFound = errors.New("tag not found")
+
+// normalizeTag lowercases and trims a tag and checks its length. Commas are
+// rejected because the list filter splits on them.
+func normalizeTag(raw string) (string, error) {
+	tag := strings.ToLower(strings.TrimSpace(raw))
+	if tag == "" {
+		return "", newValidationError("TAG_EMPTY", "tags must not be empty")
+	}
+	if len(tag) > maxTagLength {
+		return "", newValidationError("TAG_TOO_LONG", "tag %q must be at most %d characters", tag, maxTagLength)
+	}
+	if strings.Contains(tag, ",") {
+		return "", newValidationError("TAG_INVALID", "tag %q must not contain commas", tag)
+	}
+	if err := validateQueryText("tag", tag); err != nil {
+		return "", err
+	}
+	return tag, nil
+}
+
+// normalizeTags normalizes each tag and drops duplicates, keeping first-seen order
+func normalizeTags(raw []string) ([]string, error) {
+	tags := []string{}
+	seen := map[string]bool{}
+	for _, r := range raw {
+		tag, err := normalizeTag(r)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// GetTags returns the tags of a paddle in alphabetical order. Paddles without
+// tags return an empty list.
+func GetTags(paddleId string) ([]string, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	var tags []string
+	err := timedQueryRow(ctx, DB, "get_paddle_tags", `
+		SELECT ARRAY(
+			SELECT t.tag
+			FROM paddle_tags t
+			JOIN paddles p ON p.id = t.paddle_id
+			WHERE p.paddle_id = $1
+			ORDER BY t.tag
+		)
+	`, paddleId).Scan(pq.Array(&tags))
+	if err != nil {
+		return nil, err
+	}
+	if tags == nil {
+		tags = []string{}
+	}
+	return tags, nil
+}
+
+// AddTags attaches tags to a paddle, ignoring ones it already has, and
+// returns its full tag list
+func AddTags(paddleId string, tags []string) ([]string, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	var dbID int
+	err := timedQueryRow(ctx, DB, "find_paddle_for_tags",
+		"SELECT id FROM paddles WHERE paddle_id = $1", paddleId).Scan(&dbID)
+	if err == sql.ErrNoRows {
+		return nil, ErrPaddleNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("error looking up paddle: %w", err)
+	}
+
+	// New tags change the paddle for the changes feed; tags it already has do not
+	_, err = timedExec(ctx, DB, "insert_paddle_tags", `
+		WITH inserted AS (
+			INSERT INTO paddle_tags (paddle_id, tag)
+			SELECT $1, UNNEST($2::text[])
+			ON CONFLICT (paddle_id, tag) DO NOTHING
+			RETURNING paddle_id
+		)
+		UPDATE paddles SET updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND EXISTS (SELECT 1 FROM inserted)
+	`, dbID, pq.Array(tags))
+	if err != nil {
+		return nil, err
+	}
+
+	return GetTags(paddleId)
+}
+
+// RemoveTag detaches a tag from a paddle and returns its remaining tags
+func RemoveTag(paddleId, tag string) ([]string, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	var dbID int
+	err := timedQueryRow(ctx, DB, "find_paddle_for_tags",
+		"SELECT id FROM paddles WHERE paddle_id = $1", paddleId).Scan(&dbID)
+	if err == sql.ErrNoRows {
+		return nil, ErrPaddleNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("error looking up paddle: %w", err)
+	}
+
+	// The update touches the paddle only when the tag was there to delete
+	result, err := timedExec(ctx, DB, "delete_paddle_tag", `
+		WITH deleted AS (
+			DELETE FROM paddle_tags WHERE paddle_id = $1 AND tag = $2
+			RETURNING paddle_id
+		)
+		UPDATE paddles SET updated_at = CURRENT_TIMESTAMP
+		WHERE id IN (SELECT paddle_id FROM deleted)
+	`, dbID, tag)
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if deleted == 0 {
+		return nil, ErrTagNotFound
+	}
+
+	return GetTags(paddleId)
+}
+
+// respondWithTags writes a paddle's tag list
+func respondWithTags(w http.ResponseWriter, paddleId string, tags []string) {
+	response := struct {
+		ID   string   `json:"id"`
+		Tags []string `json:"tags"`
+	}{ID: paddleId, Tags: tags}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding paddle tags: %v", err)
+	}
+}
+
+// addPaddleTags handles the API request for tagging a paddle
+func addPaddleTags(w http.ResponseWriter, r *http.Request) {
+	paddleId := paddleIDFromRequest(r)
+
+	if err := validatePaddleID(paddleId); err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid paddle ID: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	var request struct {
+		Tags []string `json:"tags"`
+	}
+	if err := decodeJSONBody(r.Body, &request); err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	if len(request.Tags) == 0 {
+		respondWithValidationError(w, r, newValidationError("TAGS_REQUIRED", "at least one tag is required"))
+		return
+	}
+
+	tags, err := normalizeTags(request.Tags)
+	if err != nil {
+		respondWithValidationError(w, r, err)
+		return
+	}
+
+	tags, err = store.AddTags(paddleId, tags)
+	if err != nil {
+		if errors.Is(err, ErrPaddleNotFound) {
+			respondWithError(w, "Paddle not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Error adding paddle tags: %v", err)
+		respondWithError(w, "Failed to add paddle tags", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithTags(w, paddleId, tags)
+}
+
+// removePaddleTag handles the API request for removing one tag from a paddle
+func removePaddleTag(w http.ResponseWriter, r *http.Request) {
+	paddleId := paddleIDFromRequest(r)
+
+	if err := validatePaddleID(paddleId); err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid paddle ID: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	tag, err := normalizeTag(mux.Vars(r)["tag"])
+	if err != nil {
+		respondWithValidationError(w, r, err)
+		return
+	}
+
+	tags, err := store.RemoveTag(paddleId, tag)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrPaddleNotFound):
+			respondWithError(w, "Paddle not found", http.StatusNotFound)
+		case errors.Is(err, ErrTagNotFound):
+			respondWithError(w, "Tag not found", http.StatusNotFound)
+		default:
+			log.Printf("Error removing paddle tag: %v", err)
+			respondWithError(w, "Failed to remove paddle tag", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	respondWithTags(w, paddleId, tags)
+}
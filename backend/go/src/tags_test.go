@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// setupTagRouter returns a router with the tag and details routes
+func setupTagRouter() *mux.Router {
+	router := mux.NewRouter()
+	router.HandleFunc("/api/paddles/{id}", getPaddleDetails).Methods("GET")
+	router.HandleFunc("/api/paddles/{id}/tags", addPaddleTags).Methods("POST")
+	router.HandleFunc("/api/paddles/{id}/tags/{tag}", removePaddleTag).Methods("DELETE")
+	return router
+}
+
+// saveTagTestPaddles stores n complete paddles with IDs engage-tagged-0 onwards
+func saveTagTestPaddles(t *testing.T, n int) {
+	t.Helper()
+	for i := range n {
+		paddle := &Paddle{
+			ID:          fmt.Sprintf("engage-tagged-%d", i),
+			Metadata:    Metadata{Brand: "Engage", Model: fmt.Sprintf("Tagged %d", i)},
+			Specs:       testSpecs,
+			Performance: testPerformance,
+		}
+		if _, err := store.SavePaddle(paddle); err != nil {
+			t.Fatalf("SavePaddle() error: %v", err)
+		}
+	}
+}
+
+// tagPaddle posts tags for a paddle and returns the response
+func tagPaddle(router *mux.Router, paddleId, body string) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/paddles/"+paddleId+"/tags", bytes.NewBufferString(body)))
+	return rr
+}
+
+// TestNormalizeTags tests trimming, lowercasing, deduping and rejected tags
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      []string
+		want     []string
+		wantCode string
+	}{
+		{name: "Lowercased and trimmed", raw: []string{" Beginner-Friendly "}, want: []string{"beginner-friendly"}},
+		{name: "Duplicates dropped", raw: []string{"Power", "power", "POWER ", "control"}, want: []string{"power", "control"}},
+		{name: "Empty tag", raw: []string{"power", "  "}, wantCode: "TAG_EMPTY"},
+		{name: "Too long", raw: []string{string(bytes.Repeat([]byte("a"), maxTagLength+1))}, wantCode: "TAG_TOO_LONG"},
+		{name: "Comma", raw: []string{"power,control"}, wantCode: "TAG_INVALID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeTags(tt.raw)
+			if code := validationCode(err); code != tt.wantCode {
+				t.Fatalf("normalizeTags() error code = %q (%v), want %q", code, err, tt.wantCode)
+			}
+			if tt.wantCode == "" && !slices.Equal(got, tt.want) {
+				t.Errorf("normalizeTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestPaddleTags tests adding and removing tags and seeing them in the details response
+func TestPaddleTags(t *testing.T) {
+	setupTestStore(t)
+	saveTagTestPaddles(t, 1)
+	router := setupTagRouter()
+
+	rr := tagPaddle(router, "engage-tagged-0", `{"tags": ["Tournament-Approved", "beginner-friendly", "BEGINNER-FRIENDLY"]}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("POST tags status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	// Tagging again with a tag the paddle has must not duplicate it
+	rr = tagPaddle(router, "engage-tagged-0", `{"tags": ["beginner-friendly", "power"]}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("POST tags status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	var tagged struct {
+		ID   string   `json:"id"`
+		Tags []string `json:"tags"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &tagged); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	want := []string{"beginner-friendly", "power", "tournament-approved"}
+	if tagged.ID != "engage-tagged-0" || !slices.Equal(tagged.Tags, want) {
+		t.Errorf("POST tags = %+v, want tags %v", tagged, want)
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("DELETE", "/api/paddles/engage-tagged-0/tags/Power", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("DELETE tag status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/paddles/engage-tagged-0", nil))
+	var details Paddle
+	if err := json.Unmarshal(rr.Body.Bytes(), &details); err != nil {
+		t.Fatalf("Failed to decode details: %v", err)
+	}
+	want = []string{"beginner-friendly", "tournament-approved"}
+	if !slices.Equal(details.Tags, want) {
+		t.Errorf("details tags = %v, want %v", details.Tags, want)
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		wantCode int
+	}{
+		{name: "Unknown paddle", method: "POST", path: "/api/paddles/engage-missing/tags", body: `{"tags": ["power"]}`, wantCode: http.StatusNotFound},
+		{name: "No tags", method: "POST", path: "/api/paddles/engage-tagged-0/tags", body: `{"tags": []}`, wantCode: http.StatusBadRequest},
+		{name: "Empty tag", method: "POST", path: "/api/paddles/engage-tagged-0/tags", body: `{"tags": [" "]}`, wantCode: http.StatusBadRequest},
+		{name: "Tag not on paddle", method: "DELETE", path: "/api/paddles/engage-tagged-0/tags/power", wantCode: http.StatusNotFound},
+		{name: "Untag unknown paddle", method: "DELETE", path: "/api/paddles/engage-missing/tags/power", wantCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body)))
+			if rr.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d: %s", rr.Code, tt.wantCode, rr.Body.String())
+			}
+		})
+	}
+}
+
+// TestGetPaddlesListTagFilter tests that filtering by tags returns only paddles with every tag
+func TestGetPaddlesListTagFilter(t *testing.T) {
+	setupTestStore(t)
+	saveTagTestPaddles(t, 3)
+	router := setupTagRouter()
+
+	for id, body := range map[string]string{
+		"engage-tagged-0": `{"tags": ["beginner-friendly", "tournament-approved"]}`,
+		"engage-tagged-1": `{"tags": ["beginner-friendly"]}`,
+	} {
+		if rr := tagPaddle(router, id, body); rr.Code != http.StatusOK {
+			t.Fatalf("POST tags for %s status = %d: %s", id, rr.Code, rr.Body.String())
+		}
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		wantCode int
+		wantIDs  []string
+	}{
+		{name: "One tag", query: "tag=beginner-friendly", wantCode: http.StatusOK, wantIDs: []string{"engage-tagged-0", "engage-tagged-1"}},
+		{name: "Every tag required", query: "tag=beginner-friendly&tag=Tournament-Approved", wantCode: http.StatusOK, wantIDs: []string{"engage-tagged-0"}},
+		{name: "Comma-separated", query: "tag=tournament-approved,beginner-friendly", wantCode: http.StatusOK, wantIDs: []string{"engage-tagged-0"}},
+		{name: "Unused tag", query: "tag=power", wantCode: http.StatusOK, wantIDs: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			getPaddlesList(rr, httptest.NewRequest("GET", "/api/paddles?"+tt.query, nil))
+			if rr.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rr.Code, tt.wantCode, rr.Body.String())
+			}
+
+			var paddles []struct {
+				ID string `json:"id"`
+			}
+			if err := json.Unmarshal(rr.Body.Bytes(), &paddles); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			var ids []string
+			for _, p := range paddles {
+				ids = append(ids, p.ID)
+			}
+			if !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("paddles = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported values for TIME_FORMAT
+const (
+	timeFormatRFC3339 = "rfc3339"
+	timeFormatUnixMS  = "unixms"
+)
+
+// timeFormat controls how Time values are written to JSON
+var timeFormat = timeFormatRFC3339
+
+// loadTimeFormat reads the JSON time format from the TIME_FORMAT environment variable
+func loadTimeFormat() (string, error) {
+	format := strings.ToLower(getEnv("TIME_FORMAT", timeFormatRFC3339))
+	if format != timeFormatRFC3339 && format != timeFormatUnixMS {
+		return "", fmt.Errorf("TIME_FORMAT must be %q or %q", timeFormatRFC3339, timeFormatUnixMS)
+	}
+	return format, nil
+}
+
+// Time is a timestamp that is always held in UTC and marshals to JSON
+// according to the configured TIME_FORMAT
+type Time struct {
+	time.Time
+}
+
+// NewTime returns t normalized to UTC
+func NewTime(t time.Time) Time {
+	return Time{t.UTC()}
+}
+
+// MarshalJSON writes the time as an RFC3339 string or as epoch milliseconds
+func (t Time) MarshalJSON() ([]byte, error) {
+	if timeFormat == timeFormatUnixMS {
+		return []byte(strconv.FormatInt(t.UTC().UnixMilli(), 10)), nil
+	}
+	return json.Marshal(t.UTC().Format(time.RFC3339))
+}
+
+// UnmarshalJSON accepts either an RFC3339 string or epoch milliseconds
+func (t *Time) UnmarshalJSON(data []byte) error {
+	var ms int64
+	if err := json.Unmarshal(data, &ms); err == nil {
+		*t = NewTime(time.UnixMilli(ms))
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("time must be an RFC3339 string or epoch milliseconds")
+	}
+	parsed, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return fmt.Errorf("time must be an RFC3339 string or epoch milliseconds")
+	}
+	*t = NewTime(parsed)
+	return nil
+}
+
+// Scan implements sql.Scanner, normalizing database timestamps to UTC
+func (t *Time) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*t = Time{}
+	case time.Time:
+		*t = NewTime(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Time", src)
+	}
+	return nil
+}
+
+// Value implements driver.Valuer so Time can be used as a query argument
+func (t Time) Value() (driver.Value, error) {
+	if t.IsZero() {
+		return nil, nil
+	}
+	return t.UTC(), nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// TestTimeMarshalJSON tests both configured JSON time formats
+func TestTimeMarshalJSON(t *testing.T) {
+	defer func() { timeFormat = timeFormatRFC3339 }()
+
+	// 2024-03-01 12:30:00 in UTC-5 is 17:30 UTC
+	local := time.Date(2024, 3, 1, 12, 30, 0, 0, time.FixedZone("EST", -5*60*60))
+	ts := NewTime(local)
+
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{name: "RFC3339 in UTC", format: timeFormatRFC3339, want: `"2024-03-01T17:30:00Z"`},
+		{name: "Unix milliseconds", format: timeFormatUnixMS, want: "1709314200000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeFormat = tt.format
+			got, err := json.Marshal(ts)
+			if err != nil {
+				t.Fatalf("json.Marshal() returned error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("json.Marshal() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestTimeUnmarshalJSON tests decoding both formats back to the same UTC instant
+func TestTimeUnmarshalJSON(t *testing.T) {
+	want := time.Date(2024, 3, 1, 17, 30, 0, 0, time.UTC)
+
+	for _, input := range []string{`"2024-03-01T12:30:00-05:00"`, "1709314200000"} {
+		var ts Time
+		if err := json.Unmarshal([]byte(input), &ts); err != nil {
+			t.Fatalf("json.Unmarshal(%s) returned error: %v", input, err)
+		}
+		if !ts.Equal(want) || ts.Location() != time.UTC {
+			t.Errorf("json.Unmarshal(%s) = %v, want %v in UTC", input, ts.Time, want)
+		}
+	}
+
+	var ts Time
+	if err := json.Unmarshal([]byte(`"yesterday"`), &ts); err == nil {
+		t.Error("json.Unmarshal() should fail for a non-time string")
+	}
+}
+
+// TestZeroTimeOmitted tests that unset timestamps are left out of paddle JSON
+func TestZeroTimeOmitted(t *testing.T) {
+	body, err := json.Marshal(&Paddle{ID: "engage-pursuit-mx-6.0"})
+	if err != nil {
+		t.Fatalf("json.Marshal() returned error: %v", err)
+	}
+
+	var decoded map[string]json.RawMessage
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() returned error: %v", err)
+	}
+	if _, ok := decoded["created_at"]; ok {
+		t.Error("Expected created_at to be omitted when unset")
+	}
+}
+
+// TestLoadTimeFormat tests validating the TIME_FORMAT setting
+func TestLoadTimeFormat(t *testing.T) {
+	t.Setenv("TIME_FORMAT", "UNIXMS")
+	if format, err := loadTimeFormat(); err != nil || format != timeFormatUnixMS {
+		t.Errorf("loadTimeFormat() = %q, %v; want unixms", format, err)
+	}
+
+	t.Setenv("TIME_FORMAT", "iso")
+	if _, err := loadTimeFormat(); err == nil {
+		t.Error("loadTimeFormat() should fail for an unknown format")
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+)
+
+// unitSystem selects the units measurements are served in
+type unitSystem string
+
+const (
+	metricUnits   unitSystem = "metric"
+	imperialUnits unitSystem = "imperial"
+)
+
+// Core thickness is stored in millimetres. Real paddle cores fall well inside 8–25mm.
+const (
+	coreUnitMM   = "mm"
+	coreUnitInch = "in"
+	minCoreMM    = 8.0
+	maxCoreMM    = 25.0
+	mmPerInch    = 25.4
+)
+
+// parseUnits reads the ?units= query parameter, defaulting to metric
+func parseUnits(raw string) (unitSystem, error) {
+	switch unitSystem(raw) {
+	case "", metricUnits:
+		return metricUnits, nil
+	case imperialUnits:
+		return imperialUnits, nil
+	default:
+		return "", fmt.Errorf("units must be %q or %q", metricUnits, imperialUnits)
+	}
+}
+
+// mmToInches converts millimetres to inches at floatPrecision
+func mmToInches(mm float64) float64 {
+	return roundTo(mm/mmPerInch, floatPrecision)
+}
+
+// convertUnits rewrites the stored core thickness into the requested unit
+// system and labels its unit so clients never have to guess
+func (s *Specs) convertUnits(units unitSystem) {
+	if units != imperialUnits {
+		s.CoreUnit = coreUnitMM
+		return
+	}
+	s.Core = mmToInches(s.Core)
+	s.CoreUnit = coreUnitInch
+}
+
+// convertUnits rewrites the paddle's stored measurements, specs and quoted
+// ranges, into the requested unit system
+func (p *Paddle) convertUnits(units unitSystem) {
+	p.Specs.convertUnits(units)
+	if units != imperialUnits {
+		return
+	}
+	if r, ok := p.SpecRanges["core"]; ok {
+		p.SpecRanges["core"] = SpecRange{Min: mmToInches(r.Min), Max: mmToInches(r.Max)}
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestParseUnits tests the accepted values of the units query parameter
+func TestParseUnits(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    unitSystem
+		wantErr bool
+	}{
+		{raw: "", want: metricUnits},
+		{raw: "metric", want: metricUnits},
+		{raw: "imperial", want: imperialUnits},
+		{raw: "Imperial", wantErr: true},
+		{raw: "furlongs", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := parseUnits(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseUnits(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseUnits(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestConvertUnits tests converting core thickness between millimetres and inches
+func TestConvertUnits(t *testing.T) {
+	tests := []struct {
+		name      string
+		units     unitSystem
+		core      float64
+		wantCore  float64
+		wantUnit  string
+		wantRange SpecRange
+	}{
+		{name: "Metric is unchanged", units: metricUnits, core: 16, wantCore: 16, wantUnit: "mm", wantRange: SpecRange{Min: 15.5, Max: 16.5}},
+		{name: "Imperial 16mm", units: imperialUnits, core: 16, wantCore: 0.63, wantUnit: "in", wantRange: SpecRange{Min: 0.61, Max: 0.65}},
+		{name: "Imperial minimum", units: imperialUnits, core: minCoreMM, wantCore: 0.31, wantUnit: "in", wantRange: SpecRange{Min: 0.61, Max: 0.65}},
+		{name: "Imperial maximum", units: imperialUnits, core: maxCoreMM, wantCore: 0.98, wantUnit: "in", wantRange: SpecRange{Min: 0.61, Max: 0.65}},
+		{name: "Imperial exact inch", units: imperialUnits, core: 25.4, wantCore: 1, wantUnit: "in", wantRange: SpecRange{Min: 0.61, Max: 0.65}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paddle := &Paddle{
+				Specs:      Specs{Core: tt.core, PaddleLength: 16.5},
+				SpecRanges: map[string]SpecRange{"core": {Min: 15.5, Max: 16.5}},
+			}
+			paddle.convertUnits(tt.units)
+
+			if paddle.Specs.Core != tt.wantCore || paddle.Specs.CoreUnit != tt.wantUnit {
+				t.Errorf("core = %v %s, want %v %s", paddle.Specs.Core, paddle.Specs.CoreUnit, tt.wantCore, tt.wantUnit)
+			}
+			if got := paddle.SpecRanges["core"]; got != tt.wantRange {
+				t.Errorf("core range = %+v, want %+v", got, tt.wantRange)
+			}
+			if paddle.Specs.PaddleLength != 16.5 {
+				t.Errorf("paddle length changed to %v, want 16.5", paddle.Specs.PaddleLength)
+			}
+		})
+	}
+}
+
+// TestListUnits tests that the list cards carry the core in the requested
+// units, like the details endpoint
+func TestListUnits(t *testing.T) {
+	setupTestStore(t)
+	saveTestPaddle(t, testPaddleInput("Engage", "Pursuit MX 6.0"))
+
+	tests := []struct {
+		query    string
+		wantCore float64
+		wantUnit string
+	}{
+		{query: "", wantCore: 15.0, wantUnit: coreUnitMM},
+		{query: "?units=imperial", wantCore: mmToInches(15.0), wantUnit: coreUnitInch},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		getPaddlesList(rr, httptest.NewRequest("GET", "/api/paddles"+tt.query, nil))
+		var cards []paddleCard
+		if err := json.Unmarshal(rr.Body.Bytes(), &cards); err != nil {
+			t.Fatalf("Failed to decode response %s: %v", rr.Body.String(), err)
+		}
+		if len(cards) != 1 || cards[0].Specs.Core != tt.wantCore || cards[0].Specs.CoreUnit != tt.wantUnit {
+			t.Errorf("GET /api/paddles%s cards = %+v, want core %v %s", tt.query, cards, tt.wantCore, tt.wantUnit)
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	getPaddlesList(rr, httptest.NewRequest("GET", "/api/paddles?units=furlongs", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("unknown units returned %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
@@ -3,16 +3,72 @@ package main
 import (
 	"errors"
 	"fmt"
+	"net/url"
+	"slices"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
-// validatePaddleInput validates the PaddleInput struct
-func validatePaddleInput(input *PaddleInput) error {
+// ValidationError is a validation failure with a stable machine-readable
+// code, so clients can branch on the code and show the message. The format
+// arguments are kept so the message can be localized.
+type ValidationError struct {
+	Code    string
+	Message string
+	args    []interface{}
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// newValidationError returns a ValidationError with a formatted message
+func newValidationError(code, format string, args ...interface{}) error {
+	return &ValidationError{Code: code, Message: fmt.Sprintf(format, args...), args: args}
+}
+
+// validationCode returns the code of the ValidationError wrapped in err, or ""
+func validationCode(err error) string {
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		return verr.Code
+	}
+	return ""
+}
+
+// validationProfile selects which sections of a PaddleInput are required
+type validationProfile string
+
+const (
+	// fullProfile requires metadata, specs and performance
+	fullProfile validationProfile = "full"
+	// stubProfile requires only metadata; specs and performance are validated
+	// when given and may otherwise be left out to be filled in later
+	stubProfile validationProfile = "stub"
+)
+
+// validatePaddleInput validates the PaddleInput struct against a profile,
+// then runs any registered validation hooks
+func validatePaddleInput(input *PaddleInput, profile validationProfile) error {
+	if err := validateBuiltIn(input, profile); err != nil {
+		return err
+	}
+	return runValidationHooks(input)
+}
+
+// validateBuiltIn applies the rules every deployment enforces
+func validateBuiltIn(input *PaddleInput, profile validationProfile) error {
 	// Validate Metadata
 	if err := validateMetadata(&input.Metadata); err != nil {
 		return fmt.Errorf("invalid metadata: %w", err)
 	}
 
+	if profile == stubProfile {
+		return validateStubInput(input)
+	}
+
 	// Validate Specs
 	if err := validateSpecs(&input.Specs); err != nil {
 		return fmt.Errorf("invalid specs: %w", err)
@@ -23,111 +79,309 @@ func validatePaddleInput(input *PaddleInput) error {
 		return fmt.Errorf("invalid performance: %w", err)
 	}
 
+	// Validate quoted spec ranges against the measured specs
+	if err := validateSpecRanges(&input.Specs, input.SpecRanges); err != nil {
+		return fmt.Errorf("invalid spec ranges: %w", err)
+	}
+
+	return nil
+}
+
+// validateStubInput validates the optional sections of a stub. Performance is
+// stored against the specs, so it cannot be given without them.
+func validateStubInput(input *PaddleInput) error {
+	hasSpecs := input.Specs != (Specs{})
+	hasPerformance := input.Performance != (Performance{})
+
+	if hasSpecs {
+		if err := validateSpecs(&input.Specs); err != nil {
+			return fmt.Errorf("invalid specs: %w", err)
+		}
+	} else if hasPerformance || len(input.SpecRanges) > 0 {
+		return newValidationError("SPECS_REQUIRED", "invalid specs: specs are required when performance or spec ranges are given")
+	}
+
+	if hasPerformance {
+		if err := validatePerformance(&input.Performance); err != nil {
+			return fmt.Errorf("invalid performance: %w", err)
+		}
+	}
+
+	if err := validateSpecRanges(&input.Specs, input.SpecRanges); err != nil {
+		return fmt.Errorf("invalid spec ranges: %w", err)
+	}
+
 	return nil
 }
 
 // validateMetadata validates the Metadata struct
 func validateMetadata(metadata *Metadata) error {
 	if strings.TrimSpace(metadata.Brand) == "" {
-		return errors.New("brand is required")
+		return newValidationError("BRAND_REQUIRED", "brand is required")
 	}
 
 	if strings.TrimSpace(metadata.Model) == "" {
-		return errors.New("model is required")
+		return newValidationError("MODEL_REQUIRED", "model is required")
+	}
+
+	if err := validateText("brand", metadata.Brand); err != nil {
+		return err
+	}
+	if err := validateText("model", metadata.Model); err != nil {
+		return err
+	}
+
+	// New paddles take their ID from the metadata, and a brand and model of
+	// mostly symbols leave too little of it, so reject them here rather than
+	// let the save fail
+	if id := idGenerator.GenerateID(*metadata); validatePaddleID(id) != nil {
+		return newValidationError("PADDLE_ID_INVALID", "brand and model must give a paddle ID of %d to %d letters, digits, '.', '+' or '-', got %q", minPaddleIDLength, maxPaddleIDLength, id)
+	}
+
+	// Year is optional, but must be plausible when given
+	if metadata.Year != nil {
+		if err := validateYear(*metadata.Year); err != nil {
+			return err
+		}
+	}
+
+	// SKU and product URL are optional, but must be well formed when given
+	if len(metadata.SKU) > maxSKULength {
+		return newValidationError("SKU_TOO_LONG", "sku must be at most %d characters", maxSKULength)
+	}
+	if strings.TrimSpace(metadata.SKU) != metadata.SKU {
+		return newValidationError("SKU_WHITESPACE", "sku must not have leading or trailing whitespace")
+	}
+
+	if metadata.ProductURL != "" {
+		if err := validateProductURL(metadata.ProductURL); err != nil {
+			return err
+		}
+	}
+
+	// Price is optional, but must be positive when given
+	if metadata.Price != nil && *metadata.Price <= 0 {
+		return newValidationError("PRICE_NOT_POSITIVE", "price must be a positive number")
 	}
 
 	// SerialCode is optional, so no validation needed
 	return nil
 }
 
+// validateText rejects control and other non-printable characters, such as
+// the zero-width spaces that come along when text is copied from a PDF.
+// Letters, marks and symbols from any script are allowed, as is the plain space.
+func validateText(field, value string) error {
+	if i := strings.IndexFunc(value, func(r rune) bool { return !unicode.IsPrint(r) }); i >= 0 {
+		r, _ := utf8.DecodeRuneInString(value[i:])
+		return newValidationError("TEXT_NOT_PRINTABLE", "%s contains non-printable character %U at position %d", field, r, i)
+	}
+	return nil
+}
+
+// maxSKULength matches the size of the paddles.sku column
+const maxSKULength = 64
+
+// validateProductURL checks that a product page link is an absolute http(s) URL
+func validateProductURL(raw string) error {
+	if !isHTTPURL(raw) {
+		return newValidationError("PRODUCT_URL_INVALID", "product_url must be an absolute http or https URL")
+	}
+	return nil
+}
+
+// isHTTPURL reports whether raw is an absolute http or https URL
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// minPaddleYear is the year pickleball was invented; no paddle predates it
+const minPaddleYear = 1965
+
+// maxPaddleYear is the latest plausible release year: paddles are announced ahead of release
+func maxPaddleYear() int {
+	return time.Now().Year() + 1
+}
+
+// validateYear checks that a release year is between 1965 and next year
+func validateYear(year int) error {
+	maxYear := maxPaddleYear()
+	if year < minPaddleYear || year > maxYear {
+		return newValidationError("YEAR_OUT_OF_RANGE", "year must be between %d and %d", minPaddleYear, maxYear)
+	}
+	return nil
+}
+
 // validateSpecs validates the Specs struct
 func validateSpecs(specs *Specs) error {
 	// Validate Shape
-	validShapes := map[PaddleShape]bool{
-		Elongated: true,
-		Hybrid:    true,
-		WideBody:  true,
-	}
-
-	if !validShapes[specs.Shape] {
-		return fmt.Errorf("invalid shape: must be one of %v", []PaddleShape{Elongated, Hybrid, WideBody})
+	if !slices.Contains(paddleShapes, specs.Shape) {
+		return newValidationError("SHAPE_INVALID", "invalid shape: must be one of %v", paddleShapes)
 	}
 
 	// Validate Surface
 	if strings.TrimSpace(specs.Surface) == "" {
-		return errors.New("surface is required")
+		return newValidationError("SURFACE_REQUIRED", "surface is required")
+	}
+	if err := validateText("surface", specs.Surface); err != nil {
+		return err
 	}
 
 	// Validate numeric fields
 	if specs.AverageWeight <= 0 {
-		return errors.New("average weight must be greater than 0")
+		return newValidationError("AVERAGE_WEIGHT_NOT_POSITIVE", "average weight must be greater than 0")
 	}
 
 	if specs.Core <= 0 {
-		return errors.New("core must be greater than 0")
+		return newValidationError("CORE_NOT_POSITIVE", "core must be greater than 0")
+	}
+
+	if specs.Core < minCoreMM || specs.Core > maxCoreMM {
+		return newValidationError("CORE_OUT_OF_RANGE", "core must be between %gmm and %gmm", minCoreMM, maxCoreMM)
+	}
+
+	if specs.CoreUnit != "" && specs.CoreUnit != coreUnitMM {
+		return newValidationError("CORE_UNIT_INVALID", "core must be given in %s", coreUnitMM)
 	}
 
 	if specs.PaddleLength <= 0 {
-		return errors.New("paddle length must be greater than 0")
+		return newValidationError("PADDLE_LENGTH_NOT_POSITIVE", "paddle length must be greater than 0")
 	}
 
 	if specs.PaddleWidth <= 0 {
-		return errors.New("paddle width must be greater than 0")
+		return newValidationError("PADDLE_WIDTH_NOT_POSITIVE", "paddle width must be greater than 0")
 	}
 
 	if specs.GripLength <= 0 {
-		return errors.New("grip length must be greater than 0")
+		return newValidationError("GRIP_LENGTH_NOT_POSITIVE", "grip length must be greater than 0")
 	}
 
 	if strings.TrimSpace(specs.GripType) == "" {
-		return errors.New("grip type is required")
+		return newValidationError("GRIP_TYPE_REQUIRED", "grip type is required")
+	}
+	if err := validateText("grip type", specs.GripType); err != nil {
+		return err
 	}
 
 	if specs.GripCircumference <= 0 {
-		return errors.New("grip circumference must be greater than 0")
+		return newValidationError("GRIP_CIRCUMFERENCE_NOT_POSITIVE", "grip circumference must be greater than 0")
+	}
+
+	if err := validateGripLength(specs); err != nil {
+		return err
+	}
+
+	if specs.EdgeGuard != "" && !slices.Contains(paddleEdgeGuards, specs.EdgeGuard) {
+		return newValidationError("EDGE_GUARD_INVALID", "invalid edge guard: must be one of %v", paddleEdgeGuards)
+	}
+
+	if specs.HandleType != "" && !slices.Contains(paddleHandleTypes, specs.HandleType) {
+		return newValidationError("HANDLE_TYPE_INVALID", "invalid handle type: must be one of %v", paddleHandleTypes)
+	}
+
+	if (specs.SurfaceFront == "") != (specs.SurfaceBack == "") {
+		return newValidationError("SURFACE_SIDES_INCOMPLETE", "surface_front and surface_back must be given together")
+	}
+	for _, side := range []struct{ name, value string }{
+		{"surface_front", specs.SurfaceFront},
+		{"surface_back", specs.SurfaceBack},
+	} {
+		if side.value != "" && !slices.Contains(paddleSurfaces, side.value) {
+			return newValidationError("SURFACE_SIDE_INVALID", "invalid %s: must be one of %v", side.name, paddleSurfaces)
+		}
 	}
 
 	return nil
 }
 
+// validateGripLength checks that the grip is shorter than the paddle itself.
+// A grip as long as the paddle usually means a unit or field mix-up.
+func validateGripLength(specs *Specs) error {
+	if specs.GripLength >= specs.PaddleLength {
+		return newValidationError("GRIP_LONGER_THAN_PADDLE", "grip length (%v) must be less than paddle length (%v)", specs.GripLength, specs.PaddleLength)
+	}
+	return nil
+}
+
+// Power and pop are rated on a 0-100 scale
+const (
+	minRating = 0.0
+	maxRating = 100.0
+)
+
 // validatePerformance validates the Performance struct
 func validatePerformance(performance *Performance) error {
-	// Validate Power (assuming it's on a scale of 0-100)
-	if performance.Power < 0 || performance.Power > 100 {
-		return errors.New("power must be between 0 and 100")
+	// Validate Power
+	if performance.Power < minRating || performance.Power > maxRating {
+		return newValidationError("POWER_OUT_OF_RANGE", "power must be between %g and %g", minRating, maxRating)
 	}
 
-	// Validate Pop (assuming it's on a scale of 0-100)
-	if performance.Pop < 0 || performance.Pop > 100 {
-		return errors.New("pop must be between 0 and 100")
+	// Validate Pop
+	if performance.Pop < minRating || performance.Pop > maxRating {
+		return newValidationError("POP_OUT_OF_RANGE", "pop must be between %g and %g", minRating, maxRating)
 	}
 
 	// Validate Spin (assuming it's RPM and must be positive)
 	if performance.Spin < 0 {
-		return errors.New("spin must be non-negative")
+		return newValidationError("SPIN_NEGATIVE", "spin must be non-negative")
 	}
 
 	// Validate weights (must be positive)
 	if performance.TwistWeight <= 0 {
-		return errors.New("twist weight must be greater than 0")
+		return newValidationError("TWIST_WEIGHT_NOT_POSITIVE", "twist weight must be greater than 0")
 	}
 
 	if performance.SwingWeight <= 0 {
-		return errors.New("swing weight must be greater than 0")
+		return newValidationError("SWING_WEIGHT_NOT_POSITIVE", "swing weight must be greater than 0")
 	}
 
 	// Validate balance point (must be positive)
 	if performance.BalancePoint <= 0 {
-		return errors.New("balance point must be greater than 0")
+		return newValidationError("BALANCE_POINT_NOT_POSITIVE", "balance point must be greater than 0")
+	}
+
+	// Stddevs are optional, but a spread can never be negative
+	stddevs := performance.stddevs()
+	for _, metric := range performanceMetrics {
+		if stddev := *stddevs[metric]; stddev != nil && *stddev < 0 {
+			return newValidationError("STDDEV_NEGATIVE", "%s_stddev must be non-negative", metric)
+		}
 	}
 
 	return nil
 }
 
-// validatePaddleID validates a paddle ID
+// Paddle ID length bounds. The longest is the size of the paddles.paddle_id
+// column and the shortest is a one-letter brand and model, as in "x-1".
+const (
+	minPaddleIDLength = 3
+	maxPaddleIDLength = 100
+)
+
+// validatePaddleID validates the format of a paddle ID, so malformed IDs are
+// rejected before reaching the database. An ID is segments of letters, digits,
+// '.' and '+' joined by single hyphens, as generatePaddleID produces. Case is
+// not checked, since lookups normalize IDs to lowercase first.
 func validatePaddleID(id string) error {
 	if strings.TrimSpace(id) == "" {
 		return errors.New("paddle ID is required")
 	}
+	if n := utf8.RuneCountInString(id); n < minPaddleIDLength || n > maxPaddleIDLength {
+		return fmt.Errorf("paddle ID must be %d to %d characters, got %d", minPaddleIDLength, maxPaddleIDLength, n)
+	}
+	if i := strings.IndexFunc(id, unicode.IsSpace); i >= 0 {
+		return fmt.Errorf("paddle ID must not contain whitespace (at position %d)", i)
+	}
+	if i := strings.IndexFunc(id, func(r rune) bool { return r != '-' && !isPaddleIDRune(r) }); i >= 0 {
+		r, _ := utf8.DecodeRuneInString(id[i:])
+		return fmt.Errorf("paddle ID contains illegal character %q at position %d: only letters, digits, '.', '+' and '-' are allowed", r, i)
+	}
+	if strings.HasPrefix(id, "-") || strings.HasSuffix(id, "-") {
+		return errors.New("paddle ID must not start or end with a hyphen")
+	}
+	if strings.Contains(id, "--") {
+		return errors.New("paddle ID must not contain consecutive hyphens")
+	}
 	return nil
 }
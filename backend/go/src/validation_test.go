@@ -8,41 +8,17 @@ import (
 // TestValidatePaddleInput tests the validatePaddleInput function
 func TestValidatePaddleInput(t *testing.T) {
 	// Valid input
-	validInput := &PaddleInput{
-		Metadata: Metadata{
-			Brand: "Engage",
-			Model: "Pursuit MX 6.0",
-		},
-		Specs: Specs{
-			Shape:             Hybrid,
-			Surface:           "Composite",
-			AverageWeight:     220.0,
-			Core:              15.0,
-			PaddleLength:      16.5,
-			PaddleWidth:       7.5,
-			GripLength:        4.5,
-			GripType:          "Comfort",
-			GripCircumference: 4.0,
-		},
-		Performance: Performance{
-			Power:        75.0,
-			Pop:          70.0,
-			Spin:         3000.0,
-			TwistWeight:  200.0,
-			SwingWeight:  220.0,
-			BalancePoint: 30.0,
-		},
-	}
+	validInput := testPaddleInput("Engage", "Pursuit MX 6.0")
 
 	// Test valid input
-	if err := validatePaddleInput(validInput); err != nil {
+	if err := validatePaddleInput(validInput, fullProfile); err != nil {
 		t.Errorf("validatePaddleInput failed with valid input: %v", err)
 	}
 
 	// Test with invalid metadata
 	invalidMetadataInput := *validInput
 	invalidMetadataInput.Metadata.Brand = ""
-	if err := validatePaddleInput(&invalidMetadataInput); err == nil {
+	if err := validatePaddleInput(&invalidMetadataInput, fullProfile); err == nil {
 		t.Error("validatePaddleInput should fail with empty brand")
 	} else if !strings.Contains(err.Error(), "brand is required") {
 		t.Errorf("Expected error about brand, got: %v", err)
@@ -51,7 +27,7 @@ func TestValidatePaddleInput(t *testing.T) {
 	// Test with invalid specs
 	invalidSpecsInput := *validInput
 	invalidSpecsInput.Specs.Shape = "InvalidShape"
-	if err := validatePaddleInput(&invalidSpecsInput); err == nil {
+	if err := validatePaddleInput(&invalidSpecsInput, fullProfile); err == nil {
 		t.Error("validatePaddleInput should fail with invalid shape")
 	} else if !strings.Contains(err.Error(), "invalid shape") {
 		t.Errorf("Expected error about shape, got: %v", err)
@@ -60,13 +36,141 @@ func TestValidatePaddleInput(t *testing.T) {
 	// Test with invalid performance
 	invalidPerfInput := *validInput
 	invalidPerfInput.Performance.Power = 101
-	if err := validatePaddleInput(&invalidPerfInput); err == nil {
+	if err := validatePaddleInput(&invalidPerfInput, fullProfile); err == nil {
 		t.Error("validatePaddleInput should fail with power > 100")
 	} else if !strings.Contains(err.Error(), "power must be between") {
 		t.Errorf("Expected error about power, got: %v", err)
 	}
 }
 
+// TestValidatePaddleInputProfiles tests which sections each validation profile requires
+func TestValidatePaddleInputProfiles(t *testing.T) {
+	metadata := Metadata{Brand: "Engage", Model: "Pursuit MX 6.0"}
+	specs := Specs{
+		Shape: Hybrid, Surface: "Composite", AverageWeight: 220.0, Core: 15.0, PaddleLength: 16.5,
+		PaddleWidth: 7.5, GripLength: 4.5, GripType: "Comfort", GripCircumference: 4.0,
+	}
+	performance := Performance{Power: 75.0, Pop: 70.0, Spin: 3000.0, TwistWeight: 200.0, SwingWeight: 220.0, BalancePoint: 30.0}
+
+	tests := []struct {
+		name    string
+		profile validationProfile
+		input   PaddleInput
+		errMsg  string
+	}{
+		{name: "Full with every section", profile: fullProfile, input: PaddleInput{Metadata: metadata, Specs: specs, Performance: performance}},
+		{name: "Full without specs", profile: fullProfile, input: PaddleInput{Metadata: metadata, Performance: performance}, errMsg: "invalid specs"},
+		{name: "Full without performance", profile: fullProfile, input: PaddleInput{Metadata: metadata, Specs: specs}, errMsg: "invalid performance"},
+		{name: "Stub with metadata only", profile: stubProfile, input: PaddleInput{Metadata: metadata}},
+		{name: "Stub with specs", profile: stubProfile, input: PaddleInput{Metadata: metadata, Specs: specs}},
+		{name: "Stub with every section", profile: stubProfile, input: PaddleInput{Metadata: metadata, Specs: specs, Performance: performance}},
+		{name: "Stub without brand", profile: stubProfile, input: PaddleInput{Metadata: Metadata{Model: "Pursuit MX 6.0"}}, errMsg: "brand is required"},
+		{name: "Stub with partial specs", profile: stubProfile, input: PaddleInput{Metadata: metadata, Specs: Specs{Shape: Hybrid}}, errMsg: "invalid specs"},
+		{name: "Stub with invalid performance", profile: stubProfile, input: PaddleInput{Metadata: metadata, Specs: specs, Performance: Performance{Power: 101}}, errMsg: "invalid performance"},
+		{name: "Stub with performance but no specs", profile: stubProfile, input: PaddleInput{Metadata: metadata, Performance: performance}, errMsg: "specs are required"},
+		{name: "Stub with ranges but no specs", profile: stubProfile, input: PaddleInput{Metadata: metadata, SpecRanges: map[string]SpecRange{"core": {Min: 14, Max: 16}}}, errMsg: "specs are required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePaddleInput(&tt.input, tt.profile)
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Errorf("validatePaddleInput() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("validatePaddleInput() error = %v, want it to contain %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+// TestValidationErrorCodes tests that specific violations report specific error codes
+func TestValidationErrorCodes(t *testing.T) {
+	valid := func() PaddleInput {
+		return *testPaddleInput("Engage", "Pursuit MX 6.0")
+	}
+
+	tests := []struct {
+		name     string
+		modifier func(*PaddleInput)
+		want     string
+	}{
+		{name: "Missing brand", modifier: func(in *PaddleInput) { in.Metadata.Brand = "" }, want: "BRAND_REQUIRED"},
+		{name: "Symbol-only brand", modifier: func(in *PaddleInput) { in.Metadata.Brand, in.Metadata.Model = "!!!", "X" }, want: "PADDLE_ID_INVALID"},
+		{name: "Symbol-only brand and model", modifier: func(in *PaddleInput) { in.Metadata.Brand, in.Metadata.Model = "!!!", "???" }, want: "PADDLE_ID_INVALID"},
+		{name: "Overlong brand and model", modifier: func(in *PaddleInput) { in.Metadata.Model = strings.Repeat("x", maxPaddleIDLength) }, want: "PADDLE_ID_INVALID"},
+		{name: "Zero-width space in model", modifier: func(in *PaddleInput) { in.Metadata.Model = "Pursuit\u200b MX" }, want: "TEXT_NOT_PRINTABLE"},
+		{name: "Tab in grip type", modifier: func(in *PaddleInput) { in.Specs.GripType = "Comfort\t" }, want: "TEXT_NOT_PRINTABLE"},
+		{name: "Long SKU", modifier: func(in *PaddleInput) { in.Metadata.SKU = strings.Repeat("X", maxSKULength+1) }, want: "SKU_TOO_LONG"},
+		{name: "Invalid shape", modifier: func(in *PaddleInput) { in.Specs.Shape = "Round" }, want: "SHAPE_INVALID"},
+		{name: "Thin core", modifier: func(in *PaddleInput) { in.Specs.Core = 5 }, want: "CORE_OUT_OF_RANGE"},
+		{name: "Long grip", modifier: func(in *PaddleInput) { in.Specs.GripLength = 17 }, want: "GRIP_LONGER_THAN_PADDLE"},
+		{name: "Invalid edge guard", modifier: func(in *PaddleInput) { in.Specs.EdgeGuard = "Rubber" }, want: "EDGE_GUARD_INVALID"},
+		{name: "Lowercase edge guard", modifier: func(in *PaddleInput) { in.Specs.EdgeGuard = "edgeless" }, want: "EDGE_GUARD_INVALID"},
+		{name: "Invalid handle type", modifier: func(in *PaddleInput) { in.Specs.HandleType = "Solid" }, want: "HANDLE_TYPE_INVALID"},
+		{name: "Known edge guard and handle type", modifier: func(in *PaddleInput) { in.Specs.EdgeGuard, in.Specs.HandleType = "Low-profile", "Foam-filled" }, want: ""},
+		{name: "Front surface only", modifier: func(in *PaddleInput) { in.Specs.SurfaceFront = "Raw Carbon" }, want: "SURFACE_SIDES_INCOMPLETE"},
+		{name: "Unknown back surface", modifier: func(in *PaddleInput) { in.Specs.SurfaceFront, in.Specs.SurfaceBack = "Raw Carbon", "Wood" }, want: "SURFACE_SIDE_INVALID"},
+		{name: "Two known surfaces", modifier: func(in *PaddleInput) { in.Specs.SurfaceFront, in.Specs.SurfaceBack = "Raw Carbon", "Fiberglass" }, want: ""},
+		{name: "Power too high", modifier: func(in *PaddleInput) { in.Performance.Power = 101 }, want: "POWER_OUT_OF_RANGE"},
+		{name: "Negative spin", modifier: func(in *PaddleInput) { in.Performance.Spin = -1 }, want: "SPIN_NEGATIVE"},
+		{name: "Spec outside range", modifier: func(in *PaddleInput) { in.SpecRanges = map[string]SpecRange{"core": {Min: 16, Max: 17}} }, want: "SPEC_OUTSIDE_RANGE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := valid()
+			tt.modifier(&input)
+			err := validatePaddleInput(&input, fullProfile)
+			if got := validationCode(err); got != tt.want {
+				t.Errorf("validationCode(%v) = %q, want %q", err, got, tt.want)
+			}
+		})
+	}
+
+	if got := validationCode(nil); got != "" {
+		t.Errorf("validationCode(nil) = %q, want empty", got)
+	}
+}
+
+// TestValidateText tests that control and invisible characters are rejected
+// while accented letters and other scripts are accepted
+func TestValidateText(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr string
+	}{
+		{name: "Plain", value: "Pursuit MX 6.0"},
+		{name: "Accented letters", value: "Édition Spéciale Ñandú"},
+		{name: "Other scripts", value: "ピックル 球拍"},
+		{name: "Symbols", value: "Vanguard Power Air™ 16mm+"},
+		{name: "Zero-width space", value: "Pursuit\u200bMX", wantErr: "model contains non-printable character U+200B at position 7"},
+		{name: "Byte order mark", value: "\ufeffPursuit", wantErr: "model contains non-printable character U+FEFF at position 0"},
+		{name: "Newline", value: "Pursuit\nMX", wantErr: "model contains non-printable character U+000A at position 7"},
+		{name: "NUL", value: "Pursuit\x00", wantErr: "model contains non-printable character U+0000 at position 7"},
+		{name: "Non-breaking space", value: "Pursuit\u00a0MX", wantErr: "model contains non-printable character U+00A0 at position 7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateText("model", tt.value)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateText(%q) returned error: %v", tt.value, err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("validateText(%q) error = %v, want %q", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
 // TestValidateMetadata tests the validateMetadata function
 func TestValidateMetadata(t *testing.T) {
 	tests := []struct {
@@ -110,6 +214,84 @@ func TestValidateMetadata(t *testing.T) {
 			wantErr: true,
 			errMsg:  "model is required",
 		},
+		{
+			name: "Valid year",
+			metadata: Metadata{
+				Brand: "Engage",
+				Model: "Pursuit MX 6.0",
+				Year:  intPtr(2023),
+			},
+			wantErr: false,
+		},
+		{
+			name: "Year before pickleball existed",
+			metadata: Metadata{
+				Brand: "Engage",
+				Model: "Pursuit MX 6.0",
+				Year:  intPtr(1950),
+			},
+			wantErr: true,
+			errMsg:  "year must be between 1965",
+		},
+		{
+			name: "Valid SKU and product URL",
+			metadata: Metadata{
+				Brand:      "Engage",
+				Model:      "Pursuit MX 6.0",
+				SKU:        "ENG-PMX6-16",
+				ProductURL: "https://engagepickleball.com/products/pursuit-mx-6-0",
+			},
+			wantErr: false,
+		},
+		{
+			name: "SKU too long",
+			metadata: Metadata{
+				Brand: "Engage",
+				Model: "Pursuit MX 6.0",
+				SKU:   strings.Repeat("X", 65),
+			},
+			wantErr: true,
+			errMsg:  "sku must be at most 64 characters",
+		},
+		{
+			name: "Relative product URL",
+			metadata: Metadata{
+				Brand:      "Engage",
+				Model:      "Pursuit MX 6.0",
+				ProductURL: "/products/pursuit-mx-6-0",
+			},
+			wantErr: true,
+			errMsg:  "product_url must be an absolute http or https URL",
+		},
+		{
+			name: "Non-http product URL",
+			metadata: Metadata{
+				Brand:      "Engage",
+				Model:      "Pursuit MX 6.0",
+				ProductURL: "javascript:alert(1)",
+			},
+			wantErr: true,
+			errMsg:  "product_url must be an absolute http or https URL",
+		},
+		{
+			name: "Valid price",
+			metadata: Metadata{
+				Brand: "Engage",
+				Model: "Pursuit MX 6.0",
+				Price: bound(199.99),
+			},
+			wantErr: false,
+		},
+		{
+			name: "Zero price",
+			metadata: Metadata{
+				Brand: "Engage",
+				Model: "Pursuit MX 6.0",
+				Price: bound(0),
+			},
+			wantErr: true,
+			errMsg:  "price must be a positive number",
+		},
 	}
 
 	for _, tt := range tests {
@@ -188,6 +370,50 @@ func TestValidateSpecs(t *testing.T) {
 				s.Core = -1
 			},
 		},
+		{
+			name:    "Core below minimum",
+			specs:   validSpecs,
+			wantErr: true,
+			errMsg:  "core must be between 8mm and 25mm",
+			modifier: func(s *Specs) {
+				s.Core = 7.99
+			},
+		},
+		{
+			name:    "Core at minimum",
+			specs:   validSpecs,
+			wantErr: false,
+			modifier: func(s *Specs) {
+				s.Core = 8
+			},
+		},
+		{
+			name:    "Core at maximum",
+			specs:   validSpecs,
+			wantErr: false,
+			modifier: func(s *Specs) {
+				s.Core = 25
+			},
+		},
+		{
+			name:    "Core above maximum",
+			specs:   validSpecs,
+			wantErr: true,
+			errMsg:  "core must be between 8mm and 25mm",
+			modifier: func(s *Specs) {
+				s.Core = 25.01
+			},
+		},
+		{
+			name:    "Core in inches",
+			specs:   validSpecs,
+			wantErr: true,
+			errMsg:  "core must be given in mm",
+			modifier: func(s *Specs) {
+				s.Core = 16
+				s.CoreUnit = "in"
+			},
+		},
 		{
 			name:    "Zero paddle length",
 			specs:   validSpecs,
@@ -233,6 +459,15 @@ func TestValidateSpecs(t *testing.T) {
 				s.GripCircumference = 0
 			},
 		},
+		{
+			name:    "Grip longer than paddle",
+			specs:   validSpecs,
+			wantErr: true,
+			errMsg:  "grip length (17) must be less than paddle length (16.5)",
+			modifier: func(s *Specs) {
+				s.GripLength = 17
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -254,6 +489,35 @@ func TestValidateSpecs(t *testing.T) {
 	}
 }
 
+// TestValidateGripLength tests the validateGripLength function at its boundaries
+func TestValidateGripLength(t *testing.T) {
+	tests := []struct {
+		name         string
+		gripLength   float64
+		paddleLength float64
+		wantErr      bool
+	}{
+		{name: "Grip shorter than paddle", gripLength: 5.25, paddleLength: 16.5, wantErr: false},
+		{name: "Grip just under paddle", gripLength: 16.49, paddleLength: 16.5, wantErr: false},
+		{name: "Grip equals paddle", gripLength: 16.5, paddleLength: 16.5, wantErr: true},
+		{name: "Grip exceeds paddle", gripLength: 17.0, paddleLength: 16.5, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specs := Specs{GripLength: tt.gripLength, PaddleLength: tt.paddleLength}
+			err := validateGripLength(&specs)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateGripLength() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && !strings.Contains(err.Error(), "must be less than paddle length") {
+				t.Errorf("validateGripLength() error = %v, expected to mention paddle length", err)
+			}
+		})
+	}
+}
+
 // TestValidatePerformance tests the validatePerformance function
 func TestValidatePerformance(t *testing.T) {
 	validPerformance := Performance{
@@ -349,6 +613,23 @@ func TestValidatePerformance(t *testing.T) {
 				p.BalancePoint = 0
 			},
 		},
+		{
+			name:        "Zero stddev",
+			performance: validPerformance,
+			wantErr:     false,
+			modifier: func(p *Performance) {
+				p.PopStddev = bound(0)
+			},
+		},
+		{
+			name:        "Negative stddev",
+			performance: validPerformance,
+			wantErr:     true,
+			errMsg:      "swing_weight_stddev must be non-negative",
+			modifier: func(p *Performance) {
+				p.SwingWeightStddev = bound(-1)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -395,6 +676,74 @@ func TestValidatePaddleID(t *testing.T) {
 			wantErr: true,
 			errMsg:  "paddle ID is required",
 		},
+		{
+			name:    "Canonical ID",
+			id:      "engage-pursuit-mx-6.0",
+			wantErr: false,
+		},
+		{
+			name:    "Lowercase generated ID",
+			id:      generatePaddleID("Engage", "Pursuit MX 6.0"),
+			wantErr: false,
+		},
+		{
+			name:    "Plus and unaccented letters",
+			id:      "vatic-pro-ballr+",
+			wantErr: false,
+		},
+		{
+			name:    "Longest ID",
+			id:      strings.Repeat("a", maxPaddleIDLength),
+			wantErr: false,
+		},
+		{
+			name:    "Too short",
+			id:      "ab",
+			wantErr: true,
+			errMsg:  "must be 3 to 100 characters, got 2",
+		},
+		{
+			name:    "Too long",
+			id:      strings.Repeat("a", maxPaddleIDLength+1),
+			wantErr: true,
+			errMsg:  "must be 3 to 100 characters",
+		},
+		{
+			name:    "Inner space",
+			id:      "engage pursuit-mx",
+			wantErr: true,
+			errMsg:  "must not contain whitespace (at position 6)",
+		},
+		{
+			name:    "Inner whitespace",
+			id:      "engage-pursuit\tmx",
+			wantErr: true,
+			errMsg:  "must not contain whitespace",
+		},
+		{
+			name:    "Slash",
+			id:      "gravity-tour-4-1/8",
+			wantErr: true,
+			errMsg:  `illegal character '/' at position 16`,
+		},
+		{
+			name:    "SQL characters",
+			id:      "engage';drop",
+			wantErr: true,
+			errMsg:  `illegal character '\''`,
+		},
+		{
+			name:    "Leading hyphen",
+			id:      "-engage-pursuit",
+			wantErr: true,
+			errMsg:  "must not start or end with a hyphen",
+		},
+		{
+			name:    "Empty segment",
+			id:      "engage--pursuit",
+			wantErr: true,
+			errMsg:  "consecutive hyphens",
+		},
 	}
 
 	for _, tt := range tests {
@@ -414,3 +763,7 @@ func TestValidatePaddleID(t *testing.T) {
 func stringPtr(s string) *string {
 	return &s
 }
+
+func intPtr(i int) *int {
+	return &i
+}
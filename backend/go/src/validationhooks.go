@@ -0,0 +1,32 @@
+package main
+
+// ValidationHook is an extra, deployment-specific rule for uploads, such as
+// only accepting certain brands. Hooks run after the built-in validation has
+// passed, so they can rely on a well-formed input. A hook that returns a
+// ValidationError keeps its code; any other error is reported as
+// CUSTOM_RULE_FAILED.
+type ValidationHook func(*PaddleInput) error
+
+// validationHooks are the registered hooks, in registration order
+var validationHooks []ValidationHook
+
+// RegisterValidationHook adds hook to the rules every upload must pass.
+// Register hooks at startup, before the server handles requests; the
+// built-in rules cannot be turned off.
+func RegisterValidationHook(hook ValidationHook) {
+	validationHooks = append(validationHooks, hook)
+}
+
+// runValidationHooks runs the registered hooks in order and returns the
+// first failure
+func runValidationHooks(input *PaddleInput) error {
+	for _, hook := range validationHooks {
+		if err := hook(input); err != nil {
+			if validationCode(err) == "" {
+				return newValidationError("CUSTOM_RULE_FAILED", "%v", err)
+			}
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestValidationHooks tests that a registered hook rejects a brand after the
+// built-in rules pass, and that hook codes are kept
+func TestValidationHooks(t *testing.T) {
+	previous := validationHooks
+	defer func() { validationHooks = previous }()
+	validationHooks = nil
+
+	calls := 0
+	RegisterValidationHook(func(input *PaddleInput) error {
+		calls++
+		if input.Metadata.Brand == "Knockoff" {
+			return errors.New("brand Knockoff is not accepted")
+		}
+		return nil
+	})
+	RegisterValidationHook(func(input *PaddleInput) error {
+		if input.Metadata.Model == "Banned" {
+			return newValidationError("MODEL_BANNED", "model %s is not accepted", input.Metadata.Model)
+		}
+		return nil
+	})
+
+	if err := validatePaddleInput(testPaddleInput("Engage", "Pursuit MX 6.0"), fullProfile); err != nil {
+		t.Errorf("validatePaddleInput() rejected an accepted brand: %v", err)
+	}
+	if err := validatePaddleInput(testPaddleInput("Knockoff", "Pursuit MX 6.0"), fullProfile); validationCode(err) != "CUSTOM_RULE_FAILED" {
+		t.Errorf("validatePaddleInput() for a rejected brand = %v, want CUSTOM_RULE_FAILED", err)
+	}
+	if err := validatePaddleInput(testPaddleInput("Engage", "Banned"), fullProfile); validationCode(err) != "MODEL_BANNED" {
+		t.Errorf("validatePaddleInput() for a banned model = %v, want MODEL_BANNED", err)
+	}
+
+	// Built-in rules run first, so hooks never see invalid input
+	calls = 0
+	invalid := testPaddleInput("Knockoff", "Pursuit MX 6.0")
+	invalid.Specs.Shape = "Round"
+	if err := validatePaddleInput(invalid, fullProfile); validationCode(err) != "SHAPE_INVALID" {
+		t.Errorf("validatePaddleInput() = %v, want SHAPE_INVALID from the built-in rules", err)
+	}
+	if calls != 0 {
+		t.Errorf("hook ran %d times on input the built-in rules rejected", calls)
+	}
+
+	// Uploads are rejected with the hook's message
+	setupTestStore(t)
+	body, _ := json.Marshal(testPaddleInput("Knockoff", "Pursuit MX 6.0"))
+	rr := httptest.NewRecorder()
+	uploadPaddleStats(rr, httptest.NewRequest("POST", "/api/paddles", bytes.NewBuffer(body)))
+	if rr.Code != http.StatusBadRequest || !bytes.Contains(rr.Body.Bytes(), []byte("brand Knockoff is not accepted")) {
+		t.Errorf("Upload returned %d: %s; want 400 with the hook's message", rr.Code, rr.Body.String())
+	}
+}
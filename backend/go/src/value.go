@@ -0,0 +1,151 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// defaultValueWeights make up the composite performance score used for value
+// when the request has no w_ parameters
+var defaultValueWeights = map[string]float64{"power": 1, "pop": 1, "spin": 1, "control": 1}
+
+// PriceBracket is a retail price range that paddles are ranked by value within.
+// Min is inclusive and Max exclusive; a nil Max is open-ended.
+type PriceBracket struct {
+	Label string   `json:"label"`
+	Min   float64  `json:"min"`
+	Max   *float64 `json:"max"`
+}
+
+// priceBrackets cover every positive price, in USD
+var priceBrackets = []PriceBracket{
+	{Label: "Under $100", Min: 0, Max: bound(100)},
+	{Label: "$100 to $150", Min: 100, Max: bound(150)},
+	{Label: "$150 to $200", Min: 150, Max: bound(200)},
+	{Label: "$200 and up", Min: 200},
+}
+
+// priceBracketFor returns the index of the bracket a price falls in
+func priceBracketFor(price float64) int {
+	for i, bracket := range priceBrackets {
+		if price >= bracket.Min && (bracket.Max == nil || price < *bracket.Max) {
+			return i
+		}
+	}
+	return len(priceBrackets) - 1
+}
+
+// ValueScore is how much composite performance a paddle gives for its price.
+// Value, Bracket and Rank are nil when the paddle has no price, and Reason
+// says why.
+type ValueScore struct {
+	ID          string        `json:"id"`
+	Price       *float64      `json:"price"`
+	Composite   float64       `json:"composite"`
+	Value       *float64      `json:"value"`
+	Bracket     *PriceBracket `json:"bracket"`
+	Rank        *int          `json:"rank"`
+	BracketSize int           `json:"bracket_size,omitempty"`
+	Reason      string        `json:"reason,omitempty"`
+}
+
+// scoreValues scores every paddle's value. The composite is the rankPaddles
+// score under weights, scaled against bounds, and the value is that composite
+// divided by the price. Paddles are ranked by value
+// within their price bracket, best first, with ties in paddle ID order.
+// Paddles without a price get a composite but no value or rank.
+func scoreValues(paddles []*Paddle, weights map[string]float64, bounds map[string]metricBounds) map[string]ValueScore {
+	prices := make(map[string]*float64, len(paddles))
+	for _, paddle := range paddles {
+		prices[paddle.ID] = paddle.Metadata.Price
+	}
+
+	scores := make(map[string]ValueScore, len(paddles))
+	brackets := make([][]string, len(priceBrackets))
+	for _, ranked := range rankPaddles(paddles, weights, bounds) {
+		score := ValueScore{ID: ranked.ID, Price: prices[ranked.ID], Composite: ranked.Score}
+		if score.Price == nil {
+			score.Reason = "paddle has no price, so its value cannot be scored"
+		} else {
+			value := roundTo(score.Composite / *score.Price, 4)
+			score.Value = &value
+			i := priceBracketFor(*score.Price)
+			score.Bracket = &priceBrackets[i]
+			brackets[i] = append(brackets[i], score.ID)
+		}
+		scores[ranked.ID] = score
+	}
+
+	for _, ids := range brackets {
+		slices.SortFunc(ids, func(a, b string) int {
+			if c := cmp.Compare(*scores[b].Value, *scores[a].Value); c != 0 {
+				return c
+			}
+			return strings.Compare(a, b)
+		})
+		for i, id := range ids {
+			score := scores[id]
+			rank := i + 1
+			score.Rank = &rank
+			score.BracketSize = len(ids)
+			scores[id] = score
+		}
+	}
+	return scores
+}
+
+// getPaddleValue handles the API request for a paddle's performance per
+// dollar and its value rank within its price bracket
+func getPaddleValue(w http.ResponseWriter, r *http.Request) {
+	paddleId := paddleIDFromRequest(r)
+	if err := validatePaddleID(paddleId); err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid paddle ID: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	weights := defaultValueWeights
+	for key := range r.URL.Query() {
+		if strings.HasPrefix(key, "w_") {
+			var err error
+			if weights, err = parseRankWeights(r.URL.Query()); err != nil {
+				respondWithError(w, fmt.Sprintf("Invalid weights: %v", err), http.StatusBadRequest)
+				return
+			}
+			break
+		}
+	}
+
+	paddles, err := GetRankingCandidates(paddleFilter{Status: StatusPublished})
+	if err != nil {
+		log.Printf("Error retrieving paddles to score value: %v", err)
+		respondWithError(w, "Failed to retrieve paddle value", http.StatusInternalServerError)
+		return
+	}
+
+	stats, err := currentDatasetStats()
+	if err != nil {
+		log.Printf("Error retrieving dataset stats: %v", err)
+		respondWithError(w, "Failed to retrieve paddle value", http.StatusInternalServerError)
+		return
+	}
+
+	score, ok := scoreValues(paddles, weights, stats.bounds())[paddleId]
+	if !ok {
+		respondWithError(w, "Paddle not found", http.StatusNotFound)
+		return
+	}
+
+	response := struct {
+		ValueScore
+		Weights map[string]float64 `json:"weights"`
+	}{ValueScore: score, Weights: weights}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding paddle value: %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"testing"
+)
+
+// TestScoreValues tests ranking paddles by value within their price bracket
+func TestScoreValues(t *testing.T) {
+	newPaddle := func(id string, power float64, price *float64) *Paddle {
+		return &Paddle{ID: id, Metadata: Metadata{Price: price}, Performance: Performance{Power: power}}
+	}
+	paddles := []*Paddle{
+		newPaddle("engage-pro", 100, bound(250)),
+		newPaddle("joola-budget", 50, bound(80)),
+		newPaddle("selkirk-mid", 75, bound(125)),
+		newPaddle("gearbox-mid", 100, bound(140)),
+		newPaddle("paddletek-mid", 75, bound(100)),
+		newPaddle("onix-cheap", 0, bound(60)),
+		newPaddle("franklin-unpriced", 100, nil),
+	}
+
+	scores := scoreValues(paddles, map[string]float64{"power": 1}, map[string]metricBounds{"power": {Min: 0, Max: 100}})
+
+	tests := []struct {
+		id          string
+		wantValue   float64
+		wantBracket string
+		wantRank    int
+		wantSize    int
+	}{
+		{id: "engage-pro", wantValue: 0.4, wantBracket: "$200 and up", wantRank: 1, wantSize: 1},
+		{id: "joola-budget", wantValue: 0.625, wantBracket: "Under $100", wantRank: 1, wantSize: 2},
+		{id: "onix-cheap", wantValue: 0, wantBracket: "Under $100", wantRank: 2, wantSize: 2},
+		// 75/100 beats 100/140, which beats 75/125, all in the same bracket
+		{id: "paddletek-mid", wantValue: 0.75, wantBracket: "$100 to $150", wantRank: 1, wantSize: 3},
+		{id: "gearbox-mid", wantValue: 0.7143, wantBracket: "$100 to $150", wantRank: 2, wantSize: 3},
+		{id: "selkirk-mid", wantValue: 0.6, wantBracket: "$100 to $150", wantRank: 3, wantSize: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			score, ok := scores[tt.id]
+			if !ok {
+				t.Fatalf("no score for %s", tt.id)
+			}
+			if score.Value == nil || *score.Value != tt.wantValue {
+				t.Errorf("value = %v, want %v", score.Value, tt.wantValue)
+			}
+			if score.Bracket == nil || score.Bracket.Label != tt.wantBracket {
+				t.Errorf("bracket = %+v, want %q", score.Bracket, tt.wantBracket)
+			}
+			if score.Rank == nil || *score.Rank != tt.wantRank || score.BracketSize != tt.wantSize {
+				t.Errorf("rank = %v of %d, want %d of %d", score.Rank, score.BracketSize, tt.wantRank, tt.wantSize)
+			}
+		})
+	}
+
+	unpriced := scores["franklin-unpriced"]
+	if unpriced.Composite != 100 {
+		t.Errorf("unpriced composite = %v, want 100", unpriced.Composite)
+	}
+	if unpriced.Value != nil || unpriced.Bracket != nil || unpriced.Rank != nil || unpriced.Reason == "" {
+		t.Errorf("unpriced score = %+v, want null value, bracket and rank with a reason", unpriced)
+	}
+}
+
+// TestPriceBracketFor tests that bracket bounds include their minimum
+func TestPriceBracketFor(t *testing.T) {
+	tests := []struct {
+		price float64
+		want  string
+	}{
+		{price: 99.99, want: "Under $100"},
+		{price: 100, want: "$100 to $150"},
+		{price: 199.99, want: "$150 to $200"},
+		{price: 200, want: "$200 and up"},
+		{price: 1000, want: "$200 and up"},
+	}
+
+	for _, tt := range tests {
+		if got := priceBrackets[priceBracketFor(tt.price)].Label; got != tt.want {
+			t.Errorf("priceBracketFor(%v) = %q, want %q", tt.price, got, tt.want)
+		}
+	}
+}
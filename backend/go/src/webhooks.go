@@ -0,0 +1,403 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Webhook delivery limits. Each try of a delivery makes up to
+// webhookMaxAttempts attempts of webhookTimeout each, retried after
+// webhookRetryDelay, doubling each time. A try that fails is repeated on a
+// later round after webhookRetryBackoff, also doubling, and the delivery is
+// dead-lettered after webhookMaxTries. A claimed try outlasts the round.
+const (
+	webhookTimeout      = 5 * time.Second
+	webhookMaxAttempts  = 3
+	webhookMaxTries     = 6
+	webhookRetryBackoff = time.Minute
+	webhookClaimTimeout = 2 * outboxPollTimeout
+	maxSecretLength     = 128
+)
+
+// webhookRetryDelay is the wait before the first retry; tests shorten it
+var webhookRetryDelay = time.Second
+
+// Headers sent with every webhook delivery
+const (
+	webhookEventHeader     = "X-Pickleball-Event"
+	webhookDeliveryHeader  = "X-Pickleball-Delivery"
+	webhookSignatureHeader = "X-Pickleball-Signature"
+)
+
+// ErrWebhookNotFound is returned when no webhook matches the requested ID
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+// Webhook is a registered URL notified of paddle changes. The secret is only
+// returned when the webhook is created.
+type Webhook struct {
+	ID        int       `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// webhookPayload is the JSON body POSTed to each webhook
+type webhookPayload struct {
+	ID        int             `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Paddle    json.RawMessage `json:"paddle"`
+}
+
+// signWebhook returns the signature header value for body: the hex HMAC-SHA256
+// of the raw body keyed with the webhook's secret
+func signWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// newWebhookSecret returns a random 32-byte secret, hex encoded
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// CreateWebhook registers a URL to be notified of paddle changes
+func CreateWebhook(url, secret string) (*Webhook, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	hook := &Webhook{URL: url, Secret: secret}
+	err := timedQueryRow(ctx, DB, "insert_webhook",
+		"INSERT INTO webhooks (url, secret) VALUES ($1, $2) RETURNING id, created_at", url, secret,
+	).Scan(&hook.ID, &hook.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+// DeleteWebhook removes a registered webhook
+func DeleteWebhook(id int) error {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	result, err := timedExec(ctx, DB, "delete_webhook", "DELETE FROM webhooks WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// webhookPublisher is the outbox Publisher for webhooks. Publishing an event
+// records a delivery of it for every registered webhook, and deliverPending
+// sends those, so each webhook gets its events on its own schedule and one
+// that keeps failing holds up no other.
+type webhookPublisher struct {
+	client *http.Client
+}
+
+// newWebhookPublisher returns a webhookPublisher with the delivery timeout applied
+func newWebhookPublisher() webhookPublisher {
+	return webhookPublisher{client: &http.Client{Timeout: webhookTimeout}}
+}
+
+func (p webhookPublisher) Publish(ctx context.Context, event OutboxEvent) error {
+	// An event published again after failing to be marked keeps its deliveries
+	_, err := timedExec(ctx, DB, "insert_webhook_deliveries", `
+		INSERT INTO webhook_deliveries (event_id, webhook_id)
+		SELECT $1, id FROM webhooks
+		ON CONFLICT DO NOTHING
+	`, event.ID)
+	if err != nil {
+		return fmt.Errorf("error recording webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// webhookDelivery is an event owed to a webhook. Tries counts the tries so
+// far, the current one included.
+type webhookDelivery struct {
+	Hook  Webhook
+	Event OutboxEvent
+	Tries int
+}
+
+// claimWebhookDeliveries claims up to outboxBatchSize deliveries that are due,
+// oldest event first, until webhookClaimTimeout from now, and counts the try.
+// The claim commits at once, so the HTTP requests hold no locks, and a
+// delivery whose outcome is never recorded is tried again once it runs out.
+func claimWebhookDeliveries(ctx context.Context) ([]webhookDelivery, error) {
+	rows, err := timedQuery(ctx, DB, "claim_webhook_deliveries", `
+		UPDATE webhook_deliveries d
+		SET tries = d.tries + 1, next_try_at = CURRENT_TIMESTAMP + make_interval(secs => $2)
+		FROM (
+			SELECT event_id, webhook_id
+			FROM webhook_deliveries
+			WHERE delivered_at IS NULL AND dead_at IS NULL AND next_try_at <= CURRENT_TIMESTAMP
+			ORDER BY event_id, webhook_id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		) due, outbox o, webhooks w
+		WHERE d.event_id = due.event_id AND d.webhook_id = due.webhook_id
+			AND o.id = d.event_id AND w.id = d.webhook_id
+		RETURNING o.id, o.event_type, o.payload, o.created_at, w.id, w.url, w.secret, w.created_at, d.tries
+	`, outboxBatchSize, webhookClaimTimeout.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []webhookDelivery
+	for rows.Next() {
+		var d webhookDelivery
+		err := rows.Scan(&d.Event.ID, &d.Event.Type, &d.Event.Payload, &d.Event.CreatedAt,
+			&d.Hook.ID, &d.Hook.URL, &d.Hook.Secret, &d.Hook.CreatedAt, &d.Tries)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// webhookBackoff returns the wait after a delivery's tries-th failed try:
+// webhookRetryBackoff, doubling with each try
+func webhookBackoff(tries int) time.Duration {
+	return webhookRetryBackoff << (tries - 1)
+}
+
+// recordWebhookDelivery stores the outcome of a try. A failed delivery is
+// tried again after webhookBackoff, or dead-lettered after webhookMaxTries.
+func recordWebhookDelivery(d webhookDelivery, deliverErr error) error {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	if deliverErr == nil {
+		_, err := timedExec(ctx, DB, "mark_webhook_delivered", `
+			UPDATE webhook_deliveries SET delivered_at = CURRENT_TIMESTAMP, last_error = NULL
+			WHERE event_id = $1 AND webhook_id = $2
+		`, d.Event.ID, d.Hook.ID)
+		return err
+	}
+
+	if d.Tries >= webhookMaxTries {
+		log.Printf("Giving up on event %d for webhook %d after %d tries: %v", d.Event.ID, d.Hook.ID, d.Tries, deliverErr)
+		_, err := timedExec(ctx, DB, "mark_webhook_dead", `
+			UPDATE webhook_deliveries SET dead_at = CURRENT_TIMESTAMP, last_error = $3
+			WHERE event_id = $1 AND webhook_id = $2
+		`, d.Event.ID, d.Hook.ID, deliverErr.Error())
+		return err
+	}
+
+	backoff := webhookBackoff(d.Tries)
+	log.Printf("Event %d for webhook %d failed on try %d, retrying in %s: %v", d.Event.ID, d.Hook.ID, d.Tries, backoff, deliverErr)
+	_, err := timedExec(ctx, DB, "schedule_webhook_retry", `
+		UPDATE webhook_deliveries SET next_try_at = CURRENT_TIMESTAMP + make_interval(secs => $3), last_error = $4
+		WHERE event_id = $1 AND webhook_id = $2
+	`, d.Event.ID, d.Hook.ID, backoff.Seconds(), deliverErr.Error())
+	return err
+}
+
+// deliverPending sends the webhook deliveries that are due and records how
+// each went, returning how many were delivered. Webhooks that fail are
+// logged and retried later; only errors reading or recording deliveries are
+// returned.
+func (p webhookPublisher) deliverPending(ctx context.Context) (int, error) {
+	deliveries, err := claimWebhookDeliveries(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// Deliveries are sent concurrently, and deliverPending waits for all of
+	// them so the poller, and shutdown behind it, never leaves one running
+	results := make([]error, len(deliveries))
+	var wg sync.WaitGroup
+	for i, d := range deliveries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body, err := json.Marshal(webhookPayload{
+				ID:        d.Event.ID,
+				Type:      d.Event.Type,
+				CreatedAt: d.Event.CreatedAt,
+				Paddle:    d.Event.Payload,
+			})
+			if err != nil {
+				results[i] = err
+				return
+			}
+			results[i] = p.deliver(ctx, d.Hook, d.Event, body)
+		}()
+	}
+	wg.Wait()
+
+	delivered := 0
+	var errs []error
+	for i, d := range deliveries {
+		if err := recordWebhookDelivery(d, results[i]); err != nil {
+			errs = append(errs, fmt.Errorf("error recording event %d for webhook %d: %w", d.Event.ID, d.Hook.ID, err))
+			continue
+		}
+		if results[i] == nil {
+			delivered++
+		}
+	}
+	return delivered, errors.Join(errs...)
+}
+
+// runWebhookDeliverer sends due webhook deliveries every interval until ctx is
+// done. A round already running when ctx is done is finished, like an outbox poll.
+func runWebhookDeliverer(ctx context.Context, interval time.Duration, p webhookPublisher) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			roundCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), outboxPollTimeout)
+			if _, err := p.deliverPending(roundCtx); err != nil {
+				log.Printf("Error delivering webhooks: %v", err)
+			}
+			cancel()
+		}
+	}
+}
+
+// deliver POSTs a signed event to one webhook, retrying failed attempts until
+// webhookMaxAttempts or ctx is done. Any 2xx response counts as delivered.
+func (p webhookPublisher) deliver(ctx context.Context, hook Webhook, event OutboxEvent, body []byte) error {
+	delay := webhookRetryDelay
+	var err error
+	for attempt := 1; attempt <= webhookMaxAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("stopped after %d attempts, last error: %w", attempt-1, err)
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+
+		if err = p.attempt(ctx, hook, event, body); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("%d attempts failed, last error: %w", webhookMaxAttempts, err)
+}
+
+// attempt makes a single delivery of an event to a webhook
+func (p webhookPublisher) attempt(ctx context.Context, hook Webhook, event OutboxEvent, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookEventHeader, event.Type)
+	req.Header.Set(webhookDeliveryHeader, strconv.Itoa(event.ID))
+	req.Header.Set(webhookSignatureHeader, signWebhook(hook.Secret, body))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// registerWebhook handles the API request for registering a webhook
+func registerWebhook(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		URL    string `json:"url"`
+		Secret string `json:"secret"`
+	}
+	if err := decodeJSONBody(r.Body, &input); err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if !isHTTPURL(input.URL) {
+		respondWithError(w, "url must be an absolute http or https URL", http.StatusBadRequest)
+		return
+	}
+	if len(input.Secret) > maxSecretLength {
+		respondWithError(w, fmt.Sprintf("secret must be at most %d characters", maxSecretLength), http.StatusBadRequest)
+		return
+	}
+
+	// Generate a secret when the integrator does not supply one
+	if input.Secret == "" {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			log.Printf("Error generating webhook secret: %v", err)
+			respondWithError(w, "Failed to register webhook", http.StatusInternalServerError)
+			return
+		}
+		input.Secret = secret
+	}
+
+	hook, err := CreateWebhook(input.URL, input.Secret)
+	if err != nil {
+		log.Printf("Error registering webhook: %v", err)
+		respondWithError(w, "Failed to register webhook", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(hook); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
+
+// removeWebhook handles the API request for removing a webhook
+func removeWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, "Invalid webhook ID", http.StatusBadRequest)
+		return
+	}
+
+	err = DeleteWebhook(id)
+	if errors.Is(err, ErrWebhookNotFound) {
+		respondWithError(w, "Webhook not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Error removing webhook: %v", err)
+		respondWithError(w, "Failed to remove webhook", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,328 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// webhookRequest is a delivery captured by a test server
+type webhookRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// newWebhookServer starts a server that records deliveries on a channel and
+// answers with the given statuses in turn, then 200
+func newWebhookServer(t *testing.T, statuses ...int) (*httptest.Server, chan webhookRequest) {
+	t.Helper()
+	received := make(chan webhookRequest, 10)
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- webhookRequest{header: r.Header.Clone(), body: body}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if len(statuses) > 0 {
+			w.WriteHeader(statuses[0])
+			statuses = statuses[1:]
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, received
+}
+
+// TestWebhookDeliverRetriesAndSigns tests that a failed delivery is retried and every attempt is signed
+func TestWebhookDeliverRetriesAndSigns(t *testing.T) {
+	previous := webhookRetryDelay
+	webhookRetryDelay = time.Millisecond
+	t.Cleanup(func() { webhookRetryDelay = previous })
+
+	server, received := newWebhookServer(t, http.StatusInternalServerError)
+	hook := Webhook{ID: 1, URL: server.URL, Secret: "s3cret"}
+	event := OutboxEvent{ID: 42, Type: eventPaddleCreated}
+	body := []byte(`{"id":42}`)
+
+	if err := newWebhookPublisher().deliver(context.Background(), hook, event, body); err != nil {
+		t.Fatalf("deliver() error: %v", err)
+	}
+
+	close(received)
+	attempts := 0
+	for req := range received {
+		attempts++
+		if got := req.header.Get(webhookSignatureHeader); got != signWebhook("s3cret", body) {
+			t.Errorf("attempt %d signature = %q, want %q", attempts, got, signWebhook("s3cret", body))
+		}
+		if got := req.header.Get(webhookEventHeader); got != eventPaddleCreated {
+			t.Errorf("attempt %d event header = %q, want %q", attempts, got, eventPaddleCreated)
+		}
+		if got := req.header.Get(webhookDeliveryHeader); got != "42" {
+			t.Errorf("attempt %d delivery header = %q, want 42", attempts, got)
+		}
+	}
+	if attempts != 2 {
+		t.Errorf("deliver() made %d attempts, want 2", attempts)
+	}
+}
+
+// TestWebhookDeliverGivesUp tests that delivery stops after webhookMaxAttempts
+func TestWebhookDeliverGivesUp(t *testing.T) {
+	previous := webhookRetryDelay
+	webhookRetryDelay = time.Millisecond
+	t.Cleanup(func() { webhookRetryDelay = previous })
+
+	statuses := make([]int, webhookMaxAttempts)
+	for i := range statuses {
+		statuses[i] = http.StatusServiceUnavailable
+	}
+	server, received := newWebhookServer(t, statuses...)
+
+	err := newWebhookPublisher().deliver(context.Background(), Webhook{URL: server.URL, Secret: "s3cret"}, OutboxEvent{ID: 1}, []byte(`{}`))
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("deliver() error = %v, want the last 503", err)
+	}
+	if len(received) != webhookMaxAttempts {
+		t.Errorf("deliver() made %d attempts, want %d", len(received), webhookMaxAttempts)
+	}
+}
+
+// TestWebhookDeliverStopsWhenCanceled tests that retries end when the poll's context is done
+func TestWebhookDeliverStopsWhenCanceled(t *testing.T) {
+	previous := webhookRetryDelay
+	webhookRetryDelay = time.Hour
+	t.Cleanup(func() { webhookRetryDelay = previous })
+
+	server, received := newWebhookServer(t, http.StatusServiceUnavailable)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-received
+		cancel()
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- newWebhookPublisher().deliver(ctx, Webhook{URL: server.URL, Secret: "s3cret"}, OutboxEvent{ID: 1}, []byte(`{}`))
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("deliver() after cancel succeeded, want an error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("deliver() kept retrying after its context was canceled")
+	}
+}
+
+// TestRegisterWebhookValidation tests that invalid registrations are rejected before storage
+func TestRegisterWebhookValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "Missing URL", body: `{}`},
+		{name: "Relative URL", body: `{"url": "/hooks"}`},
+		{name: "Non-HTTP URL", body: `{"url": "ftp://example.com/hooks"}`},
+		{name: "Long secret", body: fmt.Sprintf(`{"url": "https://example.com/hooks", "secret": %q}`, strings.Repeat("x", maxSecretLength+1))},
+		{name: "Unknown field", body: `{"url": "https://example.com/hooks", "events": ["all"]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			registerWebhook(rr, httptest.NewRequest("POST", "/api/webhooks", bytes.NewBufferString(tt.body)))
+			if rr.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d: %s", rr.Code, http.StatusBadRequest, rr.Body.String())
+			}
+		})
+	}
+}
+
+// TestCreateFiresWebhook tests that saving a paddle delivers a signed paddle.created webhook
+func TestCreateFiresWebhook(t *testing.T) {
+	setupTestDB(t)
+
+	server, received := newWebhookServer(t)
+	hook, err := CreateWebhook(server.URL, "s3cret")
+	if err != nil {
+		t.Fatalf("CreateWebhook() error: %v", err)
+	}
+	t.Cleanup(func() { DeleteWebhook(hook.ID) })
+
+	hooks := newWebhookPublisher()
+	previous := publisher
+	publisher = hooks
+	t.Cleanup(func() { publisher = previous })
+
+	input := testPaddleInput("Engage", "Webhook Test")
+	paddle := input.ToPaddle()
+	if _, err := SavePaddle(paddle); err != nil {
+		t.Fatalf("SavePaddle() error: %v", err)
+	}
+
+	// Other tests may have left events behind, so drain the whole outbox
+	for {
+		published, err := publishPendingEvents(context.Background())
+		if err != nil {
+			t.Fatalf("publishPendingEvents() error: %v", err)
+		}
+		if published == 0 {
+			break
+		}
+	}
+	drainWebhookDeliveries(t, hooks)
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case req := <-received:
+			var payload struct {
+				Type   string `json:"type"`
+				Paddle Paddle `json:"paddle"`
+			}
+			if err := json.Unmarshal(req.body, &payload); err != nil {
+				t.Fatalf("Failed to decode webhook body: %v", err)
+			}
+			if payload.Paddle.ID != paddle.ID {
+				continue
+			}
+			if payload.Type != eventPaddleCreated {
+				t.Errorf("webhook type = %q, want %q", payload.Type, eventPaddleCreated)
+			}
+			if got := req.header.Get(webhookSignatureHeader); got != signWebhook("s3cret", req.body) {
+				t.Errorf("webhook signature = %q, want %q", got, signWebhook("s3cret", req.body))
+			}
+			return
+		case <-timeout:
+			t.Fatalf("No webhook received for %s", paddle.ID)
+		}
+	}
+}
+
+// drainWebhookDeliveries sends webhook deliveries until none is delivered.
+// Failed ones wait for their backoff, so this ends.
+func drainWebhookDeliveries(t *testing.T, hooks webhookPublisher) {
+	t.Helper()
+	for {
+		delivered, err := hooks.deliverPending(context.Background())
+		if err != nil {
+			t.Fatalf("deliverPending() error: %v", err)
+		}
+		if delivered == 0 {
+			return
+		}
+	}
+}
+
+// TestWebhookBackoff tests that the wait between tries doubles
+func TestWebhookBackoff(t *testing.T) {
+	for tries, want := range map[int]time.Duration{1: webhookRetryBackoff, 2: 2 * webhookRetryBackoff, 4: 8 * webhookRetryBackoff} {
+		if got := webhookBackoff(tries); got != want {
+			t.Errorf("webhookBackoff(%d) = %s, want %s", tries, got, want)
+		}
+	}
+}
+
+// TestDeadWebhookDoesNotBlock tests that a webhook that keeps failing is
+// retried on its own and dead-lettered, while the event is published and
+// other webhooks receive it
+func TestDeadWebhookDoesNotBlock(t *testing.T) {
+	setupTestDB(t)
+
+	previousDelay := webhookRetryDelay
+	webhookRetryDelay = time.Millisecond
+	t.Cleanup(func() { webhookRetryDelay = previousDelay })
+
+	healthy, received := newWebhookServer(t)
+	dead := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(dead.Close)
+
+	var hookIDs []int
+	for _, url := range []string{dead.URL, healthy.URL} {
+		hook, err := CreateWebhook(url, "s3cret")
+		if err != nil {
+			t.Fatalf("CreateWebhook() error: %v", err)
+		}
+		hookIDs = append(hookIDs, hook.ID)
+		t.Cleanup(func() { DeleteWebhook(hook.ID) })
+	}
+	deadID := hookIDs[0]
+
+	hooks := newWebhookPublisher()
+	previous := publisher
+	publisher = hooks
+	t.Cleanup(func() { publisher = previous })
+
+	paddle := saveTestPaddle(t, testPaddleInput("Engage", "Dead Webhook"))
+	for {
+		published, err := publishPendingEvents(context.Background())
+		if err != nil {
+			t.Fatalf("publishPendingEvents() error: %v", err)
+		}
+		if published == 0 {
+			break
+		}
+	}
+	var unpublished int
+	err := DB.QueryRow("SELECT COUNT(*) FROM outbox WHERE published_at IS NULL AND payload->>'id' = $1", paddle.ID).Scan(&unpublished)
+	if err != nil {
+		t.Fatalf("Failed to count unpublished events: %v", err)
+	}
+	if unpublished != 0 {
+		t.Errorf("%d events for %s are unpublished, want the event published despite the dead webhook", unpublished, paddle.ID)
+	}
+
+	drainWebhookDeliveries(t, hooks)
+	timeout := time.After(5 * time.Second)
+	for delivered := false; !delivered; {
+		select {
+		case req := <-received:
+			delivered = strings.Contains(string(req.body), paddle.ID)
+		case <-timeout:
+			t.Fatalf("The healthy webhook never received %s", paddle.ID)
+		}
+	}
+
+	// The failed delivery waits for its backoff, then is dead-lettered once
+	// it has used up its tries
+	deliveryQuery := `
+		SELECT d.tries, d.next_try_at > CURRENT_TIMESTAMP, d.dead_at IS NOT NULL
+		FROM webhook_deliveries d JOIN outbox o ON o.id = d.event_id
+		WHERE d.webhook_id = $1 AND o.payload->>'id' = $2`
+	var tries int
+	var waiting, deadLettered bool
+	if err := DB.QueryRow(deliveryQuery, deadID, paddle.ID).Scan(&tries, &waiting, &deadLettered); err != nil {
+		t.Fatalf("Failed to read the dead webhook's delivery: %v", err)
+	}
+	if tries != 1 || !waiting || deadLettered {
+		t.Errorf("dead webhook delivery has tries %d, waiting %v, dead-lettered %v; want 1 try waiting to retry", tries, waiting, deadLettered)
+	}
+
+	_, err = DB.Exec(`
+		UPDATE webhook_deliveries d SET tries = $3, next_try_at = CURRENT_TIMESTAMP
+		FROM outbox o
+		WHERE o.id = d.event_id AND d.webhook_id = $1 AND o.payload->>'id' = $2
+	`, deadID, paddle.ID, webhookMaxTries-1)
+	if err != nil {
+		t.Fatalf("Failed to make the delivery due: %v", err)
+	}
+	drainWebhookDeliveries(t, hooks)
+	if err := DB.QueryRow(deliveryQuery, deadID, paddle.ID).Scan(&tries, &waiting, &deadLettered); err != nil {
+		t.Fatalf("Failed to read the dead webhook's delivery: %v", err)
+	}
+	if tries != webhookMaxTries || !deadLettered {
+		t.Errorf("dead webhook delivery has tries %d, dead-lettered %v; want %d tries, dead-lettered", tries, deadLettered, webhookMaxTries)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// zscore returns how many standard deviations value lies from mean. It is 0
+// when std is 0, since then every value equals the mean.
+func zscore(value, mean, std float64) float64 {
+	if std == 0 {
+		return 0
+	}
+	return (value - mean) / std
+}
+
+// MetricZScore is one performance metric of a paddle against the dataset.
+// ZScore is nil, with a Reason, when the metric has no spread to score against.
+type MetricZScore struct {
+	Metric string   `json:"metric"`
+	Value  float64  `json:"value"`
+	Mean   float64  `json:"mean"`
+	Stddev float64  `json:"stddev"`
+	ZScore *float64 `json:"zscore"`
+	Reason string   `json:"reason,omitempty"`
+}
+
+// buildZScores scores each performance metric against the dataset stats, in
+// performanceMetrics order. Both the paddle's value and the stats are means
+// across measurements, so a paddle measured often does not skew the spread.
+func buildZScores(performance *Performance, stats *DatasetStats) []MetricZScore {
+	scores := make([]MetricZScore, 0, len(performanceMetrics))
+	for _, metric := range performanceMetrics {
+		score := MetricZScore{Metric: metric, Value: metricValue(performance, metric)}
+		field, ok := stats.Fields[metric]
+		switch {
+		case !ok:
+			score.Reason = "no measurements of this metric yet"
+		case stats.Stddevs[metric] == 0:
+			score.Mean = field.Mean
+			score.Reason = "every paddle has the same value, so there is no spread to score against"
+		default:
+			score.Mean, score.Stddev = field.Mean, stats.Stddevs[metric]
+			z := roundTo(zscore(score.Value, score.Mean, score.Stddev), 4)
+			score.ZScore = &z
+		}
+		scores = append(scores, score)
+	}
+	return scores
+}
+
+// getPaddleZScores handles the API request for how far each of a paddle's
+// metrics lies from the dataset mean, in standard deviations
+func getPaddleZScores(w http.ResponseWriter, r *http.Request) {
+	paddleId := paddleIDFromRequest(r)
+	if err := validatePaddleID(paddleId); err != nil {
+		respondWithError(w, fmt.Sprintf("Invalid paddle ID: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// Use the same averaged performance as the details endpoint
+	agg, err := store.GetAggregatedPerformance(paddleId)
+	if errors.Is(err, ErrPaddleNotFound) {
+		respondWithError(w, "Paddle not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Error retrieving paddle performance for z-scores: %v", err)
+		respondWithError(w, "Failed to retrieve paddle performance", http.StatusInternalServerError)
+		return
+	}
+
+	// Score against the cached dataset stats, refreshed in the background
+	stats, err := currentDatasetStats()
+	if err != nil {
+		log.Printf("Error retrieving dataset stats: %v", err)
+		respondWithError(w, "Failed to retrieve dataset stats", http.StatusInternalServerError)
+		return
+	}
+
+	response := struct {
+		ID          string         `json:"id"`
+		SampleCount int            `json:"sample_count"`
+		Metrics     []MetricZScore `json:"metrics"`
+	}{
+		ID:          paddleId,
+		SampleCount: stats.SampleCount,
+		Metrics:     buildZScores(&agg.Performance, stats),
+	}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding z-scores: %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// TestZScore tests the z-score math against a dataset with a known mean of 5
+// and population standard deviation of 2
+func TestZScore(t *testing.T) {
+	var measurements []Performance
+	for _, power := range []float64{2, 4, 4, 4, 5, 5, 7, 9} {
+		measurements = append(measurements, Performance{Power: power, Pop: 70})
+	}
+	stats := summarizePerformance(measurements)
+	mean, std := stats.Fields["power"].Mean, stats.Stddevs["power"]
+	if mean != 5 || std != 2 {
+		t.Fatalf("power mean and stddev = %v and %v, want 5 and 2", mean, std)
+	}
+
+	tests := []struct {
+		value float64
+		want  float64
+	}{
+		{value: 5, want: 0},
+		{value: 9, want: 2},
+		{value: 2, want: -1.5},
+		{value: 6, want: 0.5},
+	}
+	for _, tt := range tests {
+		if got := zscore(tt.value, mean, std); got != tt.want {
+			t.Errorf("zscore(%v, %v, %v) = %v, want %v", tt.value, mean, std, got, tt.want)
+		}
+	}
+
+	// Every pop is the same, so there is no spread
+	if std := stats.Stddevs["pop"]; std != 0 {
+		t.Errorf("pop stddev = %v, want 0", std)
+	}
+	if got := zscore(70, 70, 0); got != 0 {
+		t.Errorf("zscore with zero variance = %v, want 0", got)
+	}
+}
+
+// TestGetPaddleZScores tests scoring a paddle against the published paddles,
+// with a null score and a reason for a metric every paddle shares
+func TestGetPaddleZScores(t *testing.T) {
+	setupTestStore(t)
+	t.Cleanup(func() { datasetStatsCache.stats = nil })
+	datasetStatsCache.stats = nil
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/paddles/{id}/zscores", getPaddleZScores).Methods("GET")
+
+	// Spin 2000 and 3000, with every other metric the same
+	ids := []string{}
+	for i, spin := range []float64{2000, 3000} {
+		input := testPaddleInput("Engage", fmt.Sprintf("ZScore %d", i))
+		input.Performance.Spin = spin
+		paddle := saveTestPaddle(t, input)
+		ids = append(ids, paddle.ID)
+	}
+
+	// Drafts are not part of the population scored against
+	draft := testPaddleInput("Engage", "ZScore Draft").ToPaddle()
+	draft.Performance.Spin = 9000
+	draft.Status = StatusDraft
+	if _, err := store.SavePaddle(draft); err != nil {
+		t.Fatalf("Failed to save draft: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/paddles/"+ids[1]+"/zscores", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Handler returned wrong status code: got %v want %v, body %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	var response struct {
+		SampleCount int            `json:"sample_count"`
+		Metrics     []MetricZScore `json:"metrics"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.SampleCount != 2 || len(response.Metrics) != len(performanceMetrics) {
+		t.Fatalf("got %d samples and %d metrics, want 2 and %d", response.SampleCount, len(response.Metrics), len(performanceMetrics))
+	}
+
+	for _, score := range response.Metrics {
+		switch score.Metric {
+		case "spin":
+			if score.ZScore == nil || *score.ZScore != 1 || score.Mean != 2500 || score.Stddev != 500 {
+				t.Errorf("spin = %+v, want z-score 1 from mean 2500 and stddev 500", score)
+			}
+		case "power":
+			if score.ZScore != nil || score.Reason == "" {
+				t.Errorf("power = %+v, want a null z-score with a reason", score)
+			}
+		}
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/paddles/missing-paddle/zscores", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Handler returned wrong status code for missing paddle: got %v want %v", rr.Code, http.StatusNotFound)
+	}
+}